@@ -35,6 +35,10 @@
 // https://go.googlesource.com/go/+/refs/heads/dev.regabi/src/cmd/compile/internal-abi.md#amd64-architecture
 #define GOROUTINE_PTR(x) ((void *)(x)->r14)
 
+// Arguments that don't fit in registers (e.g. large structs passed by value) are passed
+// in the stack, after the return address.
+#define GO_STACK_PARAMS(x) ((void *)((x)->sp + 8))
+
 #elif defined(__TARGET_ARCH_arm64)
 
 #define GO_PARAM1(x) ((void *)((PT_REGS_ARM64 *)(x))->regs[0])
@@ -51,6 +55,10 @@
 // https://github.com/golang/go/blob/master/src/cmd/compile/abi-internal.md#arm64-architecture
 #define GOROUTINE_PTR(x) ((void *)((PT_REGS_ARM64 *)(x))->regs[28])
 
+// Arguments that don't fit in registers (e.g. large structs passed by value) are passed
+// in the stack, after the slot reserved for the saved link register.
+#define GO_STACK_PARAMS(x) ((void *)(((PT_REGS_ARM64 *)(x))->sp + 8))
+
 #endif /*defined(__TARGET_ARCH_arm64)*/
 
 #define bpf_clamp_umax(VAR, UMAX)                                                                  \
@@ -25,3 +25,6 @@ const redis_client_req_t *unused_7 __attribute__((unused));
 const kafka_go_req_t *unused_8 __attribute__((unused));
 const tcp_large_buffer_t *unused_9 __attribute__((unused));
 const otel_span_t *unused_10 __attribute__((unused));
+const dns_req_t *unused_11 __attribute__((unused));
+const mongo_go_client_req_t *unused_12 __attribute__((unused));
+const go_runtime_event_t *unused_13 __attribute__((unused));
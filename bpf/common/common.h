@@ -35,11 +35,16 @@
 #define KAFKA_MAX_LEN 256
 #define REDIS_MAX_LEN 256
 #define MAX_TOPIC_NAME_LEN 64
+#define MONGO_OP_MAX_LEN 32
+#define MONGO_DB_MAX_LEN 64
+#define MONGO_COLLECTION_MAX_LEN 64
+#define AWS_OPERATION_MAX_LEN 64
 #define HOST_MAX_LEN 100
 #define SCHEME_MAX_LEN 10
 #define HTTP_BODY_MAX_LEN 64
 #define HTTP_HEADER_MAX_LEN 100
 #define HTTP_CONTENT_TYPE_MAX_LEN 16
+#define DNS_MAX_LEN 512 // maximum size of a DNS message over UDP, without EDNS
 
 volatile const u32 mysql_buffer_size = 0;
 volatile const u32 postgres_buffer_size = 0;
@@ -69,15 +74,23 @@ typedef struct http_request_trace_t {
     s64 response_length;
     unsigned char path[PATH_MAX_LEN];
     unsigned char host[HOST_MAX_LEN];
+    unsigned char aws_operation[AWS_OPERATION_MAX_LEN]; // only set by the Go HTTP client
     tp_info_t tp;
     connection_info_t conn;
     pid_info pid;
 } http_request_trace;
 
+// SQL database of the request, when the instrumented client library knows it.
+// Values must match request.SQLKind
+enum sql_kind : u8 {
+    k_sql_kind_unknown = 0,
+    k_sql_kind_postgres = 2,
+};
+
 // TODO(matt): fix naming
 typedef struct sql_request_trace_t {
     u8 type; // Must be first
-    u8 _pad[1];
+    u8 kind; // enum sql_kind
     u16 status;
     pid_info pid;
     u64 start_monotime_ns;
@@ -122,6 +135,36 @@ typedef struct redis_client_req {
     tp_info_t tp;
 } redis_client_req_t;
 
+typedef struct mongo_go_client_req {
+    u8 type; // Must be first
+    u8 err;
+    u8 _pad[6];
+    u64 start_monotime_ns;
+    u64 end_monotime_ns;
+    pid_info pid;
+    connection_info_t conn;
+    tp_info_t tp;
+    unsigned char op[MONGO_OP_MAX_LEN];
+    unsigned char db[MONGO_DB_MAX_LEN];
+    unsigned char collection[MONGO_COLLECTION_MAX_LEN];
+} mongo_go_client_req_t;
+
+// Go runtime health events, which are only reported as metrics
+enum go_runtime_event_kind : u8 {
+    k_go_runtime_gc_pause = 1,
+    k_go_runtime_sched_latency = 2,
+};
+
+typedef struct go_runtime_event {
+    u8 type; // Must be first
+    u8 kind; // enum go_runtime_event_kind
+    u8 _pad[6];
+    u64 start_monotime_ns;
+    u64 end_monotime_ns;
+    pid_info pid;
+    u8 _epad[4];
+} go_runtime_event_t;
+
 // Here we track unknown TCP requests that are not HTTP, HTTP2 or gRPC
 typedef struct tcp_req {
     u8 flags; // Must be fist we use it to tell what kind of packet we have on the ring buffer
@@ -146,6 +189,19 @@ typedef struct tcp_req {
     tp_info_t tp;
 } tcp_req_t;
 
+// DNS lookups over UDP. The response message is forwarded to user space,
+// where the question, record type and response code are parsed
+typedef struct dns_req {
+    u8 type; // Must be first
+    u8 _pad[5];
+    u16 len;
+    u64 start_monotime_ns;
+    u64 end_monotime_ns;
+    connection_info_t conn;
+    pid_info pid;
+    unsigned char buf[DNS_MAX_LEN];
+} dns_req_t;
+
 typedef struct tcp_large_buffer {
     u8 type; // Must be first
     u8 packet_type;
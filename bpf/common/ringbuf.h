@@ -22,6 +22,9 @@
 #define EVENT_GO_KAFKA_SEG 11 // the segment-io version (kafka-go) has different format
 #define EVENT_TCP_LARGE_BUFFER 12
 #define EVENT_GO_SPAN 13
+#define EVENT_DNS_CLIENT 14
+#define EVENT_GO_MONGO 15
+#define EVENT_GO_RUNTIME 16
 
 // setting here the following map definitions without pinning them to a global namespace
 // would lead that services running both HTTP and GRPC server would duplicate
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#pragma once
+
+#include <bpfcore/vmlinux.h>
+#include <bpfcore/bpf_helpers.h>
+#include <bpfcore/bpf_core_read.h>
+#include <bpfcore/bpf_endian.h>
+#include <bpfcore/bpf_tracing.h>
+
+#include <common/common.h>
+#include <common/connection_info.h>
+#include <common/ringbuf.h>
+#include <common/sockaddr.h>
+
+#include <generictracer/protocol_common.h>
+
+#include <generictracer/maps/ongoing_dns_lookups.h>
+
+#include <logger/bpf_dbg.h>
+
+#include <pid/pid.h>
+
+// DNS lookups over UDP. We take the start time and the process of the lookup
+// when the query is sent, and we emit the event when the response is consumed
+// from the same socket. The DNS payload of the response is parsed in user space.
+// For reference, see:
+// https://datatracker.ietf.org/doc/html/rfc1035
+
+enum { k_dns_port = 53, k_dns_header_len = 12 };
+
+static __always_inline int dns_lookup_start(struct sock *sk, struct msghdr *msg, size_t len) {
+    if (len < k_dns_header_len) {
+        return 0;
+    }
+
+    dns_lookup_t lookup = {0};
+    parse_sock_info(sk, &lookup.conn);
+
+    // unconnected sockets provide the destination address on each sendto/sendmsg
+    struct sockaddr *addr = (struct sockaddr *)BPF_CORE_READ(msg, msg_name);
+    if (addr) {
+        connection_info_part_t dst = {0};
+        parse_sockaddr_info(0, addr, &dst);
+        __builtin_memcpy(lookup.conn.d_addr, dst.addr, sizeof(lookup.conn.d_addr));
+        lookup.conn.d_port = dst.port;
+    }
+
+    if (lookup.conn.d_port != k_dns_port) {
+        return 0;
+    }
+
+    dns_lookup_key_t key = {.sk = (u64)sk};
+    if (read_msghdr_buf(msg, (unsigned char *)&key.id, sizeof(key.id)) != sizeof(key.id)) {
+        return 0;
+    }
+
+    bpf_dbg_printk("=== udp DNS query id=%x ===", key.id);
+
+    lookup.start_monotime_ns = bpf_ktime_get_ns();
+    task_pid(&lookup.pid);
+
+    bpf_map_update_elem(&ongoing_dns_lookups, &key, &lookup, BPF_ANY);
+
+    return 0;
+}
+
+SEC("kprobe/udp_sendmsg")
+int BPF_KPROBE(obi_kprobe_udp_sendmsg, struct sock *sk, struct msghdr *msg, size_t len) {
+    (void)ctx;
+
+    u64 id = bpf_get_current_pid_tgid();
+
+    if (!valid_pid(id)) {
+        return 0;
+    }
+
+    return dns_lookup_start(sk, msg, len);
+}
+
+SEC("kprobe/udpv6_sendmsg")
+int BPF_KPROBE(obi_kprobe_udpv6_sendmsg, struct sock *sk, struct msghdr *msg, size_t len) {
+    (void)ctx;
+
+    u64 id = bpf_get_current_pid_tgid();
+
+    if (!valid_pid(id)) {
+        return 0;
+    }
+
+    return dns_lookup_start(sk, msg, len);
+}
+
+// skb_consume_udp is called by both udp_recvmsg and udpv6_recvmsg once the
+// datagram has been copied to the user buffer
+SEC("kprobe/skb_consume_udp")
+int BPF_KPROBE(obi_kprobe_skb_consume_udp, struct sock *sk, struct sk_buff *skb, int len) {
+    (void)ctx;
+    (void)len;
+
+    u64 id = bpf_get_current_pid_tgid();
+
+    if (!valid_pid(id)) {
+        return 0;
+    }
+
+    // the UDP header is not part of the skb data anymore, so we
+    // locate it from the transport header offset
+    unsigned char *head = BPF_CORE_READ(skb, head);
+    u16 transport_header = BPF_CORE_READ(skb, transport_header);
+    unsigned char *udp = head + transport_header;
+
+    struct udphdr hdr;
+    if (bpf_probe_read_kernel(&hdr, sizeof(hdr), udp) != 0) {
+        return 0;
+    }
+
+    u32 size = bpf_ntohs(hdr.len);
+    if (size < sizeof(hdr) + k_dns_header_len) {
+        return 0;
+    }
+    size -= sizeof(hdr);
+
+    unsigned char *payload = udp + sizeof(hdr);
+
+    dns_lookup_key_t key = {.sk = (u64)sk};
+    if (bpf_probe_read_kernel(&key.id, sizeof(key.id), payload) != 0) {
+        return 0;
+    }
+
+    dns_lookup_t *lookup = bpf_map_lookup_elem(&ongoing_dns_lookups, &key);
+    if (!lookup) {
+        return 0;
+    }
+
+    bpf_dbg_printk("=== udp DNS response id=%x, size=%d ===", key.id, size);
+
+    dns_req_t *trace = bpf_ringbuf_reserve(&events, sizeof(dns_req_t), 0);
+    if (trace) {
+        trace->type = EVENT_DNS_CLIENT;
+        trace->start_monotime_ns = lookup->start_monotime_ns;
+        trace->end_monotime_ns = bpf_ktime_get_ns();
+        __builtin_memcpy(&trace->conn, &lookup->conn, sizeof(connection_info_t));
+        __builtin_memcpy(&trace->pid, &lookup->pid, sizeof(pid_info));
+
+        bpf_clamp_umax(size, DNS_MAX_LEN);
+        trace->len = size;
+        bpf_probe_read_kernel(trace->buf, size, payload);
+
+        bpf_ringbuf_submit(trace, get_flags());
+    } else {
+        bpf_printk("failed to reserve space on the ringbuf");
+    }
+
+    bpf_map_delete_elem(&ongoing_dns_lookups, &key);
+
+    return 0;
+}
@@ -13,6 +13,7 @@
 #include <common/ssl_helpers.h>
 #include <common/tcp_info.h>
 
+#include <generictracer/k_dns.h>
 #include <generictracer/k_send_receive.h>
 #include <generictracer/k_tracer_defs.h>
 #include <generictracer/k_unix_sock.h>
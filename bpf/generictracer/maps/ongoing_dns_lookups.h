@@ -0,0 +1,18 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#pragma once
+
+#include <bpfcore/vmlinux.h>
+#include <bpfcore/bpf_helpers.h>
+
+#include <common/map_sizing.h>
+
+#include <generictracer/types/dns_lookup.h>
+
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, dns_lookup_key_t);
+    __type(value, dns_lookup_t);
+    __uint(max_entries, MAX_CONCURRENT_REQUESTS);
+} ongoing_dns_lookups SEC(".maps");
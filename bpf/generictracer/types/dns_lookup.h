@@ -0,0 +1,24 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#pragma once
+
+#include <bpfcore/vmlinux.h>
+
+#include <common/connection_info.h>
+
+#include <pid/types/pid_info.h>
+
+// DNS queries are matched with their responses by socket and DNS message ID, as
+// resolvers such as glibc send the A and AAAA queries concurrently over the same socket
+typedef struct dns_lookup_key {
+    u64 sk;
+    u16 id;
+    u8 _pad[6];
+} dns_lookup_key_t;
+
+typedef struct dns_lookup {
+    u64 start_monotime_ns;
+    connection_info_t conn;
+    pid_info pid;
+} dns_lookup_t;
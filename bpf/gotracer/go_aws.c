@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build obi_bpf_ignore
+
+#include <bpfcore/utils.h>
+
+#include <gotracer/go_common.h>
+#include <gotracer/go_str.h>
+
+#include <logger/bpf_dbg.h>
+
+// All the aws-sdk-go-v2 service clients run their operations through the middleware
+// stack from the same generated method. The HTTP requests sent while the operation
+// is running are tagged with its name, so their spans can be reported as AWS API calls.
+//
+// func (c *Client) invokeOperation(ctx context.Context, opID string, params interface{},
+//     optFns []func(*Options), stackFns ...func(*middleware.Stack, Options) error)
+//     (result interface{}, metadata middleware.Metadata, err error)
+SEC("uprobe/awsInvokeOperation")
+int obi_uprobe_awsInvokeOperation(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/aws Client invokeOperation === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    void *op_id_ptr = GO_PARAM4(ctx);
+    u64 op_id_len = (u64)GO_PARAM5(ctx);
+    if (!op_id_ptr || !op_id_len) {
+        return 0;
+    }
+
+    aws_operation_t op = {};
+    if (!read_go_str_n("aws operation", op_id_ptr, op_id_len, op.name, sizeof(op.name) - 1)) {
+        return 0;
+    }
+
+    bpf_dbg_printk("aws operation: %s", op.name);
+    bpf_map_update_elem(&ongoing_aws_operations, &g_key, &op, BPF_ANY);
+
+    return 0;
+}
+
+SEC("uprobe/awsInvokeOperation_return")
+int obi_uprobe_awsInvokeOperationReturn(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/aws Client invokeOperation returns === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    bpf_map_delete_elem(&ongoing_aws_operations, &g_key);
+
+    return 0;
+}
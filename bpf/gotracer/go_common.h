@@ -105,7 +105,8 @@ typedef struct sql_func_invocation {
     u64 query_len;
     tp_info_t tp;
     connection_info_t conn;
-    u8 _pad[4];
+    u8 kind; // enum sql_kind
+    u8 _pad[3];
 } sql_func_invocation_t;
 
 struct {
@@ -115,6 +116,26 @@ struct {
     __uint(max_entries, MAX_CONCURRENT_REQUESTS);
 } ongoing_sql_queries SEC(".maps");
 
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, go_addr_key_t); // key: pointer to the request goroutine
+    __type(value, mongo_go_client_req_t);
+    __uint(max_entries, MAX_CONCURRENT_REQUESTS);
+} ongoing_mongo_requests SEC(".maps");
+
+// Operation name of the AWS SDK v2 client calls, which are propagated to the HTTP client
+// requests sent from the same goroutine
+typedef struct aws_operation {
+    unsigned char name[AWS_OPERATION_MAX_LEN];
+} aws_operation_t;
+
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, go_addr_key_t); // key: pointer to the request goroutine
+    __type(value, aws_operation_t);
+    __uint(max_entries, MAX_CONCURRENT_REQUESTS);
+} ongoing_aws_operations SEC(".maps");
+
 typedef struct grpc_header_field {
     u8 *key_ptr;
     u64 key_len;
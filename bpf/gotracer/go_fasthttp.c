@@ -0,0 +1,605 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build obi_bpf_ignore
+
+#include <bpfcore/utils.h>
+
+#include <common/http_types.h>
+#include <common/ringbuf.h>
+#include <common/strings.h>
+
+#include <gotracer/go_common.h>
+#include <gotracer/go_str.h>
+
+#include <logger/bpf_dbg.h>
+
+// github.com/valyala/fasthttp.argsKV, the element type of the RequestHeader.h slice
+// where the non-standard headers (e.g. traceparent) are stored.
+typedef struct fasthttp_args_kv {
+    u8 *key_ptr;
+    u64 key_len;
+    u64 key_cap;
+    u8 *val_ptr;
+    u64 val_len;
+    u64 val_cap;
+    u64 no_value;
+} fasthttp_args_kv_t;
+
+enum { k_fasthttp_max_headers = 16 };
+
+typedef struct fasthttp_client_req {
+    void *resp_ptr;   // *fasthttp.Response, read when the request returns
+    void *writer_ptr; // *bufio.Writer where the request header is being written
+    u8 is_tls;
+    u8 _pad[7];
+} fasthttp_client_req_t;
+
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, go_addr_key_t); // key: goroutine id
+    __type(value, void *);      // the *fasthttp.RequestHeader being read
+    __uint(max_entries, MAX_CONCURRENT_REQUESTS);
+} fasthttp_request_headers SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, go_addr_key_t);          // key: goroutine id
+    __type(value, fasthttp_client_req_t); // the ongoing client request
+    __uint(max_entries, MAX_CONCURRENT_REQUESTS);
+} fasthttp_client_requests SEC(".maps");
+
+// fasthttp structs are larger than the u8 offsets accepted by read_go_str, so the
+// field address is computed first.
+static __always_inline int
+fasthttp_read_bytes(char *name, void *base_ptr, u64 offset, void *field, u64 max_size) {
+    return read_go_str(name, base_ptr + offset, 0, field, max_size);
+}
+
+static __always_inline s64 fasthttp_read_int(void *base_ptr, u64 offset) {
+    s64 val = 0;
+    bpf_probe_read(&val, sizeof(val), base_ptr + offset);
+    return val;
+}
+
+// fasthttp keeps the request URI with its query string, which we don't report as part of the path
+static __always_inline void fasthttp_strip_query(u8 *path) {
+    for (u16 i = 0; i < PATH_MAX_LEN; i++) {
+        if (path[i] == 0) {
+            break;
+        }
+        if (path[i] == '?') {
+            path[i] = 0;
+            break;
+        }
+    }
+}
+
+// fasthttp leaves the method empty when it's not explicitly set, meaning GET
+static __always_inline void fasthttp_default_method(u8 *method) {
+    if (method[0] == 0) {
+        __builtin_memcpy(method, "GET", 4);
+    }
+}
+
+static __always_inline u8 fasthttp_find_traceparent(void *header_ptr, tp_info_t *tp) {
+    off_table_t *ot = get_offsets_table();
+
+    void *h = 0;
+    u64 h_len = 0;
+    u64 h_pos = go_offset_of(ot, (go_offset){.v = _fasthttp_request_header_h_pos});
+    bpf_probe_read(&h, sizeof(h), header_ptr + h_pos);
+    bpf_probe_read(&h_len, sizeof(h_len), header_ptr + h_pos + 8);
+    bpf_dbg_printk("h ptr %llx, len %d", h, h_len);
+
+    if (!h) {
+        return 0;
+    }
+
+    for (u8 i = 0; i < k_fasthttp_max_headers; i++) {
+        if (i >= h_len) {
+            break;
+        }
+        fasthttp_args_kv_t kv = {};
+        bpf_probe_read(&kv, sizeof(kv), h + (i * sizeof(fasthttp_args_kv_t)));
+        if (kv.key_len == W3C_KEY_LENGTH && kv.val_len == W3C_VAL_LENGTH) {
+            unsigned char temp[W3C_VAL_LENGTH];
+
+            bpf_probe_read(&temp, W3C_KEY_LENGTH, kv.key_ptr);
+            if (stricmp((const char *)temp, "traceparent", W3C_KEY_LENGTH)) {
+                bpf_probe_read(&temp, W3C_VAL_LENGTH, kv.val_ptr);
+                decode_go_traceparent(temp, tp->trace_id, tp->parent_id, &tp->flags);
+                return 1;
+            }
+        }
+    }
+
+    return 0;
+}
+
+/* fasthttp server */
+
+// func (h *RequestHeader) readLoop(r *bufio.Reader, waitForMore bool) error
+SEC("uprobe/fasthttpRequestHeaderRead")
+int obi_uprobe_fasthttpRequestHeaderRead(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp RequestHeader read === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    // only the headers read from a server connection are requests to this service
+    if (!bpf_map_lookup_elem(&ongoing_server_connections, &g_key)) {
+        return 0;
+    }
+
+    void *header_ptr = GO_PARAM1(ctx);
+    if (header_ptr) {
+        bpf_map_update_elem(&fasthttp_request_headers, &g_key, &header_ptr, BPF_ANY);
+    }
+
+    return 0;
+}
+
+SEC("uprobe/fasthttpRequestHeaderRead")
+int obi_uprobe_fasthttpRequestHeaderReadReturns(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp RequestHeader read returns === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    void **header_ptr_ptr = bpf_map_lookup_elem(&fasthttp_request_headers, &g_key);
+    if (!header_ptr_ptr) {
+        return 0;
+    }
+    void *header_ptr = *header_ptr_ptr;
+    bpf_map_delete_elem(&fasthttp_request_headers, &g_key);
+
+    void *err_ptr = GO_PARAM1(ctx);
+    if (err_ptr) {
+        bpf_dbg_printk("error reading the request header");
+        return 0;
+    }
+
+    off_table_t *ot = get_offsets_table();
+
+    server_http_func_invocation_t invocation = {
+        .start_monotime_ns = bpf_ktime_get_ns(),
+        .tp = {0},
+        .status = 0,
+        .content_length = 0,
+        .response_length = 0,
+    };
+
+    tp_info_t decoded = {};
+    tp_info_t *decoded_tp = 0;
+    if (fasthttp_find_traceparent(header_ptr, &decoded)) {
+        decoded_tp = &decoded;
+    }
+    server_trace_parent(goroutine_addr, &invocation.tp, decoded_tp);
+
+    u64 method_pos = go_offset_of(ot, (go_offset){.v = _fasthttp_request_header_method_pos});
+    if (!fasthttp_read_bytes(
+            "method", header_ptr, method_pos, &invocation.method, sizeof(invocation.method))) {
+        bpf_dbg_printk("can't read fasthttp RequestHeader.method");
+        return 0;
+    }
+    fasthttp_default_method(invocation.method);
+
+    u64 path_pos = go_offset_of(ot, (go_offset){.v = _fasthttp_request_header_request_uri_pos});
+    if (!fasthttp_read_bytes(
+            "path", header_ptr, path_pos, &invocation.path, sizeof(invocation.path))) {
+        bpf_dbg_printk("can't read fasthttp RequestHeader.requestURI");
+        return 0;
+    }
+    fasthttp_strip_query(invocation.path);
+
+    s64 content_length = fasthttp_read_int(
+        header_ptr,
+        go_offset_of(ot, (go_offset){.v = _fasthttp_request_header_content_length_pos}));
+    // negative values flag chunked or identity bodies
+    if (content_length > 0) {
+        invocation.content_length = content_length;
+    }
+
+    bpf_dbg_printk("method: %s, path: %s", invocation.method, invocation.path);
+
+    if (bpf_map_update_elem(&ongoing_http_server_requests, &g_key, &invocation, BPF_ANY)) {
+        bpf_dbg_printk("can't update map element");
+    }
+
+    // The same goroutine serves all the requests of a keep-alive connection, so the
+    // request starts when its header has been read.
+    goroutine_metadata *g_metadata = bpf_map_lookup_elem(&ongoing_goroutines, &g_key);
+    if (!g_metadata) {
+        goroutine_metadata metadata = {
+            .timestamp = invocation.start_monotime_ns,
+            .parent = g_key,
+        };
+
+        if (bpf_map_update_elem(&ongoing_goroutines, &g_key, &metadata, BPF_ANY)) {
+            bpf_dbg_printk("can't update active goroutine");
+        }
+    } else {
+        g_metadata->timestamp = invocation.start_monotime_ns;
+    }
+
+    return 0;
+}
+
+// func (resp *Response) Write(w *bufio.Writer) error
+SEC("uprobe/fasthttpResponseWrite")
+int obi_uprobe_fasthttpResponseWrite(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp Response write === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    server_http_func_invocation_t *invocation =
+        bpf_map_lookup_elem(&ongoing_http_server_requests, &g_key);
+    if (!invocation) {
+        return 0;
+    }
+
+    void *resp_ptr = GO_PARAM1(ctx);
+    if (!resp_ptr) {
+        return 0;
+    }
+
+    off_table_t *ot = get_offsets_table();
+
+    void *header_ptr = resp_ptr + go_offset_of(ot, (go_offset){.v = _fasthttp_response_header_pos});
+
+    s64 status = fasthttp_read_int(
+        header_ptr, go_offset_of(ot, (go_offset){.v = _fasthttp_response_header_status_code_pos}));
+    // an unset status code is written as 200 OK
+    invocation->status = status > 0 ? status : 200;
+
+    s64 response_length = fasthttp_read_int(
+        header_ptr,
+        go_offset_of(ot, (go_offset){.v = _fasthttp_response_header_content_length_pos}));
+    invocation->response_length = response_length > 0 ? response_length : 0;
+
+    bpf_dbg_printk(
+        "status %d, response_length %d", invocation->status, invocation->response_length);
+
+    return 0;
+}
+
+SEC("uprobe/fasthttpResponseWrite")
+int obi_uprobe_fasthttpResponseWriteReturns(struct pt_regs *ctx) {
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    // the response is only reported when written by the goroutine serving the request
+    if (!bpf_map_lookup_elem(&ongoing_http_server_requests, &g_key)) {
+        return 0;
+    }
+
+    return serve_http_returns(ctx);
+}
+
+/* fasthttp client */
+
+// func (c *HostClient) doNonNilReqResp(req *Request, resp *Response) (bool, error)
+SEC("uprobe/fasthttpDoNonNilReqResp")
+int obi_uprobe_fasthttpDoNonNilReqResp(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp HostClient doNonNilReqResp === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    void *hc_ptr = GO_PARAM1(ctx);
+    void *req_ptr = GO_PARAM2(ctx);
+    void *resp_ptr = GO_PARAM3(ctx);
+    if (!hc_ptr || !req_ptr || !resp_ptr) {
+        return 0;
+    }
+
+    off_table_t *ot = get_offsets_table();
+
+    http_func_invocation_t invocation = {.start_monotime_ns = bpf_ktime_get_ns(), .tp = {0}};
+    client_trace_parent(goroutine_addr, &invocation.tp);
+
+    http_client_data_t trace = {0};
+
+    void *header_ptr = req_ptr + go_offset_of(ot, (go_offset){.v = _fasthttp_request_header_pos});
+    u64 method_pos = go_offset_of(ot, (go_offset){.v = _fasthttp_request_header_method_pos});
+    if (!fasthttp_read_bytes(
+            "method", header_ptr, method_pos, &trace.method, sizeof(trace.method))) {
+        bpf_dbg_printk("can't read fasthttp RequestHeader.method");
+        return 0;
+    }
+    fasthttp_default_method(trace.method);
+
+    s64 content_length = fasthttp_read_int(
+        header_ptr,
+        go_offset_of(ot, (go_offset){.v = _fasthttp_request_header_content_length_pos}));
+    if (content_length > 0) {
+        trace.content_length = content_length;
+    }
+
+    // The URI is parsed by the Client before choosing the HostClient for the request
+    void *uri_ptr = req_ptr + go_offset_of(ot, (go_offset){.v = _fasthttp_request_uri_pos});
+    u64 path_pos = go_offset_of(ot, (go_offset){.v = _fasthttp_uri_path_pos});
+    if (!fasthttp_read_bytes("path", uri_ptr, path_pos, &trace.path, sizeof(trace.path))) {
+        bpf_dbg_printk("can't read fasthttp URI.path");
+        return 0;
+    }
+
+    u64 host_pos = go_offset_of(ot, (go_offset){.v = _fasthttp_uri_host_pos});
+    if (!fasthttp_read_bytes("host", uri_ptr, host_pos, &trace.host, sizeof(trace.host))) {
+        bpf_dbg_printk("can't read fasthttp URI.host");
+        return 0;
+    }
+
+    u64 scheme_pos = go_offset_of(ot, (go_offset){.v = _fasthttp_uri_scheme_pos});
+    if (!fasthttp_read_bytes("scheme", uri_ptr, scheme_pos, &trace.scheme, sizeof(trace.scheme))) {
+        bpf_dbg_printk("can't read fasthttp URI.scheme");
+        return 0;
+    }
+
+    bpf_dbg_printk("path: %s", trace.path);
+    bpf_dbg_printk("host: %s", trace.host);
+    bpf_dbg_printk("scheme: %s", trace.scheme);
+
+    fasthttp_client_req_t client_req = {.resp_ptr = resp_ptr};
+    bpf_probe_read(&client_req.is_tls,
+                   sizeof(client_req.is_tls),
+                   hc_ptr + go_offset_of(ot, (go_offset){.v = _fasthttp_host_client_is_tls_pos}));
+
+    if (bpf_map_update_elem(&go_ongoing_http_client_requests, &g_key, &invocation, BPF_ANY)) {
+        bpf_dbg_printk("can't update http client map element");
+    }
+
+    bpf_map_update_elem(&ongoing_http_client_requests_data, &g_key, &trace, BPF_ANY);
+    bpf_map_update_elem(&fasthttp_client_requests, &g_key, &client_req, BPF_ANY);
+
+    return 0;
+}
+
+// func (c *HostClient) acquireConn(reqTimeout time.Duration, connectionClose bool)
+//   (cc *clientConn, err error)
+// renamed as AcquireConn in v1.65.0
+SEC("uprobe/fasthttpAcquireConn")
+int obi_uprobe_fasthttpAcquireConnReturns(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp HostClient acquireConn returns === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    fasthttp_client_req_t *client_req = bpf_map_lookup_elem(&fasthttp_client_requests, &g_key);
+    if (!client_req) {
+        return 0;
+    }
+
+    http_func_invocation_t *invocation =
+        bpf_map_lookup_elem(&go_ongoing_http_client_requests, &g_key);
+    if (!invocation) {
+        bpf_dbg_printk("can't find invocation info for client call, this might be a bug");
+        return 0;
+    }
+
+    void *cc_ptr = GO_PARAM1(ctx);
+    if (!cc_ptr) {
+        return 0;
+    }
+
+    off_table_t *ot = get_offsets_table();
+
+    // clientConn.c is a net.Conn interface: skip its 8 bytes type pointer to get
+    // the address of its data pointer, as net/http does for conn.rwc
+    void *conn_conn_ptr =
+        cc_ptr + 8 + go_offset_of(ot, (go_offset){.v = _fasthttp_client_conn_conn_pos});
+    bpf_dbg_printk("conn_conn_ptr %llx, is_tls %d", conn_conn_ptr, client_req->is_tls);
+
+    if (client_req->is_tls) {
+        // the connection of TLS host clients is a *tls.Conn wrapping the net.Conn. There's no
+        // tls state to pass here, any non-nil pointer makes it unwrap the connection.
+        conn_conn_ptr = unwrap_tls_conn_info(conn_conn_ptr, cc_ptr);
+    }
+    setup_client_connection(&g_key, invocation, conn_conn_ptr, client_req->is_tls);
+
+    return 0;
+}
+
+#ifndef NO_HEADER_PROPAGATION
+// func (h *RequestHeader) Write(w *bufio.Writer) error
+// Context propagation through the HTTP headers, as net/http does in writeSubset. The whole
+// header is written at once into the bufio.Writer, before it's encrypted by the TLS
+// connection, so the traceparent is inserted before the empty line that ends the header.
+SEC("uprobe/fasthttpRequestHeaderWrite")
+int obi_uprobe_fasthttpRequestHeaderWrite(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp RequestHeader Write === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    fasthttp_client_req_t *client_req = bpf_map_lookup_elem(&fasthttp_client_requests, &g_key);
+    if (!client_req) {
+        return 0;
+    }
+    client_req->writer_ptr = GO_PARAM2(ctx);
+
+    return 0;
+}
+
+SEC("uprobe/fasthttpRequestHeaderWrite")
+int obi_uprobe_fasthttpRequestHeaderWriteReturns(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp RequestHeader Write returns === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    fasthttp_client_req_t *client_req = bpf_map_lookup_elem(&fasthttp_client_requests, &g_key);
+    if (!client_req || !client_req->writer_ptr) {
+        return 0;
+    }
+    void *io_writer_addr = client_req->writer_ptr;
+    client_req->writer_ptr = 0;
+
+    // the returned error interface
+    if (GO_PARAM1(ctx)) {
+        return 0;
+    }
+
+    http_func_invocation_t *invocation =
+        bpf_map_lookup_elem(&go_ongoing_http_client_requests, &g_key);
+    if (!invocation) {
+        bpf_dbg_printk("can't find invocation info for client call, this might be a bug");
+        return 0;
+    }
+
+    off_table_t *ot = get_offsets_table();
+
+    u64 io_writer_buf_ptr_pos = go_offset_of(ot, (go_offset){.v = _io_writer_buf_ptr_pos});
+    u64 io_writer_n_pos = go_offset_of(ot, (go_offset){.v = _io_writer_n_pos});
+
+    // writing with bad offsets can crash the application, be defensive here
+    if (!io_writer_buf_ptr_pos || !io_writer_n_pos) {
+        return 0;
+    }
+
+    void *buf_ptr = 0;
+    bpf_probe_read(&buf_ptr, sizeof(buf_ptr), (void *)(io_writer_addr + io_writer_buf_ptr_pos));
+    if (!buf_ptr) {
+        return 0;
+    }
+
+    s64 size = 0;
+    bpf_probe_read(
+        &size, sizeof(s64), (void *)(io_writer_addr + io_writer_buf_ptr_pos + 8)); // grab size
+
+    s64 len = 0;
+    bpf_probe_read(&len, sizeof(s64), (void *)(io_writer_addr + io_writer_n_pos)); // grab len
+
+    bpf_dbg_printk("buf_ptr %llx, len=%d, size=%d", (void *)buf_ptr, len, size);
+
+    // 4 = strlen(": ") + strlen("\r\n")
+    if (len < 4 || len >= (size - TP_MAX_VAL_LENGTH - TP_MAX_KEY_LENGTH - 4)) {
+        return 0;
+    }
+
+    // if the header didn't fit in the buffer, it has been already flushed
+    char header_end[4] = {};
+    bpf_probe_read(header_end, sizeof(header_end), buf_ptr + ((len - 4) & 0x0ffff));
+    if (header_end[0] != '\r' || header_end[1] != '\n' || header_end[2] != '\r' ||
+        header_end[3] != '\n') {
+        bpf_dbg_printk("the fasthttp request header is not at the end of the buffer");
+        return 0;
+    }
+
+    unsigned char buf[TRACEPARENT_LEN];
+    make_tp_string(buf, &invocation->tp);
+
+    // overwrite the empty line with the traceparent, followed by a new empty line
+    len -= 2;
+    char key[TP_MAX_KEY_LENGTH + 2] = "Traceparent: ";
+    char end[4] = "\r\n\r\n";
+    bpf_probe_write_user(buf_ptr + (len & 0x0ffff), key, sizeof(key));
+    len += TP_MAX_KEY_LENGTH + 2;
+    bpf_probe_write_user(buf_ptr + (len & 0x0ffff), buf, sizeof(buf));
+    len += TP_MAX_VAL_LENGTH;
+    bpf_probe_write_user(buf_ptr + (len & 0x0ffff), end, sizeof(end));
+    len += 4;
+    bpf_probe_write_user((void *)(io_writer_addr + io_writer_n_pos), &len, sizeof(len));
+
+    // As in writeSubset, the TC context propagation must skip the request if the
+    // traceparent has been written in the header
+    connection_info_t *info = bpf_map_lookup_elem(&ongoing_client_connections, &g_key);
+    if (info) {
+        egress_key_t e_key = {
+            .d_port = info->d_port,
+            .s_port = info->s_port,
+        };
+        bpf_map_delete_elem(&outgoing_trace_map, &e_key);
+        bpf_dbg_printk("wrote fasthttp traceparent, removing outgoing trace map %d:%d",
+                       e_key.s_port,
+                       e_key.d_port);
+    }
+
+    return 0;
+}
+#else
+SEC("uprobe/fasthttpRequestHeaderWrite")
+int obi_uprobe_fasthttpRequestHeaderWrite(struct pt_regs *ctx) {
+    return 0;
+}
+
+SEC("uprobe/fasthttpRequestHeaderWrite")
+int obi_uprobe_fasthttpRequestHeaderWriteReturns(struct pt_regs *ctx) {
+    return 0;
+}
+#endif
+
+SEC("uprobe/fasthttpDoNonNilReqResp")
+int obi_uprobe_fasthttpDoNonNilReqRespReturns(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/fasthttp HostClient doNonNilReqResp returns === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    fasthttp_client_req_t *client_req = bpf_map_lookup_elem(&fasthttp_client_requests, &g_key);
+    if (!client_req) {
+        return 0;
+    }
+    void *resp_ptr = client_req->resp_ptr;
+    bpf_map_delete_elem(&fasthttp_client_requests, &g_key);
+
+    off_table_t *ot = get_offsets_table();
+
+    u16 status = 0;
+    s64 response_length = 0;
+
+    // the first returned value is a bool, followed by the error interface
+    void *err_ptr = GO_PARAM2(ctx);
+    if (!err_ptr) {
+        void *header_ptr =
+            resp_ptr + go_offset_of(ot, (go_offset){.v = _fasthttp_response_header_pos});
+
+        s64 status_code = fasthttp_read_int(
+            header_ptr,
+            go_offset_of(ot, (go_offset){.v = _fasthttp_response_header_status_code_pos}));
+        status = status_code > 0 ? status_code : 200;
+
+        response_length = fasthttp_read_int(
+            header_ptr,
+            go_offset_of(ot, (go_offset){.v = _fasthttp_response_header_content_length_pos}));
+        if (response_length < 0) {
+            response_length = 0;
+        }
+    }
+
+    bpf_dbg_printk("status %d, response_length %d", status, response_length);
+
+    submit_http_client_trace(&g_key, status, response_length);
+    return 0;
+}
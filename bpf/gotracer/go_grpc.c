@@ -270,6 +270,7 @@ int obi_uprobe_server_handleStream_return(struct pt_regs *ctx) {
     trace->content_length = 0;
     trace->method[0] = '\0';
     trace->host[0] = '\0';
+    trace->aws_operation[0] = '\0';
     trace->scheme[0] = '\0';
     trace->go_start_monotime_ns = invocation->start_monotime_ns;
     bpf_map_delete_elem(&ongoing_goroutines, &g_key);
@@ -453,6 +454,7 @@ static __always_inline int grpc_connect_done(struct pt_regs *ctx, void *err) {
     trace->content_length = 0;
     trace->method[0] = '\0';
     trace->host[0] = '\0';
+    trace->aws_operation[0] = '\0';
     trace->scheme[0] = '\0';
 
     // Read arguments from the original set of registers
@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build obi_bpf_ignore
+
+#include <bpfcore/utils.h>
+
+#include <common/ringbuf.h>
+
+#include <gotracer/go_common.h>
+#include <gotracer/go_str.h>
+
+#include <logger/bpf_dbg.h>
+
+enum {
+    // OP_MSG wire message layout: 16 bytes header (length, request id, response to, opcode),
+    // 4 bytes flags, 1 byte section kind and the body BSON document: 4 bytes length,
+    // followed by the command element (type, name and value).
+    k_mongo_opcode_pos = 12,
+    k_mongo_section_kind_pos = 20,
+    k_mongo_elem_type_pos = 25,
+    k_mongo_elem_name_pos = 26,
+    k_mongo_op_msg = 2013,
+    k_mongo_section_body = 0,
+    k_mongo_bson_string = 0x02,
+};
+
+// func (op Operation) Execute(ctx context.Context) error
+// The Operation is passed by value and it's too large to fit in registers, so it's in the stack.
+SEC("uprobe/mongoOperationExecute")
+int obi_uprobe_mongoOperationExecute(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/mongo Operation Execute === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    // operations executed while running another operation (e.g. the connection handshake)
+    // are accounted as part of it
+    if (bpf_map_lookup_elem(&ongoing_mongo_requests, &g_key)) {
+        bpf_dbg_printk("mongo operation already tracked for this goroutine");
+        return 0;
+    }
+
+    mongo_go_client_req_t req = {
+        .type = EVENT_GO_MONGO,
+        .start_monotime_ns = bpf_ktime_get_ns(),
+    };
+
+    client_trace_parent(goroutine_addr, &req.tp);
+
+    void *op_ptr = GO_STACK_PARAMS(ctx);
+    off_table_t *ot = get_offsets_table();
+
+    u64 db_pos = go_offset_of(ot, (go_offset){.v = _mongo_operation_database_pos});
+    if (!read_go_str("database", op_ptr + db_pos, 0, req.db, sizeof(req.db))) {
+        bpf_dbg_printk("can't read mongo Operation.Database");
+    }
+
+    // Operation.Name only exists since v1.13.0. Otherwise, the command name
+    // is read from the wire message.
+    u64 name_pos = go_offset_of(ot, (go_offset){.v = _mongo_operation_name_pos});
+    if (name_pos) {
+        read_go_str("name", op_ptr + name_pos, 0, req.op, sizeof(req.op));
+    }
+
+    bpf_dbg_printk("db: %s, op: %s", req.db, req.op);
+
+    bpf_map_update_elem(&ongoing_mongo_requests, &g_key, &req, BPF_ANY);
+
+    return 0;
+}
+
+SEC("uprobe/mongoOperationExecute")
+int obi_uprobe_mongoOperationExecuteReturn(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/mongo Operation Execute returns === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    mongo_go_client_req_t *req = bpf_map_lookup_elem(&ongoing_mongo_requests, &g_key);
+    if (!req) {
+        return 0;
+    }
+
+    mongo_go_client_req_t *trace = bpf_ringbuf_reserve(&events, sizeof(mongo_go_client_req_t), 0);
+    if (trace) {
+        bpf_dbg_printk("Sending mongo client go trace");
+        __builtin_memcpy(trace, req, sizeof(mongo_go_client_req_t));
+        trace->end_monotime_ns = bpf_ktime_get_ns();
+        trace->err = GO_PARAM1(ctx) != NULL;
+        task_pid(&trace->pid);
+        bpf_ringbuf_submit(trace, get_flags());
+    } else {
+        bpf_dbg_printk("can't reserve space in the ringbuffer");
+    }
+
+    bpf_map_delete_elem(&ongoing_mongo_requests, &g_key);
+
+    return 0;
+}
+
+// Reads the command name and the collection from the first element of the command
+// document, e.g. {"find": "collection", ...}. Compressed messages are ignored.
+static __always_inline void mongo_parse_wire_message(struct pt_regs *ctx, void *wm, u64 wm_len) {
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx, wm %llx, len %d", goroutine_addr, wm, wm_len);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    mongo_go_client_req_t *req = bpf_map_lookup_elem(&ongoing_mongo_requests, &g_key);
+    // retries send the same command again
+    if (!req || req->collection[0] || !wm || wm_len <= k_mongo_elem_name_pos) {
+        return;
+    }
+
+    s32 opcode = 0;
+    bpf_probe_read(&opcode, sizeof(opcode), wm + k_mongo_opcode_pos);
+    u8 section_kind = 0xff;
+    bpf_probe_read(&section_kind, sizeof(section_kind), wm + k_mongo_section_kind_pos);
+    if (opcode != k_mongo_op_msg || section_kind != k_mongo_section_body) {
+        bpf_dbg_printk("unsupported mongo wire message, opcode %d", opcode);
+        return;
+    }
+
+    long name_len = bpf_probe_read_user_str(req->op, sizeof(req->op), wm + k_mongo_elem_name_pos);
+    if (name_len <= 0) {
+        return;
+    }
+
+    u8 elem_type = 0;
+    bpf_probe_read(&elem_type, sizeof(elem_type), wm + k_mongo_elem_type_pos);
+    if (elem_type == k_mongo_bson_string) {
+        // the string value is prefixed by its 4 bytes length
+        bpf_probe_read_user_str(req->collection,
+                                sizeof(req->collection),
+                                wm + k_mongo_elem_name_pos + name_len + sizeof(s32));
+    }
+
+    bpf_dbg_printk("op: %s, collection: %s", req->op, req->collection);
+}
+
+// func (op Operation) roundTrip(ctx context.Context, conn Connection, wm []byte) ([]byte, error)
+SEC("uprobe/mongoOperationRoundTrip")
+int obi_uprobe_mongoOperationRoundTrip(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/mongo Operation roundTrip === ");
+    mongo_parse_wire_message(ctx, GO_PARAM5(ctx), (u64)GO_PARAM6(ctx));
+    return 0;
+}
+
+// func (op Operation) roundTrip(ctx context.Context, conn *mnet.Connection, wm []byte) ([]byte, error)
+SEC("uprobe/mongoOperationRoundTrip")
+int obi_uprobe_mongoV2OperationRoundTrip(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/mongo v2 Operation roundTrip === ");
+    mongo_parse_wire_message(ctx, GO_PARAM4(ctx), (u64)GO_PARAM5(ctx));
+    return 0;
+}
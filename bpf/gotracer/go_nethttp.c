@@ -44,6 +44,7 @@ typedef struct http_client_data {
     unsigned char host[HOST_MAX_LEN];
     unsigned char scheme[SCHEME_MAX_LEN];
     unsigned char method[METHOD_MAX_LEN];
+    unsigned char aws_operation[AWS_OPERATION_MAX_LEN];
     u8 _pad[3];
 } http_client_data_t;
 
@@ -388,6 +389,7 @@ static __always_inline int serve_http_returns(struct pt_regs *ctx) {
     trace->start_monotime_ns = invocation->start_monotime_ns;
     trace->end_monotime_ns = bpf_ktime_get_ns();
     trace->host[0] = '\0';
+    trace->aws_operation[0] = '\0';
     trace->scheme[0] = '\0';
 
     goroutine_metadata *g_metadata = bpf_map_lookup_elem(&ongoing_goroutines, &g_key);
@@ -520,6 +522,13 @@ static __always_inline void roundTripStartHelper(struct pt_regs *ctx) {
     bpf_dbg_printk("host: %s", trace.host);
     bpf_dbg_printk("scheme: %s", trace.scheme);
 
+    // Requests sent by the AWS SDK carry the name of the invoked service operation
+    aws_operation_t *aws_op = bpf_map_lookup_elem(&ongoing_aws_operations, &g_key);
+    if (aws_op) {
+        __builtin_memcpy(trace.aws_operation, aws_op->name, sizeof(trace.aws_operation));
+        bpf_dbg_printk("aws operation: %s", trace.aws_operation);
+    }
+
     // Write event
     if (bpf_map_update_elem(&go_ongoing_http_client_requests, &g_key, &invocation, BPF_ANY)) {
         bpf_dbg_printk("can't update http client map element");
@@ -547,25 +556,18 @@ int obi_uprobe_roundTrip(struct pt_regs *ctx) {
     return 0;
 }
 
-SEC("uprobe/roundTrip_return")
-int obi_uprobe_roundTripReturn(struct pt_regs *ctx) {
-    bpf_dbg_printk("=== uprobe/proc http roundTrip return === ");
-
-    void *goroutine_addr = GOROUTINE_PTR(ctx);
-    off_table_t *ot = get_offsets_table();
-
-    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
-    go_addr_key_t g_key = {};
-    go_addr_key_from_id(&g_key, goroutine_addr);
-
+// Submits the client span for the request tracked by the goroutine, with the response
+// status and length read by the caller.
+static __always_inline void
+submit_http_client_trace(go_addr_key_t *g_key, u16 status, s64 response_length) {
     http_func_invocation_t *invocation =
-        bpf_map_lookup_elem(&go_ongoing_http_client_requests, &g_key);
+        bpf_map_lookup_elem(&go_ongoing_http_client_requests, g_key);
     if (invocation == NULL) {
         bpf_dbg_printk("can't read http invocation metadata");
         goto done;
     }
 
-    http_client_data_t *data = bpf_map_lookup_elem(&ongoing_http_client_requests_data, &g_key);
+    http_client_data_t *data = bpf_map_lookup_elem(&ongoing_http_client_requests_data, g_key);
     if (data == NULL) {
         bpf_dbg_printk("can't read http client invocation data");
         goto done;
@@ -588,13 +590,10 @@ int obi_uprobe_roundTripReturn(struct pt_regs *ctx) {
     __builtin_memcpy(trace->path, data->path, sizeof(trace->path));
     __builtin_memcpy(trace->host, data->host, sizeof(trace->host));
     __builtin_memcpy(trace->scheme, data->scheme, sizeof(trace->scheme));
+    __builtin_memcpy(trace->aws_operation, data->aws_operation, sizeof(trace->aws_operation));
     trace->content_length = data->content_length;
 
-    // Get request/response struct
-
-    void *resp_ptr = (void *)GO_PARAM1(ctx);
-
-    connection_info_t *info = bpf_map_lookup_elem(&ongoing_client_connections, &g_key);
+    connection_info_t *info = bpf_map_lookup_elem(&ongoing_client_connections, g_key);
     if (info) {
         __builtin_memcpy(&trace->conn, info, sizeof(connection_info_t));
 
@@ -609,6 +608,8 @@ int obi_uprobe_roundTripReturn(struct pt_regs *ctx) {
     }
 
     trace->tp = invocation->tp;
+    trace->status = status;
+    trace->response_length = response_length;
 
     unsigned char tp_buf[TP_MAX_VAL_LENGTH];
     make_tp_string(tp_buf, &invocation->tp);
@@ -616,29 +617,46 @@ int obi_uprobe_roundTripReturn(struct pt_regs *ctx) {
     bpf_dbg_printk("method: %s", trace->method);
     bpf_dbg_printk("path: %s", trace->path);
 
+    // submit the completed trace via ringbuffer
+    bpf_ringbuf_submit(trace, get_flags());
+
+done:
+    bpf_map_delete_elem(&go_ongoing_http_client_requests, g_key);
+    bpf_map_delete_elem(&ongoing_http_client_requests_data, g_key);
+    bpf_map_delete_elem(&ongoing_client_connections, g_key);
+}
+
+SEC("uprobe/roundTrip_return")
+int obi_uprobe_roundTripReturn(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/proc http roundTrip return === ");
+
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    off_table_t *ot = get_offsets_table();
+
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    // Get request/response struct
+    void *resp_ptr = (void *)GO_PARAM1(ctx);
+
+    u16 status = 0;
     u64 status_code_ptr_pos = go_offset_of(ot, (go_offset){.v = _status_code_ptr_pos});
-    bpf_probe_read(&trace->status, sizeof(trace->status), (void *)(resp_ptr + status_code_ptr_pos));
+    bpf_probe_read(&status, sizeof(status), (void *)(resp_ptr + status_code_ptr_pos));
 
-    bpf_dbg_printk(
-        "status %d, offset %d, resp_ptr %lx", trace->status, status_code_ptr_pos, (u64)resp_ptr);
+    bpf_dbg_printk("status %d, offset %d, resp_ptr %lx", status, status_code_ptr_pos, (u64)resp_ptr);
 
+    s64 response_length = 0;
     u64 response_length_ptr_pos = go_offset_of(ot, (go_offset){.v = _response_length_ptr_pos});
-    bpf_probe_read(&trace->response_length,
-                   sizeof(trace->response_length),
-                   (void *)(resp_ptr + response_length_ptr_pos));
+    bpf_probe_read(
+        &response_length, sizeof(response_length), (void *)(resp_ptr + response_length_ptr_pos));
 
     bpf_dbg_printk("response_length %llx, offset %llu, resp_ptr %llx",
-                   trace->response_length,
+                   response_length,
                    response_length_ptr_pos,
                    (u64)resp_ptr);
 
-    // submit the completed trace via ringbuffer
-    bpf_ringbuf_submit(trace, get_flags());
-
-done:
-    bpf_map_delete_elem(&go_ongoing_http_client_requests, &g_key);
-    bpf_map_delete_elem(&ongoing_http_client_requests_data, &g_key);
-    bpf_map_delete_elem(&ongoing_client_connections, &g_key);
+    submit_http_client_trace(&g_key, status, response_length);
     return 0;
 }
 
@@ -1232,6 +1250,13 @@ int obi_uprobe_netFdRead(struct pt_regs *ctx) {
         get_conn_info_from_fd(fd_ptr,
                               &sql_conn->conn); // ok to not check the result, we leave it as 0
     }
+    // lookup active mongo request
+    mongo_go_client_req_t *mongo_req = bpf_map_lookup_elem(&ongoing_mongo_requests, &g_key);
+    if (mongo_req && mongo_req->conn.d_port == 0 && mongo_req->conn.s_port == 0) {
+        void *fd_ptr = GO_PARAM1(ctx);
+        get_conn_info_from_fd(fd_ptr,
+                              &mongo_req->conn); // ok to not check the result, we leave it as 0
+    }
 
     return 0;
 }
@@ -1314,6 +1339,73 @@ int obi_uprobe_connServeRet(struct pt_regs *ctx) {
     return 0;
 }
 
+// Stores the connection of an ongoing client request, given the already unwrapped
+// net.Conn pointer, and sets up the black-box and TC context propagation for it.
+static __always_inline void setup_client_connection(go_addr_key_t *g_key,
+                                                    http_func_invocation_t *invocation,
+                                                    void *conn_conn_ptr,
+                                                    u8 is_tls) {
+    if (!conn_conn_ptr) {
+        return;
+    }
+
+    off_table_t *ot = get_offsets_table();
+
+    void *conn_ptr = 0;
+    bpf_probe_read(&conn_ptr,
+                   sizeof(conn_ptr),
+                   (void *)(conn_conn_ptr +
+                            go_offset_of(ot, (go_offset){.v = _net_conn_pos}))); // find conn
+    bpf_dbg_printk("conn_ptr %llx", conn_ptr);
+    if (!conn_ptr) {
+        return;
+    }
+
+    connection_info_t conn = {0};
+    get_conn_info(conn_ptr,
+                  &conn); // initialized to 0, no need to check the result if we succeeded
+    u64 pid_tid = bpf_get_current_pid_tgid();
+    u32 pid = pid_from_pid_tgid(pid_tid);
+    tp_info_pid_t tp_p = {
+        .pid = pid,
+        .valid = 1,
+        .written = 0,
+        .req_type = EVENT_HTTP_CLIENT,
+    };
+
+    tp_clone(&tp_p.tp, &invocation->tp);
+    tp_p.tp.ts = bpf_ktime_get_ns();
+    bpf_dbg_printk("storing trace_map info for black-box tracing");
+    bpf_map_update_elem(&ongoing_client_connections, g_key, &conn, BPF_ANY);
+
+    // Must sort the connection info, this map is shared with kprobes which use sorted connection
+    // info always.
+    sort_connection_info(&conn);
+    set_trace_info_for_connection(&conn, TRACE_TYPE_CLIENT, &tp_p);
+
+    // Setup information for the TC context propagation.
+    // We need the PID id to be able to query ongoing_http and update
+    // the span id with the SEQ/ACK pair.
+
+    egress_key_t e_key = {
+        .d_port = conn.d_port,
+        .s_port = conn.s_port,
+    };
+
+    if (is_tls) {
+        // Clone and mark it invalid for the purpose of storing it in the
+        // outgoing trace map, if it's an SSL connection
+        tp_info_pid_t tp_p_invalid = {0};
+        __builtin_memcpy(&tp_p_invalid, &tp_p, sizeof(tp_p));
+        tp_p_invalid.valid = 0;
+        bpf_map_update_elem(&outgoing_trace_map, &e_key, &tp_p_invalid, BPF_ANY);
+    } else {
+        bpf_map_update_elem(&outgoing_trace_map, &e_key, &tp_p, BPF_ANY);
+    }
+
+    bpf_map_update_elem(&go_ongoing_http, &e_key, g_key, BPF_ANY);
+}
+
 SEC("uprobe/persistConnRoundTrip")
 int obi_uprobe_persistConnRoundTrip(struct pt_regs *ctx) {
     bpf_dbg_printk("=== uprobe/proc http persistConn roundTrip === ");
@@ -1343,62 +1435,7 @@ int obi_uprobe_persistConnRoundTrip(struct pt_regs *ctx) {
         bpf_dbg_printk("conn_conn_ptr %llx, tls_state %llx", conn_conn_ptr, tls_state);
 
         conn_conn_ptr = unwrap_tls_conn_info(conn_conn_ptr, tls_state);
-
-        if (conn_conn_ptr) {
-            void *conn_ptr = 0;
-            bpf_probe_read(
-                &conn_ptr,
-                sizeof(conn_ptr),
-                (void *)(conn_conn_ptr +
-                         go_offset_of(ot, (go_offset){.v = _net_conn_pos}))); // find conn
-            bpf_dbg_printk("conn_ptr %llx", conn_ptr);
-            if (conn_ptr) {
-                connection_info_t conn = {0};
-                get_conn_info(
-                    conn_ptr,
-                    &conn); // initialized to 0, no need to check the result if we succeeded
-                u64 pid_tid = bpf_get_current_pid_tgid();
-                u32 pid = pid_from_pid_tgid(pid_tid);
-                tp_info_pid_t tp_p = {
-                    .pid = pid,
-                    .valid = 1,
-                    .written = 0,
-                    .req_type = EVENT_HTTP_CLIENT,
-                };
-
-                tp_clone(&tp_p.tp, &invocation->tp);
-                tp_p.tp.ts = bpf_ktime_get_ns();
-                bpf_dbg_printk("storing trace_map info for black-box tracing");
-                bpf_map_update_elem(&ongoing_client_connections, &g_key, &conn, BPF_ANY);
-
-                // Must sort the connection info, this map is shared with kprobes which use sorted connection
-                // info always.
-                sort_connection_info(&conn);
-                set_trace_info_for_connection(&conn, TRACE_TYPE_CLIENT, &tp_p);
-
-                // Setup information for the TC context propagation.
-                // We need the PID id to be able to query ongoing_http and update
-                // the span id with the SEQ/ACK pair.
-
-                egress_key_t e_key = {
-                    .d_port = conn.d_port,
-                    .s_port = conn.s_port,
-                };
-
-                if (tls_state) {
-                    // Clone and mark it invalid for the purpose of storing it in the
-                    // outgoing trace map, if it's an SSL connection
-                    tp_info_pid_t tp_p_invalid = {0};
-                    __builtin_memcpy(&tp_p_invalid, &tp_p, sizeof(tp_p));
-                    tp_p_invalid.valid = 0;
-                    bpf_map_update_elem(&outgoing_trace_map, &e_key, &tp_p_invalid, BPF_ANY);
-                } else {
-                    bpf_map_update_elem(&outgoing_trace_map, &e_key, &tp_p, BPF_ANY);
-                }
-
-                bpf_map_update_elem(&go_ongoing_http, &e_key, &g_key, BPF_ANY);
-            }
-        }
+        setup_client_connection(&g_key, invocation, conn_conn_ptr, tls_state != 0);
     }
 
     return 0;
@@ -58,6 +58,12 @@ typedef enum {
     _grpc_transport_buf_writer_conn_pos,
     // redis
     _redis_conn_bw_pos,
+    // pgx
+    _pgx_batch_queued_queries_pos,
+    _pgx_queued_query_sql_pos,
+    // mongo driver
+    _mongo_operation_database_pos,
+    _mongo_operation_name_pos,
     // kafka go
     _kafka_go_writer_topic_pos,
     _kafka_go_protocol_conn_pos,
@@ -80,6 +86,23 @@ typedef enum {
     _error_string_off,
     // go jsonrpc
     _jsonrpc_request_header_service_method_pos,
+    // fasthttp
+    _fasthttp_request_header_method_pos,
+    _fasthttp_request_header_request_uri_pos,
+    _fasthttp_request_header_content_length_pos,
+    _fasthttp_request_header_h_pos,
+    _fasthttp_response_header_status_code_pos,
+    _fasthttp_response_header_content_length_pos,
+    _fasthttp_request_header_pos,
+    _fasthttp_request_uri_pos,
+    _fasthttp_response_header_pos,
+    _fasthttp_uri_path_pos,
+    _fasthttp_uri_host_pos,
+    _fasthttp_uri_scheme_pos,
+    _fasthttp_host_client_is_tls_pos,
+    _fasthttp_client_conn_conn_pos,
+    // go runtime versioning
+    _go_stw_reason,
     _last_go_offset,
 } go_offset_const;
 
@@ -17,6 +17,8 @@
 
 #include <bpfcore/utils.h>
 
+#include <common/ringbuf.h>
+
 #include <gotracer/go_common.h>
 
 #include <logger/bpf_dbg.h>
@@ -112,3 +114,136 @@ int obi_uprobe_proc_goexit1(struct pt_regs *ctx) {
 
     return 0;
 }
+
+// Go runtime health events. The GC pause probes below are only attached when the Go runtime
+// instrumentation is enabled, and the scheduling ones when the Go scheduler instrumentation
+// is enabled.
+
+// Same sampling period as the Go runtime uses for its own scheduling latency
+// histogram (gTrackingPeriod), to bound the number of reported events. It doesn't bound
+// the cost of the uprobes, which trigger for every goroutine status change and execution.
+#define SCHED_LATENCY_SAMPLE_PERIOD 8
+
+// Values of runtime.stwReason (Go 1.21+) for the pauses started by the GC
+enum {
+    k_stw_gc_mark_term = 1,
+    k_stw_gc_sweep_term = 2,
+};
+
+// Value of the runtime._Grunnable goroutine status
+enum { k_g_runnable = 1 };
+
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, u32);   // key: pid of the stopped process
+    __type(value, u64); // timestamp of the stop the world start
+    __uint(max_entries, MAX_GO_PROGRAMS);
+} ongoing_stw_pauses SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, go_addr_key_t); // key: pointer to the runnable goroutine
+    __type(value, u64);         // timestamp of the goroutine becoming runnable
+    __uint(max_entries, MAX_CONCURRENT_REQUESTS);
+} runnable_goroutines SEC(".maps");
+
+static __always_inline void submit_go_runtime_event(u8 kind, u64 start_monotime_ns) {
+    go_runtime_event_t *event = bpf_ringbuf_reserve(&events, sizeof(go_runtime_event_t), 0);
+    if (!event) {
+        bpf_dbg_printk("can't reserve space in the ringbuffer");
+        return;
+    }
+
+    event->type = EVENT_GO_RUNTIME;
+    event->kind = kind;
+    event->start_monotime_ns = start_monotime_ns;
+    event->end_monotime_ns = bpf_ktime_get_ns();
+    task_pid(&event->pid);
+
+    bpf_ringbuf_submit(event, get_flags());
+}
+
+// func stopTheWorldWithSema(reason stwReason) worldStop
+SEC("uprobe/runtime_stopTheWorldWithSema")
+int obi_uprobe_runtime_stopTheWorldWithSema(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/runtime stopTheWorldWithSema === ");
+
+    // Before Go 1.21 the reason is unknown, and all the pauses are accounted
+    off_table_t *ot = get_offsets_table();
+    if (go_offset_of(ot, (go_offset){.v = _go_stw_reason}) == 1) {
+        const u8 reason = (u8)(u64)GO_PARAM1(ctx);
+        bpf_dbg_printk("stop the world reason %d", reason);
+        if (reason != k_stw_gc_mark_term && reason != k_stw_gc_sweep_term) {
+            return 0;
+        }
+    }
+
+    const u32 pid = pid_from_pid_tgid(bpf_get_current_pid_tgid());
+    const u64 start = bpf_ktime_get_ns();
+    bpf_map_update_elem(&ongoing_stw_pauses, &pid, &start, BPF_ANY);
+
+    return 0;
+}
+
+// func startTheWorldWithSema(now int64, w worldStop) int64
+SEC("uprobe/runtime_startTheWorldWithSema_return")
+int obi_uprobe_runtime_startTheWorldWithSemaReturn(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/runtime startTheWorldWithSema returns === ");
+
+    const u32 pid = pid_from_pid_tgid(bpf_get_current_pid_tgid());
+    u64 *start = bpf_map_lookup_elem(&ongoing_stw_pauses, &pid);
+    if (!start) {
+        return 0;
+    }
+
+    submit_go_runtime_event(k_go_runtime_gc_pause, *start);
+    bpf_map_delete_elem(&ongoing_stw_pauses, &pid);
+
+    return 0;
+}
+
+static __always_inline void track_runnable_goroutine(void *gp) {
+    if (bpf_get_prandom_u32() % SCHED_LATENCY_SAMPLE_PERIOD) {
+        return;
+    }
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, gp);
+
+    const u64 runnable = bpf_ktime_get_ns();
+    bpf_map_update_elem(&runnable_goroutines, &g_key, &runnable, BPF_ANY);
+}
+
+// func casgstatus(gp *g, oldval, newval uint32)
+// All the goroutines that become runnable change their status here. Hooking runqput and
+// globrunqput would miss the goroutines readied by the netpoller, as injectglist enqueues
+// them in batches (runqputbatch, globrunqputbatch), and globrunqput is often inlined.
+SEC("uprobe/runtime_casgstatus")
+int obi_uprobe_runtime_casgstatus(struct pt_regs *ctx) {
+    if ((u32)(u64)GO_PARAM3(ctx) != k_g_runnable) {
+        return 0;
+    }
+    track_runnable_goroutine(GO_PARAM1(ctx));
+    return 0;
+}
+
+// func execute(gp *g, inheritTime bool)
+SEC("uprobe/runtime_execute")
+int obi_uprobe_runtime_execute(struct pt_regs *ctx) {
+    void *gp = GO_PARAM1(ctx);
+
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, gp);
+
+    u64 *runnable = bpf_map_lookup_elem(&runnable_goroutines, &g_key);
+    if (!runnable) {
+        return 0;
+    }
+
+    bpf_dbg_printk("=== uprobe/runtime execute === goroutine_addr %lx", gp);
+
+    submit_go_runtime_event(k_go_runtime_sched_latency, *runnable);
+    bpf_map_delete_elem(&runnable_goroutines, &g_key);
+
+    return 0;
+}
@@ -26,12 +26,14 @@
 
 #include <gotracer/go_common.h>
 
-static __always_inline void set_sql_info(void *goroutine_addr, void *sql_param, void *query_len) {
+static __always_inline void
+set_sql_info(void *goroutine_addr, void *sql_param, void *query_len, u8 kind) {
     sql_func_invocation_t invocation = {.start_monotime_ns = bpf_ktime_get_ns(),
                                         .sql_param = (u64)sql_param,
                                         .query_len = (u64)query_len,
                                         .conn = {0},
-                                        .tp = {0}};
+                                        .tp = {0},
+                                        .kind = kind};
 
     client_trace_parent(goroutine_addr, &invocation.tp);
     go_addr_key_t g_key = {};
@@ -52,7 +54,7 @@ int obi_uprobe_queryDC(struct pt_regs *ctx) {
     void *sql_param = GO_PARAM8(ctx);
     void *query_len = GO_PARAM9(ctx);
 
-    set_sql_info(goroutine_addr, sql_param, query_len);
+    set_sql_info(goroutine_addr, sql_param, query_len, k_sql_kind_unknown);
     return 0;
 }
 
@@ -64,35 +66,20 @@ int obi_uprobe_execDC(struct pt_regs *ctx) {
 
     void *sql_param = GO_PARAM6(ctx);
     void *query_len = GO_PARAM7(ctx);
-    set_sql_info(goroutine_addr, sql_param, query_len);
+    set_sql_info(goroutine_addr, sql_param, query_len, k_sql_kind_unknown);
     return 0;
 }
 
-SEC("uprobe/queryDC")
-int obi_uprobe_queryReturn(struct pt_regs *ctx) {
-
-    bpf_dbg_printk("=== uprobe/query return === ");
-    void *goroutine_addr = GOROUTINE_PTR(ctx);
-    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
-    go_addr_key_t g_key = {};
-    go_addr_key_from_id(&g_key, goroutine_addr);
-
-    sql_func_invocation_t *invocation = bpf_map_lookup_elem(&ongoing_sql_queries, &g_key);
-    if (invocation == NULL) {
-        bpf_dbg_printk("Request not found for this goroutine");
-        return 0;
-    }
-    bpf_map_delete_elem(&ongoing_sql_queries, &g_key);
-
+static __always_inline void
+submit_sql_trace(go_addr_key_t *g_key, sql_func_invocation_t *invocation, u16 status) {
     sql_request_trace *trace = bpf_ringbuf_reserve(&events, sizeof(sql_request_trace), 0);
     if (trace) {
         task_pid(&trace->pid);
         trace->type = EVENT_SQL_CLIENT;
+        trace->kind = invocation->kind;
         trace->start_monotime_ns = invocation->start_monotime_ns;
         trace->end_monotime_ns = bpf_ktime_get_ns();
-
-        void *resp_ptr = GO_PARAM1(ctx);
-        trace->status = (resp_ptr == NULL);
+        trace->status = status;
         trace->tp = invocation->tp;
 
         u64 query_len = invocation->query_len;
@@ -115,5 +102,128 @@ int obi_uprobe_queryReturn(struct pt_regs *ctx) {
     } else {
         bpf_dbg_printk("can't reserve space in the ringbuffer");
     }
+    bpf_map_delete_elem(&ongoing_sql_queries, g_key);
+}
+
+SEC("uprobe/queryDC")
+int obi_uprobe_queryReturn(struct pt_regs *ctx) {
+
+    bpf_dbg_printk("=== uprobe/query return === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    sql_func_invocation_t *invocation = bpf_map_lookup_elem(&ongoing_sql_queries, &g_key);
+    if (invocation == NULL) {
+        bpf_dbg_printk("Request not found for this goroutine");
+        return 0;
+    }
+
+    void *resp_ptr = GO_PARAM1(ctx);
+    submit_sql_trace(&g_key, invocation, resp_ptr == NULL);
+    return 0;
+}
+
+// pgx is also used as a database/sql driver through its stdlib package. In that case,
+// the query is already tracked by the database/sql probes, so it's not overridden.
+static __always_inline void set_pgx_info(void *goroutine_addr, void *sql_param, void *query_len) {
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    if (bpf_map_lookup_elem(&ongoing_sql_queries, &g_key)) {
+        bpf_dbg_printk("query already tracked by database/sql");
+        return;
+    }
+
+    set_sql_info(goroutine_addr, sql_param, query_len, k_sql_kind_postgres);
+}
+
+// func (c *Conn) Query(ctx context.Context, sql string, args ...any) (Rows, error)
+// func (c *Conn) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+SEC("uprobe/pgxConnQuery")
+int obi_uprobe_pgxConnQuery(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/pgx Conn Query/Exec === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    void *sql_param = GO_PARAM4(ctx);
+    void *query_len = GO_PARAM5(ctx);
+
+    set_pgx_info(goroutine_addr, sql_param, query_len);
+    return 0;
+}
+
+// func (c *Conn) SendBatch(ctx context.Context, b *Batch) (br BatchResults)
+// The span reports the statement of the first query in the batch.
+SEC("uprobe/pgxConnSendBatch")
+int obi_uprobe_pgxConnSendBatch(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/pgx Conn SendBatch === ");
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+
+    void *batch_ptr = GO_PARAM4(ctx);
+    if (!batch_ptr) {
+        return 0;
+    }
+
+    off_table_t *ot = get_offsets_table();
+
+    void **queued_queries = 0;
+    u64 queued_len = 0;
+    u64 queued_pos = go_offset_of(ot, (go_offset){.v = _pgx_batch_queued_queries_pos});
+    bpf_probe_read(&queued_queries, sizeof(queued_queries), batch_ptr + queued_pos);
+    bpf_probe_read(&queued_len, sizeof(queued_len), batch_ptr + queued_pos + 8);
+    bpf_dbg_printk("queued_queries %llx, len %d", queued_queries, queued_len);
+    if (!queued_queries || !queued_len) {
+        return 0;
+    }
+
+    void *first_query = 0;
+    bpf_probe_read(&first_query, sizeof(first_query), queued_queries);
+    if (!first_query) {
+        return 0;
+    }
+
+    void *sql_param = 0;
+    void *query_len = 0;
+    u64 sql_pos = go_offset_of(ot, (go_offset){.v = _pgx_queued_query_sql_pos});
+    bpf_probe_read(&sql_param, sizeof(sql_param), first_query + sql_pos);
+    bpf_probe_read(&query_len, sizeof(query_len), first_query + sql_pos + 8);
+
+    set_pgx_info(goroutine_addr, sql_param, query_len);
+    return 0;
+}
+
+static __always_inline void pgx_query_return(struct pt_regs *ctx, u16 status) {
+    void *goroutine_addr = GOROUTINE_PTR(ctx);
+    bpf_dbg_printk("goroutine_addr %lx", goroutine_addr);
+    go_addr_key_t g_key = {};
+    go_addr_key_from_id(&g_key, goroutine_addr);
+
+    sql_func_invocation_t *invocation = bpf_map_lookup_elem(&ongoing_sql_queries, &g_key);
+    // the query might be tracked by the database/sql probes, which will submit it
+    if (invocation == NULL || invocation->kind != k_sql_kind_postgres) {
+        bpf_dbg_printk("pgx request not found for this goroutine");
+        return;
+    }
+
+    submit_sql_trace(&g_key, invocation, status);
+}
+
+SEC("uprobe/pgxConnQuery")
+int obi_uprobe_pgxConnQueryReturn(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/pgx Conn Query/Exec return === ");
+    // Query and Exec return a two-words value, followed by the error interface
+    void *err_ptr = GO_PARAM3(ctx);
+    pgx_query_return(ctx, err_ptr != NULL);
+    return 0;
+}
+
+SEC("uprobe/pgxConnSendBatch")
+int obi_uprobe_pgxConnSendBatchReturn(struct pt_regs *ctx) {
+    bpf_dbg_printk("=== uprobe/pgx Conn SendBatch return === ");
+    // SendBatch errors are deferred to the returned BatchResults
+    pgx_query_return(ctx, 0);
     return 0;
 }
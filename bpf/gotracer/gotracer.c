@@ -18,9 +18,12 @@
 
 #include "go_runtime.c"
 #include "go_nethttp.c"
+#include "go_fasthttp.c"
 #include "go_sql.c"
 #include "go_grpc.c"
 #include "go_redis.c"
+#include "go_mongo.c"
+#include "go_aws.c"
 #include "go_kafka_go.c"
 #include "go_sarama.c"
 #include "go_sdk.c"
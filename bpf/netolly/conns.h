@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#pragma once
+
+#include <bpfcore/vmlinux.h>
+#include <bpfcore/bpf_helpers.h>
+#include <bpfcore/bpf_core_read.h>
+
+#include <common/protocol_defs.h>
+
+#include <netolly/flows_common.h>
+
+// connection_start is stored for each established connection, until it is closed
+typedef struct connection_start_t {
+    u64 mono_time_ns;
+    u8 initiator;
+    u8 _pad[7];
+} connection_start;
+
+// Key: the address of the socket of an established TCP connection.
+// Value: when and by whom the connection was established.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, u64);
+    __type(value, connection_start);
+    __uint(max_entries, 1 << 16);
+} conn_starts SEC(".maps");
+
+// Key: the connection identifier, from the local to the remote endpoint. Value: the lifecycle
+// metrics of the connections with that identifier. The userspace will aggregate them.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_PERCPU_HASH);
+    __type(key, flow_id);
+    __type(value, conn_metrics);
+} conn_stats SEC(".maps");
+
+static __always_inline void fill_conn_id(struct trace_event_raw_inet_sock_set_state *args,
+                                         flow_id *id) {
+    id->transport_protocol = IPPROTO_TCP;
+    id->src_port = args->sport;
+    id->dst_port = args->dport;
+    if (args->family == AF_INET) {
+        id->eth_protocol = ETH_P_IP;
+        __builtin_memcpy(id->src_ip.s6_addr, ip4in6, sizeof(ip4in6));
+        __builtin_memcpy(id->dst_ip.s6_addr, ip4in6, sizeof(ip4in6));
+        __builtin_memcpy(id->src_ip.s6_addr + sizeof(ip4in6), args->saddr, sizeof(args->saddr));
+        __builtin_memcpy(id->dst_ip.s6_addr + sizeof(ip4in6), args->daddr, sizeof(args->daddr));
+    } else {
+        id->eth_protocol = ETH_P_IPV6;
+        __builtin_memcpy(id->src_ip.s6_addr, args->saddr_v6, IP_MAX_LEN);
+        __builtin_memcpy(id->dst_ip.s6_addr, args->daddr_v6, IP_MAX_LEN);
+    }
+}
+
+// tracks the TCP state transitions that open, close or fail a connection:
+// - SYN_SENT -> ESTABLISHED: connect succeeded
+// - SYN_RECV -> ESTABLISHED: connection accepted
+// - SYN_SENT -> CLOSE: connect failed (refused, timed out, unreachable...)
+// - any other state -> CLOSE: an established connection is closed
+SEC("tracepoint/sock/inet_sock_set_state")
+int obi_inet_sock_set_state(struct trace_event_raw_inet_sock_set_state *args) {
+    if (args->protocol != IPPROTO_TCP ||
+        (args->family != AF_INET && args->family != AF_INET6)) {
+        return 0;
+    }
+    const int oldstate = args->oldstate;
+    const int newstate = args->newstate;
+    u64 skaddr = (u64)args->skaddr;
+    u64 now = bpf_ktime_get_ns();
+
+    conn_metrics event = {
+        .start_mono_time_ns = now,
+        .end_mono_time_ns = now,
+    };
+    if (newstate == TCP_ESTABLISHED && (oldstate == TCP_SYN_SENT || oldstate == TCP_SYN_RECV)) {
+        connection_start start = {
+            .mono_time_ns = now,
+            .initiator = oldstate == TCP_SYN_SENT ? INITIATOR_SRC : INITIATOR_DST,
+        };
+        bpf_map_update_elem(&conn_starts, &skaddr, &start, BPF_ANY);
+        event.opened = 1;
+        event.initiator = start.initiator;
+    } else if (newstate == TCP_CLOSE && oldstate == TCP_SYN_SENT) {
+        struct sock *sk = (struct sock *)args->skaddr;
+        event.failed = 1;
+        event.initiator = INITIATOR_SRC;
+        event.errno = (u8)BPF_CORE_READ(sk, sk_err);
+    } else if (newstate == TCP_CLOSE) {
+        connection_start *start = (connection_start *)bpf_map_lookup_elem(&conn_starts, &skaddr);
+        // connections established before the tracer was started are ignored
+        if (!start) {
+            return 0;
+        }
+        event.closed = 1;
+        event.initiator = start->initiator;
+        event.duration_ns = now - start->mono_time_ns;
+        bpf_map_delete_elem(&conn_starts, &skaddr);
+    } else {
+        return 0;
+    }
+
+    flow_id id = {0};
+    fill_conn_id(args, &id);
+
+    conn_metrics *stats = (conn_metrics *)bpf_map_lookup_elem(&conn_stats, &id);
+    if (stats) {
+        stats->end_mono_time_ns = now;
+        stats->duration_ns += event.duration_ns;
+        stats->opened += event.opened;
+        stats->failed += event.failed;
+        stats->closed += event.closed;
+        stats->initiator = event.initiator;
+        if (event.failed) {
+            stats->errno = event.errno;
+        }
+        return 0;
+    }
+    // errors are intentionally omitted. A full map would only discard the event
+    bpf_map_update_elem(&conn_stats, &id, &event, BPF_NOEXIST);
+    return 0;
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#pragma once
+
+#include <bpfcore/vmlinux.h>
+#include <bpfcore/bpf_helpers.h>
+#include <bpfcore/bpf_core_read.h>
+#include <bpfcore/bpf_endian.h>
+
+#include <common/protocol_defs.h>
+
+#include <netolly/flows_common.h>
+
+// Constant definition, to be overridden by the invoker.
+// Value of SKB_CONSUMED in the kernel enum skb_drop_reason. Newer kernels report through
+// kfree_skb some packets that have been consumed instead of dropped, so they are ignored.
+// The default value never matches any drop reason, as older kernels don't define it.
+volatile const u32 skb_consumed_reason = 0xFFFFFFFF;
+
+// Key: the dropped flow identifier and the drop reason. Value: the dropped packets
+// for that key. The userspace will aggregate them.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_PERCPU_HASH);
+    __type(key, drop_id);
+    __type(value, drop_metrics);
+} drop_stats SEC(".maps");
+
+// the source and destination ports are the first fields of the TCP, UDP and SCTP headers
+struct __l4ports {
+    __be16 source;
+    __be16 dest;
+};
+
+static __always_inline void read_drop_ports(void *l4, flow_id *id) {
+    switch (id->transport_protocol) {
+    case IPPROTO_TCP:
+    case IPPROTO_UDP:
+    case IPPROTO_SCTP: {
+        struct __l4ports ports;
+        if (bpf_probe_read_kernel(&ports, sizeof(ports), l4) == 0) {
+            id->src_port = bpf_ntohs(ports.source);
+            id->dst_port = bpf_ntohs(ports.dest);
+        }
+    } break;
+    default:
+        break;
+    }
+}
+
+// fills the flow identifier from the network headers of the dropped socket buffer.
+// Returns DISCARD if the packet is not IPv4 or IPv6
+static __always_inline int fill_drop_id(struct sk_buff *skb, flow_id *id) {
+    unsigned char *head = BPF_CORE_READ(skb, head);
+    void *l3 = head + BPF_CORE_READ(skb, network_header);
+
+    id->if_index = BPF_CORE_READ(skb, dev, ifindex);
+    if (id->if_index == 0) {
+        id->if_index = BPF_CORE_READ(skb, skb_iif);
+    }
+    id->eth_protocol = bpf_ntohs(BPF_CORE_READ(skb, protocol));
+    switch (id->eth_protocol) {
+    case ETH_P_IP: {
+        struct iphdr ip;
+        if (bpf_probe_read_kernel(&ip, sizeof(ip), l3)) {
+            return DISCARD;
+        }
+        __builtin_memcpy(id->src_ip.s6_addr, ip4in6, sizeof(ip4in6));
+        __builtin_memcpy(id->dst_ip.s6_addr, ip4in6, sizeof(ip4in6));
+        __builtin_memcpy(id->src_ip.s6_addr + sizeof(ip4in6), &ip.saddr, sizeof(ip.saddr));
+        __builtin_memcpy(id->dst_ip.s6_addr + sizeof(ip4in6), &ip.daddr, sizeof(ip.daddr));
+        id->transport_protocol = ip.protocol;
+        read_drop_ports(l3 + ip.ihl * 4, id);
+    } break;
+    case ETH_P_IPV6: {
+        struct ipv6hdr ip;
+        if (bpf_probe_read_kernel(&ip, sizeof(ip), l3)) {
+            return DISCARD;
+        }
+        __builtin_memcpy(id->src_ip.s6_addr, ip.saddr.in6_u.u6_addr8, IP_MAX_LEN);
+        __builtin_memcpy(id->dst_ip.s6_addr, ip.daddr.in6_u.u6_addr8, IP_MAX_LEN);
+        id->transport_protocol = ip.nexthdr;
+        // IPv6 extension headers are not followed, so their flows won't report ports
+        read_drop_ports(l3 + sizeof(ip), id);
+    } break;
+    default:
+        return DISCARD;
+    }
+    return SUBMIT;
+}
+
+// accounts the packets that are freed by the kernel because they have been dropped
+SEC("tracepoint/skb/kfree_skb")
+int obi_kfree_skb(struct trace_event_raw_kfree_skb *args) {
+    u32 reason = 0;
+    // kernels older than 5.17 don't report the drop reason, so they are reported
+    // as SKB_DROP_REASON_NOT_SPECIFIED
+    if (bpf_core_field_exists(args->reason)) {
+        reason = BPF_CORE_READ(args, reason);
+    }
+    if (reason == skb_consumed_reason) {
+        return 0;
+    }
+
+    struct sk_buff *skb = (struct sk_buff *)args->skbaddr;
+    drop_id key = {.reason = reason};
+    if (fill_drop_id(skb, &key.id) == DISCARD) {
+        return 0;
+    }
+
+    u64 now = bpf_ktime_get_ns();
+    u32 len = BPF_CORE_READ(skb, len);
+    drop_metrics *stats = (drop_metrics *)bpf_map_lookup_elem(&drop_stats, &key);
+    if (stats) {
+        stats->end_mono_time_ns = now;
+        stats->packets += 1;
+        stats->bytes += len;
+        return 0;
+    }
+    drop_metrics new_stats = {
+        .start_mono_time_ns = now,
+        .end_mono_time_ns = now,
+        .bytes = len,
+        .packets = 1,
+    };
+    // errors are intentionally omitted. A full map would only discard the event
+    bpf_map_update_elem(&drop_stats, &key, &new_stats, BPF_NOEXIST);
+    return 0;
+}
@@ -48,8 +48,17 @@ typedef struct flow_metrics_t {
 
     u32 packets;
 
+    // TCP health metrics. Only set for TCP flows
+    // latest smoothed round-trip time of the flow's socket, in microseconds.
+    // 0 if unknown (e.g. the socket is not local or the packet was captured at ingress)
+    u32 srtt_us;
+    // retransmitted segments of the flow's socket during the flow lifetime
+    u32 retransmits;
+
     // TCP Flags from https://www.ietf.org/rfc/rfc793.txt
     u16 flags;
+    // number of packets with the TCP RST flag
+    u16 resets;
     // direction of the flow EGRESS / INGRESS
     u8 iface_direction;
     // who initiated of the connection: INITIATOR_SRC or INITIATOR_DST
@@ -60,7 +69,7 @@ typedef struct flow_metrics_t {
     // https://chromium.googlesource.com/chromiumos/docs/+/master/constants/errnos.md
     u8 errno;
 
-    u8 _pad[7];
+    u8 _pad[5];
 } flow_metrics;
 
 // Attributes that uniquely identify a flow
@@ -83,6 +92,61 @@ typedef struct flow_id_t {
     u8 _pad[1];
 } flow_id;
 
+// Lifecycle metrics of the TCP connections that share the same flow_id, where the source is
+// the local endpoint and the destination is the remote endpoint of the connection.
+typedef struct conn_metrics_t {
+    // monotonic timestamps of the first and last lifecycle events accounted in the metrics
+    u64 start_mono_time_ns;
+    u64 end_mono_time_ns;
+    // accumulated lifetime of the closed connections, in nanoseconds
+    u64 duration_ns;
+
+    // connections that have been established, either by connect or accept
+    u32 opened;
+    // connection attempts that didn't reach the established state
+    u32 failed;
+    // established connections that have been closed
+    u32 closed;
+
+    // who initiated the connections: INITIATOR_SRC (connect) or INITIATOR_DST (accept)
+    u8 initiator;
+    // The positive errno of the last failed connection attempt (e.g. ECONNREFUSED, ETIMEDOUT).
+    // 0 otherwise
+    u8 errno;
+
+    u8 _pad[2];
+} conn_metrics;
+
+// maximum number of payload bytes that are sampled from each flow
+#define FLOW_PAYLOAD_SAMPLE_LEN 256
+
+// First application-layer payload bytes of a flow, which are used by the user space to
+// classify the L7 protocol of the flow (HTTP, gRPC, Kafka, Postgres...)
+typedef struct flow_payload_t {
+    u8 buf[FLOW_PAYLOAD_SAMPLE_LEN];
+    // number of valid bytes in buf
+    u16 len;
+    u8 _pad[6];
+} flow_payload;
+
+// Identifies the packets dropped by the kernel for the same flow and drop reason
+typedef struct drop_id_t {
+    flow_id id;
+    // value of the kernel enum skb_drop_reason. Its numeric values depend on the kernel version,
+    // so it's translated to a name in the user space
+    u32 reason;
+} drop_id;
+
+// Packets dropped by the kernel, as reported by the skb:kfree_skb tracepoint
+typedef struct drop_metrics_t {
+    // monotonic timestamps of the first and last dropped packets accounted in the metrics
+    u64 start_mono_time_ns;
+    u64 end_mono_time_ns;
+    u64 bytes;
+    u32 packets;
+    u8 _pad[4];
+} drop_metrics;
+
 // Flow record is a tuple containing both flow identifier and metrics. It is used to send
 // a complete flow via ring buffer when only when the accounting hashmap is full.
 // Contents in this struct must match byte-by-byte with Go's pkc/flow/Record struct
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#pragma once
+
+#include <bpfcore/vmlinux.h>
+#include <bpfcore/bpf_helpers.h>
+#include <bpfcore/utils.h>
+
+#include <common/scratch_mem.h>
+
+#include <netolly/flow.h>
+#include <netolly/flows_common.h>
+
+// Constant definition, to be overridden by the invoker.
+// If set, the first payload bytes of each flow are sampled for the L7 protocol classification.
+volatile const u8 sample_payloads = 0;
+
+// Key: the flow identifier. Value: the first payload bytes of the flow that
+// have been observed since the last eviction of the map from the user space.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, flow_id);
+    __type(value, flow_payload);
+} flow_payloads SEC(".maps");
+
+// Key: the flow identifier. Value: unused.
+// Flows whose payload has been already sampled. It outlives the eviction of the
+// flow_payloads entries, so the mid-stream packets of long-lived flows aren't sampled
+// again and misclassified.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, flow_id);
+    __type(value, u8);
+} classified_flows SEC(".maps");
+
+// the payload sample is too big for the stack
+SCRATCH_MEM(flow_payload)
+
+// stores the payload of the packet, starting at payload_off, if the flow
+// hasn't been already sampled. A zero payload_off means that the packet
+// payload couldn't be located.
+// Only the first payload of a flow is sampled. For TCP, the connection handshake
+// must have been observed, so the sample is the start of the application protocol.
+static __always_inline void sample_flow_payload(struct __sk_buff *skb,
+                                                flow_id *id,
+                                                u16 flags,
+                                                u32 payload_off) {
+    if (!sample_payloads || payload_off == 0 || payload_off >= skb->len) {
+        return;
+    }
+    if (bpf_map_lookup_elem(&classified_flows, id)) {
+        return;
+    }
+    // flow_directions only stores the flows whose SYN or SYN/ACK was observed
+    if (id->transport_protocol == IPPROTO_TCP && !(flags & SYN_FLAG) &&
+        !bpf_map_lookup_elem(&flow_directions, id)) {
+        return;
+    }
+    flow_payload *payload = (flow_payload *)flow_payload_mem();
+    if (!payload) {
+        return;
+    }
+    u32 len = skb->len - payload_off;
+    bpf_clamp_umax(len, FLOW_PAYLOAD_SAMPLE_LEN);
+    // the verifier can't infer from the payload_off check above that len is positive,
+    // and bpf_skb_load_bytes rejects zero-sized reads
+    if (len == 0) {
+        return;
+    }
+    if (bpf_skb_load_bytes(skb, payload_off, payload->buf, len) != 0) {
+        return;
+    }
+    payload->len = len;
+    u8 classified = 1;
+    // errors are intentionally omitted. Another CPU might have sampled the flow concurrently
+    if (bpf_map_update_elem(&classified_flows, id, &classified, BPF_NOEXIST) == 0) {
+        bpf_map_update_elem(&flow_payloads, id, payload, BPF_NOEXIST);
+    }
+}
+
+// allows sampling again the payload of a closed connection, if its flow identifier is reused
+static __always_inline void forget_flow_payload(flow_id *id) {
+    bpf_map_delete_elem(&classified_flows, id);
+}
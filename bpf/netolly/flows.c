@@ -26,7 +26,57 @@
 
 #include <logger/bpf_dbg.h>
 
+#include <netolly/conns.h>
+#include <netolly/drops.h>
 #include <netolly/flows_common.h>
+#include <netolly/flow_payload.h>
+
+// Key: the cookie of a TCP socket. Value: the last observed number of retransmitted segments
+// of the socket, to account only the new retransmissions in each flow.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, u64);
+    __type(value, u32);
+    __uint(max_entries, 1 << 16);
+} tcp_retransmits SEC(".maps");
+
+// tcp_health holds the TCP health metrics of the socket that is associated to a packet
+typedef struct tcp_health_t {
+    u32 srtt_us;
+    u32 retransmits;
+} tcp_health;
+
+// reads the smoothed RTT and the new retransmissions from the local TCP socket of the packet,
+// if any. The socket is usually only available for egress packets.
+static __always_inline void read_tcp_health(struct __sk_buff *skb, tcp_health *health) {
+    struct bpf_sock *sk = skb->sk;
+    if (!sk) {
+        return;
+    }
+    sk = bpf_sk_fullsock(sk);
+    if (!sk) {
+        return;
+    }
+    struct bpf_tcp_sock *tp = bpf_tcp_sock(sk);
+    if (!tp) {
+        return;
+    }
+    // srtt_us is stored left-shifted by 3
+    health->srtt_us = tp->srtt_us >> 3;
+
+    u64 cookie = bpf_get_socket_cookie(skb);
+    u32 total_retrans = tp->total_retrans;
+    u32 *last_retrans = (u32 *)bpf_map_lookup_elem(&tcp_retransmits, &cookie);
+    if (last_retrans == NULL) {
+        // errors are intentionally omitted
+        bpf_map_update_elem(&tcp_retransmits, &cookie, &total_retrans, BPF_ANY);
+        return;
+    }
+    if (total_retrans > *last_retrans) {
+        health->retransmits = total_retrans - *last_retrans;
+        *last_retrans = total_retrans;
+    }
+}
 
 // sets the TCP header flags for connection information
 static inline void set_flags(struct tcphdr *th, u16 *flags) {
@@ -56,7 +106,9 @@ static inline void set_flags(struct tcphdr *th, u16 *flags) {
     }
 }
 // sets flow fields from IPv4 header information
-static inline int fill_iphdr(struct iphdr *ip, void *data_end, flow_id *id, u16 *flags) {
+// payload_off is set relative to the start of the IP header
+static inline int
+fill_iphdr(struct iphdr *ip, void *data_end, flow_id *id, u16 *flags, u32 *payload_off) {
     if ((void *)ip + sizeof(*ip) > data_end) {
         return DISCARD;
     }
@@ -75,6 +127,7 @@ static inline int fill_iphdr(struct iphdr *ip, void *data_end, flow_id *id, u16
             id->src_port = __bpf_ntohs(tcp->source);
             id->dst_port = __bpf_ntohs(tcp->dest);
             set_flags(tcp, flags);
+            *payload_off = sizeof(*ip) + tcp->doff * 4;
         }
     } break;
     case IPPROTO_UDP: {
@@ -82,6 +135,7 @@ static inline int fill_iphdr(struct iphdr *ip, void *data_end, flow_id *id, u16
         if ((void *)udp + sizeof(*udp) <= data_end) {
             id->src_port = __bpf_ntohs(udp->source);
             id->dst_port = __bpf_ntohs(udp->dest);
+            *payload_off = sizeof(*ip) + sizeof(*udp);
         }
     } break;
     default:
@@ -91,7 +145,9 @@ static inline int fill_iphdr(struct iphdr *ip, void *data_end, flow_id *id, u16
 }
 
 // sets flow fields from IPv6 header information
-static inline int fill_ip6hdr(struct ipv6hdr *ip, void *data_end, flow_id *id, u16 *flags) {
+// payload_off is set relative to the start of the IP header
+static inline int
+fill_ip6hdr(struct ipv6hdr *ip, void *data_end, flow_id *id, u16 *flags, u32 *payload_off) {
     if ((void *)ip + sizeof(*ip) > data_end) {
         return DISCARD;
     }
@@ -108,6 +164,7 @@ static inline int fill_ip6hdr(struct ipv6hdr *ip, void *data_end, flow_id *id, u
             id->src_port = __bpf_ntohs(tcp->source);
             id->dst_port = __bpf_ntohs(tcp->dest);
             set_flags(tcp, flags);
+            *payload_off = sizeof(*ip) + tcp->doff * 4;
         }
     } break;
     case IPPROTO_UDP: {
@@ -115,6 +172,7 @@ static inline int fill_ip6hdr(struct ipv6hdr *ip, void *data_end, flow_id *id, u
         if ((void *)udp + sizeof(*udp) <= data_end) {
             id->src_port = __bpf_ntohs(udp->source);
             id->dst_port = __bpf_ntohs(udp->dest);
+            *payload_off = sizeof(*ip) + sizeof(*udp);
         }
     } break;
     default:
@@ -123,7 +181,9 @@ static inline int fill_ip6hdr(struct ipv6hdr *ip, void *data_end, flow_id *id, u
     return SUBMIT;
 }
 // sets flow fields from Ethernet header information
-static inline int fill_ethhdr(struct ethhdr *eth, void *data_end, flow_id *id, u16 *flags) {
+// payload_off is set to the offset of the L4 payload in the packet, or 0 if it's unknown
+static inline int
+fill_ethhdr(struct ethhdr *eth, void *data_end, flow_id *id, u16 *flags, u32 *payload_off) {
     if ((void *)eth + sizeof(*eth) > data_end) {
         return DISCARD;
     }
@@ -132,10 +192,18 @@ static inline int fill_ethhdr(struct ethhdr *eth, void *data_end, flow_id *id, u
 
     if (id->eth_protocol == ETH_P_IP) {
         struct iphdr *ip = (struct iphdr *)((void *)eth + sizeof(*eth));
-        return fill_iphdr(ip, data_end, id, flags);
+        int ret = fill_iphdr(ip, data_end, id, flags, payload_off);
+        if (*payload_off != 0) {
+            *payload_off += sizeof(*eth);
+        }
+        return ret;
     } else if (id->eth_protocol == ETH_P_IPV6) {
         struct ipv6hdr *ip6 = (struct ipv6hdr *)((void *)eth + sizeof(*eth));
-        return fill_ip6hdr(ip6, data_end, id, flags);
+        int ret = fill_ip6hdr(ip6, data_end, id, flags, payload_off);
+        if (*payload_off != 0) {
+            *payload_off += sizeof(*eth);
+        }
+        return ret;
     } else {
         // TODO : Need to implement other specific ethertypes if needed
         // For now other parts of flow id remain zero
@@ -160,13 +228,22 @@ static inline int flow_monitor(struct __sk_buff *skb) {
     __builtin_memset(&id, 0, sizeof(id));
     struct ethhdr *eth = (struct ethhdr *)data;
     u16 flags = 0;
-    if (fill_ethhdr(eth, data_end, &id, &flags) == DISCARD) {
+    u32 payload_off = 0;
+    if (fill_ethhdr(eth, data_end, &id, &flags, &payload_off) == DISCARD) {
         return TC_ACT_UNSPEC;
     }
     id.if_index = skb->ifindex;
 
+    sample_flow_payload(skb, &id, flags, payload_off);
+
     u64 current_time = bpf_ktime_get_ns();
 
+    tcp_health health = {0};
+    if (id.transport_protocol == IPPROTO_TCP) {
+        read_tcp_health(skb, &health);
+    }
+    u16 resets = is_tcp_reset(flags);
+
     // TODO: we need to add spinlock here when we deprecate versions prior to 5.1, or provide
     // a spinlocked alternative version and use it selectively https://lwn.net/Articles/779120/
     flow_metrics *aggregate_flow = (flow_metrics *)bpf_map_lookup_elem(&aggregated_flows, &id);
@@ -180,6 +257,11 @@ static inline int flow_monitor(struct __sk_buff *skb) {
             aggregate_flow->start_mono_time_ns = current_time;
         }
         aggregate_flow->flags |= flags;
+        aggregate_flow->resets += resets;
+        aggregate_flow->retransmits += health.retransmits;
+        if (health.srtt_us != 0) {
+            aggregate_flow->srtt_us = health.srtt_us;
+        }
 
         long ret = bpf_map_update_elem(&aggregated_flows, &id, aggregate_flow, BPF_ANY);
         if (trace_messages && ret != 0) {
@@ -198,6 +280,9 @@ static inline int flow_monitor(struct __sk_buff *skb) {
             .start_mono_time_ns = current_time,
             .end_mono_time_ns = current_time,
             .flags = flags,
+            .resets = resets,
+            .srtt_us = health.srtt_us,
+            .retransmits = health.retransmits,
             .iface_direction = UNKNOWN,
             .initiator = INITIATOR_UNKNOWN,
         };
@@ -264,6 +349,7 @@ cleanup:
     // finally, when flow receives FIN or RST, clean flow_directions
     if (flags & FIN_FLAG || flags & RST_FLAG || flags & FIN_ACK_FLAG || flags & RST_ACK_FLAG) {
         bpf_map_delete_elem(&flow_directions, &id);
+        forget_flow_payload(&id);
     }
     return TC_ACT_UNSPEC;
 }
@@ -282,5 +368,9 @@ int obi_egress_flow_parse(struct __sk_buff *skb) {
 const flow_metrics *unused_flow_metrics __attribute__((unused));
 const flow_id *unused_flow_id __attribute__((unused));
 const flow_record *unused_flow_record __attribute__((unused));
+const conn_metrics *unused_conn_metrics __attribute__((unused));
+const drop_id *unused_drop_id __attribute__((unused));
+const drop_metrics *unused_drop_metrics __attribute__((unused));
+const flow_payload *unused_flow_payload __attribute__((unused));
 
 char _license[] SEC("license") = "GPL";
@@ -31,6 +31,11 @@
 #define FIN_ACK_FLAG 0x200
 #define RST_ACK_FLAG 0x400
 
+// returns whether the flags contain a TCP reset
+static __always_inline u8 is_tcp_reset(u16 flags) {
+    return (flags & (RST_FLAG | RST_ACK_FLAG)) != 0;
+}
+
 // In conn_initiator_key, which sorted ip:port initiated the connection
 #define INITIATOR_LOW 1
 #define INITIATOR_HIGH 2
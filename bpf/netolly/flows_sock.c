@@ -28,7 +28,10 @@
 
 #include <logger/bpf_dbg.h>
 
+#include <netolly/conns.h>
+#include <netolly/drops.h>
 #include <netolly/flows_common.h>
+#include <netolly/flow_payload.h>
 
 struct __tcphdr {
     __be16 source;
@@ -49,7 +52,9 @@ struct __udphdr {
     __sum16 check;
 };
 
-static __always_inline bool read_sk_buff(struct __sk_buff *skb, flow_id *id, u16 *custom_flags) {
+// payload_off is set to the offset of the L4 payload in the packet
+static __always_inline bool
+read_sk_buff(struct __sk_buff *skb, flow_id *id, u16 *custom_flags, u32 *payload_off) {
     // we read the protocol just like here linux/samples/bpf/parse_ldabs.c
     u16 h_proto;
     bpf_skb_load_bytes(skb, offsetof(struct ethhdr, h_proto), &h_proto, sizeof(h_proto));
@@ -156,11 +161,13 @@ static __always_inline bool read_sk_buff(struct __sk_buff *skb, flow_id *id, u16
         id->src_port = __bpf_htons(port);
         bpf_skb_load_bytes(skb, hdr_len + offsetof(struct __udphdr, dest), &port, sizeof(port));
         id->dst_port = __bpf_htons(port);
+        hdr_len += sizeof(struct __udphdr);
         break;
     }
     default:
         return false;
     }
+    *payload_off = hdr_len;
 
     // custom flags
     if ((*custom_flags & (TCPHDR_ACK | TCPHDR_SYN))) {
@@ -194,7 +201,8 @@ int obi_socket__filter(struct __sk_buff *skb) {
     u16 flags = 0;
     flow_id id;
     __builtin_memset(&id, 0, sizeof(id));
-    if (!read_sk_buff(skb, &id, &flags)) {
+    u32 payload_off = 0;
+    if (!read_sk_buff(skb, &id, &flags, &payload_off)) {
         return TC_ACT_UNSPEC;
     }
 
@@ -203,6 +211,8 @@ int obi_socket__filter(struct __sk_buff *skb) {
         return TC_ACT_UNSPEC;
     }
 
+    sample_flow_payload(skb, &id, flags, payload_off);
+
     u64 current_time = bpf_ktime_get_ns();
 
     // TODO: we need to add spinlock here when we deprecate versions prior to 5.1, or provide
@@ -218,6 +228,7 @@ int obi_socket__filter(struct __sk_buff *skb) {
             aggregate_flow->start_mono_time_ns = current_time;
         }
         aggregate_flow->flags |= flags;
+        aggregate_flow->resets += is_tcp_reset(flags);
 
         long ret = bpf_map_update_elem(&aggregated_flows, &id, aggregate_flow, BPF_ANY);
         if (trace_messages && ret != 0) {
@@ -236,6 +247,7 @@ int obi_socket__filter(struct __sk_buff *skb) {
             .start_mono_time_ns = current_time,
             .end_mono_time_ns = current_time,
             .flags = flags,
+            .resets = is_tcp_reset(flags),
             .iface_direction = UNKNOWN,
         };
 
@@ -301,6 +313,7 @@ cleanup:
     // finally, when flow receives FIN or RST, clean flow_directions
     if (flags & FIN_FLAG || flags & RST_FLAG) {
         bpf_map_delete_elem(&flow_directions, &id);
+        forget_flow_payload(&id);
     }
     return TC_ACT_UNSPEC;
 }
@@ -309,5 +322,9 @@ cleanup:
 const flow_metrics *unused_flow_metrics __attribute__((unused));
 const flow_id *unused_flow_id __attribute__((unused));
 const flow_record *unused_flow_record __attribute__((unused));
+const conn_metrics *unused_conn_metrics __attribute__((unused));
+const drop_id *unused_drop_id __attribute__((unused));
+const drop_metrics *unused_drop_metrics __attribute__((unused));
+const flow_payload *unused_flow_payload __attribute__((unused));
 
 char _license[] SEC("license") = "GPL";
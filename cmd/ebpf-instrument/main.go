@@ -88,6 +88,9 @@ func main() {
 	// child process isn't found.
 	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	// the instrumenter reloads the configuration from this file, if enabled
+	config.ConfigReload.Path = *configPath
+
 	if err := instrumenter.Run(ctx, config); err != nil {
 		slog.Error("OpenTelemetry eBPF Instrumentation ran with errors", "error", err)
 		os.Exit(-1)
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func RequestHandler(log *slog.Logger, client *fasthttp.Client) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		log.Debug("received request", "url", string(ctx.RequestURI()))
+
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(resp)
+
+		req.SetRequestURI("https://opentelemetry.io")
+		req.Header.SetMethod(fasthttp.MethodGet)
+		if err := client.Do(req, resp); err != nil {
+			log.Debug("client request failed", "error", err)
+		}
+
+		status := fasthttp.StatusOK
+		args := ctx.QueryArgs()
+		if v := args.Peek("status"); len(v) > 0 {
+			if s, err := strconv.Atoi(string(v)); err != nil {
+				log.Debug("wrong status value. Ignoring", "error", err)
+			} else {
+				status = s
+			}
+		}
+		if v := args.Peek("delay"); len(v) > 0 {
+			if d, err := time.ParseDuration(string(v)); err != nil {
+				log.Debug("wrong delay value. Ignoring", "error", err)
+			} else {
+				time.Sleep(d)
+			}
+		}
+		ctx.SetStatusCode(status)
+		ctx.SetBody(resp.Body())
+	}
+}
+
+func main() {
+	log := slog.With("component", "fasthttp.Server")
+	address := fmt.Sprintf(":%d", 8080)
+	log.Info("starting HTTP server", "address", address)
+	err := fasthttp.ListenAndServe(address, RequestHandler(log, &fasthttp.Client{}))
+	log.Error("HTTP server has unexpectedly stopped", "error", err)
+}
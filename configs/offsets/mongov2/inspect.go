@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func HTTPHandler(log *slog.Logger, echoPort int) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		log.Debug("received request", "url", req.RequestURI)
+
+		ctx := context.Background()
+
+		client, err := mongo.Connect(options.Client().ApplyURI("mongodb://localhost:27017"))
+		if err != nil {
+			panic(err)
+		}
+		defer client.Disconnect(ctx)
+
+		coll := client.Database("obi").Collection("obi")
+		if _, err := coll.InsertOne(ctx, bson.D{{Key: "name", Value: "rocks"}}); err != nil {
+			panic(err)
+		}
+
+		var val bson.M
+		if err := coll.FindOne(ctx, bson.D{{Key: "name", Value: "rocks"}}).Decode(&val); err != nil {
+			panic(err)
+		}
+
+		status := 200
+		for k, v := range req.URL.Query() {
+			if len(v) == 0 {
+				continue
+			}
+			switch k {
+			case "status":
+				if s, err := strconv.Atoi(v[0]); err != nil {
+					log.Debug("wrong status value. Ignoring", "error", err)
+				} else {
+					status = s
+				}
+			case "delay":
+				if d, err := time.ParseDuration(v[0]); err != nil {
+					log.Debug("wrong delay value. Ignoring", "error", err)
+				} else {
+					time.Sleep(d)
+				}
+			}
+		}
+		rw.WriteHeader(status)
+		rw.Write([]byte(fmt.Sprint(val["name"])))
+	}
+}
+
+func main() {
+	log := slog.With("component", "std.Server")
+	address := fmt.Sprintf(":%d", 8080)
+	log.Info("starting HTTP server", "address", address)
+	err := http.ListenAndServe(address, HTTPHandler(log, 8080))
+	log.Error("HTTP server has unexpectedly stopped", "error", err)
+}
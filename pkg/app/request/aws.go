@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package request
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// RPCSystemAWSAPI is the rpc.system value of the HTTP client spans invoked from an AWS SDK
+const RPCSystemAWSAPI = "aws-api"
+
+var awsDomainSuffixes = []string{".amazonaws.com", ".amazonaws.com.cn"}
+
+// e.g. us-east-1, eu-central-2, us-gov-west-1, cn-north-1
+var awsRegionRegex = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d+$`)
+
+// awsService describes an AWS service that is reported by its SDK service ID
+type awsService struct {
+	// endpoint prefix of the service host names (e.g. "logs" in logs.us-east-1.amazonaws.com)
+	endpoint string
+	// sdkPackage is the aws-sdk-go-v2/service package of the service client
+	sdkPackage string
+	// id is the service ID reported by the AWS SDKs
+	id string
+}
+
+// awsServices lists the most common AWS services. Their SDK client operations are
+// instrumented, and their endpoints are reported with the SDK service ID.
+var awsServices = []awsService{
+	{endpoint: "dynamodb", sdkPackage: "dynamodb", id: "DynamoDB"},
+	{endpoint: "events", sdkPackage: "eventbridge", id: "EventBridge"},
+	{endpoint: "kinesis", sdkPackage: "kinesis", id: "Kinesis"},
+	{endpoint: "kms", sdkPackage: "kms", id: "KMS"},
+	{endpoint: "lambda", sdkPackage: "lambda", id: "Lambda"},
+	{endpoint: "logs", sdkPackage: "cloudwatchlogs", id: "CloudWatch Logs"},
+	{endpoint: "monitoring", sdkPackage: "cloudwatch", id: "CloudWatch"},
+	{endpoint: "s3", sdkPackage: "s3", id: "S3"},
+	{endpoint: "secretsmanager", sdkPackage: "secretsmanager", id: "Secrets Manager"},
+	{endpoint: "sns", sdkPackage: "sns", id: "SNS"},
+	{endpoint: "sqs", sdkPackage: "sqs", id: "SQS"},
+	{endpoint: "ssm", sdkPackage: "ssm", id: "SSM"},
+	{endpoint: "states", sdkPackage: "sfn", id: "SFN"},
+	{endpoint: "sts", sdkPackage: "sts", id: "STS"},
+}
+
+// awsServiceIDs maps the endpoint prefix of the awsServices to their service ID
+var awsServiceIDs = func() map[string]string {
+	ids := make(map[string]string, len(awsServices))
+	for _, s := range awsServices {
+		ids[s.endpoint] = s.id
+	}
+	return ids
+}()
+
+// AWSSDKServicePackages returns the aws-sdk-go-v2/service packages whose client
+// operations are reported as AWS API calls
+func AWSSDKServicePackages() []string {
+	pkgs := make([]string, 0, len(awsServices))
+	for _, s := range awsServices {
+		pkgs = append(pkgs, s.sdkPackage)
+	}
+	return pkgs
+}
+
+// AWSService holds the AWS service and region that are targeted by an HTTP client request
+type AWSService struct {
+	Name   string
+	Region string
+}
+
+// SpanAWSService returns the AWS service targeted by an HTTP client span, which
+// is derived from the host name of the service endpoint. It returns false if
+// the span wasn't sent from an AWS SDK operation, even if the request targets an AWS
+// endpoint (e.g. presigned URLs or requests from non-instrumented clients). Requests
+// from the AWS SDK to custom endpoints (e.g. local emulators) are reported with an
+// empty service.
+func SpanAWSService(span *Span) (AWSService, bool) {
+	if span.Type != EventTypeHTTPClient || span.AWSOperation == "" {
+		return AWSService{}, false
+	}
+	aws, _ := parseAWSHost(HTTPClientHost(span))
+	return aws, true
+}
+
+// spanAWSService returns the AWS service of the spans sent from the AWS SDK, or an empty service otherwise
+func spanAWSService(span *Span) AWSService {
+	aws, _ := SpanAWSService(span)
+	return aws
+}
+
+// spanRPCSystem returns the rpc.system metric attribute: grpc for the gRPC spans, and aws-api for
+// the HTTP client spans sent from the AWS SDK
+func spanRPCSystem(span *Span) string {
+	if span.Type != EventTypeHTTPClient {
+		return "grpc"
+	}
+	if _, ok := SpanAWSService(span); ok {
+		return RPCSystemAWSAPI
+	}
+	return ""
+}
+
+// spanRPCMethod returns the rpc.method metric attribute: the gRPC method, or the AWS SDK
+// operation of the HTTP client spans
+func spanRPCMethod(span *Span) string {
+	if span.Type == EventTypeHTTPClient {
+		return span.AWSOperation
+	}
+	return span.Path
+}
+
+// parseAWSHost extracts the service and region from AWS endpoints, as:
+// dynamodb.us-east-1.amazonaws.com, bucket.s3.eu-west-1.amazonaws.com,
+// s3.dualstack.us-west-2.amazonaws.com, s3-us-west-2.amazonaws.com or sts.amazonaws.com
+func parseAWSHost(host string) (AWSService, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	prefix := ""
+	for _, suffix := range awsDomainSuffixes {
+		if p, ok := strings.CutSuffix(host, suffix); ok {
+			prefix = p
+			break
+		}
+	}
+	if prefix == "" {
+		return AWSService{}, false
+	}
+
+	labels := strings.Split(prefix, ".")
+	endpoint, region := "", ""
+	for i := len(labels) - 1; i > 0; i-- {
+		if awsRegionRegex.MatchString(labels[i]) {
+			region = labels[i]
+			endpoint = labels[i-1]
+			if endpoint == "dualstack" && i > 1 {
+				endpoint = labels[i-2]
+			}
+			break
+		}
+	}
+	if endpoint == "" {
+		// global endpoints (sts.amazonaws.com) or legacy S3 ones (s3-us-west-2.amazonaws.com)
+		endpoint = labels[len(labels)-1]
+		if service, reg, ok := strings.Cut(endpoint, "-"); ok && awsRegionRegex.MatchString(reg) {
+			endpoint, region = service, reg
+		}
+	}
+	endpoint = strings.TrimSuffix(endpoint, "-fips")
+
+	service, ok := awsServiceIDs[endpoint]
+	if !ok {
+		service = endpoint
+	}
+	return AWSService{Name: service, Region: region}, true
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAWSHost(t *testing.T) {
+	for _, tc := range []struct {
+		host     string
+		expected AWSService
+	}{
+		{host: "dynamodb.us-east-1.amazonaws.com", expected: AWSService{Name: "DynamoDB", Region: "us-east-1"}},
+		{host: "sqs.eu-west-1.amazonaws.com:443", expected: AWSService{Name: "SQS", Region: "eu-west-1"}},
+		{host: "my-bucket.s3.eu-central-2.amazonaws.com", expected: AWSService{Name: "S3", Region: "eu-central-2"}},
+		{host: "my.dotted.bucket.s3.dualstack.us-west-2.amazonaws.com", expected: AWSService{Name: "S3", Region: "us-west-2"}},
+		{host: "s3-us-west-2.amazonaws.com", expected: AWSService{Name: "S3", Region: "us-west-2"}},
+		{host: "my-bucket.s3.amazonaws.com", expected: AWSService{Name: "S3"}},
+		{host: "sts.amazonaws.com", expected: AWSService{Name: "STS"}},
+		{host: "dynamodb-fips.us-gov-west-1.amazonaws.com", expected: AWSService{Name: "DynamoDB", Region: "us-gov-west-1"}},
+		{host: "vpce-0123.kinesis.ap-south-1.vpce.amazonaws.com", expected: AWSService{Name: "Kinesis", Region: "ap-south-1"}},
+		{host: "sns.cn-north-1.amazonaws.com.cn", expected: AWSService{Name: "SNS", Region: "cn-north-1"}},
+		{host: "glue.us-east-2.amazonaws.com", expected: AWSService{Name: "glue", Region: "us-east-2"}},
+	} {
+		t.Run(tc.host, func(t *testing.T) {
+			aws, ok := parseAWSHost(tc.host)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, aws)
+		})
+	}
+
+	for _, host := range []string{"example.com", "amazonaws.com.example.com", "localhost:4566", ""} {
+		t.Run(host, func(t *testing.T) {
+			_, ok := parseAWSHost(host)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestAWSSpanTraceName(t *testing.T) {
+	span := Span{Type: EventTypeHTTPClient, Method: "PUT", Statement: "https;my-bucket.s3.us-east-1.amazonaws.com", AWSOperation: "PutObject"}
+	assert.Equal(t, "S3.PutObject", span.TraceName())
+
+	// custom endpoints, as local emulators
+	span = Span{Type: EventTypeHTTPClient, Method: "POST", Statement: "http;localhost:4566", AWSOperation: "SendMessage"}
+	assert.Equal(t, "SendMessage", span.TraceName())
+	aws, ok := SpanAWSService(&span)
+	assert.True(t, ok)
+	assert.Empty(t, aws.Name)
+
+	// plain HTTP requests to AWS endpoints, as presigned URLs, aren't AWS API calls
+	span = Span{Type: EventTypeHTTPClient, Method: "GET", Statement: "https;my-bucket.s3.us-east-1.amazonaws.com"}
+	assert.Equal(t, "GET", span.TraceName())
+	_, ok = SpanAWSService(&span)
+	assert.False(t, ok)
+
+	span = Span{Type: EventTypeHTTPClient, Method: "POST", Statement: "https;sqs.us-east-1.amazonaws.com", AWSOperation: "SendMessage"}
+	aws, ok = SpanAWSService(&span)
+	assert.True(t, ok)
+	assert.Equal(t, AWSService{Name: "SQS", Region: "us-east-1"}, aws)
+}
+
+func TestAWSServices(t *testing.T) {
+	// all the instrumented SDK services are reported with their service ID
+	for _, tc := range []struct {
+		host string
+		name string
+	}{
+		{host: "kms.us-east-1.amazonaws.com", name: "KMS"},
+		{host: "logs.us-east-1.amazonaws.com", name: "CloudWatch Logs"},
+		{host: "monitoring.us-east-1.amazonaws.com", name: "CloudWatch"},
+		{host: "states.us-east-1.amazonaws.com", name: "SFN"},
+	} {
+		aws, ok := parseAWSHost(tc.host)
+		assert.True(t, ok)
+		assert.Equal(t, tc.name, aws.Name)
+	}
+	assert.Len(t, AWSSDKServicePackages(), len(awsServiceIDs))
+	assert.Subset(t, AWSSDKServicePackages(), []string{"kms", "cloudwatchlogs", "cloudwatch", "sfn"})
+}
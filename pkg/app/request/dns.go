@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package request
+
+import "strconv"
+
+// DNS response codes, as defined in RFC 1035 (section 4.1.1). They are stored
+// in the Span.Status field of EventTypeDNSClient spans.
+var dnsRCodeNames = map[uint8]string{
+	0: "NOERROR",
+	1: "FORMERR",
+	2: "SERVFAIL",
+	3: "NXDOMAIN",
+	4: "NOTIMP",
+	5: "REFUSED",
+}
+
+// DNSRCodeName returns the mnemonic of a DNS response code, or RCODE<n> for
+// unknown codes
+func DNSRCodeName(rcode uint8) string {
+	if name, ok := dnsRCodeNames[rcode]; ok {
+		return name
+	}
+	return "RCODE" + strconv.Itoa(int(rcode))
+}
@@ -0,0 +1,16 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSRCodeName(t *testing.T) {
+	assert.Equal(t, "NOERROR", DNSRCodeName(0))
+	assert.Equal(t, "NXDOMAIN", DNSRCodeName(3))
+	assert.Equal(t, "RCODE11", DNSRCodeName(11))
+}
@@ -112,6 +112,53 @@ func MessagingOperationType(val string) attribute.KeyValue {
 	return attribute.Key(attr.MessagingOpType).String(val)
 }
 
+func MessagingRabbitMQRoutingKey(val string) attribute.KeyValue {
+	return attribute.Key(attr.MessagingRabbitMQRoutingKey).String(val)
+}
+
+func MessagingMQTTQoS(val int) attribute.KeyValue {
+	return attribute.Key(attr.MessagingMQTTQoS).Int(val)
+}
+
+func MessagingMQTTReasonCode(val int) attribute.KeyValue {
+	return attribute.Key(attr.MessagingMQTTReasonCode).Int(val)
+}
+
+func AWSRegion(val string) attribute.KeyValue {
+	return attribute.Key(attr.AWSRegion).String(val)
+}
+
+func DNSQuestionName(val string) attribute.KeyValue {
+	return attribute.Key(attr.DNSQuestionName).String(val)
+}
+
+func DNSQuestionType(val string) attribute.KeyValue {
+	return attribute.Key(attr.DNSQuestionType).String(val)
+}
+
+func DNSResponseCode(rcode int) attribute.KeyValue {
+	return attribute.Key(attr.DNSResponseCode).String(DNSRCodeName(uint8(rcode)))
+}
+
+func MemcachedKey(val string) attribute.KeyValue {
+	return attribute.Key(attr.MemcachedKey).String(val)
+}
+
+func MemcachedHit(val bool) attribute.KeyValue {
+	return attribute.Key(attr.MemcachedHit).Bool(val)
+}
+
+func MemcachedResultName(val int) string {
+	switch val {
+	case MemcachedResultHit:
+		return "hit"
+	case MemcachedResultMiss:
+		return "miss"
+	default:
+		return ""
+	}
+}
+
 func SpanHost(span *Span) string {
 	if span.HostName != "" {
 		return span.HostName
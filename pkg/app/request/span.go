@@ -43,6 +43,17 @@ const (
 	EventTypeGPUKernelLaunch
 	EventTypeGPUMalloc
 	EventTypeGPUMemcpy
+	EventTypeMemcachedClient
+	EventTypeAMQPClient
+	EventTypeNATSClient
+	EventTypeNATSServer
+	EventTypeCassandraClient
+	EventTypeMQTTClient
+	EventTypeDNSClient
+	// EventTypeGoGCPause and EventTypeGoScheduleLatency are Go runtime events. They are
+	// only exported as metrics, and ignored by the traces, span metrics and service graph exporters.
+	EventTypeGoGCPause
+	EventTypeGoScheduleLatency
 )
 
 const (
@@ -64,6 +75,20 @@ const (
 	DBMySQL
 )
 
+// Result of a memcached operation, stored in the Span.SubType field of
+// EventTypeMemcachedClient spans. Only lookups and operations that might
+// not find the key report a hit or a miss.
+const (
+	MemcachedResultUnknown = iota
+	MemcachedResultHit
+	MemcachedResultMiss
+)
+
+// MQTTReasonCodeNone is stored in the Span.Status field of EventTypeMQTTClient spans
+// when the operation wasn't acknowledged, as QoS 0 publications. Otherwise, Status
+// holds the reason code returned by the broker, and SubType the QoS level.
+const MQTTReasonCodeNone = -1
+
 //nolint:cyclop
 func (t EventType) String() string {
 	switch t {
@@ -95,6 +120,24 @@ func (t EventType) String() string {
 		return "CUDAMemcpy"
 	case EventTypeMongoClient:
 		return "MongoClient"
+	case EventTypeMemcachedClient:
+		return "MemcachedClient"
+	case EventTypeAMQPClient:
+		return "AMQPClient"
+	case EventTypeNATSClient:
+		return "NATSClient"
+	case EventTypeNATSServer:
+		return "NATSServer"
+	case EventTypeCassandraClient:
+		return "CassandraClient"
+	case EventTypeMQTTClient:
+		return "MQTTClient"
+	case EventTypeDNSClient:
+		return "DNSClient"
+	case EventTypeGoGCPause:
+		return "GoGCPause"
+	case EventTypeGoScheduleLatency:
+		return "GoScheduleLatency"
 	case EventTypeManualSpan:
 		return "CUSTOM"
 	default:
@@ -107,8 +150,9 @@ func (t EventType) MarshalText() ([]byte, error) {
 }
 
 const (
-	MessagingPublish = "publish"
-	MessagingProcess = "process"
+	MessagingPublish   = "publish"
+	MessagingProcess   = "process"
+	MessagingSubscribe = "subscribe"
 )
 
 type converter struct {
@@ -175,6 +219,8 @@ type Span struct {
 	DBNamespace    string         `json:"-"`
 	SQLCommand     string         `json:"-"`
 	SQLError       *SQLError      `json:"-"`
+	// AWSOperation is the AWS SDK operation (e.g. PutObject) that sent an HTTP client request
+	AWSOperation string `json:"-"`
 }
 
 func (s *Span) Inside(parent *Span) bool {
@@ -187,6 +233,12 @@ func (s *Span) InternalSignal() bool {
 	return s.Type == EventTypeProcessAlive
 }
 
+// GoRuntimeSignal returns whether a span reports a Go runtime event (GC pause or
+// goroutine scheduling latency) instead of a request.
+func (s *Span) GoRuntimeSignal() bool {
+	return s.Type == EventTypeGoGCPause || s.Type == EventTypeGoScheduleLatency
+}
+
 // helper attribute functions used by JSON serialization
 type SpanAttributes map[string]string
 
@@ -285,6 +337,58 @@ func spanAttributes(s *Span) SpanAttributes {
 			"operation":  s.Method,
 			"table":      s.Path,
 		}
+	case EventTypeMemcachedClient:
+		return SpanAttributes{
+			"serverAddr": SpanHost(s),
+			"serverPort": strconv.Itoa(s.HostPort),
+			"operation":  s.Method,
+			"key":        s.Path,
+			"result":     MemcachedResultName(s.SubType),
+		}
+	case EventTypeAMQPClient:
+		return SpanAttributes{
+			"serverAddr":  SpanHost(s),
+			"serverPort":  strconv.Itoa(s.HostPort),
+			"operation":   s.Method,
+			"destination": s.Path,
+			"routingKey":  s.Statement,
+		}
+	case EventTypeNATSClient, EventTypeNATSServer:
+		return SpanAttributes{
+			"serverAddr": SpanHost(s),
+			"serverPort": strconv.Itoa(s.HostPort),
+			"operation":  s.Method,
+			"subject":    s.Path,
+		}
+	case EventTypeMQTTClient:
+		attrs := SpanAttributes{
+			"serverAddr": SpanHost(s),
+			"serverPort": strconv.Itoa(s.HostPort),
+			"operation":  s.Method,
+			"topic":      s.Path,
+			"qos":        strconv.Itoa(s.SubType),
+		}
+		if s.Status != MQTTReasonCodeNone {
+			attrs["reasonCode"] = strconv.Itoa(s.Status)
+		}
+		return attrs
+	case EventTypeCassandraClient:
+		return SpanAttributes{
+			"serverAddr": SpanHost(s),
+			"serverPort": strconv.Itoa(s.HostPort),
+			"operation":  s.Method,
+			"table":      s.Path,
+			"keyspace":   s.DBNamespace,
+			"statement":  s.Statement,
+		}
+	case EventTypeDNSClient:
+		return SpanAttributes{
+			"serverAddr":   SpanHost(s),
+			"serverPort":   strconv.Itoa(s.HostPort),
+			"questionName": s.Path,
+			"questionType": s.Method,
+			"responseCode": DNSRCodeName(uint8(s.Status)),
+		}
 	}
 
 	return SpanAttributes{}
@@ -385,9 +489,23 @@ func (s *Span) IsValid() bool {
 	return true
 }
 
+// IsMessagingSpan returns whether the span is a message publication or processing
+// in a messaging system, whose destination is stored in the Path field
+func (s *Span) IsMessagingSpan() bool {
+	switch s.Type {
+	case EventTypeKafkaClient, EventTypeKafkaServer, EventTypeAMQPClient, EventTypeNATSClient, EventTypeNATSServer,
+		EventTypeMQTTClient:
+		return true
+	}
+
+	return false
+}
+
 func (s *Span) IsClientSpan() bool {
 	switch s.Type {
-	case EventTypeGRPCClient, EventTypeHTTPClient, EventTypeRedisClient, EventTypeKafkaClient, EventTypeSQLClient, EventTypeMongoClient:
+	case EventTypeGRPCClient, EventTypeHTTPClient, EventTypeRedisClient, EventTypeKafkaClient, EventTypeSQLClient, EventTypeMongoClient,
+		EventTypeMemcachedClient, EventTypeAMQPClient, EventTypeNATSClient, EventTypeCassandraClient, EventTypeMQTTClient,
+		EventTypeDNSClient:
 		return true
 	}
 
@@ -406,11 +524,19 @@ func SpanStatusCode(span *Span) string {
 		return HTTPSpanStatusCode(span)
 	case EventTypeGRPC, EventTypeGRPCClient:
 		return GrpcSpanStatusCode(span)
-	case EventTypeSQLClient, EventTypeRedisClient, EventTypeRedisServer, EventTypeMongoClient:
+	case EventTypeSQLClient, EventTypeRedisClient, EventTypeRedisServer, EventTypeMongoClient, EventTypeMemcachedClient,
+		EventTypeNATSClient, EventTypeNATSServer, EventTypeCassandraClient, EventTypeDNSClient:
 		if span.Status != 0 {
 			return StatusCodeError
 		}
 		return StatusCodeUnset
+	case EventTypeMQTTClient:
+		// MQTT 5 reason codes from 0x80 onwards, as well as the MQTT 3.1.1
+		// SUBACK failure return code, indicate failures
+		if span.Status >= 0x80 {
+			return StatusCodeError
+		}
+		return StatusCodeUnset
 	case EventTypeManualSpan:
 		switch span.Status {
 		case int(codes.Error):
@@ -425,7 +551,7 @@ func SpanStatusCode(span *Span) string {
 
 func SpanStatusMessage(span *Span) string {
 	switch span.Type {
-	case EventTypeRedisClient, EventTypeRedisServer, EventTypeMongoClient:
+	case EventTypeRedisClient, EventTypeRedisServer, EventTypeMongoClient, EventTypeMemcachedClient, EventTypeCassandraClient:
 		if span.Status != 0 && span.DBError.Description != "" {
 			return span.DBError.Description
 		}
@@ -433,12 +559,25 @@ func SpanStatusMessage(span *Span) string {
 		if span.Status != 0 && span.SQLError != nil {
 			return span.SQLErrorDescription()
 		}
+	case EventTypeDNSClient:
+		if span.Status != 0 {
+			return DNSRCodeName(uint8(span.Status))
+		}
 	case EventTypeManualSpan:
 		return span.Path
 	}
 	return ""
 }
 
+// spanErrorType returns the value of the error.type attribute for spans
+// that are reported as errors
+func spanErrorType(span *Span) string {
+	if span.Type == EventTypeDNSClient {
+		return DNSRCodeName(uint8(span.Status))
+	}
+	return "error"
+}
+
 // HTTPSpanStatusCode https://opentelemetry.io/docs/specs/otel/trace/semantic_conventions/http/#status
 func HTTPSpanStatusCode(span *Span) string {
 	if span.Status == 0 {
@@ -503,17 +642,26 @@ func (s *Span) ResponseBodyLength() int64 {
 // ServiceGraphKind returns the Kind string representation that is compliant with service graph metrics specification
 func (s *Span) ServiceGraphKind() string {
 	switch s.Type {
-	case EventTypeHTTP, EventTypeGRPC, EventTypeKafkaServer, EventTypeRedisServer:
+	case EventTypeHTTP, EventTypeGRPC, EventTypeKafkaServer, EventTypeRedisServer, EventTypeNATSServer:
 		return "SPAN_KIND_SERVER"
-	case EventTypeHTTPClient, EventTypeGRPCClient, EventTypeSQLClient, EventTypeRedisClient, EventTypeMongoClient:
+	case EventTypeHTTPClient, EventTypeGRPCClient, EventTypeSQLClient, EventTypeRedisClient, EventTypeMongoClient,
+		EventTypeMemcachedClient, EventTypeCassandraClient, EventTypeDNSClient:
 		return "SPAN_KIND_CLIENT"
-	case EventTypeKafkaClient:
+	case EventTypeKafkaClient, EventTypeAMQPClient:
+		switch s.Method {
+		case MessagingPublish:
+			return "SPAN_KIND_PRODUCER"
+		case MessagingProcess:
+			return "SPAN_KIND_CONSUMER"
+		}
+	case EventTypeNATSClient, EventTypeMQTTClient:
 		switch s.Method {
 		case MessagingPublish:
 			return "SPAN_KIND_PRODUCER"
 		case MessagingProcess:
 			return "SPAN_KIND_CONSUMER"
 		}
+		return "SPAN_KIND_CLIENT"
 	}
 	return "SPAN_KIND_INTERNAL"
 }
@@ -521,6 +669,12 @@ func (s *Span) ServiceGraphKind() string {
 func (s *Span) TraceName() string {
 	switch s.Type {
 	case EventTypeHTTP, EventTypeHTTPClient:
+		if s.AWSOperation != "" {
+			if aws, _ := SpanAWSService(s); aws.Name != "" {
+				return aws.Name + "." + s.AWSOperation
+			}
+			return s.AWSOperation
+		}
 		name := s.Method
 		if s.Route != "" {
 			name += " " + s.Route
@@ -543,7 +697,8 @@ func (s *Span) TraceName() string {
 			return "REDIS"
 		}
 		return s.Method
-	case EventTypeKafkaClient, EventTypeKafkaServer:
+	case EventTypeKafkaClient, EventTypeKafkaServer, EventTypeAMQPClient, EventTypeNATSClient, EventTypeNATSServer,
+		EventTypeMQTTClient:
 		if s.Path == "" {
 			return s.Method
 		}
@@ -560,6 +715,24 @@ func (s *Span) TraceName() string {
 			return s.Method
 		}
 		return semconv.DBSystemMongoDB.Value.AsString()
+	case EventTypeMemcachedClient:
+		if s.Method == "" {
+			return "MEMCACHED"
+		}
+		return s.Method
+	case EventTypeCassandraClient:
+		if s.Method == "" {
+			return semconv.DBSystemCassandra.Value.AsString()
+		}
+		if s.Path != "" {
+			return s.Method + " " + s.Path
+		}
+		return s.Method
+	case EventTypeDNSClient:
+		if s.Method == "" {
+			return "DNS"
+		}
+		return "DNS " + s.Method
 	case EventTypeManualSpan:
 		return s.Method
 	}
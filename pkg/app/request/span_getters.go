@@ -49,9 +49,13 @@ func SpanOTELGetters(name attr.Name) (attributes.Getter[*Span, attribute.KeyValu
 	case attr.ServerPort:
 		getter = func(s *Span) attribute.KeyValue { return ServerPort(s.HostPort) }
 	case attr.RPCMethod:
-		getter = func(s *Span) attribute.KeyValue { return semconv.RPCMethod(s.Path) }
+		getter = func(s *Span) attribute.KeyValue { return semconv.RPCMethod(spanRPCMethod(s)) }
 	case attr.RPCSystem:
-		getter = func(_ *Span) attribute.KeyValue { return semconv.RPCSystemGRPC }
+		getter = func(s *Span) attribute.KeyValue { return semconv.RPCSystemKey.String(spanRPCSystem(s)) }
+	case attr.RPCService:
+		getter = func(s *Span) attribute.KeyValue { return semconv.RPCService(spanAWSService(s).Name) }
+	case attr.AWSRegion:
+		getter = func(s *Span) attribute.KeyValue { return AWSRegion(spanAWSService(s).Region) }
 	case attr.RPCGRPCStatusCode:
 		getter = func(s *Span) attribute.KeyValue { return semconv.RPCGRPCStatusCodeKey.Int(s.Status) }
 	case attr.Server:
@@ -88,30 +92,48 @@ func SpanOTELGetters(name attr.Name) (attributes.Getter[*Span, attribute.KeyValu
 				return DBSystemName(semconv.DBSystemRedis.Value.AsString())
 			case EventTypeMongoClient:
 				return DBSystemName(semconv.DBSystemMongoDB.Value.AsString())
+			case EventTypeMemcachedClient:
+				return DBSystemName(semconv.DBSystemMemcached.Value.AsString())
+			case EventTypeCassandraClient:
+				return DBSystemName(semconv.DBSystemCassandra.Value.AsString())
 			}
 			return DBSystemName("unknown")
 		}
 	case attr.ErrorType:
 		getter = func(span *Span) attribute.KeyValue {
 			if SpanStatusCode(span) == StatusCodeError {
-				return ErrorType("error")
+				return ErrorType(spanErrorType(span))
 			}
 			return ErrorType("")
 		}
 	case attr.MessagingSystem:
 		getter = func(span *Span) attribute.KeyValue {
-			if span.Type == EventTypeKafkaClient || span.Type == EventTypeKafkaServer {
+			switch span.Type {
+			case EventTypeKafkaClient, EventTypeKafkaServer:
 				return semconv.MessagingSystem("kafka")
+			case EventTypeAMQPClient:
+				return semconv.MessagingSystem("rabbitmq")
+			case EventTypeNATSClient, EventTypeNATSServer:
+				return semconv.MessagingSystem("nats")
+			case EventTypeMQTTClient:
+				return semconv.MessagingSystem("mqtt")
 			}
 			return semconv.MessagingSystem("unknown")
 		}
 	case attr.MessagingDestination:
 		getter = func(span *Span) attribute.KeyValue {
-			if span.Type == EventTypeKafkaClient || span.Type == EventTypeKafkaServer {
+			if span.IsMessagingSpan() {
 				return semconv.MessagingDestinationName(span.Path)
 			}
 			return semconv.MessagingDestinationName("")
 		}
+	case attr.DNSQuestionName:
+		getter = func(span *Span) attribute.KeyValue {
+			if span.Type == EventTypeDNSClient {
+				return DNSQuestionName(span.Path)
+			}
+			return DNSQuestionName("")
+		}
 	case attr.CudaKernelName:
 		getter = func(span *Span) attribute.KeyValue { return CudaKernel(span.Method) }
 	case attr.CudaMemcpyKind:
@@ -149,9 +171,13 @@ func SpanPromGetters(attrName attr.Name) (attributes.Getter[*Span, string], bool
 	case attr.ServerPort:
 		getter = func(s *Span) string { return strconv.Itoa(s.HostPort) }
 	case attr.RPCMethod:
-		getter = func(s *Span) string { return s.Path }
+		getter = spanRPCMethod
 	case attr.RPCSystem:
-		getter = func(_ *Span) string { return "grpc" }
+		getter = spanRPCSystem
+	case attr.RPCService:
+		getter = func(s *Span) string { return spanAWSService(s).Name }
+	case attr.AWSRegion:
+		getter = func(s *Span) string { return spanAWSService(s).Region }
 	case attr.RPCGRPCStatusCode:
 		getter = func(s *Span) string { return strconv.Itoa(s.Status) }
 	case attr.DBOperation:
@@ -159,7 +185,7 @@ func SpanPromGetters(attrName attr.Name) (attributes.Getter[*Span, string], bool
 	case attr.ErrorType:
 		getter = func(span *Span) string {
 			if SpanStatusCode(span) == StatusCodeError {
-				return "error"
+				return spanErrorType(span)
 			}
 			return ""
 		}
@@ -172,6 +198,10 @@ func SpanPromGetters(attrName attr.Name) (attributes.Getter[*Span, string], bool
 				return semconv.DBSystemRedis.Value.AsString()
 			case EventTypeMongoClient:
 				return semconv.DBSystemMongoDB.Value.AsString()
+			case EventTypeMemcachedClient:
+				return semconv.DBSystemMemcached.Value.AsString()
+			case EventTypeCassandraClient:
+				return semconv.DBSystemCassandra.Value.AsString()
 			}
 			return "unknown"
 		}
@@ -180,21 +210,35 @@ func SpanPromGetters(attrName attr.Name) (attributes.Getter[*Span, string], bool
 			if span.Type == EventTypeSQLClient {
 				return span.DBSystemName().Value.AsString()
 			}
-			if span.Type == EventTypeMongoClient {
+			if span.Type == EventTypeMongoClient || span.Type == EventTypeCassandraClient {
 				return span.Path
 			}
 			return ""
 		}
 	case attr.MessagingSystem:
 		getter = func(span *Span) string {
-			if span.Type == EventTypeKafkaClient || span.Type == EventTypeKafkaServer {
+			switch span.Type {
+			case EventTypeKafkaClient, EventTypeKafkaServer:
 				return "kafka"
+			case EventTypeAMQPClient:
+				return "rabbitmq"
+			case EventTypeNATSClient, EventTypeNATSServer:
+				return "nats"
+			case EventTypeMQTTClient:
+				return "mqtt"
 			}
 			return "unknown"
 		}
 	case attr.MessagingDestination:
 		getter = func(span *Span) string {
-			if span.Type == EventTypeKafkaClient || span.Type == EventTypeKafkaServer {
+			if span.IsMessagingSpan() {
+				return span.Path
+			}
+			return ""
+		}
+	case attr.DNSQuestionName:
+		getter = func(span *Span) string {
+			if span.Type == EventTypeDNSClient {
 				return span.Path
 			}
 			return ""
@@ -33,17 +33,26 @@ func TestSpanClientServer(t *testing.T) {
 
 func TestEventTypeString(t *testing.T) {
 	typeStringMap := map[EventType]string{
-		EventTypeHTTP:        "HTTP",
-		EventTypeGRPC:        "GRPC",
-		EventTypeHTTPClient:  "HTTPClient",
-		EventTypeGRPCClient:  "GRPCClient",
-		EventTypeSQLClient:   "SQLClient",
-		EventTypeRedisClient: "RedisClient",
-		EventTypeKafkaClient: "KafkaClient",
-		EventTypeRedisServer: "RedisServer",
-		EventTypeKafkaServer: "KafkaServer",
-		EventTypeMongoClient: "MongoClient",
-		EventType(99):        "UNKNOWN (99)",
+		EventTypeHTTP:              "HTTP",
+		EventTypeGRPC:              "GRPC",
+		EventTypeHTTPClient:        "HTTPClient",
+		EventTypeGRPCClient:        "GRPCClient",
+		EventTypeSQLClient:         "SQLClient",
+		EventTypeRedisClient:       "RedisClient",
+		EventTypeKafkaClient:       "KafkaClient",
+		EventTypeRedisServer:       "RedisServer",
+		EventTypeKafkaServer:       "KafkaServer",
+		EventTypeMongoClient:       "MongoClient",
+		EventTypeMemcachedClient:   "MemcachedClient",
+		EventTypeAMQPClient:        "AMQPClient",
+		EventTypeNATSClient:        "NATSClient",
+		EventTypeNATSServer:        "NATSServer",
+		EventTypeCassandraClient:   "CassandraClient",
+		EventTypeMQTTClient:        "MQTTClient",
+		EventTypeDNSClient:         "DNSClient",
+		EventTypeGoGCPause:         "GoGCPause",
+		EventTypeGoScheduleLatency: "GoScheduleLatency",
+		EventType(99):              "UNKNOWN (99)",
 	}
 
 	for ev, str := range typeStringMap {
@@ -62,8 +71,19 @@ func TestKindString(t *testing.T) {
 		{Type: EventTypeSQLClient}:                             "SPAN_KIND_CLIENT",
 		{Type: EventTypeRedisClient}:                           "SPAN_KIND_CLIENT",
 		{Type: EventTypeMongoClient}:                           "SPAN_KIND_CLIENT",
+		{Type: EventTypeMemcachedClient}:                       "SPAN_KIND_CLIENT",
+		{Type: EventTypeCassandraClient}:                       "SPAN_KIND_CLIENT",
+		{Type: EventTypeDNSClient}:                             "SPAN_KIND_CLIENT",
 		{Type: EventTypeKafkaClient, Method: MessagingPublish}: "SPAN_KIND_PRODUCER",
 		{Type: EventTypeKafkaClient, Method: MessagingProcess}: "SPAN_KIND_CONSUMER",
+		{Type: EventTypeAMQPClient, Method: MessagingPublish}:  "SPAN_KIND_PRODUCER",
+		{Type: EventTypeAMQPClient, Method: MessagingProcess}:  "SPAN_KIND_CONSUMER",
+		{Type: EventTypeNATSClient, Method: MessagingPublish}:  "SPAN_KIND_PRODUCER",
+		{Type: EventTypeNATSClient, Method: "subscribe"}:       "SPAN_KIND_CLIENT",
+		{Type: EventTypeNATSServer, Method: MessagingPublish}:  "SPAN_KIND_SERVER",
+		{Type: EventTypeMQTTClient, Method: MessagingPublish}:  "SPAN_KIND_PRODUCER",
+		{Type: EventTypeMQTTClient, Method: MessagingProcess}:  "SPAN_KIND_CONSUMER",
+		{Type: EventTypeMQTTClient, Method: "subscribe"}:       "SPAN_KIND_CLIENT",
 		{}: "SPAN_KIND_INTERNAL",
 	}
 
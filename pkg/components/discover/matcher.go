@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"slices"
 
@@ -43,6 +44,9 @@ func CriteriaMatcherProvider(
 		Namespace:        beylaNamespace,
 		HasHostPidAccess: hasHostPidAccess(),
 	}
+	if updates := cfg.Reloads.DiscoveryUpdates(); updates != nil {
+		m.Updates = updates.Subscribe()
+	}
 	return swarm.DirectInstance(m.Run)
 }
 
@@ -60,6 +64,13 @@ type Matcher struct {
 	Output           *msg.Queue[[]Event[ProcessMatch]]
 	Namespace        string
 	HasHostPidAccess bool
+	// Updates receives the new configurations after a reload, whose discovery criteria
+	// replace the current ones. It can be nil.
+	Updates <-chan *obi.Config
+
+	// knownProcesses keeps the last attributes of all the alive processes, matched or not,
+	// so they can be matched again when the discovery criteria change
+	knownProcesses map[PID]ProcessAttrs
 }
 
 // ProcessMatch matches a found process with the first selection criteria it fulfilled.
@@ -76,6 +87,16 @@ func (m *Matcher) Run(ctx context.Context) {
 		case <-ctx.Done():
 			m.Log.Debug("context cancelled, stopping criteria matcher node")
 			return
+		case cfg, ok := <-m.Updates:
+			if !ok {
+				m.Updates = nil
+				continue
+			}
+			o := m.reload(cfg)
+			m.Log.Info("discovery criteria reloaded", "changedProcesses", len(o))
+			if len(o) > 0 {
+				m.Output.Send(o)
+			}
 		case i, ok := <-m.Input:
 			if !ok {
 				m.Log.Debug("input channel closed, stopping criteria matcher node")
@@ -92,8 +113,16 @@ func (m *Matcher) Run(ctx context.Context) {
 }
 
 func (m *Matcher) filter(events []Event[ProcessAttrs]) []Event[ProcessMatch] {
+	if m.knownProcesses == nil {
+		m.knownProcesses = map[PID]ProcessAttrs{}
+	}
 	var matches []Event[ProcessMatch]
 	for _, ev := range events {
+		if ev.Type == EventDeleted {
+			delete(m.knownProcesses, ev.Obj.pid)
+		} else {
+			m.knownProcesses[ev.Obj.pid] = ev.Obj
+		}
 		if ev.Type == EventDeleted {
 			if ev, ok := m.filterDeleted(ev.Obj); ok {
 				matches = append(matches, ev)
@@ -182,6 +211,63 @@ func (m *Matcher) filterDeleted(obj ProcessAttrs) (Event[ProcessMatch], bool) {
 	}, true
 }
 
+// reload replaces the discovery criteria and matches again all the known processes.
+// It returns deletion events for the instrumented processes that don't match anymore the
+// new criteria, followed by creation events for the processes that match now.
+func (m *Matcher) reload(cfg *obi.Config) []Event[ProcessMatch] {
+	m.Criteria = FindingCriteria(cfg)
+	m.ExcludeCriteria = ExcludingCriteria(cfg)
+
+	pids := slices.Sorted(maps.Keys(m.knownProcesses))
+
+	// instrumented processes are kept if they still match the criteria, or if they were
+	// matched by any of their ancestors and the ancestor is kept
+	kept := map[PID]struct{}{}
+	byParent := map[PID]PID{}
+	for _, pid := range pids {
+		if _, ok := m.ProcessHistory[pid]; !ok {
+			continue
+		}
+		obj := m.knownProcesses[pid]
+		proc, err := processInfo(obj)
+		if err != nil {
+			m.Log.Debug("can't get information for process", "pid", pid, "error", err)
+			continue
+		}
+		if pm := m.matchCriteria(obj, proc); pm != nil {
+			kept[pid] = struct{}{}
+		} else {
+			byParent[pid] = PID(proc.PPid)
+		}
+	}
+	for found := true; found; {
+		found = false
+		for pid, ppid := range byParent {
+			if _, ok := kept[ppid]; ok {
+				kept[pid] = struct{}{}
+				delete(byParent, pid)
+				found = true
+			}
+		}
+	}
+
+	var events []Event[ProcessMatch]
+	for _, pid := range pids {
+		if _, ok := kept[pid]; ok || !m.alreadyMatched(pid) {
+			continue
+		}
+		if ev, ok := m.filterDeleted(m.knownProcesses[pid]); ok {
+			events = append(events, ev)
+		}
+	}
+	for _, pid := range pids {
+		if ev, ok := m.filterCreated(m.knownProcesses[pid]); ok {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
 func (m *Matcher) isExcluded(obj *ProcessAttrs, proc *services.ProcessInfo) bool {
 	for i := range m.ExcludeCriteria {
 		m.Log.Debug("checking exclusion criteria", "pid", proc.Pid, "comm", proc.ExePath)
@@ -586,3 +586,109 @@ func TestCriteriaMatcher_Granular(t *testing.T) {
 	assert.True(t, asteroidAttrs.ExportModes.CanExportMetrics())
 	require.Nil(t, asteroidAttrs.Sampler)
 }
+
+func TestCriteriaMatcher_Reload(t *testing.T) {
+	pipeConfig := obi.Config{Reloads: obi.NewConfigReloads()}
+	require.NoError(t, yaml.Unmarshal([]byte(`discovery:
+  instrument:
+  - name: port-only
+    open_ports: 8080
+`), &pipeConfig))
+
+	discoveredProcesses := msg.NewQueue[[]Event[ProcessAttrs]](msg.ChannelBufferLen(10))
+	filteredProcessesQu := msg.NewQueue[[]Event[ProcessMatch]](msg.ChannelBufferLen(10))
+	filteredProcesses := filteredProcessesQu.Subscribe()
+	matcherFunc, err := CriteriaMatcherProvider(&pipeConfig, discoveredProcesses, filteredProcessesQu)(t.Context())
+	require.NoError(t, err)
+	go matcherFunc(t.Context())
+	defer filteredProcessesQu.Close()
+
+	processInfo = func(pp ProcessAttrs) (*services.ProcessInfo, error) {
+		exePath := map[PID]string{1: "/bin/server", 2: "/bin/client", 3: "/bin/other"}[pp.pid]
+		return &services.ProcessInfo{Pid: int32(pp.pid), ExePath: exePath, OpenPorts: pp.openPorts}, nil
+	}
+	discoveredProcesses.Send([]Event[ProcessAttrs]{
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 1, openPorts: []uint32{8080}}},
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 2}},
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 3, openPorts: []uint32{9090}}},
+		{Type: EventDeleted, Obj: ProcessAttrs{pid: 3, openPorts: []uint32{9090}}},
+	})
+	matches := testutil.ReadChannel(t, filteredProcesses, testTimeout)
+	require.Len(t, matches, 1)
+	testMatch(t, matches[0], "port-only", "", services.ProcessInfo{Pid: 1, ExePath: "/bin/server", OpenPorts: []uint32{8080}})
+
+	// the already running processes are matched again against the new criteria
+	newConfig := obi.Config{}
+	require.NoError(t, yaml.Unmarshal([]byte(`discovery:
+  instrument:
+  - name: clients
+    exe_path: "*/client"
+  - name: others
+    exe_path: "*/other"
+`), &newConfig))
+	pipeConfig.Reloads.Discovery.Send(&newConfig)
+
+	matches = testutil.ReadChannel(t, filteredProcesses, testTimeout)
+	require.Len(t, matches, 2)
+	// processes not matching anymore are removed
+	assert.Equal(t, EventDeleted, matches[0].Type)
+	assert.EqualValues(t, 1, matches[0].Obj.Process.Pid)
+	// processes matching now are added, but not these that already ended
+	testMatch(t, matches[1], "clients", "", services.ProcessInfo{Pid: 2, ExePath: "/bin/client"})
+
+	// after the reload, new processes are matched against the new criteria
+	discoveredProcesses.Send([]Event[ProcessAttrs]{
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 3, openPorts: []uint32{9090}}},
+	})
+	matches = testutil.ReadChannel(t, filteredProcesses, testTimeout)
+	require.Len(t, matches, 1)
+	testMatch(t, matches[0], "others", "", services.ProcessInfo{Pid: 3, ExePath: "/bin/other", OpenPorts: []uint32{9090}})
+}
+
+func TestCriteriaMatcher_ReloadKeepsDescendants(t *testing.T) {
+	pipeConfig := obi.Config{Reloads: obi.NewConfigReloads()}
+	require.NoError(t, yaml.Unmarshal([]byte(`discovery:
+  instrument:
+  - name: port-only
+    open_ports: 8080
+`), &pipeConfig))
+
+	discoveredProcesses := msg.NewQueue[[]Event[ProcessAttrs]](msg.ChannelBufferLen(10))
+	filteredProcessesQu := msg.NewQueue[[]Event[ProcessMatch]](msg.ChannelBufferLen(10))
+	filteredProcesses := filteredProcessesQu.Subscribe()
+	matcherFunc, err := CriteriaMatcherProvider(&pipeConfig, discoveredProcesses, filteredProcessesQu)(t.Context())
+	require.NoError(t, err)
+	go matcherFunc(t.Context())
+	defer filteredProcessesQu.Close()
+
+	// 2, 3 and 5 are the child, grandchild and great-grandchild of the matching process
+	processInfo = func(pp ProcessAttrs) (*services.ProcessInfo, error) {
+		exePath := map[PID]string{1: "/bin/server", 2: "/bin/worker", 3: "/bin/worker", 4: "/bin/other", 5: "/bin/worker"}[pp.pid]
+		ppid := map[PID]int32{2: 1, 3: 2, 5: 3}[pp.pid]
+		return &services.ProcessInfo{Pid: int32(pp.pid), PPid: ppid, ExePath: exePath, OpenPorts: pp.openPorts}, nil
+	}
+	discoveredProcesses.Send([]Event[ProcessAttrs]{
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 1, openPorts: []uint32{8080}}},
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 2}},
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 3}},
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 4}},
+		{Type: EventCreated, Obj: ProcessAttrs{pid: 5}},
+	})
+	matches := testutil.ReadChannel(t, filteredProcesses, testTimeout)
+	require.Len(t, matches, 4)
+
+	// the descendants of a kept process are neither removed nor added again
+	newConfig := obi.Config{}
+	require.NoError(t, yaml.Unmarshal([]byte(`discovery:
+  instrument:
+  - name: port-only
+    open_ports: 8080
+  - name: others
+    exe_path: "*/other"
+`), &newConfig))
+	pipeConfig.Reloads.Discovery.Send(&newConfig)
+
+	matches = testutil.ReadChannel(t, filteredProcesses, testTimeout)
+	require.Len(t, matches, 1)
+	testMatch(t, matches[0], "others", "", services.ProcessInfo{Pid: 4, ExePath: "/bin/other"})
+}
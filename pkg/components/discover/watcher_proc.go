@@ -77,6 +77,9 @@ func ProcessWatcherFunc(cfg *obi.Config, ebpfContext *ebpfcommon.EBPFEventContex
 		findingCriteria:   FindingCriteria(cfg),
 		ebpfContext:       ebpfContext,
 	}
+	if updates := cfg.Reloads.DiscoveryUpdates(); updates != nil {
+		acc.updates = updates.Subscribe()
+	}
 	if acc.interval == 0 {
 		acc.interval = defaultPollInterval
 	}
@@ -113,6 +116,8 @@ type pollAccounter struct {
 	findingCriteria   []services.Selector
 	output            *msg.Queue[[]Event[ProcessAttrs]]
 	ebpfContext       *ebpfcommon.EBPFEventContext
+	// updates receives the new configurations after a reload. It can be nil.
+	updates <-chan *obi.Config
 }
 
 func (pa *pollAccounter) run(ctx context.Context) {
@@ -150,6 +155,13 @@ func (pa *pollAccounter) run(ctx context.Context) {
 		case <-ctx.Done():
 			log.Debug("context canceled. Exiting")
 			return
+		case cfg, ok := <-pa.updates:
+			if !ok {
+				pa.updates = nil
+				continue
+			}
+			// the ports of the already running processes might match now the new criteria
+			pa.setFindingCriteria(cfg)
 		case <-time.After(pa.interval):
 			// poll event starting again
 		}
@@ -182,6 +194,21 @@ func (pa *pollAccounter) portFetchRequired() bool {
 	return ret
 }
 
+func (pa *pollAccounter) setFindingCriteria(cfg *obi.Config) {
+	criteria := FindingCriteria(cfg)
+	pa.stateMux.Lock()
+	defer pa.stateMux.Unlock()
+	pa.findingCriteria = criteria
+	pa.cfg = cfg
+	pa.fetchPorts = true
+}
+
+func (pa *pollAccounter) isPortOfInterest(port int) bool {
+	pa.stateMux.Lock()
+	defer pa.stateMux.Unlock()
+	return pa.cfg.Port.Matches(port) || portOfInterest(pa.findingCriteria, port)
+}
+
 func portOfInterest(criteria []services.Selector, port int) bool {
 	for _, cr := range criteria {
 		if cr.GetOpenPorts().Matches(port) {
@@ -202,7 +229,7 @@ func (pa *pollAccounter) watchForProcessEvents(ctx context.Context, log *slog.Lo
 				pa.bpfWatcherIsReady()
 			case watcher.NewPort:
 				port := int(e.Payload)
-				if pa.cfg.Port.Matches(port) || portOfInterest(pa.findingCriteria, port) {
+				if pa.isPortOfInterest(port) {
 					pa.refetchPorts()
 				}
 			default:
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"unsafe"
+
+	trace2 "go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+// https://www.rabbitmq.com/resources/specs/amqp0-9-1.pdf
+const (
+	amqpFrameMethod    = 1
+	amqpFrameHeader    = 2
+	amqpFrameBody      = 3
+	amqpFrameHeartbeat = 8
+
+	amqpFrameHeaderLen = 7
+	amqpFrameEnd       = 0xCE
+	// frame_max is negotiated per connection (RabbitMQ defaults to 128KB). Frames
+	// announcing more than 16MB are assumed not to be AMQP
+	amqpMaxFrameSize = 1 << 24
+
+	amqpClassBasic = 60
+
+	amqpBasicPublish = 40
+	amqpBasicDeliver = 60
+	amqpBasicGet     = 70
+	amqpBasicGetOk   = 71
+)
+
+var amqpProtocolHeader = []byte("AMQP\x00\x00\x09\x01")
+
+type AMQPInfo struct {
+	Operation  string
+	Exchange   string
+	RoutingKey string
+	Queue      string
+}
+
+// DestinationName follows the RabbitMQ semantic conventions, joining the non-empty
+// exchange, routing key and queue names with a colon.
+func (a *AMQPInfo) DestinationName() string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{a.Exchange, a.RoutingKey, a.Queue} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// amqpInfoFromEvent returns the AMQP publish or consume operation carried by the TCP event,
+// or nil if the buffers don't contain a Basic.Publish, Basic.Deliver or Basic.Get-Ok method frame.
+// Only the client side of the connection is reported: publications must be sent by the traced
+// process, and deliveries must be received by it.
+func amqpInfoFromEvent(event *TCPRequestInfo, requestBuffer, responseBuffer []byte) *AMQPInfo {
+	sent, received := requestBuffer, responseBuffer
+	if event.Direction == 0 {
+		// the broker talked first, so the request buffer holds what the process received
+		sent, received = received, sent
+	}
+
+	info := amqpClientInfo(sent, received)
+	if info == nil {
+		return nil
+	}
+
+	if event.Direction == 0 {
+		// We've caught the event reversed, as the broker started the exchange
+		// by pushing a message, let's reverse the event
+		reverseTCPEvent(event)
+	}
+
+	return info
+}
+
+func amqpClientInfo(sent, received []byte) *AMQPInfo {
+	if method, args, ok := findAMQPMethod(sent, amqpBasicPublish, amqpBasicGet); ok {
+		switch method {
+		case amqpBasicPublish:
+			return parseAMQPPublish(args)
+		case amqpBasicGet:
+			return parseAMQPGet(args, received)
+		}
+	}
+
+	if _, args, ok := findAMQPMethod(received, amqpBasicDeliver); ok {
+		return parseAMQPDeliver(args)
+	}
+
+	return nil
+}
+
+// findAMQPMethod walks the AMQP frames in the buffer and returns the arguments of the
+// first Basic class method frame matching any of the provided method IDs. The last frame
+// might have been truncated by the capture, in which case its arguments are truncated too.
+func findAMQPMethod(buf []byte, methods ...uint16) (uint16, []byte, bool) {
+	buf = bytes.TrimPrefix(buf, amqpProtocolHeader)
+
+	for len(buf) >= amqpFrameHeaderLen {
+		frameType := buf[0]
+		size := int(binary.BigEndian.Uint32(buf[3:7]))
+		if !isAMQPFrameType(frameType) || size > amqpMaxFrameSize {
+			return 0, nil, false
+		}
+
+		end := amqpFrameHeaderLen + size
+		if end < len(buf) && buf[end] != amqpFrameEnd {
+			return 0, nil, false
+		}
+
+		payload := buf[amqpFrameHeaderLen:min(end, len(buf))]
+		if frameType == amqpFrameMethod && len(payload) >= 4 &&
+			binary.BigEndian.Uint16(payload[0:2]) == amqpClassBasic {
+			method := binary.BigEndian.Uint16(payload[2:4])
+			for _, m := range methods {
+				if m == method {
+					return method, payload[4:], true
+				}
+			}
+		}
+
+		if end >= len(buf) {
+			break
+		}
+		buf = buf[end+1:]
+	}
+
+	return 0, nil, false
+}
+
+func isAMQPFrameType(t uint8) bool {
+	switch t {
+	case amqpFrameMethod, amqpFrameHeader, amqpFrameBody, amqpFrameHeartbeat:
+		return true
+	}
+	return false
+}
+
+// readAMQPShortString reads an AMQP shortstr (one byte length, followed by the string)
+// and returns the string and the remaining buffer
+func readAMQPShortString(buf []byte) (string, []byte, bool) {
+	if len(buf) < 1 {
+		return "", nil, false
+	}
+	l := int(buf[0])
+	if len(buf) < 1+l {
+		return "", nil, false
+	}
+	s := buf[1 : 1+l]
+	for _, c := range s {
+		if c < ' ' || c == 0x7f {
+			return "", nil, false
+		}
+	}
+	return string(s), buf[1+l:], true
+}
+
+// readAMQPExchangeAndRoutingKey reads the exchange and routing-key shortstr pair
+// that is common to the Publish, Deliver and Get-Ok methods.
+func readAMQPExchangeAndRoutingKey(buf []byte, info *AMQPInfo) bool {
+	var ok bool
+	if info.Exchange, buf, ok = readAMQPShortString(buf); !ok {
+		return false
+	}
+	info.RoutingKey, _, ok = readAMQPShortString(buf)
+	return ok
+}
+
+// Basic.Publish: reserved-1 (short), exchange (shortstr), routing-key (shortstr), mandatory/immediate (bits)
+func parseAMQPPublish(args []byte) *AMQPInfo {
+	if len(args) < 2 {
+		return nil
+	}
+	info := &AMQPInfo{Operation: request.MessagingPublish}
+	if !readAMQPExchangeAndRoutingKey(args[2:], info) {
+		return nil
+	}
+	return info
+}
+
+// Basic.Deliver: consumer-tag (shortstr), delivery-tag (longlong), redelivered (bit),
+// exchange (shortstr), routing-key (shortstr)
+func parseAMQPDeliver(args []byte) *AMQPInfo {
+	_, args, ok := readAMQPShortString(args)
+	if !ok || len(args) < 9 {
+		return nil
+	}
+	info := &AMQPInfo{Operation: request.MessagingProcess}
+	if !readAMQPExchangeAndRoutingKey(args[9:], info) {
+		return nil
+	}
+	return info
+}
+
+// Basic.Get: reserved-1 (short), queue (shortstr), no-ack (bit)
+// Basic.Get-Ok: delivery-tag (longlong), redelivered (bit), exchange (shortstr),
+// routing-key (shortstr), message-count (long)
+// A Basic.Get answered with Get-Empty didn't fetch any message, so it is not reported.
+func parseAMQPGet(args []byte, received []byte) *AMQPInfo {
+	if len(args) < 2 {
+		return nil
+	}
+	queue, _, ok := readAMQPShortString(args[2:])
+	if !ok {
+		return nil
+	}
+
+	_, okArgs, ok := findAMQPMethod(received, amqpBasicGetOk)
+	if !ok || len(okArgs) < 9 {
+		return nil
+	}
+	info := &AMQPInfo{Operation: request.MessagingProcess, Queue: queue}
+	if !readAMQPExchangeAndRoutingKey(okArgs[9:], info) {
+		return nil
+	}
+	return info
+}
+
+func TCPToAMQPToSpan(trace *TCPRequestInfo, data *AMQPInfo) request.Span {
+	peer := ""
+	hostname := ""
+	hostPort := 0
+
+	if trace.ConnInfo.S_port != 0 || trace.ConnInfo.D_port != 0 {
+		peer, hostname = (*BPFConnInfo)(unsafe.Pointer(&trace.ConnInfo)).reqHostInfo()
+		hostPort = int(trace.ConnInfo.D_port)
+	}
+
+	return request.Span{
+		Type:          request.EventTypeAMQPClient,
+		Method:        data.Operation,
+		Path:          data.DestinationName(),
+		Statement:     data.RoutingKey,
+		Peer:          peer,
+		PeerPort:      int(trace.ConnInfo.S_port),
+		Host:          hostname,
+		HostPort:      hostPort,
+		ContentLength: 0,
+		RequestStart:  int64(trace.StartMonotimeNs),
+		Start:         int64(trace.StartMonotimeNs),
+		End:           int64(trace.EndMonotimeNs),
+		Status:        0,
+		TraceID:       trace2.TraceID(trace.Tp.TraceId),
+		SpanID:        trace2.SpanID(trace.Tp.SpanId),
+		ParentSpanID:  trace2.SpanID(trace.Tp.ParentId),
+		TraceFlags:    trace.Tp.Flags,
+		Pid: request.PidInfo{
+			HostPID:   trace.Pid.HostPid,
+			UserPID:   trace.Pid.UserPid,
+			Namespace: trace.Pid.Ns,
+		},
+	}
+}
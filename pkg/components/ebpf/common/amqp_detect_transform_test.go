@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+func amqpShortString(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
+
+func amqpFrame(frameType uint8, payload []byte) []byte {
+	frame := []byte{frameType, 0, 1, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(frame[3:7], uint32(len(payload)))
+	frame = append(frame, payload...)
+	return append(frame, amqpFrameEnd)
+}
+
+func amqpBasicMethod(method uint16, args ...[]byte) []byte {
+	payload := []byte{0, amqpClassBasic, 0, 0}
+	binary.BigEndian.PutUint16(payload[2:4], method)
+	for _, a := range args {
+		payload = append(payload, a...)
+	}
+	return amqpFrame(amqpFrameMethod, payload)
+}
+
+func amqpPublishFrames(exchange, routingKey string) []byte {
+	buf := amqpBasicMethod(amqpBasicPublish, []byte{0, 0}, amqpShortString(exchange), amqpShortString(routingKey), []byte{0})
+	// content header and body
+	buf = append(buf, amqpFrame(amqpFrameHeader, make([]byte, 14))...)
+	return append(buf, amqpFrame(amqpFrameBody, []byte("hello"))...)
+}
+
+func amqpDeliverFrames(exchange, routingKey string) []byte {
+	return amqpBasicMethod(amqpBasicDeliver, amqpShortString("ctag-1"), make([]byte, 8), []byte{0},
+		amqpShortString(exchange), amqpShortString(routingKey))
+}
+
+func TestAMQPDestinationName(t *testing.T) {
+	assert.Equal(t, "orders:created", (&AMQPInfo{Exchange: "orders", RoutingKey: "created"}).DestinationName())
+	assert.Equal(t, "tasks", (&AMQPInfo{RoutingKey: "tasks"}).DestinationName())
+	assert.Equal(t, "orders:created:billing", (&AMQPInfo{Exchange: "orders", RoutingKey: "created", Queue: "billing"}).DestinationName())
+	assert.Empty(t, (&AMQPInfo{}).DestinationName())
+}
+
+func TestAMQPClientInfo(t *testing.T) {
+	heartbeat := amqpFrame(amqpFrameHeartbeat, nil)
+
+	tests := []struct {
+		name     string
+		sent     []byte
+		received []byte
+		expected *AMQPInfo
+	}{
+		{
+			name:     "publish",
+			sent:     amqpPublishFrames("orders", "created"),
+			expected: &AMQPInfo{Operation: request.MessagingPublish, Exchange: "orders", RoutingKey: "created"},
+		},
+		{
+			name:     "publish after heartbeat",
+			sent:     append(append([]byte{}, heartbeat...), amqpPublishFrames("", "tasks")...),
+			received: heartbeat,
+			expected: &AMQPInfo{Operation: request.MessagingPublish, RoutingKey: "tasks"},
+		},
+		{
+			name:     "publish after protocol header",
+			sent:     append(append([]byte{}, amqpProtocolHeader...), amqpPublishFrames("orders", "created")...),
+			expected: &AMQPInfo{Operation: request.MessagingPublish, Exchange: "orders", RoutingKey: "created"},
+		},
+		{
+			name:     "publish with truncated body",
+			sent:     amqpPublishFrames("orders", "created")[:40],
+			expected: &AMQPInfo{Operation: request.MessagingPublish, Exchange: "orders", RoutingKey: "created"},
+		},
+		{
+			name:     "deliver",
+			received: amqpDeliverFrames("orders", "created"),
+			expected: &AMQPInfo{Operation: request.MessagingProcess, Exchange: "orders", RoutingKey: "created"},
+		},
+		{
+			name:     "get",
+			sent:     amqpBasicMethod(amqpBasicGet, []byte{0, 0}, amqpShortString("billing"), []byte{0}),
+			received: amqpBasicMethod(amqpBasicGetOk, make([]byte, 8), []byte{0}, amqpShortString("orders"), amqpShortString("created"), make([]byte, 4)),
+			expected: &AMQPInfo{Operation: request.MessagingProcess, Exchange: "orders", RoutingKey: "created", Queue: "billing"},
+		},
+		{
+			name:     "get empty",
+			sent:     amqpBasicMethod(amqpBasicGet, []byte{0, 0}, amqpShortString("billing"), []byte{0}),
+			received: amqpBasicMethod(72, amqpShortString("")),
+		},
+		{
+			name:     "received publish is ignored",
+			received: amqpPublishFrames("orders", "created"),
+		},
+		{
+			name: "sent deliver is ignored",
+			sent: amqpDeliverFrames("orders", "created"),
+		},
+		{
+			name: "bad frame end",
+			sent: append(amqpBasicMethod(amqpBasicPublish, []byte{0, 0}, amqpShortString("orders"), amqpShortString("created"))[:20], 0xff, 0x01),
+		},
+		{
+			name: "unknown frame type",
+			sent: []byte{0x05, 0, 1, 0, 0, 0, 4, 0, 60, 0, 40, amqpFrameEnd},
+		},
+		{
+			name:     "http",
+			sent:     []byte("GET /index.html HTTP/1.1\r\n"),
+			received: []byte("HTTP/1.1 200 OK\r\n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, amqpClientInfo(tt.sent, tt.received))
+		})
+	}
+}
+
+func TestAMQPInfoFromEvent(t *testing.T) {
+	publish := amqpPublishFrames("orders", "created")
+	deliver := amqpDeliverFrames("orders", "created")
+
+	t.Run("publish", func(t *testing.T) {
+		event := makeTCPReq(string(publish), 1, 33000, 5672, 1)
+		info := amqpInfoFromEvent(&event, publish, nil)
+		require.NotNil(t, info)
+
+		span := TCPToAMQPToSpan(&event, info)
+		assert.Equal(t, request.EventTypeAMQPClient, span.Type)
+		assert.Equal(t, request.MessagingPublish, span.Method)
+		assert.Equal(t, "orders:created", span.Path)
+		assert.Equal(t, "created", span.Statement)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 5672, span.HostPort)
+	})
+
+	t.Run("deliver pushed by the broker", func(t *testing.T) {
+		event := makeTCPReq(string(deliver), 0, 5672, 33000, 1)
+		info := amqpInfoFromEvent(&event, deliver, nil)
+		require.NotNil(t, info)
+
+		span := TCPToAMQPToSpan(&event, info)
+		assert.Equal(t, request.MessagingProcess, span.Method)
+		assert.Equal(t, "orders:created", span.Path)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 5672, span.HostPort)
+	})
+
+	t.Run("broker side is ignored", func(t *testing.T) {
+		event := makeTCPReq(string(publish), 0, 33000, 5672, 1)
+		assert.Nil(t, amqpInfoFromEvent(&event, publish, nil))
+		assert.Equal(t, uint16(33000), event.ConnInfo.S_port)
+	})
+}
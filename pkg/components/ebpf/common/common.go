@@ -27,7 +27,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/config"
 )
 
-//go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -target amd64,arm64 -type http_request_trace -type sql_request_trace -type http_info_t -type connection_info_t -type http2_grpc_request_t -type tcp_req_t -type kafka_client_req_t -type kafka_go_req_t -type redis_client_req_t -type tcp_large_buffer_t -type otel_span_t Bpf ../../../../bpf/common/common.c -- -I../../../../bpf
+//go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -target amd64,arm64 -type http_request_trace -type sql_request_trace -type http_info_t -type connection_info_t -type http2_grpc_request_t -type tcp_req_t -type kafka_client_req_t -type kafka_go_req_t -type redis_client_req_t -type tcp_large_buffer_t -type otel_span_t -type dns_req_t -type mongo_go_client_req_t -type go_runtime_event_t Bpf ../../../../bpf/common/common.c -- -I../../../../bpf
 
 // HTTPRequestTrace contains information from an HTTP request as directly received from the
 // eBPF layer. This contains low-level C structures for accurate binary read from ring buffer.
@@ -42,6 +42,9 @@ type (
 	GoKafkaGoClientInfo  BpfKafkaGoReqT
 	TCPLargeBufferHeader BpfTcpLargeBufferT
 	GoOTelSpanTrace      BpfOtelSpanT
+	DNSRequestInfo       BpfDnsReqT
+	GoMongoClientInfo    BpfMongoGoClientReqT
+	GoRuntimeEvent       BpfGoRuntimeEventT
 )
 
 const (
@@ -54,6 +57,9 @@ const (
 	EventTypeGoKafkaGo      = 11 // Kafka-Go client from Segment-io
 	EventTypeTCPLargeBuffer = 12 // Dynamically sized TCP buffers
 	EventOTelSDKGo          = 13 // OTel SDK manual span
+	EventTypeDNS            = 14 // EVENT_DNS_CLIENT
+	EventTypeGoMongo        = 15 // MongoDB client for Go
+	EventTypeGoRuntime      = 16 // Go runtime GC pauses and scheduling latencies
 
 )
 
@@ -120,13 +126,15 @@ type MisclassifiedEvent struct {
 }
 
 type EBPFParseContext struct {
-	h2c                        *lru.Cache[uint64, h2Connection]
-	redisDBCache               *simplelru.LRU[BpfConnectionInfoT, int]
-	largeBuffers               *expirable.LRU[largeBufferKey, *largeBuffer]
-	mongoRequestCache          PendingMongoDBRequests
-	mysqlPreparedStatements    *simplelru.LRU[mysqlPreparedStatementsKey, string]
-	postgresPreparedStatements *simplelru.LRU[postgresPreparedStatementsKey, string]
-	postgresPortals            *simplelru.LRU[postgresPortalsKey, string]
+	h2c                         *lru.Cache[uint64, h2Connection]
+	redisDBCache                *simplelru.LRU[BpfConnectionInfoT, int]
+	largeBuffers                *expirable.LRU[largeBufferKey, *largeBuffer]
+	mongoRequestCache           PendingMongoDBRequests
+	mysqlPreparedStatements     *simplelru.LRU[mysqlPreparedStatementsKey, string]
+	postgresPreparedStatements  *simplelru.LRU[postgresPreparedStatementsKey, string]
+	postgresPortals             *simplelru.LRU[postgresPortalsKey, string]
+	cassandraPreparedStatements *simplelru.LRU[cassandraPreparedStatementsKey, cassandraPreparedStatement]
+	cassandraKeyspaces          *simplelru.LRU[BpfConnectionInfoT, string]
 }
 
 type EBPFEventContext struct {
@@ -144,12 +152,14 @@ func ptlog() *slog.Logger { return slog.With("component", "ebpf.ProcessTracer")
 
 func NewEBPFParseContext(cfg *config.EBPFTracer) *EBPFParseContext {
 	var (
-		err                        error
-		redisDBCache               *simplelru.LRU[BpfConnectionInfoT, int]
-		mysqlPreparedStatements    *simplelru.LRU[mysqlPreparedStatementsKey, string]
-		postgresPreparedStatements *simplelru.LRU[postgresPreparedStatementsKey, string]
-		postgresPortals            *simplelru.LRU[postgresPortalsKey, string]
-		mongoRequestCache          PendingMongoDBRequests
+		err                         error
+		redisDBCache                *simplelru.LRU[BpfConnectionInfoT, int]
+		mysqlPreparedStatements     *simplelru.LRU[mysqlPreparedStatementsKey, string]
+		postgresPreparedStatements  *simplelru.LRU[postgresPreparedStatementsKey, string]
+		postgresPortals             *simplelru.LRU[postgresPortalsKey, string]
+		mongoRequestCache           PendingMongoDBRequests
+		cassandraPreparedStatements *simplelru.LRU[cassandraPreparedStatementsKey, cassandraPreparedStatement]
+		cassandraKeyspaces          *simplelru.LRU[BpfConnectionInfoT, string]
 	)
 
 	h2c, _ := lru.New[uint64, h2Connection](1024 * 10)
@@ -179,17 +189,29 @@ func NewEBPFParseContext(cfg *config.EBPFTracer) *EBPFParseContext {
 			ptlog().Error("failed to create Postgres portals cache", "error", err)
 		}
 
+		cassandraPreparedStatements, err = simplelru.NewLRU[cassandraPreparedStatementsKey, cassandraPreparedStatement](cfg.CassandraPreparedStatementsCacheSize, nil)
+		if err != nil {
+			ptlog().Error("failed to create Cassandra prepared statements cache", "error", err)
+		}
+
+		cassandraKeyspaces, err = simplelru.NewLRU[BpfConnectionInfoT, string](cfg.CassandraKeyspacesCacheSize, nil)
+		if err != nil {
+			ptlog().Error("failed to create Cassandra keyspaces cache", "error", err)
+		}
+
 		mongoRequestCache = expirable.NewLRU[MongoRequestKey, *MongoRequestValue](cfg.MongoRequestsCacheSize, nil, 0)
 	}
 
 	return &EBPFParseContext{
-		h2c:                        h2c,
-		redisDBCache:               redisDBCache,
-		largeBuffers:               largeBuffers,
-		mongoRequestCache:          mongoRequestCache,
-		mysqlPreparedStatements:    mysqlPreparedStatements,
-		postgresPreparedStatements: postgresPreparedStatements,
-		postgresPortals:            postgresPortals,
+		h2c:                         h2c,
+		redisDBCache:                redisDBCache,
+		largeBuffers:                largeBuffers,
+		mongoRequestCache:           mongoRequestCache,
+		mysqlPreparedStatements:     mysqlPreparedStatements,
+		postgresPreparedStatements:  postgresPreparedStatements,
+		postgresPortals:             postgresPortals,
+		cassandraPreparedStatements: cassandraPreparedStatements,
+		cassandraKeyspaces:          cassandraKeyspaces,
 	}
 }
 
@@ -228,6 +250,12 @@ func ReadBPFTraceAsSpan(parseCtx *EBPFParseContext, cfg *config.EBPFTracer, reco
 		return appendTCPLargeBuffer(parseCtx, record)
 	case EventOTelSDKGo:
 		return ReadGoOTelEventIntoSpan(record)
+	case EventTypeDNS:
+		return ReadDNSRequestIntoSpan(record, filter)
+	case EventTypeGoMongo:
+		return ReadGoMongoRequestIntoSpan(record)
+	case EventTypeGoRuntime:
+		return ReadGoRuntimeEventIntoSpan(record)
 	}
 
 	event, err := ReinterpretCast[HTTPRequestTrace](record.RawSample)
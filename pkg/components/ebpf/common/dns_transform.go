@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"unsafe"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
+	"go.opentelemetry.io/obi/pkg/components/rdns/dnsmsg"
+)
+
+func ReadDNSRequestIntoSpan(record *ringbuf.Record, filter ServiceFilter) (request.Span, bool, error) {
+	event, err := ReinterpretCast[DNSRequestInfo](record.RawSample)
+	if err != nil {
+		return request.Span{}, true, err
+	}
+
+	// DNS lookups are only tracked by kprobes, so they are also reported for the
+	// processes that are instrumented by the Go tracer
+	if !filter.ValidPID(event.Pid.UserPid, event.Pid.Ns, PIDTypeKProbes) &&
+		!filter.ValidPID(event.Pid.UserPid, event.Pid.Ns, PIDTypeGo) {
+		return request.Span{}, true, nil
+	}
+
+	size := min(int(event.Len), len(event.Buf))
+	msg := dnsmsg.Parse(event.Buf[:size])
+
+	// we only report responses to queries, with at least a question
+	if msg == nil || !msg.IsResponse() {
+		return request.Span{}, true, nil
+	}
+
+	return DNSRequestToSpan(event, msg), false, nil
+}
+
+func DNSRequestToSpan(event *DNSRequestInfo, msg *dnsmsg.Message) request.Span {
+	peer, hostname := (*BPFConnInfo)(unsafe.Pointer(&event.Conn)).reqHostInfo()
+	q := msg.Questions[0]
+
+	return request.Span{
+		Type:          request.EventTypeDNSClient,
+		Method:        dnsmsg.TypeName(q.Type),
+		Path:          q.Name,
+		Peer:          peer,
+		PeerPort:      int(event.Conn.S_port),
+		Host:          hostname,
+		HostPort:      int(event.Conn.D_port),
+		ContentLength: int64(event.Len),
+		RequestStart:  int64(event.StartMonotimeNs),
+		Start:         int64(event.StartMonotimeNs),
+		End:           int64(event.EndMonotimeNs),
+		Status:        int(msg.RCode()),
+		Pid: request.PidInfo{
+			HostPID:   event.Pid.HostPid,
+			UserPID:   event.Pid.UserPid,
+			Namespace: event.Pid.Ns,
+		},
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
+	"go.opentelemetry.io/obi/pkg/components/exec"
+	"go.opentelemetry.io/obi/pkg/components/imetrics"
+	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/services"
+)
+
+func dnsMessageBytes(flags uint16, name string, qtype uint16) []byte {
+	b := binary.BigEndian.AppendUint16(nil, 0x1234)
+	b = binary.BigEndian.AppendUint16(b, flags)
+	// one question, no other records
+	b = append(b, 0, 1, 0, 0, 0, 0, 0, 0)
+	for _, label := range bytes.Split([]byte(name), []byte(".")) {
+		b = append(b, uint8(len(label)))
+		b = append(b, label...)
+	}
+	b = append(b, 0)
+	b = binary.BigEndian.AppendUint16(b, qtype)
+	return binary.BigEndian.AppendUint16(b, 1)
+}
+
+func makeDNSRecord(t *testing.T, msg []byte) *ringbuf.Record {
+	event := DNSRequestInfo{
+		Type:            EventTypeDNS,
+		Len:             uint16(len(msg)),
+		StartMonotimeNs: 1_000_000,
+		EndMonotimeNs:   3_000_000,
+	}
+	copy(event.Buf[:], msg)
+	event.Conn.S_addr = [16]uint8{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 10, 0, 0, 5}
+	event.Conn.S_port = 41000
+	event.Conn.D_addr = [16]uint8{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 10, 96, 0, 10}
+	event.Conn.D_port = 53
+	event.Pid.HostPid = 1234
+	event.Pid.UserPid = 1234
+
+	rec := bytes.Buffer{}
+	require.NoError(t, binary.Write(&rec, binary.LittleEndian, event))
+	return &ringbuf.Record{RawSample: rec.Bytes()}
+}
+
+func TestReadDNSRequestIntoSpan(t *testing.T) {
+	fltr := TestPidsFilter{services: map[uint32]svc.Attrs{}}
+
+	t.Run("successful lookup", func(t *testing.T) {
+		span, ignore, err := ReadDNSRequestIntoSpan(makeDNSRecord(t, dnsMessageBytes(0x8180, "example.com", 1)), &fltr)
+		require.NoError(t, err)
+		require.False(t, ignore)
+
+		assert.Equal(t, request.EventTypeDNSClient, span.Type)
+		assert.Equal(t, "A", span.Method)
+		assert.Equal(t, "example.com", span.Path)
+		assert.Equal(t, 0, span.Status)
+		assert.Equal(t, "10.0.0.5", span.Peer)
+		assert.Equal(t, 41000, span.PeerPort)
+		assert.Equal(t, "10.96.0.10", span.Host)
+		assert.Equal(t, 53, span.HostPort)
+		assert.Equal(t, int64(2_000_000), span.End-span.Start)
+		assert.Equal(t, uint32(1234), span.Pid.HostPID)
+		assert.Equal(t, "DNS A", span.TraceName())
+		assert.Equal(t, request.StatusCodeUnset, request.SpanStatusCode(&span))
+	})
+
+	t.Run("failed lookup", func(t *testing.T) {
+		span, ignore, err := ReadDNSRequestIntoSpan(makeDNSRecord(t, dnsMessageBytes(0x8183, "missing.local", 28)), &fltr)
+		require.NoError(t, err)
+		require.False(t, ignore)
+
+		assert.Equal(t, "AAAA", span.Method)
+		assert.Equal(t, "missing.local", span.Path)
+		assert.Equal(t, 3, span.Status)
+		assert.Equal(t, request.StatusCodeError, request.SpanStatusCode(&span))
+		assert.Equal(t, "NXDOMAIN", request.SpanStatusMessage(&span))
+	})
+
+	t.Run("query instead of response", func(t *testing.T) {
+		_, ignore, err := ReadDNSRequestIntoSpan(makeDNSRecord(t, dnsMessageBytes(0x0100, "example.com", 1)), &fltr)
+		require.NoError(t, err)
+		assert.True(t, ignore)
+	})
+
+	t.Run("not dns", func(t *testing.T) {
+		_, ignore, err := ReadDNSRequestIntoSpan(makeDNSRecord(t, []byte("HTTP/1.1 200 OK\r\n\r\n")), &fltr)
+		require.NoError(t, err)
+		assert.True(t, ignore)
+	})
+
+	t.Run("processes instrumented by the Go tracer", func(t *testing.T) {
+		readNamespacePIDs = func(pid int32) ([]uint32, error) {
+			return []uint32{uint32(pid)}, nil
+		}
+		t.Cleanup(func() { readNamespacePIDs = exec.FindNamespacedPids })
+		pf := newPIDsFilter(&services.DiscoveryConfig{}, slog.With("env", "testing"), &imetrics.NoopReporter{})
+		record := makeDNSRecord(t, dnsMessageBytes(0x8180, "example.com", 1))
+
+		_, ignore, err := ReadDNSRequestIntoSpan(record, pf)
+		require.NoError(t, err)
+		assert.True(t, ignore)
+
+		pf.AllowPID(1234, 0, &svc.Attrs{}, PIDTypeGo)
+		span, ignore, err := ReadDNSRequestIntoSpan(record, pf)
+		require.NoError(t, err)
+		require.False(t, ignore)
+		assert.Equal(t, "example.com", span.Path)
+	})
+}
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
+)
+
+// kinds of Go runtime events, as defined in the go_runtime_event_kind enum of bpf/common/common.h
+const (
+	goRuntimeGCPause      = 1
+	goRuntimeSchedLatency = 2
+)
+
+func ReadGoRuntimeEventIntoSpan(record *ringbuf.Record) (request.Span, bool, error) {
+	event, err := ReinterpretCast[GoRuntimeEvent](record.RawSample)
+	if err != nil {
+		return request.Span{}, true, err
+	}
+
+	var eventType request.EventType
+	switch event.Kind {
+	case goRuntimeGCPause:
+		eventType = request.EventTypeGoGCPause
+	case goRuntimeSchedLatency:
+		eventType = request.EventTypeGoScheduleLatency
+	default:
+		return request.Span{}, true, nil
+	}
+
+	return request.Span{
+		Type:         eventType,
+		RequestStart: int64(event.StartMonotimeNs),
+		Start:        int64(event.StartMonotimeNs),
+		End:          int64(event.EndMonotimeNs),
+		Pid: request.PidInfo{
+			HostPID:   event.Pid.HostPid,
+			UserPID:   event.Pid.UserPid,
+			Namespace: event.Pid.Ns,
+		},
+	}, false, nil
+}
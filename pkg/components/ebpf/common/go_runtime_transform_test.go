@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
+)
+
+func makeGoRuntimeRecord(t *testing.T, kind uint8) *ringbuf.Record {
+	event := GoRuntimeEvent{
+		Type:            EventTypeGoRuntime,
+		Kind:            kind,
+		StartMonotimeNs: 1_000_000,
+		EndMonotimeNs:   1_250_000,
+	}
+	event.Pid.HostPid = 1234
+	event.Pid.UserPid = 1234
+
+	rec := bytes.Buffer{}
+	require.NoError(t, binary.Write(&rec, binary.LittleEndian, event))
+	return &ringbuf.Record{RawSample: rec.Bytes()}
+}
+
+func TestReadGoRuntimeEventIntoSpan(t *testing.T) {
+	span, ignore, err := ReadGoRuntimeEventIntoSpan(makeGoRuntimeRecord(t, goRuntimeGCPause))
+	require.NoError(t, err)
+	require.False(t, ignore)
+	assert.Equal(t, request.EventTypeGoGCPause, span.Type)
+	assert.Equal(t, int64(1_000_000), span.RequestStart)
+	assert.Equal(t, int64(1_250_000), span.End)
+	assert.Equal(t, uint32(1234), span.Pid.HostPID)
+	assert.True(t, span.GoRuntimeSignal())
+
+	span, ignore, err = ReadGoRuntimeEventIntoSpan(makeGoRuntimeRecord(t, goRuntimeSchedLatency))
+	require.NoError(t, err)
+	require.False(t, ignore)
+	assert.Equal(t, request.EventTypeGoScheduleLatency, span.Type)
+
+	_, ignore, err = ReadGoRuntimeEventIntoSpan(makeGoRuntimeRecord(t, 42))
+	require.NoError(t, err)
+	assert.True(t, ignore)
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+
+	"golang.org/x/net/http2"
+
+	"go.opentelemetry.io/obi/pkg/components/ebpf/bhpack"
+)
+
+// Application-layer protocols, as returned by ClassifyPayload
+const (
+	L7ProtocolHTTP      = "http"
+	L7ProtocolHTTP2     = "http2"
+	L7ProtocolGRPC      = "grpc"
+	L7ProtocolKafka     = "kafka"
+	L7ProtocolPostgres  = "postgresql"
+	L7ProtocolMySQL     = "mysql"
+	L7ProtocolRedis     = "redis"
+	L7ProtocolMongo     = "mongodb"
+	L7ProtocolCassandra = "cassandra"
+)
+
+const (
+	http2Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+	// the first bytes of a Postgres StartupMessage or SSLRequest are the message length,
+	// followed by the protocol version or the SSL request code
+	postgresStartupMinLen = 8
+	postgresProtocolV3    = 196608
+	postgresSSLRequest    = 80877103
+
+	// the initial handshake packet from a MySQL server starts with the protocol version 10
+	mysqlHandshakeV10 = 0x0a
+)
+
+var http1Prefixes = []string{
+	"GET ", "POST ", "PUT ", "PATCH ", "DELETE ", "HEAD ", "OPTIONS ", "CONNECT ", "TRACE ", "HTTP/1.",
+}
+
+// ClassifyPayload guesses the application-layer protocol of a network flow from the
+// first bytes of its payload. It returns an empty string if the protocol is unknown.
+// It relies on the same heuristics that the generic TCP tracer uses to detect the
+// protocol of the captured requests, plus the detection of the connection
+// handshakes, as the payload might correspond to the first message of a connection.
+func ClassifyPayload(buf []byte) string {
+	switch {
+	case isHTTP1(buf):
+		return L7ProtocolHTTP
+	case bytes.HasPrefix(buf, []byte(http2Preface)):
+		if isGRPC(buf[len(http2Preface):]) {
+			return L7ProtocolGRPC
+		}
+		return L7ProtocolHTTP2
+	case isHTTP2(buf, len(buf)):
+		if isGRPC(buf) {
+			return L7ProtocolGRPC
+		}
+		return L7ProtocolHTTP2
+	case isPostgresStartup(buf) || isPostgres(buf):
+		return L7ProtocolPostgres
+	case isMySQLHandshake(buf) || isMySQL(buf):
+		return L7ProtocolMySQL
+	case isCQLRequest(buf):
+		return L7ProtocolCassandra
+	case isMongo(buf):
+		return L7ProtocolMongo
+	case isKafka(buf):
+		return L7ProtocolKafka
+	case isRedis(buf):
+		return L7ProtocolRedis
+	}
+	return ""
+}
+
+func isHTTP1(buf []byte) bool {
+	for _, prefix := range http1Prefixes {
+		if bytes.HasPrefix(buf, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGRPC returns true if any of the HTTP2 headers frames in the buffer has a gRPC content type
+// or a gRPC status. Since the buffer might not start at the beginning of the connection,
+// a new header decoder is used, so only the literal (non-indexed) headers can be read.
+func isGRPC(buf []byte) bool {
+	grpc := false
+	hdec := bhpack.NewDecoder(initialHeaderTableSize, nil)
+	hdec.SetEmitFunc(func(hf bhpack.HeaderField) {
+		switch strings.ToLower(hf.Name) {
+		case "content-type":
+			grpc = grpc || strings.HasPrefix(hf.Value, "application/grpc")
+		case "grpc-status":
+			grpc = true
+		}
+	})
+	framer := byteFramer(buf)
+	for !grpc {
+		f, err := framer.ReadFrame()
+		if err != nil {
+			break
+		}
+		if hf, ok := f.(*http2.HeadersFrame); ok {
+			// errors are ignored, as the headers block might be truncated
+			_, _ = hdec.Write(hf.HeaderBlockFragment())
+		}
+	}
+	return grpc
+}
+
+func isPostgresStartup(buf []byte) bool {
+	if len(buf) < postgresStartupMinLen {
+		return false
+	}
+	size := binary.BigEndian.Uint32(buf)
+	code := binary.BigEndian.Uint32(buf[4:])
+	return size >= postgresStartupMinLen && size <= 10000 &&
+		(code == postgresProtocolV3 || code == postgresSSLRequest)
+}
+
+func isMySQLHandshake(buf []byte) bool {
+	// 3 bytes of length, sequence id 0 and protocol version
+	if len(buf) < 5 || buf[3] != 0 || buf[4] != mysqlHandshakeV10 {
+		return false
+	}
+	size := int(buf[0]) | int(buf[1])<<8 | int(buf[2])<<16
+	return size > 0 && size < 1024
+}
+
+func isMongo(buf []byte) bool {
+	if len(buf) < msgHeaderSize {
+		return false
+	}
+	header, err := parseMongoHeader(buf)
+	return err == nil && header.OpCode == opMsg
+}
+
+func isKafka(buf []byte) bool {
+	if len(buf) < KafkaMinLength {
+		return false
+	}
+	_, err := parseKafkaHeader(buf)
+	return err == nil
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+func TestClassifyPayload(t *testing.T) {
+	kafkaProduce := make([]byte, 20)
+	binary.BigEndian.PutUint32(kafkaProduce[0:], 100) // message size
+	binary.BigEndian.PutUint16(kafkaProduce[4:], 0)   // api key: produce
+	binary.BigEndian.PutUint16(kafkaProduce[6:], 3)   // api version
+	binary.BigEndian.PutUint32(kafkaProduce[8:], 1)   // correlation id
+	binary.BigEndian.PutUint16(kafkaProduce[12:], 0)  // client id size
+
+	pgStartup := binary.BigEndian.AppendUint32(nil, 40)
+	pgStartup = binary.BigEndian.AppendUint32(pgStartup, postgresProtocolV3)
+	pgStartup = append(pgStartup, "user\x00postgres\x00"...)
+
+	mongoMsg := binary.LittleEndian.AppendUint32(nil, 40)       // length
+	mongoMsg = binary.LittleEndian.AppendUint32(mongoMsg, 1)    // request id
+	mongoMsg = binary.LittleEndian.AppendUint32(mongoMsg, 0)    // response to
+	mongoMsg = binary.LittleEndian.AppendUint32(mongoMsg, 2013) // OP_MSG
+
+	for _, tc := range []struct {
+		name     string
+		payload  []byte
+		expected string
+	}{
+		{name: "http request", payload: []byte("GET /index.html HTTP/1.1\r\nHost: foo\r\n\r\n"), expected: L7ProtocolHTTP},
+		{name: "http response", payload: []byte("HTTP/1.1 200 OK\r\n"), expected: L7ProtocolHTTP},
+		{name: "http2 preface", payload: append([]byte(http2Preface), h2Settings(t)...), expected: L7ProtocolHTTP2},
+		{name: "grpc preface", payload: append([]byte(http2Preface), h2Headers(t, "application/grpc")...), expected: L7ProtocolGRPC},
+		{name: "grpc headers", payload: h2Headers(t, "application/grpc+proto"), expected: L7ProtocolGRPC},
+		{name: "http2 headers", payload: h2Headers(t, "text/html"), expected: L7ProtocolHTTP2},
+		{name: "kafka", payload: kafkaProduce, expected: L7ProtocolKafka},
+		{name: "postgres startup", payload: pgStartup, expected: L7ProtocolPostgres},
+		{name: "postgres ssl request", payload: []byte{0, 0, 0, 8, 0x04, 0xd2, 0x16, 0x2f}, expected: L7ProtocolPostgres},
+		{name: "postgres query", payload: append([]byte{'Q', 0, 0, 0, 13}, "SELECT 1;\x00"...), expected: L7ProtocolPostgres},
+		{name: "mysql handshake", payload: []byte{0x4a, 0, 0, 0, 0x0a, '8', '.', '0'}, expected: L7ProtocolMySQL},
+		{name: "mongodb", payload: mongoMsg, expected: L7ProtocolMongo},
+		{name: "redis", payload: []byte("*1\r\n$4\r\nPING\r\n"), expected: L7ProtocolRedis},
+		{name: "unknown", payload: []byte{0x16, 0x03, 0x01, 0x02, 0x00, 0x01}, expected: ""},
+		{name: "empty", payload: nil, expected: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ClassifyPayload(tc.payload))
+		})
+	}
+}
+
+func h2Settings(t *testing.T) []byte {
+	buf := &bytes.Buffer{}
+	require.NoError(t, http2.NewFramer(buf, nil).WriteSettings(http2.Setting{ID: http2.SettingMaxFrameSize, Val: 16384}))
+	return buf.Bytes()
+}
+
+func h2Headers(t *testing.T, contentType string) []byte {
+	hbuf := &bytes.Buffer{}
+	enc := hpack.NewEncoder(hbuf)
+	for _, hf := range []hpack.HeaderField{
+		{Name: ":method", Value: "POST"},
+		{Name: ":path", Value: "/foo.Bar/Baz"},
+		{Name: "content-type", Value: contentType},
+	} {
+		require.NoError(t, enc.WriteField(hf))
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, http2.NewFramer(buf, nil).WriteHeaders(http2.HeadersFrameParam{
+		StreamID: 1, BlockFragment: hbuf.Bytes(), EndHeaders: true,
+	}))
+	return buf.Bytes()
+}
@@ -0,0 +1,348 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	trace2 "go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+// https://github.com/memcached/memcached/blob/master/doc/protocol.txt
+// https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped
+const (
+	memcachedBinaryReqMagic  = 0x80
+	memcachedBinaryRespMagic = 0x81
+	memcachedBinaryHdrLen    = 24
+
+	memcachedBinaryStatusOK          = 0x0000
+	memcachedBinaryStatusKeyNotFound = 0x0001
+	memcachedBinaryStatusKeyExists   = 0x0002
+	memcachedBinaryStatusNotStored   = 0x0005
+
+	// keys can't be longer than 250 bytes, as defined in the protocol spec
+	memcachedMaxKeyLen = 250
+	// K_TCP_RES_LEN, the smallest of the buffers captured by tcp_req_t. Lines longer
+	// than this might have been truncated by the capture.
+	memcachedTruncatedLen = 128
+)
+
+var memcachedBinaryOps = map[uint8]string{
+	0x00: "get",
+	0x01: "set",
+	0x02: "add",
+	0x03: "replace",
+	0x04: "delete",
+	0x05: "incr",
+	0x06: "decr",
+	0x09: "getq",
+	0x0c: "getk",
+	0x0d: "getkq",
+	0x0e: "append",
+	0x0f: "prepend",
+	0x11: "setq",
+	0x12: "addq",
+	0x13: "replaceq",
+	0x14: "deleteq",
+	0x15: "incrq",
+	0x16: "decrq",
+	0x19: "appendq",
+	0x1a: "prependq",
+	0x1c: "touch",
+	0x1d: "gat",
+	0x1e: "gatq",
+}
+
+// memcached text commands, and the position of the first key in the command line
+var memcachedTextCommands = map[string]int{
+	"get":     1,
+	"gets":    1,
+	"gat":     2,
+	"gats":    2,
+	"set":     1,
+	"add":     1,
+	"replace": 1,
+	"append":  1,
+	"prepend": 1,
+	"cas":     1,
+	"delete":  1,
+	"incr":    1,
+	"decr":    1,
+	"touch":   1,
+}
+
+var memcachedTextErrors = [...]string{
+	"ERROR",
+	"CLIENT_ERROR",
+	"SERVER_ERROR",
+}
+
+type memcachedInfo struct {
+	op     string
+	key    string
+	result int
+	err    request.DBError
+	failed bool
+}
+
+func isMemcachedRetrieval(op string) bool {
+	switch op {
+	case "get", "gets", "gat", "gats", "getq", "getk", "getkq", "gatq":
+		return true
+	}
+	return false
+}
+
+// memcachedInfoFromEvent returns the memcached operation carried by the TCP event, or nil if
+// the captured buffers don't look like a memcached command and its response. Only client
+// side events are reported, and the event is reversed if we caught it from the other end.
+func memcachedInfoFromEvent(event *TCPRequestInfo, requestBuffer, responseBuffer []byte) *memcachedInfo {
+	reversed := false
+	info, ok := parseMemcached(requestBuffer, responseBuffer)
+	if !ok {
+		if info, ok = parseMemcached(responseBuffer, requestBuffer); !ok {
+			return nil
+		}
+		reversed = true
+	}
+
+	// we can only report client spans for memcached
+	clientSide := event.Direction != 0
+	if reversed {
+		clientSide = !clientSide
+	}
+	if !clientSide {
+		return nil
+	}
+
+	if reversed {
+		reverseTCPEvent(event)
+	}
+
+	return info
+}
+
+func parseMemcached(req, resp []byte) (*memcachedInfo, bool) {
+	if len(req) == 0 || len(resp) == 0 {
+		return nil, false
+	}
+	if req[0] == memcachedBinaryReqMagic {
+		return parseMemcachedBinary(req, resp)
+	}
+	return parseMemcachedText(req, resp)
+}
+
+func parseMemcachedBinary(req, resp []byte) (*memcachedInfo, bool) {
+	if len(req) < memcachedBinaryHdrLen || len(resp) < memcachedBinaryHdrLen {
+		return nil, false
+	}
+	if req[0] != memcachedBinaryReqMagic || resp[0] != memcachedBinaryRespMagic {
+		return nil, false
+	}
+	// the response must echo the request opcode and opaque value
+	if req[1] != resp[1] || !bytes.Equal(req[12:16], resp[12:16]) {
+		return nil, false
+	}
+
+	op, ok := memcachedBinaryOps[req[1]]
+	if !ok {
+		return nil, false
+	}
+
+	keyLen := int(binary.BigEndian.Uint16(req[2:4]))
+	extrasLen := int(req[4])
+	bodyLen := int(binary.BigEndian.Uint32(req[8:12]))
+	if keyLen > memcachedMaxKeyLen || extrasLen+keyLen > bodyLen {
+		return nil, false
+	}
+
+	if op == "set" && binary.BigEndian.Uint64(req[16:24]) != 0 {
+		op = "cas"
+	}
+
+	info := &memcachedInfo{op: op}
+
+	keyStart := memcachedBinaryHdrLen + extrasLen
+	keyEnd := min(keyStart+keyLen, len(req))
+	if keyStart < keyEnd {
+		info.key = string(req[keyStart:keyEnd])
+	}
+
+	status := binary.BigEndian.Uint16(resp[6:8])
+	switch status {
+	case memcachedBinaryStatusOK:
+		if isMemcachedRetrieval(op) {
+			info.result = request.MemcachedResultHit
+		}
+	case memcachedBinaryStatusKeyNotFound:
+		info.result = request.MemcachedResultMiss
+	case memcachedBinaryStatusKeyExists, memcachedBinaryStatusNotStored:
+		// expected outcomes of conditional stores, not errors
+	default:
+		info.failed = true
+		info.err = request.DBError{ErrorCode: "0x" + strconv.FormatUint(uint64(status), 16)}
+		// the error description is sent as the response value
+		respBodyLen := int(binary.BigEndian.Uint32(resp[8:12]))
+		valStart := memcachedBinaryHdrLen + int(resp[4]) + int(binary.BigEndian.Uint16(resp[2:4]))
+		valEnd := min(memcachedBinaryHdrLen+respBodyLen, len(resp))
+		if valStart < valEnd {
+			info.err.Description = string(resp[valStart:valEnd])
+		}
+	}
+
+	return info, true
+}
+
+func memcachedLine(buf []byte) (string, bool) {
+	line, _, found := bytes.Cut(buf, []byte("\r\n"))
+	if !found && len(buf) < memcachedTruncatedLen {
+		// the command line must be terminated unless it was truncated by the capture
+		return "", false
+	}
+	return string(line), true
+}
+
+func parseMemcachedText(req, resp []byte) (*memcachedInfo, bool) {
+	line, ok := memcachedLine(req)
+	if !ok {
+		return nil, false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, false
+	}
+	op := fields[0]
+	keyPos, ok := memcachedTextCommands[op]
+	if !ok || len(fields) <= keyPos {
+		return nil, false
+	}
+	if !isValidMemcachedKey(fields[keyPos]) {
+		return nil, false
+	}
+
+	info := &memcachedInfo{op: op, key: fields[keyPos]}
+
+	respLine, ok := memcachedLine(resp)
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case strings.HasPrefix(respLine, "VALUE "):
+		if !isMemcachedRetrieval(op) {
+			return nil, false
+		}
+		info.result = request.MemcachedResultHit
+	case respLine == "END":
+		if !isMemcachedRetrieval(op) {
+			return nil, false
+		}
+		info.result = request.MemcachedResultMiss
+	case respLine == "NOT_FOUND":
+		info.result = request.MemcachedResultMiss
+	case respLine == "STORED", respLine == "NOT_STORED", respLine == "EXISTS",
+		respLine == "DELETED", respLine == "TOUCHED":
+	case isMemcachedNumber(respLine):
+		if op != "incr" && op != "decr" {
+			return nil, false
+		}
+	default:
+		dbErr, isErr := getMemcachedError(respLine)
+		if !isErr {
+			return nil, false
+		}
+		info.failed = true
+		info.err = dbErr
+	}
+
+	return info, true
+}
+
+func getMemcachedError(line string) (request.DBError, bool) {
+	for _, code := range memcachedTextErrors {
+		if line == code || strings.HasPrefix(line, code+" ") {
+			return request.DBError{
+				ErrorCode:   code,
+				Description: line,
+			}, true
+		}
+	}
+	return request.DBError{}, false
+}
+
+func isValidMemcachedKey(key string) bool {
+	if len(key) > memcachedMaxKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		// keys can't contain control characters or whitespace
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func isMemcachedNumber(line string) bool {
+	if line == "" {
+		return false
+	}
+	for i := 0; i < len(line); i++ {
+		if line[i] < '0' || line[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func TCPToMemcachedToSpan(trace *TCPRequestInfo, info *memcachedInfo) request.Span {
+	peer := ""
+	peerPort := 0
+	hostname := ""
+	hostPort := 0
+
+	if trace.ConnInfo.S_port != 0 || trace.ConnInfo.D_port != 0 {
+		peer, hostname = (*BPFConnInfo)(unsafe.Pointer(&trace.ConnInfo)).reqHostInfo()
+		peerPort = int(trace.ConnInfo.S_port)
+		hostPort = int(trace.ConnInfo.D_port)
+	}
+
+	status := 0
+	if info.failed {
+		status = 1
+	}
+
+	return request.Span{
+		Type:          request.EventTypeMemcachedClient,
+		Method:        info.op,
+		Path:          info.key,
+		Peer:          peer,
+		PeerPort:      peerPort,
+		Host:          hostname,
+		HostPort:      hostPort,
+		ContentLength: int64(trace.ReqLen),
+		RequestStart:  int64(trace.StartMonotimeNs),
+		Start:         int64(trace.StartMonotimeNs),
+		End:           int64(trace.EndMonotimeNs),
+		Status:        status,
+		SubType:       info.result,
+		DBError:       info.err,
+		TraceID:       trace2.TraceID(trace.Tp.TraceId),
+		SpanID:        trace2.SpanID(trace.Tp.SpanId),
+		ParentSpanID:  trace2.SpanID(trace.Tp.ParentId),
+		TraceFlags:    trace.Tp.Flags,
+		Pid: request.PidInfo{
+			HostPID:   trace.Pid.HostPid,
+			UserPID:   trace.Pid.UserPid,
+			Namespace: trace.Pid.Ns,
+		},
+	}
+}
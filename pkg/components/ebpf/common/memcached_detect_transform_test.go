@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+func memcachedBinaryPacket(magic, opcode uint8, status uint16, opaque uint32, cas uint64, extras, key, value []byte) []byte {
+	hdr := make([]byte, memcachedBinaryHdrLen)
+	hdr[0] = magic
+	hdr[1] = opcode
+	binary.BigEndian.PutUint16(hdr[2:4], uint16(len(key)))
+	hdr[4] = uint8(len(extras))
+	binary.BigEndian.PutUint16(hdr[6:8], status)
+	binary.BigEndian.PutUint32(hdr[8:12], uint32(len(extras)+len(key)+len(value)))
+	binary.BigEndian.PutUint32(hdr[12:16], opaque)
+	binary.BigEndian.PutUint64(hdr[16:24], cas)
+
+	pkt := append(hdr, extras...)
+	pkt = append(pkt, key...)
+	return append(pkt, value...)
+}
+
+func TestParseMemcachedText(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      string
+		resp     string
+		valid    bool
+		expected memcachedInfo
+	}{
+		{
+			name:     "get hit",
+			req:      "get session:1234\r\n",
+			resp:     "VALUE session:1234 0 5\r\nhello\r\nEND\r\n",
+			valid:    true,
+			expected: memcachedInfo{op: "get", key: "session:1234", result: request.MemcachedResultHit},
+		},
+		{
+			name:     "get miss",
+			req:      "get session:1234\r\n",
+			resp:     "END\r\n",
+			valid:    true,
+			expected: memcachedInfo{op: "get", key: "session:1234", result: request.MemcachedResultMiss},
+		},
+		{
+			name:     "gat key position",
+			req:      "gat 300 session:1234\r\n",
+			resp:     "END\r\n",
+			valid:    true,
+			expected: memcachedInfo{op: "gat", key: "session:1234", result: request.MemcachedResultMiss},
+		},
+		{
+			name:     "set stored",
+			req:      "set session:1234 0 300 5\r\nhello\r\n",
+			resp:     "STORED\r\n",
+			valid:    true,
+			expected: memcachedInfo{op: "set", key: "session:1234"},
+		},
+		{
+			name:     "delete not found",
+			req:      "delete session:1234\r\n",
+			resp:     "NOT_FOUND\r\n",
+			valid:    true,
+			expected: memcachedInfo{op: "delete", key: "session:1234", result: request.MemcachedResultMiss},
+		},
+		{
+			name:     "incr",
+			req:      "incr counter 1\r\n",
+			resp:     "42\r\n",
+			valid:    true,
+			expected: memcachedInfo{op: "incr", key: "counter"},
+		},
+		{
+			name:  "server error",
+			req:   "set session:1234 0 300 5\r\nhello\r\n",
+			resp:  "SERVER_ERROR out of memory storing object\r\n",
+			valid: true,
+			expected: memcachedInfo{
+				op:     "set",
+				key:    "session:1234",
+				failed: true,
+				err:    request.DBError{ErrorCode: "SERVER_ERROR", Description: "SERVER_ERROR out of memory storing object"},
+			},
+		},
+		{
+			name:  "unknown command",
+			req:   "stats\r\n",
+			resp:  "END\r\n",
+			valid: false,
+		},
+		{
+			name:  "number response to get",
+			req:   "get session:1234\r\n",
+			resp:  "42\r\n",
+			valid: false,
+		},
+		{
+			name:  "unterminated line",
+			req:   "get session:1234",
+			resp:  "END\r\n",
+			valid: false,
+		},
+		{
+			name:  "http request",
+			req:   "GET /index.html HTTP/1.1\r\n",
+			resp:  "HTTP/1.1 200 OK\r\n",
+			valid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := parseMemcached([]byte(tt.req), []byte(tt.resp))
+			assert.Equal(t, tt.valid, ok)
+			if tt.valid {
+				require.NotNil(t, info)
+				assert.Equal(t, tt.expected, *info)
+			}
+		})
+	}
+}
+
+func TestParseMemcachedBinary(t *testing.T) {
+	key := []byte("session:1234")
+
+	t.Run("get hit", func(t *testing.T) {
+		req := memcachedBinaryPacket(memcachedBinaryReqMagic, 0x00, 0, 7, 0, nil, key, nil)
+		resp := memcachedBinaryPacket(memcachedBinaryRespMagic, 0x00, memcachedBinaryStatusOK, 7, 1, []byte{0, 0, 0, 0}, nil, []byte("hello"))
+
+		info, ok := parseMemcached(req, resp)
+		require.True(t, ok)
+		assert.Equal(t, memcachedInfo{op: "get", key: "session:1234", result: request.MemcachedResultHit}, *info)
+	})
+
+	t.Run("get miss", func(t *testing.T) {
+		req := memcachedBinaryPacket(memcachedBinaryReqMagic, 0x00, 0, 7, 0, nil, key, nil)
+		resp := memcachedBinaryPacket(memcachedBinaryRespMagic, 0x00, memcachedBinaryStatusKeyNotFound, 7, 0, nil, nil, []byte("Not found"))
+
+		info, ok := parseMemcached(req, resp)
+		require.True(t, ok)
+		assert.Equal(t, memcachedInfo{op: "get", key: "session:1234", result: request.MemcachedResultMiss}, *info)
+	})
+
+	t.Run("set with cas", func(t *testing.T) {
+		req := memcachedBinaryPacket(memcachedBinaryReqMagic, 0x01, 0, 9, 12345, make([]byte, 8), key, []byte("hello"))
+		resp := memcachedBinaryPacket(memcachedBinaryRespMagic, 0x01, memcachedBinaryStatusOK, 9, 12346, nil, nil, nil)
+
+		info, ok := parseMemcached(req, resp)
+		require.True(t, ok)
+		assert.Equal(t, memcachedInfo{op: "cas", key: "session:1234"}, *info)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		req := memcachedBinaryPacket(memcachedBinaryReqMagic, 0x01, 0, 9, 0, make([]byte, 8), key, []byte("hello"))
+		resp := memcachedBinaryPacket(memcachedBinaryRespMagic, 0x01, 0x82, 9, 0, nil, nil, []byte("Out of memory"))
+
+		info, ok := parseMemcached(req, resp)
+		require.True(t, ok)
+		assert.True(t, info.failed)
+		assert.Equal(t, request.DBError{ErrorCode: "0x82", Description: "Out of memory"}, info.err)
+	})
+
+	t.Run("mismatched opaque", func(t *testing.T) {
+		req := memcachedBinaryPacket(memcachedBinaryReqMagic, 0x00, 0, 7, 0, nil, key, nil)
+		resp := memcachedBinaryPacket(memcachedBinaryRespMagic, 0x00, memcachedBinaryStatusOK, 8, 0, nil, nil, nil)
+
+		_, ok := parseMemcached(req, resp)
+		assert.False(t, ok)
+	})
+
+	t.Run("truncated header", func(t *testing.T) {
+		req := memcachedBinaryPacket(memcachedBinaryReqMagic, 0x00, 0, 7, 0, nil, key, nil)
+
+		_, ok := parseMemcached(req, []byte{memcachedBinaryRespMagic, 0x00})
+		assert.False(t, ok)
+	})
+}
+
+func TestMemcachedInfoFromEvent(t *testing.T) {
+	req := "get session:1234\r\n"
+	resp := "VALUE session:1234 0 5\r\nhello\r\nEND\r\n"
+
+	t.Run("client side", func(t *testing.T) {
+		event := makeTCPReq(req, 1, 33000, 11211, 1)
+		info := memcachedInfoFromEvent(&event, []byte(req), []byte(resp))
+		require.NotNil(t, info)
+
+		span := TCPToMemcachedToSpan(&event, info)
+		assert.Equal(t, request.EventTypeMemcachedClient, span.Type)
+		assert.Equal(t, "get", span.Method)
+		assert.Equal(t, "session:1234", span.Path)
+		assert.Equal(t, request.MemcachedResultHit, span.SubType)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 11211, span.HostPort)
+		assert.Equal(t, 0, span.Status)
+	})
+
+	t.Run("reversed client side", func(t *testing.T) {
+		event := makeTCPReq(resp, 0, 11211, 33000, 1)
+		info := memcachedInfoFromEvent(&event, []byte(resp), []byte(req))
+		require.NotNil(t, info)
+
+		span := TCPToMemcachedToSpan(&event, info)
+		assert.Equal(t, "get", span.Method)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 11211, span.HostPort)
+	})
+
+	t.Run("server side is ignored", func(t *testing.T) {
+		event := makeTCPReq(req, 0, 33000, 11211, 1)
+		assert.Nil(t, memcachedInfoFromEvent(&event, []byte(req), []byte(resp)))
+		assert.Equal(t, uint16(33000), event.ConnInfo.S_port)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	trace2 "go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
 )
 
 type mongoSpanInfo struct {
@@ -492,6 +493,54 @@ func TCPToMongoToSpan(trace *TCPRequestInfo, info *mongoSpanInfo) request.Span {
 	}
 }
 
+func ReadGoMongoRequestIntoSpan(record *ringbuf.Record) (request.Span, bool, error) {
+	event, err := ReinterpretCast[GoMongoClientInfo](record.RawSample)
+	if err != nil {
+		return request.Span{}, true, err
+	}
+
+	op := cstr(event.Op[:])
+	// the driver monitors the servers from its own goroutines
+	if isHeartbeat(op) {
+		return request.Span{}, true, nil
+	}
+
+	peer := ""
+	peerPort := 0
+	hostname := ""
+	hostPort := 0
+
+	if event.Conn.S_port != 0 || event.Conn.D_port != 0 {
+		peer, hostname = (*BPFConnInfo)(unsafe.Pointer(&event.Conn)).reqHostInfo()
+		peerPort = int(event.Conn.S_port)
+		hostPort = int(event.Conn.D_port)
+	}
+
+	return request.Span{
+		Type:         request.EventTypeMongoClient, // always client for Go
+		Method:       op,
+		Path:         cstr(event.Collection[:]),
+		Peer:         peer,
+		PeerPort:     peerPort,
+		Host:         hostname,
+		HostPort:     hostPort,
+		RequestStart: int64(event.StartMonotimeNs),
+		Start:        int64(event.StartMonotimeNs),
+		End:          int64(event.EndMonotimeNs),
+		Status:       int(event.Err),
+		DBNamespace:  cstr(event.Db[:]),
+		TraceID:      trace2.TraceID(event.Tp.TraceId),
+		SpanID:       trace2.SpanID(event.Tp.SpanId),
+		ParentSpanID: trace2.SpanID(event.Tp.ParentId),
+		TraceFlags:   event.Tp.Flags,
+		Pid: request.PidInfo{
+			HostPID:   event.Pid.HostPid,
+			UserPID:   event.Pid.UserPid,
+			Namespace: event.Pid.Ns,
+		},
+	}, false, nil
+}
+
 func isHeartbeat(comm string) bool {
 	return comm == commHello || comm == commIsMaster || comm == commPing || comm == commIsWritablePrimary || comm == commAtlasVersion
 }
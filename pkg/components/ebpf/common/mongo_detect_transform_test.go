@@ -12,6 +12,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
 )
 
 var requests = expirable.NewLRU[MongoRequestKey, *MongoRequestValue](1000, nil, 0)
@@ -511,3 +514,48 @@ func TestGetMongoInfoOperationUnknownForEmptyRequestSection(t *testing.T) {
 	assert.Empty(t, res.ErrorCode, "Expected ErrorCode to be empty in successful request")
 	assert.Empty(t, res.ErrorCodeName, "Expected ErrorCodeName to be empty in successful request")
 }
+
+func goMongoRecord(op, db, collection string, failed bool) *ringbuf.Record {
+	event := GoMongoClientInfo{
+		Type:            EventTypeGoMongo,
+		StartMonotimeNs: StartTime,
+		EndMonotimeNs:   EndTime,
+	}
+	if failed {
+		event.Err = 1
+	}
+	copy(event.Op[:], op)
+	copy(event.Db[:], db)
+	copy(event.Collection[:], collection)
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, &event)
+	return &ringbuf.Record{RawSample: buf.Bytes()}
+}
+
+func TestReadGoMongoRequestIntoSpan(t *testing.T) {
+	span, ignore, err := ReadGoMongoRequestIntoSpan(goMongoRecord("find", "shop", "orders", false))
+	require.NoError(t, err)
+	assert.False(t, ignore)
+	assert.Equal(t, request.EventTypeMongoClient, span.Type)
+	assert.Equal(t, "find", span.Method)
+	assert.Equal(t, "orders", span.Path)
+	assert.Equal(t, "shop", span.DBNamespace)
+	assert.Equal(t, int64(StartTime), span.Start)
+	assert.Equal(t, int64(EndTime), span.End)
+	assert.Equal(t, 0, span.Status)
+	assert.Equal(t, "find orders", span.TraceName())
+
+	span, ignore, err = ReadGoMongoRequestIntoSpan(goMongoRecord("listCollections", "shop", "", true))
+	require.NoError(t, err)
+	assert.False(t, ignore)
+	assert.Equal(t, "listCollections", span.Method)
+	assert.Empty(t, span.Path)
+	assert.Equal(t, 1, span.Status)
+}
+
+func TestReadGoMongoRequestIntoSpanIgnoresHeartbeats(t *testing.T) {
+	_, ignore, err := ReadGoMongoRequestIntoSpan(goMongoRecord("hello", "admin", "", false))
+	require.NoError(t, err)
+	assert.True(t, ignore)
+}
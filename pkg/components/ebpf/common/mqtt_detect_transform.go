@@ -0,0 +1,493 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"encoding/binary"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	trace2 "go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+// MQTT 3.1.1: https://docs.oasis-open.org/mqtt/mqtt/v3.1.1/mqtt-v3.1.1.html
+// MQTT 5.0: https://docs.oasis-open.org/mqtt/mqtt/v5.0/mqtt-v5.0.html
+const (
+	mqttConnect     = 1
+	mqttConnAck     = 2
+	mqttPublish     = 3
+	mqttPubAck      = 4
+	mqttPubRec      = 5
+	mqttPubRel      = 6
+	mqttPubComp     = 7
+	mqttSubscribe   = 8
+	mqttSubAck      = 9
+	mqttUnsubscribe = 10
+	mqttUnsubAck    = 11
+	mqttPingReq     = 12
+	mqttPingResp    = 13
+	mqttDisconnect  = 14
+	mqttAuth        = 15
+
+	// the remaining length is encoded in at most 4 bytes of 7 bits each
+	mqttMaxRemainingLengthBytes = 4
+
+	mqttPropertyUserProperty = 0x26
+)
+
+type MQTTInfo struct {
+	Operation string
+	Topic     string
+	QoS       int
+	// ReasonCode of the PUBACK, PUBREC or SUBACK that acknowledged the operation,
+	// or request.MQTTReasonCodeNone if it wasn't captured
+	ReasonCode int
+
+	TraceID    trace2.TraceID
+	ParentID   trace2.SpanID
+	TraceFlags uint8
+	HasParent  bool
+}
+
+type mqttPacket struct {
+	packetType uint8
+	flags      uint8
+	// body holds the variable header and the payload, and might be truncated
+	// if the packet didn't fit in the captured buffer
+	body []byte
+}
+
+// mqttInfoFromEvent returns the MQTT publication or subscription carried by the TCP event,
+// or nil if the buffers don't contain a PUBLISH or SUBSCRIBE packet.
+// Only the client side of the connection is reported: publications and subscriptions
+// sent by the traced process, and publications delivered to it.
+func mqttInfoFromEvent(event *TCPRequestInfo, requestBuffer, responseBuffer []byte) *MQTTInfo {
+	sent, received := requestBuffer, responseBuffer
+	if event.Direction == 0 {
+		// the broker talked first, so the request buffer holds what the process received
+		sent, received = received, sent
+	}
+
+	info := mqttClientInfo(sent, received)
+	if info == nil {
+		return nil
+	}
+
+	if event.Direction == 0 {
+		// We've caught the event reversed, as the broker started the exchange
+		// by pushing a message, let's reverse the event
+		reverseTCPEvent(event)
+	}
+
+	return info
+}
+
+func mqttClientInfo(sent, received []byte) *MQTTInfo {
+	if p, ok := findMQTTPacket(sent, mqttPublish, mqttSubscribe); ok {
+		switch p.packetType {
+		case mqttPublish:
+			return parseMQTTPublish(p, request.MessagingPublish, received)
+		case mqttSubscribe:
+			return parseMQTTSubscribe(p, received)
+		}
+	}
+
+	if p, ok := findMQTTPacket(received, mqttPublish); ok {
+		return parseMQTTPublish(p, request.MessagingProcess, nil)
+	}
+
+	return nil
+}
+
+// findMQTTPacket walks the MQTT control packets in the buffer and returns the first one
+// matching any of the provided packet types. Any malformed fixed header discards the
+// whole buffer, as the MQTT framing doesn't leave much room to tell it apart from other
+// binary protocols.
+func findMQTTPacket(buf []byte, types ...uint8) (mqttPacket, bool) {
+	for len(buf) > 0 {
+		p, rest, ok := readMQTTPacket(buf)
+		if !ok {
+			return mqttPacket{}, false
+		}
+		for _, t := range types {
+			if p.packetType == t {
+				return p, true
+			}
+		}
+		buf = rest
+	}
+
+	return mqttPacket{}, false
+}
+
+func readMQTTPacket(buf []byte) (mqttPacket, []byte, bool) {
+	if len(buf) < 2 {
+		return mqttPacket{}, nil, false
+	}
+
+	p := mqttPacket{packetType: buf[0] >> 4, flags: buf[0] & 0x0f}
+	size, n, ok := mqttVarInt(buf[1:])
+	if !ok || !isValidMQTTFixedHeader(p.packetType, p.flags, size) {
+		return mqttPacket{}, nil, false
+	}
+
+	start := 1 + n
+	end := start + size
+	p.body = buf[start:min(end, len(buf))]
+	if end >= len(buf) {
+		return p, nil, true
+	}
+	return p, buf[end:], true
+}
+
+// mqttVarInt decodes a Variable Byte Integer, returning the value and the number of bytes read
+func mqttVarInt(buf []byte) (int, int, bool) {
+	value, shift := 0, 0
+	for i := 0; i < len(buf) && i < mqttMaxRemainingLengthBytes; i++ {
+		value |= int(buf[i]&0x7f) << shift
+		if buf[i]&0x80 == 0 {
+			return value, i + 1, true
+		}
+		shift += 7
+	}
+	return 0, 0, false
+}
+
+func isValidMQTTFixedHeader(packetType, flags uint8, size int) bool {
+	switch packetType {
+	case mqttPublish:
+		// QoS 3 is forbidden, and the body holds at least the topic length
+		return (flags>>1)&0x03 != 3 && size >= 2
+	case mqttPubRel, mqttSubscribe, mqttUnsubscribe:
+		// reserved flags, which must be 0010
+		return flags == 0x02 && size >= 2
+	case mqttPubAck, mqttPubRec, mqttPubComp, mqttSubAck, mqttUnsubAck:
+		return flags == 0 && size >= 2
+	case mqttPingReq, mqttPingResp:
+		return flags == 0 && size == 0
+	case mqttConnect, mqttConnAck, mqttDisconnect, mqttAuth:
+		return flags == 0
+	}
+	// 0 is reserved
+	return false
+}
+
+// parseMQTTPublish decodes the PUBLISH variable header. When the message is published
+// by the traced process, the acknowledgement from the broker provides the reason code.
+func parseMQTTPublish(p mqttPacket, operation string, received []byte) *MQTTInfo {
+	r := mqttReader{buf: p.body}
+	topic, ok := r.string()
+	if !ok || !isValidMQTTTopic(topic, false) {
+		return nil
+	}
+
+	info := &MQTTInfo{
+		Operation:  operation,
+		Topic:      topic,
+		QoS:        int(p.flags>>1) & 0x03,
+		ReasonCode: request.MQTTReasonCodeNone,
+	}
+
+	var packetID uint16
+	if info.QoS > 0 {
+		if packetID, ok = r.uint16(); !ok {
+			return nil
+		}
+	}
+
+	// The protocol version is negotiated in the CONNECT packet, which we don't usually
+	// capture, so we just try to decode the MQTT 5 properties. A MQTT 3.1.1 payload
+	// would hardly parse as a valid list of properties.
+	if props, ok := r.properties(); ok {
+		if traceparent, ok := mqttUserProperty(props, "traceparent"); ok {
+			info.TraceID, info.ParentID, info.TraceFlags, info.HasParent = parseTraceparent(traceparent)
+		}
+	}
+
+	if info.QoS > 0 && received != nil {
+		if ack, ok := findMQTTPacket(received, mqttPubAck, mqttPubRec); ok {
+			if code, ok := mqttAckReasonCode(ack, packetID); ok {
+				info.ReasonCode = code
+			}
+		}
+	}
+
+	return info
+}
+
+// mqttAckReasonCode returns the reason code of a PUBACK or PUBREC packet acknowledging
+// the provided packet identifier. MQTT 3.1.1 acknowledgements and successful MQTT 5
+// acknowledgements might omit the reason code.
+func mqttAckReasonCode(ack mqttPacket, packetID uint16) (int, bool) {
+	r := mqttReader{buf: ack.body}
+	id, ok := r.uint16()
+	if !ok || id != packetID {
+		return 0, false
+	}
+	code, ok := r.byte()
+	if !ok {
+		return 0, true
+	}
+	return int(code), true
+}
+
+// parseMQTTSubscribe decodes the first topic filter of the SUBSCRIBE packet, and the
+// reason code that the broker returned for it in the SUBACK packet.
+func parseMQTTSubscribe(p mqttPacket, received []byte) *MQTTInfo {
+	r := mqttReader{buf: p.body}
+	packetID, ok := r.uint16()
+	if !ok {
+		return nil
+	}
+
+	// MQTT 5 subscriptions start with the properties, which decode as an invalid topic
+	// filter if read as MQTT 3.1.1, so we try the older protocol version first.
+	v5 := false
+	filter, qos, ok := r.clone().topicFilter()
+	if !ok {
+		if _, ok = r.properties(); !ok {
+			return nil
+		}
+		if filter, qos, ok = r.topicFilter(); !ok {
+			return nil
+		}
+		v5 = true
+	}
+
+	info := &MQTTInfo{
+		Operation:  request.MessagingSubscribe,
+		Topic:      filter,
+		QoS:        qos,
+		ReasonCode: request.MQTTReasonCodeNone,
+	}
+
+	if ack, ok := findMQTTPacket(received, mqttSubAck); ok {
+		if code, ok := mqttSubAckReasonCode(ack, packetID, v5); ok {
+			info.ReasonCode = code
+		}
+	}
+
+	return info
+}
+
+// mqttSubAckReasonCode returns the reason code for the first topic filter of the
+// subscription, which is the granted QoS or a failure code from 0x80 onwards
+func mqttSubAckReasonCode(ack mqttPacket, packetID uint16, v5 bool) (int, bool) {
+	r := mqttReader{buf: ack.body}
+	id, ok := r.uint16()
+	if !ok || id != packetID {
+		return 0, false
+	}
+	if v5 {
+		if _, ok := r.properties(); !ok {
+			return 0, false
+		}
+	}
+	code, ok := r.byte()
+	return int(code), ok
+}
+
+// mqttUserProperty looks for a User Property with the provided name, stopping at
+// the first malformed or unknown property.
+func mqttUserProperty(props []byte, name string) (string, bool) {
+	r := mqttReader{buf: props}
+	for len(r.buf) > 0 {
+		id, _ := r.byte()
+		if id == mqttPropertyUserProperty {
+			k, ok := r.string()
+			if !ok {
+				return "", false
+			}
+			v, ok := r.string()
+			if !ok {
+				return "", false
+			}
+			if strings.EqualFold(k, name) {
+				return v, true
+			}
+			continue
+		}
+		if !r.skipProperty(id) {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isValidMQTTTopic(topic string, filter bool) bool {
+	if topic == "" || !utf8.ValidString(topic) {
+		return false
+	}
+	for i := 0; i < len(topic); i++ {
+		switch c := topic[i]; {
+		case c < 0x20 || c == 0x7f:
+			return false
+		case (c == '#' || c == '+') && !filter:
+			// wildcards are only allowed in subscriptions
+			return false
+		}
+	}
+	return true
+}
+
+type mqttReader struct {
+	buf []byte
+}
+
+func (r *mqttReader) clone() *mqttReader {
+	return &mqttReader{buf: r.buf}
+}
+
+func (r *mqttReader) byte() (uint8, bool) {
+	if len(r.buf) < 1 {
+		return 0, false
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b, true
+}
+
+func (r *mqttReader) uint16() (uint16, bool) {
+	if len(r.buf) < 2 {
+		return 0, false
+	}
+	v := binary.BigEndian.Uint16(r.buf)
+	r.buf = r.buf[2:]
+	return v, true
+}
+
+func (r *mqttReader) skip(n int) bool {
+	if len(r.buf) < n {
+		return false
+	}
+	r.buf = r.buf[n:]
+	return true
+}
+
+// string reads a length-prefixed UTF-8 string, which must not be truncated
+func (r *mqttReader) string() (string, bool) {
+	l, ok := r.uint16()
+	if !ok || len(r.buf) < int(l) {
+		return "", false
+	}
+	s := string(r.buf[:l])
+	r.buf = r.buf[l:]
+	return s, true
+}
+
+// topicFilter reads a SUBSCRIBE topic filter followed by its subscription options,
+// returning the requested QoS
+func (r *mqttReader) topicFilter() (string, int, bool) {
+	filter, ok := r.string()
+	if !ok || !isValidMQTTTopic(filter, true) {
+		return "", 0, false
+	}
+	options, ok := r.byte()
+	// the upper two bits are reserved, and MQTT 3.1.1 reserves all but the QoS
+	if !ok || options&0xc0 != 0 || options&0x03 == 3 {
+		return "", 0, false
+	}
+	return filter, int(options & 0x03), true
+}
+
+// properties reads the MQTT 5 properties block and validates all the contained properties
+func (r *mqttReader) properties() ([]byte, bool) {
+	l, n, ok := mqttVarInt(r.buf)
+	if !ok || len(r.buf) < n+l {
+		return nil, false
+	}
+	props := r.buf[n : n+l]
+
+	pr := mqttReader{buf: props}
+	for len(pr.buf) > 0 {
+		id, _ := pr.byte()
+		if !pr.skipProperty(id) {
+			return nil, false
+		}
+	}
+
+	r.buf = r.buf[n+l:]
+	return props, true
+}
+
+// skipProperty skips the value of the property with the provided identifier,
+// according to its data type
+func (r *mqttReader) skipProperty(id uint8) bool {
+	switch id {
+	case 0x01, 0x17, 0x19, 0x24, 0x25, 0x28, 0x29, 0x2A:
+		// byte
+		return r.skip(1)
+	case 0x13, 0x21, 0x22, 0x23:
+		// two byte integer
+		return r.skip(2)
+	case 0x02, 0x11, 0x18, 0x27:
+		// four byte integer
+		return r.skip(4)
+	case 0x0B:
+		// variable byte integer
+		_, n, ok := mqttVarInt(r.buf)
+		return ok && r.skip(n)
+	case 0x03, 0x08, 0x09, 0x12, 0x15, 0x16, 0x1A, 0x1C, 0x1F:
+		// UTF-8 strings and binary data share the same encoding
+		_, ok := r.string()
+		return ok
+	case mqttPropertyUserProperty:
+		_, ok := r.string()
+		if !ok {
+			return false
+		}
+		_, ok = r.string()
+		return ok
+	}
+	return false
+}
+
+func TCPToMQTTToSpan(trace *TCPRequestInfo, data *MQTTInfo) request.Span {
+	peer := ""
+	hostname := ""
+	hostPort := 0
+
+	if trace.ConnInfo.S_port != 0 || trace.ConnInfo.D_port != 0 {
+		peer, hostname = (*BPFConnInfo)(unsafe.Pointer(&trace.ConnInfo)).reqHostInfo()
+		hostPort = int(trace.ConnInfo.D_port)
+	}
+
+	span := request.Span{
+		Type:          request.EventTypeMQTTClient,
+		Method:        data.Operation,
+		Path:          data.Topic,
+		Peer:          peer,
+		PeerPort:      int(trace.ConnInfo.S_port),
+		Host:          hostname,
+		HostPort:      hostPort,
+		ContentLength: 0,
+		RequestStart:  int64(trace.StartMonotimeNs),
+		Start:         int64(trace.StartMonotimeNs),
+		End:           int64(trace.EndMonotimeNs),
+		Status:        data.ReasonCode,
+		SubType:       data.QoS,
+		TraceID:       trace2.TraceID(trace.Tp.TraceId),
+		SpanID:        trace2.SpanID(trace.Tp.SpanId),
+		ParentSpanID:  trace2.SpanID(trace.Tp.ParentId),
+		TraceFlags:    trace.Tp.Flags,
+		Pid: request.PidInfo{
+			HostPID:   trace.Pid.HostPid,
+			UserPID:   trace.Pid.UserPid,
+			Namespace: trace.Pid.Ns,
+		},
+	}
+
+	// the traceparent propagated in the MQTT 5 user properties links together
+	// the publish and process sides of the message
+	if data.HasParent {
+		span.TraceID = data.TraceID
+		span.ParentSpanID = data.ParentID
+		span.TraceFlags = data.TraceFlags
+	}
+
+	return span
+}
@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+func mqttPacketBytes(header uint8, body []byte) []byte {
+	b := []byte{header}
+	size := len(body)
+	for {
+		enc := uint8(size & 0x7f)
+		size >>= 7
+		if size > 0 {
+			enc |= 0x80
+		}
+		b = append(b, enc)
+		if size == 0 {
+			break
+		}
+	}
+	return append(b, body...)
+}
+
+func mqttString(s string) []byte {
+	return append(cqlShort(uint16(len(s))), s...)
+}
+
+func mqttProperties(props ...[]byte) []byte {
+	body := cqlConcat(props...)
+	return append([]byte{uint8(len(body))}, body...)
+}
+
+func mqttUserPropertyBytes(k, v string) []byte {
+	return cqlConcat([]byte{mqttPropertyUserProperty}, mqttString(k), mqttString(v))
+}
+
+func mqttPublishBytes(qos uint8, topic string, packetID uint16, props []byte, payload string) []byte {
+	body := mqttString(topic)
+	if qos > 0 {
+		body = append(body, cqlShort(packetID)...)
+	}
+	body = append(body, props...)
+	return mqttPacketBytes(mqttPublish<<4|qos<<1, append(body, payload...))
+}
+
+func TestMQTTVarInt(t *testing.T) {
+	tests := []struct {
+		buf   []byte
+		value int
+		n     int
+		ok    bool
+	}{
+		{buf: []byte{0x00}, value: 0, n: 1, ok: true},
+		{buf: []byte{0x7f}, value: 127, n: 1, ok: true},
+		{buf: []byte{0x80, 0x01}, value: 128, n: 2, ok: true},
+		{buf: []byte{0xff, 0xff, 0xff, 0x7f}, value: 268_435_455, n: 4, ok: true},
+		{buf: []byte{0xff, 0xff, 0xff, 0xff, 0x01}},
+		{buf: []byte{0x80}},
+	}
+	for _, tt := range tests {
+		value, n, ok := mqttVarInt(tt.buf)
+		assert.Equal(t, tt.ok, ok, tt.buf)
+		assert.Equal(t, tt.value, value, tt.buf)
+		assert.Equal(t, tt.n, n, tt.buf)
+	}
+}
+
+func TestFindMQTTPacket(t *testing.T) {
+	publish := mqttPublishBytes(1, "sensors/temperature", 10, nil, "21.5")
+
+	tests := []struct {
+		name  string
+		buf   []byte
+		valid bool
+	}{
+		{name: "publish", buf: publish, valid: true},
+		{name: "publish after ping", buf: cqlConcat([]byte{mqttPingReq << 4, 0}, publish), valid: true},
+		{name: "truncated publish", buf: publish[:10], valid: true},
+		{name: "publish with QoS 3", buf: cqlConcat([]byte{0x36}, publish[1:])},
+		{name: "subscribe with invalid flags", buf: mqttPacketBytes(mqttSubscribe<<4, cqlConcat(cqlShort(1), mqttString("a"), []byte{0}))},
+		{name: "only pings", buf: []byte{mqttPingReq << 4, 0, mqttPingResp << 4, 0}},
+		{name: "http", buf: []byte("HTTP/1.1 200 OK\r\n\r\n")},
+		{name: "json", buf: []byte(`{"temperature":21.5}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := findMQTTPacket(tt.buf, mqttPublish)
+			require.Equal(t, tt.valid, ok)
+			if ok {
+				assert.Equal(t, uint8(mqttPublish), p.packetType)
+			}
+		})
+	}
+}
+
+func TestMQTTInfoFromEvent(t *testing.T) {
+	t.Run("publish QoS 0", func(t *testing.T) {
+		pub := mqttPublishBytes(0, "sensors/temperature", 0, nil, "21.5")
+		event := makeTCPReq(string(pub), 1, 33000, 1883, 1)
+		info := mqttInfoFromEvent(&event, pub, nil)
+		require.NotNil(t, info)
+
+		span := TCPToMQTTToSpan(&event, info)
+		assert.Equal(t, request.EventTypeMQTTClient, span.Type)
+		assert.Equal(t, request.MessagingPublish, span.Method)
+		assert.Equal(t, "sensors/temperature", span.Path)
+		assert.Equal(t, 0, span.SubType)
+		assert.Equal(t, request.MQTTReasonCodeNone, span.Status)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 1883, span.HostPort)
+		assert.Equal(t, request.StatusCodeUnset, request.SpanStatusCode(&span))
+	})
+
+	t.Run("publish QoS 1 MQTT 3.1.1", func(t *testing.T) {
+		pub := mqttPublishBytes(1, "sensors/temperature", 10, nil, "21.5")
+		ack := mqttPacketBytes(mqttPubAck<<4, cqlShort(10))
+		event := makeTCPReq(string(pub), 1, 33000, 1883, 1)
+		info := mqttInfoFromEvent(&event, pub, ack)
+		require.NotNil(t, info)
+
+		assert.Equal(t, 1, info.QoS)
+		assert.Equal(t, 0, info.ReasonCode)
+		assert.False(t, info.HasParent)
+	})
+
+	t.Run("publish QoS 1 MQTT 5 rejected", func(t *testing.T) {
+		pub := mqttPublishBytes(1, "sensors/temperature", 10, mqttProperties([]byte{0x01, 0x01}), "21.5")
+		// Not authorized
+		ack := mqttPacketBytes(mqttPubAck<<4, cqlConcat(cqlShort(10), []byte{0x87, 0}))
+		event := makeTCPReq(string(pub), 1, 33000, 1883, 1)
+		info := mqttInfoFromEvent(&event, pub, ack)
+		require.NotNil(t, info)
+
+		span := TCPToMQTTToSpan(&event, info)
+		assert.Equal(t, 0x87, span.Status)
+		assert.Equal(t, 1, span.SubType)
+		assert.Equal(t, request.StatusCodeError, request.SpanStatusCode(&span))
+	})
+
+	t.Run("publish QoS 2 with mismatched acknowledgement", func(t *testing.T) {
+		pub := mqttPublishBytes(2, "sensors/temperature", 10, nil, "21.5")
+		rec := mqttPacketBytes(mqttPubRec<<4, cqlConcat(cqlShort(11), []byte{0x80}))
+		event := makeTCPReq(string(pub), 1, 33000, 1883, 1)
+		info := mqttInfoFromEvent(&event, pub, rec)
+		require.NotNil(t, info)
+
+		assert.Equal(t, 2, info.QoS)
+		assert.Equal(t, request.MQTTReasonCodeNone, info.ReasonCode)
+	})
+
+	t.Run("process with traceparent", func(t *testing.T) {
+		props := mqttProperties(
+			[]byte{0x01, 0x01},
+			mqttUserPropertyBytes("tenant", "acme"),
+			mqttUserPropertyBytes("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"),
+		)
+		pub := mqttPublishBytes(1, "sensors/temperature", 3, props, "21.5")
+		ack := mqttPacketBytes(mqttPubAck<<4, cqlShort(3))
+		// the broker pushes the message to the client, which receives it first
+		event := makeTCPReq(string(pub), 0, 1883, 33000, 1)
+		info := mqttInfoFromEvent(&event, pub, ack)
+		require.NotNil(t, info)
+
+		span := TCPToMQTTToSpan(&event, info)
+		assert.Equal(t, request.MessagingProcess, span.Method)
+		assert.Equal(t, "sensors/temperature", span.Path)
+		assert.Equal(t, request.MQTTReasonCodeNone, span.Status)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 1883, span.HostPort)
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.TraceID.String())
+		assert.Equal(t, "b7ad6b7169203331", span.ParentSpanID.String())
+		assert.Equal(t, uint8(1), span.TraceFlags)
+	})
+
+	t.Run("subscribe MQTT 3.1.1", func(t *testing.T) {
+		sub := mqttPacketBytes(mqttSubscribe<<4|0x02, cqlConcat(cqlShort(5), mqttString("sensors/+/temperature"), []byte{1}))
+		ack := mqttPacketBytes(mqttSubAck<<4, cqlConcat(cqlShort(5), []byte{1}))
+		event := makeTCPReq(string(sub), 1, 33000, 1883, 1)
+		info := mqttInfoFromEvent(&event, sub, ack)
+		require.NotNil(t, info)
+
+		span := TCPToMQTTToSpan(&event, info)
+		assert.Equal(t, request.MessagingSubscribe, span.Method)
+		assert.Equal(t, "sensors/+/temperature", span.Path)
+		assert.Equal(t, 1, span.SubType)
+		assert.Equal(t, 1, span.Status)
+		assert.Equal(t, request.StatusCodeUnset, request.SpanStatusCode(&span))
+	})
+
+	t.Run("subscribe MQTT 5 failure", func(t *testing.T) {
+		sub := mqttPacketBytes(mqttSubscribe<<4|0x02, cqlConcat(cqlShort(5), mqttProperties([]byte{0x0B, 0x01}), mqttString("sensors/#"), []byte{2}))
+		// Topic Filter invalid
+		ack := mqttPacketBytes(mqttSubAck<<4, cqlConcat(cqlShort(5), mqttProperties(), []byte{0x8F}))
+		event := makeTCPReq(string(sub), 1, 33000, 1883, 1)
+		info := mqttInfoFromEvent(&event, sub, ack)
+		require.NotNil(t, info)
+
+		span := TCPToMQTTToSpan(&event, info)
+		assert.Equal(t, "sensors/#", span.Path)
+		assert.Equal(t, 2, span.SubType)
+		assert.Equal(t, 0x8F, span.Status)
+		assert.Equal(t, request.StatusCodeError, request.SpanStatusCode(&span))
+	})
+
+	t.Run("server receiving publish", func(t *testing.T) {
+		// a broker receiving a publication can't be told apart from a client
+		// receiving a delivery, so it's reported as a process span
+		pub := mqttPublishBytes(0, "sensors/temperature", 0, nil, "21.5")
+		event := makeTCPReq(string(pub), 0, 33000, 1883, 1)
+		info := mqttInfoFromEvent(&event, pub, nil)
+		require.NotNil(t, info)
+		assert.Equal(t, request.MessagingProcess, info.Operation)
+	})
+
+	t.Run("wildcards in publish topic", func(t *testing.T) {
+		pub := mqttPublishBytes(0, "sensors/#", 0, nil, "21.5")
+		event := makeTCPReq(string(pub), 1, 33000, 1883, 1)
+		assert.Nil(t, mqttInfoFromEvent(&event, pub, nil))
+	})
+
+	t.Run("not mqtt", func(t *testing.T) {
+		req := []byte("GET / HTTP/1.1\r\n\r\n")
+		event := makeTCPReq(string(req), 1, 33000, 1883, 1)
+		assert.Nil(t, mqttInfoFromEvent(&event, req, []byte("HTTP/1.1 200 OK\r\n\r\n")))
+
+		// a Redis integer reply frames as a truncated PUBLISH packet with DUP and QoS 1
+		req = []byte("*2\r\n$4\r\nINCR\r\n$7\r\ncounter\r\n")
+		event = makeTCPReq(string(req), 1, 33000, 6379, 1)
+		assert.Nil(t, mqttInfoFromEvent(&event, req, []byte(":1\r\n")))
+	})
+}
@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	trace2 "go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+// https://docs.nats.io/reference/reference-protocols/nats-protocol
+const natsMaxSubjectLen = 255
+
+var natsCRLF = []byte("\r\n")
+
+type NATSInfo struct {
+	Operation string
+	Subject   string
+	// Client is true when the traced process is the NATS client, and false
+	// when it is the NATS server
+	Client bool
+	Failed bool
+
+	TraceID    trace2.TraceID
+	ParentID   trace2.SpanID
+	TraceFlags uint8
+	HasParent  bool
+}
+
+type natsCommand struct {
+	op string
+	// fromClient is true for the commands that only a client can send
+	fromClient bool
+	subject    string
+	headers    []byte
+}
+
+// natsInfoFromEvent returns the NATS message carried by the TCP event,
+// or nil if none of the buffers contain a NATS PUB, HPUB, MSG or HMSG command.
+// Both sides of the connection are reported: the client and the NATS server.
+func natsInfoFromEvent(event *TCPRequestInfo, requestBuffer, responseBuffer []byte) *NATSInfo {
+	inRequest := true
+	cmd, ok := findNATSCommand(requestBuffer)
+	if !ok {
+		if cmd, ok = findNATSCommand(responseBuffer); !ok {
+			return nil
+		}
+		inRequest = false
+	}
+
+	// the request buffer holds what the traced process sent if it talked first
+	sentFirst := event.Direction != 0
+	sentByUs := inRequest == sentFirst
+	client := cmd.fromClient == sentByUs
+
+	info := &NATSInfo{
+		Operation: cmd.op,
+		Subject:   cmd.subject,
+		Client:    client,
+	}
+
+	other := responseBuffer
+	if !inRequest {
+		other = requestBuffer
+	}
+	info.Failed = natsHasError(other)
+
+	if traceparent, ok := natsHeader(cmd.headers, "traceparent"); ok {
+		info.TraceID, info.ParentID, info.TraceFlags, info.HasParent = parseTraceparent(traceparent)
+	}
+
+	// client spans must have the NATS server as destination, and server spans the client
+	if client != sentFirst {
+		reverseTCPEvent(event)
+	}
+
+	return info
+}
+
+// findNATSCommand walks the protocol lines of the buffer, skipping the payloads, and
+// returns the first message or subscription command.
+func findNATSCommand(buf []byte) (*natsCommand, bool) {
+	for len(buf) > 0 {
+		line, rest, found := bytes.Cut(buf, natsCRLF)
+		if !found {
+			// we can't trust a truncated command line
+			return nil, false
+		}
+
+		fields := strings.Fields(string(line))
+		if len(fields) == 0 {
+			return nil, false
+		}
+
+		op := strings.ToUpper(fields[0])
+		switch op {
+		// SUB and UNSUB only register the interest of the client, without any reply from the
+		// server, so they are neither messaging operations nor requests with a duration
+		case "PING", "PONG", "+OK", "-ERR", "INFO", "CONNECT", "SUB", "UNSUB":
+			buf = rest
+			continue
+		case "PUB":
+			// PUB <subject> [reply-to] <#bytes>
+			if len(fields) != 3 && len(fields) != 4 {
+				return nil, false
+			}
+			if _, ok := natsSize(fields[len(fields)-1]); !ok {
+				return nil, false
+			}
+			return natsCommandWithSubject(request.MessagingPublish, true, fields[1], nil)
+		case "MSG":
+			// MSG <subject> <sid> [reply-to] <#bytes>
+			if len(fields) != 4 && len(fields) != 5 {
+				return nil, false
+			}
+			if _, ok := natsSize(fields[len(fields)-1]); !ok {
+				return nil, false
+			}
+			return natsCommandWithSubject(request.MessagingProcess, false, fields[1], nil)
+		case "HPUB", "HMSG":
+			// HPUB <subject> [reply-to] <#header bytes> <#total bytes>
+			// HMSG <subject> <sid> [reply-to] <#header bytes> <#total bytes>
+			minFields := 4
+			operation := request.MessagingPublish
+			if op == "HMSG" {
+				minFields = 5
+				operation = request.MessagingProcess
+			}
+			if len(fields) != minFields && len(fields) != minFields+1 {
+				return nil, false
+			}
+			hdrLen, ok := natsSize(fields[len(fields)-2])
+			if !ok {
+				return nil, false
+			}
+			totalLen, ok := natsSize(fields[len(fields)-1])
+			if !ok || hdrLen > totalLen {
+				return nil, false
+			}
+			headers := rest[:min(hdrLen, len(rest))]
+			return natsCommandWithSubject(operation, op == "HPUB", fields[1], headers)
+		default:
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+func natsCommandWithSubject(op string, fromClient bool, subject string, headers []byte) (*natsCommand, bool) {
+	if !isValidNATSSubject(subject) {
+		return nil, false
+	}
+	return &natsCommand{op: op, fromClient: fromClient, subject: subject, headers: headers}, true
+}
+
+func natsSize(field string) (int, bool) {
+	n, err := strconv.Atoi(field)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func isValidNATSSubject(subject string) bool {
+	if subject == "" || len(subject) > natsMaxSubjectLen {
+		return false
+	}
+	for i := 0; i < len(subject); i++ {
+		if subject[i] <= ' ' || subject[i] == 0x7f {
+			return false
+		}
+	}
+	return !strings.HasPrefix(subject, ".") && !strings.HasSuffix(subject, ".")
+}
+
+// natsHasError returns whether the buffer contains an -ERR protocol message
+func natsHasError(buf []byte) bool {
+	for len(buf) > 0 {
+		line, rest, found := bytes.Cut(buf, natsCRLF)
+		if bytes.HasPrefix(line, []byte("-ERR")) {
+			return true
+		}
+		if !found {
+			break
+		}
+		buf = rest
+	}
+	return false
+}
+
+// natsHeader looks for a header in a NATS headers block, which follows
+// the NATS/1.0 version line with HTTP-like "Name: value" lines
+func natsHeader(headers []byte, name string) (string, bool) {
+	if !bytes.HasPrefix(headers, []byte("NATS/")) {
+		return "", false
+	}
+	lines := strings.Split(string(headers), "\r\n")
+	for _, l := range lines[1:] {
+		k, v, found := strings.Cut(l, ":")
+		if found && strings.EqualFold(strings.TrimSpace(k), name) {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
+
+func TCPToNATSToSpan(trace *TCPRequestInfo, data *NATSInfo) request.Span {
+	peer := ""
+	hostname := ""
+	hostPort := 0
+
+	if trace.ConnInfo.S_port != 0 || trace.ConnInfo.D_port != 0 {
+		peer, hostname = (*BPFConnInfo)(unsafe.Pointer(&trace.ConnInfo)).reqHostInfo()
+		hostPort = int(trace.ConnInfo.D_port)
+	}
+
+	reqType := request.EventTypeNATSClient
+	if !data.Client {
+		reqType = request.EventTypeNATSServer
+	}
+
+	status := 0
+	if data.Failed {
+		status = 1
+	}
+
+	span := request.Span{
+		Type:          reqType,
+		Method:        data.Operation,
+		Path:          data.Subject,
+		Peer:          peer,
+		PeerPort:      int(trace.ConnInfo.S_port),
+		Host:          hostname,
+		HostPort:      hostPort,
+		ContentLength: 0,
+		RequestStart:  int64(trace.StartMonotimeNs),
+		Start:         int64(trace.StartMonotimeNs),
+		End:           int64(trace.EndMonotimeNs),
+		Status:        status,
+		TraceID:       trace2.TraceID(trace.Tp.TraceId),
+		SpanID:        trace2.SpanID(trace.Tp.SpanId),
+		ParentSpanID:  trace2.SpanID(trace.Tp.ParentId),
+		TraceFlags:    trace.Tp.Flags,
+		Pid: request.PidInfo{
+			HostPID:   trace.Pid.HostPid,
+			UserPID:   trace.Pid.UserPid,
+			Namespace: trace.Pid.Ns,
+		},
+	}
+
+	// the traceparent propagated in the message headers links together the
+	// publish and process sides of the message
+	if data.HasParent {
+		span.TraceID = data.TraceID
+		span.ParentSpanID = data.ParentID
+		span.TraceFlags = data.TraceFlags
+	}
+
+	return span
+}
@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+)
+
+func TestFindNATSCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		buf        string
+		valid      bool
+		op         string
+		subject    string
+		fromClient bool
+	}{
+		{name: "pub", buf: "PUB orders.created 5\r\nhello\r\n", valid: true, op: request.MessagingPublish, subject: "orders.created", fromClient: true},
+		{name: "pub with reply", buf: "PUB orders.created _INBOX.abc 5\r\nhello\r\n", valid: true, op: request.MessagingPublish, subject: "orders.created", fromClient: true},
+		{name: "lowercase pub", buf: "pub orders.created 5\r\nhello\r\n", valid: true, op: request.MessagingPublish, subject: "orders.created", fromClient: true},
+		{name: "pub after connect", buf: "CONNECT {\"verbose\":false}\r\nPING\r\nPUB orders.created 5\r\nhello\r\n", valid: true, op: request.MessagingPublish, subject: "orders.created", fromClient: true},
+		{name: "hpub", buf: "HPUB orders.created 22 27\r\nNATS/1.0\r\nFoo: bar\r\n\r\nhello\r\n", valid: true, op: request.MessagingPublish, subject: "orders.created", fromClient: true},
+		{name: "pub after sub", buf: "SUB orders.* workers 1\r\nPUB orders.created 5\r\nhello\r\n", valid: true, op: request.MessagingPublish, subject: "orders.created", fromClient: true},
+		{name: "msg", buf: "MSG orders.created 1 5\r\nhello\r\n", valid: true, op: request.MessagingProcess, subject: "orders.created"},
+		{name: "hmsg", buf: "HMSG orders.created 1 _INBOX.abc 22 27\r\nNATS/1.0\r\nFoo: bar\r\n\r\nhello\r\n", valid: true, op: request.MessagingProcess, subject: "orders.created"},
+		{name: "only pings", buf: "PING\r\nPONG\r\n"},
+		{name: "only sub", buf: "SUB orders.* workers 1\r\n"},
+		{name: "truncated line", buf: "PUB orders.crea"},
+		{name: "pub without size", buf: "PUB orders.created\r\n"},
+		{name: "pub with bad size", buf: "PUB orders.created five\r\nhello\r\n"},
+		{name: "hpub with headers larger than total", buf: "HPUB orders.created 30 27\r\n"},
+		{name: "invalid subject", buf: "PUB orders. 5\r\nhello\r\n"},
+		{name: "http", buf: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"},
+		{name: "redis", buf: "*1\r\n$4\r\nPING\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := findNATSCommand([]byte(tt.buf))
+			require.Equal(t, tt.valid, ok)
+			if tt.valid {
+				assert.Equal(t, tt.op, cmd.op)
+				assert.Equal(t, tt.subject, cmd.subject)
+				assert.Equal(t, tt.fromClient, cmd.fromClient)
+			}
+		})
+	}
+}
+
+func TestNATSHeader(t *testing.T) {
+	headers := []byte("NATS/1.0\r\nFoo: bar\r\ntraceparent: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01\r\n\r\n")
+
+	v, ok := natsHeader(headers, "Traceparent")
+	assert.True(t, ok)
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", v)
+
+	_, ok = natsHeader(headers, "missing")
+	assert.False(t, ok)
+
+	_, ok = natsHeader([]byte("Foo: bar\r\n"), "Foo")
+	assert.False(t, ok)
+}
+
+func TestNATSInfoFromEvent(t *testing.T) {
+	pub := "PUB orders.created 5\r\nhello\r\n"
+	msg := "MSG orders.created 1 5\r\nhello\r\n"
+
+	t.Run("client publish", func(t *testing.T) {
+		event := makeTCPReq(pub, 1, 33000, 4222, 1)
+		info := natsInfoFromEvent(&event, []byte(pub), nil)
+		require.NotNil(t, info)
+
+		span := TCPToNATSToSpan(&event, info)
+		assert.Equal(t, request.EventTypeNATSClient, span.Type)
+		assert.Equal(t, request.MessagingPublish, span.Method)
+		assert.Equal(t, "orders.created", span.Path)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 4222, span.HostPort)
+		assert.Equal(t, 0, span.Status)
+	})
+
+	t.Run("client process", func(t *testing.T) {
+		// the server pushes the message to the client, which receives it first
+		event := makeTCPReq(msg, 0, 4222, 33000, 1)
+		info := natsInfoFromEvent(&event, []byte(msg), nil)
+		require.NotNil(t, info)
+
+		span := TCPToNATSToSpan(&event, info)
+		assert.Equal(t, request.EventTypeNATSClient, span.Type)
+		assert.Equal(t, request.MessagingProcess, span.Method)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 4222, span.HostPort)
+	})
+
+	t.Run("server receiving publish", func(t *testing.T) {
+		event := makeTCPReq(pub, 0, 33000, 4222, 1)
+		info := natsInfoFromEvent(&event, []byte(pub), nil)
+		require.NotNil(t, info)
+
+		span := TCPToNATSToSpan(&event, info)
+		assert.Equal(t, request.EventTypeNATSServer, span.Type)
+		assert.Equal(t, request.MessagingPublish, span.Method)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 4222, span.HostPort)
+	})
+
+	t.Run("publish error", func(t *testing.T) {
+		event := makeTCPReq(pub, 1, 33000, 4222, 1)
+		info := natsInfoFromEvent(&event, []byte(pub), []byte("-ERR 'Permissions Violation for Publish to orders.created'\r\n"))
+		require.NotNil(t, info)
+
+		span := TCPToNATSToSpan(&event, info)
+		assert.Equal(t, 1, span.Status)
+		assert.Equal(t, request.StatusCodeError, request.SpanStatusCode(&span))
+	})
+
+	t.Run("traceparent in headers", func(t *testing.T) {
+		hmsg := "HMSG orders.created 1 83 88\r\nNATS/1.0\r\ntraceparent: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01\r\n\r\nhello\r\n"
+		event := makeTCPReq(hmsg, 0, 4222, 33000, 1)
+		info := natsInfoFromEvent(&event, []byte(hmsg), nil)
+		require.NotNil(t, info)
+
+		span := TCPToNATSToSpan(&event, info)
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.TraceID.String())
+		assert.Equal(t, "b7ad6b7169203331", span.ParentSpanID.String())
+		assert.Equal(t, uint8(1), span.TraceFlags)
+	})
+}
+
+func TestParseTraceparent(t *testing.T) {
+	traceID, spanID, flags, ok := parseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.True(t, ok)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", traceID.String())
+	assert.Equal(t, "b7ad6b7169203331", spanID.String())
+	assert.Equal(t, uint8(1), flags)
+
+	for _, invalid := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0af7651916cd43dd8448eb211c80319z-b7ad6b7169203331-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+	} {
+		_, _, _, ok := parseTraceparent(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
@@ -36,6 +36,11 @@ func HTTPRequestTraceToSpan(trace *HTTPRequestTrace) request.Span {
 		schemeHost = strings.Join([]string{scheme, origHost}, request.SchemeHostSeparator)
 	}
 
+	awsOperation := ""
+	if request.EventType(trace.Type) == request.EventTypeHTTPClient {
+		awsOperation = cstr(trace.AwsOperation[:])
+	}
+
 	return request.Span{
 		Type:           request.EventType(trace.Type),
 		Method:         method,
@@ -59,7 +64,8 @@ func HTTPRequestTraceToSpan(trace *HTTPRequestTrace) request.Span {
 			UserPID:   trace.Pid.UserPid,
 			Namespace: trace.Pid.Ns,
 		},
-		Statement: schemeHost,
+		Statement:    schemeHost,
+		AWSOperation: awsOperation,
 	}
 }
 
@@ -87,6 +93,7 @@ func SQLRequestTraceToSpan(trace *SQLRequestTrace) request.Span {
 
 	return request.Span{
 		Type:          request.EventType(trace.Type),
+		SubType:       int(trace.Kind),
 		Method:        method,
 		Path:          path,
 		Peer:          peer,
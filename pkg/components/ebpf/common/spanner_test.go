@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	semconv "go.opentelemetry.io/otel/semconv/v1.19.0"
+
 	"go.opentelemetry.io/obi/pkg/app/request"
 )
 
@@ -141,3 +143,31 @@ func Test_EmptyHostInfo(t *testing.T) {
 	assert.Empty(t, src)
 	assert.Empty(t, dest)
 }
+
+func TestSQLRequestTraceParsing(t *testing.T) {
+	makeSQLTrace := func(sql string, kind request.SQLKind) *SQLRequestTrace {
+		tr := &SQLRequestTrace{
+			Type:            uint8(request.EventTypeSQLClient),
+			Kind:            uint8(kind),
+			StartMonotimeNs: 1000000,
+			EndMonotimeNs:   3000000,
+		}
+		copy(tr.Sql[:], tocstr(sql))
+		return tr
+	}
+
+	t.Run("database/sql query with unknown database", func(t *testing.T) {
+		s := SQLRequestTraceToSpan(makeSQLTrace("SELECT * FROM users", 0))
+		assert.Equal(t, "SELECT", s.Method)
+		assert.Equal(t, "users", s.Path)
+		assert.Equal(t, "SELECT * FROM users", s.Statement)
+		assert.Equal(t, semconv.DBSystemOtherSQL.Value.AsString(), s.DBSystemName().Value.AsString())
+	})
+
+	t.Run("pgx query", func(t *testing.T) {
+		s := SQLRequestTraceToSpan(makeSQLTrace("INSERT INTO accounts VALUES ($1)", request.DBPostgres))
+		assert.Equal(t, "INSERT", s.Method)
+		assert.Equal(t, "accounts", s.Path)
+		assert.Equal(t, semconv.DBSystemPostgreSQL.Value.AsString(), s.DBSystemName().Value.AsString())
+	})
+}
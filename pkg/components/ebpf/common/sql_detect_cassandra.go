@@ -0,0 +1,511 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"encoding/binary"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	trace2 "go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/sqlprune"
+)
+
+// CQL native protocol, versions 3 to 5
+// https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec
+// https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v5.spec
+const (
+	cqlHdrSize        = 9
+	cqlResponseFlag   = 0x80
+	cqlMinVersion     = 3
+	cqlMaxVersion     = 5
+	cqlMaxFrameLength = 256 * 1024 * 1024
+
+	// v5 frames are wrapped into segments after the STARTUP handshake. Uncompressed segments
+	// have a 6 bytes header: 17 bits of payload length, 1 bit of self-contained flag
+	// and 6 bits of padding, followed by a 24 bits CRC of the first 3 bytes.
+	cqlSegmentHdrSize = 6
+
+	cqlFlagCompression   = 0x01
+	cqlFlagTracing       = 0x02
+	cqlFlagCustomPayload = 0x04
+	cqlFlagWarning       = 0x08
+
+	cqlOpError   = 0x00
+	cqlOpQuery   = 0x07
+	cqlOpResult  = 0x08
+	cqlOpPrepare = 0x09
+	cqlOpExecute = 0x0A
+	cqlOpBatch   = 0x0D
+
+	cqlResultRows        = 0x0002
+	cqlResultSetKeyspace = 0x0003
+	cqlResultPrepared    = 0x0004
+
+	cqlMetadataGlobalTablesSpec = 0x0001
+	cqlMetadataHasMorePages     = 0x0002
+	cqlMetadataNoMetadata       = 0x0004
+	cqlMetadataChanged          = 0x0008
+
+	cqlBatchQueryString   = 0
+	cqlBatchQueryPrepared = 1
+)
+
+type cassandraPreparedStatementsKey struct {
+	connInfo BpfConnectionInfoT
+	id       string
+}
+
+type cassandraPreparedStatement struct {
+	query    string
+	keyspace string
+}
+
+type cqlFrame struct {
+	version uint8
+	flags   uint8
+	stream  int16
+	opcode  uint8
+	body    []byte
+}
+
+func (f *cqlFrame) isResponse() bool {
+	return f.version&cqlResponseFlag != 0
+}
+
+// cqlReader reads the CQL notation types from a frame body. Any read past the
+// end of the buffer marks the reader as failed.
+type cqlReader struct {
+	buf []byte
+	err bool
+}
+
+func (r *cqlReader) bytes(n int) []byte {
+	if r.err || n < 0 || n > len(r.buf) {
+		r.err = true
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *cqlReader) byte() uint8 {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *cqlReader) short() uint16 {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (r *cqlReader) int() int32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+// string reads a [string]: a [short] n, followed by n bytes
+func (r *cqlReader) string() string {
+	return string(r.bytes(int(r.short())))
+}
+
+// shortBytes reads a [short bytes]: a [short] n, followed by n bytes
+func (r *cqlReader) shortBytes() []byte {
+	return r.bytes(int(r.short()))
+}
+
+// longString reads a [long string]: an [int] n, followed by n bytes. The
+// string might have been truncated by the capture, so we return what we have.
+func (r *cqlReader) longString() string {
+	n := int(r.int())
+	if r.err || n <= 0 {
+		r.err = true
+		return ""
+	}
+	return string(r.buf[:min(n, len(r.buf))])
+}
+
+// skipBytesMap skips a [bytes map]: a [short] n, followed by n [string] and [bytes] pairs
+func (r *cqlReader) skipBytesMap() {
+	n := int(r.short())
+	for i := 0; i < n && !r.err; i++ {
+		r.string()
+		r.bytes(int(r.int()))
+	}
+}
+
+// skipStringList skips a [string list]: a [short] n, followed by n [string]
+func (r *cqlReader) skipStringList() {
+	n := int(r.short())
+	for i := 0; i < n && !r.err; i++ {
+		r.string()
+	}
+}
+
+func parseCQLFrameHeader(b []byte) (*cqlFrame, int, bool) {
+	if len(b) < cqlHdrSize {
+		return nil, 0, false
+	}
+	version := b[0] &^ cqlResponseFlag
+	if version < cqlMinVersion || version > cqlMaxVersion {
+		return nil, 0, false
+	}
+	length := binary.BigEndian.Uint32(b[5:9])
+	if length > cqlMaxFrameLength {
+		return nil, 0, false
+	}
+	return &cqlFrame{
+		version: b[0],
+		flags:   b[1],
+		stream:  int16(binary.BigEndian.Uint16(b[2:4])),
+		opcode:  b[4],
+	}, int(length), true
+}
+
+// parseCQLFrame parses the first CQL frame in the buffer, unwrapping it from a
+// v5 segment if needed. The frame body might have been truncated by the capture.
+func parseCQLFrame(b []byte) (*cqlFrame, bool) {
+	frame, length, ok := parseCQLFrameHeader(b)
+	if !ok && len(b) > cqlSegmentHdrSize {
+		// might be a v5 segment
+		segLen := int((uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16) & 0x1FFFF)
+		b = b[cqlSegmentHdrSize:]
+		if frame, length, ok = parseCQLFrameHeader(b); !ok ||
+			frame.version&^cqlResponseFlag != 5 || length+cqlHdrSize > segLen {
+			return nil, false
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	frame.body = b[cqlHdrSize:min(cqlHdrSize+length, len(b))]
+	return frame, true
+}
+
+func isCQLRequestOpcode(opcode uint8) bool {
+	switch opcode {
+	case cqlOpQuery, cqlOpPrepare, cqlOpExecute, cqlOpBatch:
+		return true
+	}
+	return false
+}
+
+func isCQLRequest(b []byte) bool {
+	frame, ok := parseCQLFrame(b)
+	return ok && !frame.isResponse() && isCQLRequestOpcode(frame.opcode) && frame.flags&cqlFlagCompression == 0
+}
+
+// cqlRequestAndResponse returns the request and response frames of a CQL exchange,
+// and whether the buffers were captured in the reverse order
+func cqlRequestAndResponse(requestBuffer, responseBuffer []byte) (*cqlFrame, *cqlFrame, bool, bool) {
+	reversed := false
+	if !isCQLRequest(requestBuffer) {
+		if !isCQLRequest(responseBuffer) {
+			return nil, nil, false, false
+		}
+		requestBuffer, responseBuffer = responseBuffer, requestBuffer
+		reversed = true
+	}
+
+	req, _ := parseCQLFrame(requestBuffer)
+	resp, ok := parseCQLFrame(responseBuffer)
+	if !ok || !resp.isResponse() || resp.stream != req.stream ||
+		(resp.opcode != cqlOpResult && resp.opcode != cqlOpError) {
+		return nil, nil, false, false
+	}
+
+	return req, resp, reversed, true
+}
+
+func handleCassandra(parseCtx *EBPFParseContext, event *TCPRequestInfo, requestBuffer, responseBuffer []byte) (request.Span, error) {
+	var span request.Span
+
+	req, resp, reversed, ok := cqlRequestAndResponse(requestBuffer, responseBuffer)
+	if !ok {
+		return span, errFallback
+	}
+
+	// we only report the client side of the connection
+	clientSide := event.Direction != 0
+	if reversed {
+		clientSide = !clientSide
+	}
+	if !clientSide {
+		return span, errIgnore
+	}
+	if reversed {
+		reverseTCPEvent(event)
+	}
+
+	// from this point on, we know it's a CQL exchange, so we don't let other protocol
+	// detectors have it, even if we can't parse it
+
+	var (
+		op, table, query, keyspace string
+		r                          = cqlReader{buf: req.body}
+	)
+
+	if req.flags&cqlFlagCustomPayload != 0 {
+		r.skipBytesMap()
+	}
+
+	switch req.opcode {
+	case cqlOpQuery, cqlOpPrepare:
+		query = r.longString()
+	case cqlOpExecute:
+		id := r.shortBytes()
+		if r.err {
+			return span, errIgnore
+		}
+		stmt, found := cassandraPreparedStatement{}, false
+		if parseCtx.cassandraPreparedStatements != nil {
+			stmt, found = parseCtx.cassandraPreparedStatements.Get(cassandraPreparedStatementsKey{
+				connInfo: event.ConnInfo,
+				id:       string(id),
+			})
+		}
+		if !found {
+			slog.Debug("Cassandra EXECUTE command with unknown prepared statement")
+			op = "EXECUTE"
+		}
+		query, keyspace = stmt.query, stmt.keyspace
+	case cqlOpBatch:
+		r.byte() // batch type
+		if r.short() > 0 {
+			switch r.byte() {
+			case cqlBatchQueryString:
+				query = r.longString()
+			case cqlBatchQueryPrepared:
+				id := r.shortBytes()
+				if !r.err && parseCtx.cassandraPreparedStatements != nil {
+					stmt, _ := parseCtx.cassandraPreparedStatements.Get(cassandraPreparedStatementsKey{
+						connInfo: event.ConnInfo,
+						id:       string(id),
+					})
+					query, keyspace = stmt.query, stmt.keyspace
+				}
+			}
+		}
+		op = "BATCH"
+	}
+	if r.err {
+		return span, errIgnore
+	}
+
+	if query != "" {
+		stmtOp, stmtTable, stmtKeyspace := cqlParseOperationAndTable(query)
+		if op == "" {
+			op = stmtOp
+		}
+		table = stmtTable
+		if stmtKeyspace != "" {
+			keyspace = stmtKeyspace
+		}
+	}
+
+	result, dbErr, failed := parseCQLResponse(resp, req.version&^cqlResponseFlag)
+	if result.keyspace != "" {
+		keyspace = result.keyspace
+	}
+	if table == "" {
+		table = result.table
+	}
+
+	if keyspace != "" {
+		if req.opcode == cqlOpQuery && result.kind == cqlResultSetKeyspace {
+			parseCtx.setCassandraKeyspace(event.ConnInfo, keyspace)
+		}
+	} else {
+		keyspace = parseCtx.cassandraKeyspace(event.ConnInfo)
+	}
+
+	if req.opcode == cqlOpPrepare {
+		if result.kind == cqlResultPrepared && parseCtx.cassandraPreparedStatements != nil {
+			parseCtx.cassandraPreparedStatements.Add(cassandraPreparedStatementsKey{
+				connInfo: event.ConnInfo,
+				id:       string(result.preparedID),
+			}, cassandraPreparedStatement{query: query, keyspace: keyspace})
+		}
+		// prepared statements are reported when they are executed
+		if !failed {
+			return span, errIgnore
+		}
+		op = "PREPARE"
+	}
+
+	if op == "" {
+		return span, errIgnore
+	}
+
+	return TCPToCassandraToSpan(event, op, table, query, keyspace, failed, dbErr), nil
+}
+
+type cqlResult struct {
+	kind       int32
+	keyspace   string
+	table      string
+	preparedID []byte
+}
+
+// parseCQLResponse parses the RESULT or ERROR response. The keyspace and table are
+// extracted from the result metadata, when it's present and the global tables spec is set.
+func parseCQLResponse(resp *cqlFrame, version uint8) (cqlResult, request.DBError, bool) {
+	var result cqlResult
+
+	r := cqlReader{buf: resp.body}
+	if resp.flags&cqlFlagTracing != 0 {
+		r.bytes(16) // tracing session UUID
+	}
+	if resp.flags&cqlFlagWarning != 0 {
+		r.skipStringList()
+	}
+	if resp.flags&cqlFlagCustomPayload != 0 {
+		r.skipBytesMap()
+	}
+
+	if resp.opcode == cqlOpError {
+		code := uint32(r.int())
+		// the error message might have been truncated by the capture
+		n := int(r.short())
+		message := ""
+		if !r.err {
+			message = string(r.buf[:min(n, len(r.buf))])
+		}
+		return result, request.DBError{
+			ErrorCode:   "0x" + strconv.FormatUint(uint64(code), 16),
+			Description: message,
+		}, true
+	}
+
+	result.kind = r.int()
+	switch result.kind {
+	case cqlResultSetKeyspace:
+		result.keyspace = r.string()
+	case cqlResultRows:
+		flags := r.int()
+		r.int() // columns count
+		if flags&cqlMetadataHasMorePages != 0 {
+			r.bytes(int(r.int())) // paging state
+		}
+		if version >= 5 && flags&cqlMetadataChanged != 0 {
+			r.shortBytes() // new metadata ID
+		}
+		if flags&cqlMetadataNoMetadata == 0 && flags&cqlMetadataGlobalTablesSpec != 0 {
+			result.keyspace = r.string()
+			result.table = r.string()
+		}
+	case cqlResultPrepared:
+		result.preparedID = r.shortBytes()
+		if version >= 5 {
+			r.shortBytes() // result metadata ID
+		}
+		flags := r.int()
+		r.int() // columns count
+		pkCount := int(r.int())
+		r.bytes(pkCount * 2) // partition key indexes
+		if flags&cqlMetadataGlobalTablesSpec != 0 {
+			result.keyspace = r.string()
+			result.table = r.string()
+		}
+	}
+
+	if r.err {
+		// the metadata might have been truncated, we keep what we could read
+		result.keyspace, result.table = "", ""
+		if result.kind == cqlResultPrepared && len(result.preparedID) == 0 {
+			result.kind = 0
+		}
+	}
+
+	return result, request.DBError{}, false
+}
+
+// cqlParseOperationAndTable returns the operation, table and keyspace of a CQL query.
+// Tables might be qualified with the keyspace name.
+func cqlParseOperationAndTable(query string) (string, string, string) {
+	fields := strings.Fields(query)
+	if len(fields) > 1 && asciiToUpper(fields[0]) == "USE" {
+		return "USE", "", strings.Trim(strings.TrimSuffix(fields[1], ";"), "\"")
+	}
+
+	op, table := sqlprune.SQLParseOperationAndTable(query)
+	keyspace := ""
+	if ks, t, found := strings.Cut(table, "."); found && !strings.Contains(table, ",") {
+		keyspace, table = ks, t
+	}
+	return op, table, keyspace
+}
+
+func (ctx *EBPFParseContext) cassandraKeyspace(conn BpfConnectionInfoT) string {
+	if ctx.cassandraKeyspaces == nil {
+		return ""
+	}
+	ks, _ := ctx.cassandraKeyspaces.Get(conn)
+	return ks
+}
+
+func (ctx *EBPFParseContext) setCassandraKeyspace(conn BpfConnectionInfoT, keyspace string) {
+	if ctx.cassandraKeyspaces != nil {
+		ctx.cassandraKeyspaces.Add(conn, keyspace)
+	}
+}
+
+func TCPToCassandraToSpan(trace *TCPRequestInfo, op, table, query, keyspace string, failed bool, dbErr request.DBError) request.Span {
+	peer := ""
+	peerPort := 0
+	hostname := ""
+	hostPort := 0
+
+	if trace.ConnInfo.S_port != 0 || trace.ConnInfo.D_port != 0 {
+		peer, hostname = (*BPFConnInfo)(unsafe.Pointer(&trace.ConnInfo)).reqHostInfo()
+		peerPort = int(trace.ConnInfo.S_port)
+		hostPort = int(trace.ConnInfo.D_port)
+	}
+
+	status := 0
+	if failed {
+		status = 1
+	}
+
+	return request.Span{
+		Type:          request.EventTypeCassandraClient,
+		Method:        op,
+		Path:          table,
+		Statement:     query,
+		DBNamespace:   keyspace,
+		Peer:          peer,
+		PeerPort:      peerPort,
+		Host:          hostname,
+		HostPort:      hostPort,
+		ContentLength: int64(trace.ReqLen),
+		RequestStart:  int64(trace.StartMonotimeNs),
+		Start:         int64(trace.StartMonotimeNs),
+		End:           int64(trace.EndMonotimeNs),
+		Status:        status,
+		DBError:       dbErr,
+		TraceID:       trace2.TraceID(trace.Tp.TraceId),
+		SpanID:        trace2.SpanID(trace.Tp.SpanId),
+		ParentSpanID:  trace2.SpanID(trace.Tp.ParentId),
+		TraceFlags:    trace.Tp.Flags,
+		Pid: request.PidInfo{
+			HostPID:   trace.Pid.HostPid,
+			UserPID:   trace.Pid.UserPid,
+			Namespace: trace.Pid.Ns,
+		},
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
+	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/config"
+)
+
+func cqlFrameBytes(version, flags uint8, stream int16, opcode uint8, body []byte) []byte {
+	b := make([]byte, cqlHdrSize, cqlHdrSize+len(body))
+	b[0] = version
+	b[1] = flags
+	binary.BigEndian.PutUint16(b[2:4], uint16(stream))
+	b[4] = opcode
+	binary.BigEndian.PutUint32(b[5:9], uint32(len(body)))
+	return append(b, body...)
+}
+
+func cqlInt(v int32) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(v))
+}
+
+func cqlShort(v uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, v)
+}
+
+func cqlString(s string) []byte {
+	return append(cqlShort(uint16(len(s))), s...)
+}
+
+func cqlLongString(s string) []byte {
+	return append(cqlInt(int32(len(s))), s...)
+}
+
+func cqlConcat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func cqlQuery(stream int16, query string) []byte {
+	// query, consistency ONE, no flags
+	return cqlFrameBytes(0x04, 0, stream, cqlOpQuery, cqlConcat(cqlLongString(query), cqlShort(1), []byte{0}))
+}
+
+func cqlRowsResult(stream int16, keyspace, table string) []byte {
+	return cqlFrameBytes(0x84, 0, stream, cqlOpResult, cqlConcat(
+		cqlInt(cqlResultRows), cqlInt(cqlMetadataGlobalTablesSpec), cqlInt(1), cqlString(keyspace), cqlString(table)))
+}
+
+func cqlVoidResult(stream int16) []byte {
+	return cqlFrameBytes(0x84, 0, stream, cqlOpResult, cqlInt(1))
+}
+
+func cqlTestContext() *EBPFParseContext {
+	return NewEBPFParseContext(&config.EBPFTracer{CassandraPreparedStatementsCacheSize: 10, CassandraKeyspacesCacheSize: 10})
+}
+
+func TestCQLParseOperationAndTable(t *testing.T) {
+	tests := []struct {
+		query, op, table, keyspace string
+	}{
+		{query: "SELECT * FROM users WHERE id = ?", op: "SELECT", table: "users"},
+		{query: "SELECT * FROM shop.users WHERE id = ?", op: "SELECT", table: "users", keyspace: "shop"},
+		{query: "INSERT INTO shop.orders (id, total) VALUES (?, ?)", op: "INSERT", table: "orders", keyspace: "shop"},
+		{query: "USE shop", op: "USE", keyspace: "shop"},
+		{query: "use \"Shop\";", op: "USE", keyspace: "Shop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			op, table, keyspace := cqlParseOperationAndTable(tt.query)
+			assert.Equal(t, tt.op, op)
+			assert.Equal(t, tt.table, table)
+			assert.Equal(t, tt.keyspace, keyspace)
+		})
+	}
+}
+
+func TestHandleCassandraQuery(t *testing.T) {
+	ctx := cqlTestContext()
+
+	req := cqlQuery(5, "SELECT * FROM users WHERE id = ?")
+	resp := cqlRowsResult(5, "shop", "users")
+	event := makeTCPReq(string(req), 1, 33000, 9042, 1)
+
+	span, err := handleCassandra(ctx, &event, req, resp)
+	require.NoError(t, err)
+
+	assert.Equal(t, request.EventTypeCassandraClient, span.Type)
+	assert.Equal(t, "SELECT", span.Method)
+	assert.Equal(t, "users", span.Path)
+	assert.Equal(t, "shop", span.DBNamespace)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", span.Statement)
+	assert.Equal(t, 33000, span.PeerPort)
+	assert.Equal(t, 9042, span.HostPort)
+	assert.Equal(t, 0, span.Status)
+}
+
+func TestHandleCassandraKeyspace(t *testing.T) {
+	ctx := cqlTestContext()
+
+	use := cqlQuery(1, "USE shop")
+	setKeyspace := cqlFrameBytes(0x84, 0, 1, cqlOpResult, cqlConcat(cqlInt(cqlResultSetKeyspace), cqlString("shop")))
+	event := makeTCPReq(string(use), 1, 33000, 9042, 1)
+
+	span, err := handleCassandra(ctx, &event, use, setKeyspace)
+	require.NoError(t, err)
+	assert.Equal(t, "USE", span.Method)
+	assert.Equal(t, "shop", span.DBNamespace)
+
+	// the following statements on the same connection inherit the keyspace
+	insert := cqlQuery(2, "INSERT INTO orders (id) VALUES (?)")
+	event = makeTCPReq(string(insert), 1, 33000, 9042, 1)
+
+	span, err = handleCassandra(ctx, &event, insert, cqlVoidResult(2))
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT", span.Method)
+	assert.Equal(t, "orders", span.Path)
+	assert.Equal(t, "shop", span.DBNamespace)
+}
+
+func TestHandleCassandraPreparedStatements(t *testing.T) {
+	ctx := cqlTestContext()
+
+	prepare := cqlFrameBytes(0x04, 0, 3, cqlOpPrepare, cqlLongString("SELECT * FROM shop.users WHERE id = ?"))
+	prepared := cqlFrameBytes(0x84, 0, 3, cqlOpResult, cqlConcat(
+		cqlInt(cqlResultPrepared), cqlString("stmt-1"),
+		cqlInt(0), cqlInt(1), cqlInt(1), cqlShort(0), // metadata without global tables spec
+		cqlInt(cqlMetadataNoMetadata), cqlInt(1), // result metadata
+	))
+	event := makeTCPReq(string(prepare), 1, 33000, 9042, 1)
+
+	_, err := handleCassandra(ctx, &event, prepare, prepared)
+	require.ErrorIs(t, err, errIgnore)
+
+	execute := cqlFrameBytes(0x04, 0, 4, cqlOpExecute, cqlConcat(cqlString("stmt-1"), cqlShort(1), []byte{0}))
+	event = makeTCPReq(string(execute), 1, 33000, 9042, 1)
+
+	span, err := handleCassandra(ctx, &event, execute, cqlVoidResult(4))
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT", span.Method)
+	assert.Equal(t, "users", span.Path)
+	assert.Equal(t, "shop", span.DBNamespace)
+	assert.Equal(t, "SELECT * FROM shop.users WHERE id = ?", span.Statement)
+
+	// unknown statements on other connections are still reported
+	event = makeTCPReq(string(execute), 1, 33001, 9042, 1)
+	span, err = handleCassandra(ctx, &event, execute, cqlVoidResult(4))
+	require.NoError(t, err)
+	assert.Equal(t, "EXECUTE", span.Method)
+	assert.Empty(t, span.Statement)
+}
+
+func TestHandleCassandraBatch(t *testing.T) {
+	ctx := cqlTestContext()
+
+	batch := cqlFrameBytes(0x04, 0, 6, cqlOpBatch, cqlConcat(
+		[]byte{0}, cqlShort(2),
+		[]byte{cqlBatchQueryString}, cqlLongString("INSERT INTO shop.orders (id) VALUES (1)"), cqlShort(0),
+		[]byte{cqlBatchQueryString}, cqlLongString("INSERT INTO shop.orders (id) VALUES (2)"), cqlShort(0),
+	))
+	event := makeTCPReq(string(batch), 1, 33000, 9042, 1)
+
+	span, err := handleCassandra(ctx, &event, batch, cqlVoidResult(6))
+	require.NoError(t, err)
+	assert.Equal(t, "BATCH", span.Method)
+	assert.Equal(t, "orders", span.Path)
+	assert.Equal(t, "shop", span.DBNamespace)
+}
+
+func TestHandleCassandraError(t *testing.T) {
+	ctx := cqlTestContext()
+
+	req := cqlQuery(7, "SELECT * FROM shop.missing")
+	resp := cqlFrameBytes(0x84, 0, 7, cqlOpError, cqlConcat(cqlInt(0x2200), cqlString("unconfigured table missing")))
+	event := makeTCPReq(string(req), 1, 33000, 9042, 1)
+
+	span, err := handleCassandra(ctx, &event, req, resp)
+	require.NoError(t, err)
+	assert.Equal(t, 1, span.Status)
+	assert.Equal(t, request.DBError{ErrorCode: "0x2200", Description: "unconfigured table missing"}, span.DBError)
+	assert.Equal(t, request.StatusCodeError, request.SpanStatusCode(&span))
+	assert.Equal(t, "unconfigured table missing", request.SpanStatusMessage(&span))
+}
+
+func TestHandleCassandraDirection(t *testing.T) {
+	req := cqlQuery(5, "SELECT * FROM shop.users")
+	resp := cqlRowsResult(5, "shop", "users")
+
+	t.Run("reversed", func(t *testing.T) {
+		event := makeTCPReq(string(resp), 0, 9042, 33000, 1)
+		span, err := handleCassandra(cqlTestContext(), &event, resp, req)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT", span.Method)
+		assert.Equal(t, 33000, span.PeerPort)
+		assert.Equal(t, 9042, span.HostPort)
+	})
+
+	t.Run("server side", func(t *testing.T) {
+		event := makeTCPReq(string(req), 0, 33000, 9042, 1)
+		_, err := handleCassandra(cqlTestContext(), &event, req, resp)
+		require.ErrorIs(t, err, errIgnore)
+	})
+
+	t.Run("mismatched stream", func(t *testing.T) {
+		event := makeTCPReq(string(req), 1, 33000, 9042, 1)
+		_, err := handleCassandra(cqlTestContext(), &event, req, cqlRowsResult(6, "shop", "users"))
+		require.ErrorIs(t, err, errFallback)
+	})
+
+	t.Run("not cql", func(t *testing.T) {
+		buf := []byte("GET / HTTP/1.1\r\n\r\n")
+		event := makeTCPReq(string(buf), 1, 33000, 9042, 1)
+		_, err := handleCassandra(cqlTestContext(), &event, buf, []byte("HTTP/1.1 200 OK\r\n\r\n"))
+		require.ErrorIs(t, err, errFallback)
+	})
+}
+
+func TestParseCQLFrameV5Segment(t *testing.T) {
+	frame := cqlFrameBytes(0x05, 0, 1, cqlOpQuery, cqlConcat(cqlLongString("SELECT * FROM shop.users"), cqlShort(1), cqlInt(0)))
+	segment := make([]byte, cqlSegmentHdrSize)
+	segment[0] = byte(len(frame))
+	segment[1] = byte(len(frame) >> 8)
+	segment[2] = 0x02 // self-contained
+	segment = append(segment, frame...)
+
+	f, ok := parseCQLFrame(segment)
+	require.True(t, ok)
+	assert.Equal(t, uint8(cqlOpQuery), f.opcode)
+	assert.True(t, isCQLRequest(segment))
+}
+
+func TestReadTCPRequestIntoSpan_CassandraDetection(t *testing.T) {
+	req := cqlQuery(5, "SELECT * FROM users WHERE id = ?")
+	resp := cqlRowsResult(5, "shop", "users")
+	event := makeTCPReq(string(req), 1, 33000, 9042, 1)
+	copy(event.Rbuf[:], resp)
+	event.RespLen = uint32(len(resp))
+	binaryRecord := bytes.Buffer{}
+	require.NoError(t, binary.Write(&binaryRecord, binary.LittleEndian, event))
+	fltr := TestPidsFilter{services: map[uint32]svc.Attrs{}}
+
+	cfg := config.EBPFTracer{CassandraPreparedStatementsCacheSize: 10, CassandraKeyspacesCacheSize: 10}
+	_, ignore, err := ReadTCPRequestIntoSpan(NewEBPFParseContext(&cfg), &cfg,
+		&ringbuf.Record{RawSample: binaryRecord.Bytes()}, &fltr)
+	require.NoError(t, err)
+	assert.True(t, ignore, "CQL must not be parsed when the Cassandra instrumentation is not selected")
+
+	cfg.CassandraDetection = true
+	span, ignore, err := ReadTCPRequestIntoSpan(NewEBPFParseContext(&cfg), &cfg,
+		&ringbuf.Record{RawSample: binaryRecord.Bytes()}, &fltr)
+	require.NoError(t, err)
+	require.False(t, ignore)
+	assert.Equal(t, request.EventTypeCassandraClient, span.Type)
+	assert.Equal(t, "users", span.Path)
+}
@@ -77,6 +77,19 @@ func ReadTCPRequestIntoSpan(parseCtx *EBPFParseContext, cfg *config.EBPFTracer,
 	default:
 	}
 
+	// CQL queries would be also detected as SQL, so Cassandra must be checked first.
+	// It's only checked if any exporter reports Cassandra, to avoid the parsing cost and the
+	// misclassification risk of the other protocols
+	if cfg.CassandraDetection {
+		span, err := handleCassandra(parseCtx, event, requestBuffer, responseBuffer)
+		switch {
+		case errors.Is(err, errIgnore):
+			return request.Span{}, true, nil
+		case err == nil:
+			return span, false, nil
+		}
+	}
+
 	// Check if we have a SQL statement
 	op, table, sql, kind := detectSQLPayload(cfg.HeuristicSQLDetect, requestBuffer)
 	if validSQL(op, table, kind) {
@@ -100,6 +113,22 @@ func ReadTCPRequestIntoSpan(parseCtx *EBPFParseContext, cfg *config.EBPFTracer,
 		mongoSpan := TCPToMongoToSpan(event, mongoInfo)
 		return mongoSpan, false, nil
 	}
+	memcachedInfo := memcachedInfoFromEvent(event, requestBuffer, responseBuffer)
+	if memcachedInfo != nil {
+		return TCPToMemcachedToSpan(event, memcachedInfo), false, nil
+	}
+	amqpInfo := amqpInfoFromEvent(event, requestBuffer, responseBuffer)
+	if amqpInfo != nil {
+		return TCPToAMQPToSpan(event, amqpInfo), false, nil
+	}
+	natsInfo := natsInfoFromEvent(event, requestBuffer, responseBuffer)
+	if natsInfo != nil {
+		return TCPToNATSToSpan(event, natsInfo), false, nil
+	}
+	mqttInfo := mqttInfoFromEvent(event, requestBuffer, responseBuffer)
+	if mqttInfo != nil {
+		return TCPToMQTTToSpan(event, mqttInfo), false, nil
+	}
 
 	switch {
 	case isRedis(requestBuffer) && isRedis(responseBuffer):
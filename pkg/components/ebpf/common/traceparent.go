@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"encoding/hex"
+	"strings"
+
+	trace2 "go.opentelemetry.io/otel/trace"
+)
+
+// parseTraceparent parses the value of a W3C traceparent header, as found in the
+// headers or properties of messaging protocols that we decode in user space.
+// https://www.w3.org/TR/trace-context/#traceparent-header
+func parseTraceparent(val string) (trace2.TraceID, trace2.SpanID, uint8, bool) {
+	var traceID trace2.TraceID
+	var spanID trace2.SpanID
+
+	parts := strings.Split(strings.TrimSpace(val), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return traceID, spanID, 0, false
+	}
+
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || !traceID.IsValid() {
+		return traceID, spanID, 0, false
+	}
+	if _, err := hex.Decode(spanID[:], []byte(parts[2])); err != nil || !spanID.IsValid() {
+		return traceID, spanID, 0, false
+	}
+	var flags [1]byte
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return traceID, spanID, 0, false
+	}
+
+	return traceID, spanID, flags[0], true
+}
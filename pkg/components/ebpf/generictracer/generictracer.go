@@ -310,6 +310,19 @@ func (p *Tracer) KProbes() map[string]ebpfcommon.ProbeDesc {
 		},
 	}
 
+	if p.cfg.SelectedInstrumentations().DNSEnabled() {
+		// Tracking of DNS lookups over UDP
+		kp["udp_sendmsg"] = ebpfcommon.ProbeDesc{
+			Start: p.bpfObjects.ObiKprobeUdpSendmsg,
+		}
+		kp["udpv6_sendmsg"] = ebpfcommon.ProbeDesc{
+			Start: p.bpfObjects.ObiKprobeUdpv6Sendmsg,
+		}
+		kp["skb_consume_udp"] = ebpfcommon.ProbeDesc{
+			Start: p.bpfObjects.ObiKprobeSkbConsumeUdp,
+		}
+	}
+
 	if p.cfg.EBPF.ContextPropagation != config.ContextPropagationDisabled {
 		// tcp_rate_check_app_limited and tcp_sendmsg_fastopen are backup
 		// for tcp_sendmsg_locked which doesn't fire on certain kernels
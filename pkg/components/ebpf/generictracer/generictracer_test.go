@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/obi"
 )
 
 func TestBitPositionCalculation(t *testing.T) {
@@ -30,3 +33,21 @@ func TestBitPositionCalculation(t *testing.T) {
 func makeKey(first, second uint32) uint64 {
 	return (uint64(first) << 32) | uint64(second)
 }
+
+func TestKProbes_DNS(t *testing.T) {
+	dnsProbes := []string{"udp_sendmsg", "udpv6_sendmsg", "skb_consume_udp"}
+
+	cfg := &obi.Config{}
+	cfg.Traces.TracesEndpoint = "http://localhost:4318"
+	cfg.Traces.Instrumentations = []string{instrumentations.InstrumentationHTTP}
+	probes := New(nil, cfg, nil).KProbes()
+	for _, fn := range dnsProbes {
+		assert.NotContains(t, probes, fn)
+	}
+
+	cfg.Traces.Instrumentations = []string{instrumentations.InstrumentationHTTP, instrumentations.InstrumentationDNS}
+	probes = New(nil, cfg, nil).KProbes()
+	for _, fn := range dnsProbes {
+		assert.Contains(t, probes, fn)
+	}
+}
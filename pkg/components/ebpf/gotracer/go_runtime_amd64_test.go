@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package gotracer
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/arch/x86/x86asm"
+
+	"go.opentelemetry.io/obi/pkg/obi"
+)
+
+// Goroutines that are woken up by the netpoller are made runnable by injectglist, which
+// enqueues them with runqputbatch or globrunqputbatch instead of runqput. This test wakes up
+// a goroutine from the netpoller and verifies, in the test binary, that such path goes
+// through the casgstatus function that is hooked by the scheduler probes.
+func TestSchedProbes_NetpollWokenGoroutine(t *testing.T) {
+	cfg := &obi.Config{}
+	cfg.EBPF.InstrumentGoScheduler = true
+	require.Contains(t, New(nil, cfg, nil).GoProbes(), "runtime.casgstatus")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	read := make(chan error, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			read <- err
+			return
+		}
+		defer conn.Close()
+		// the goroutine waits in the netpoller until the client writes
+		_, err = conn.Read(make([]byte, 1))
+		read <- err
+	}()
+	client, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	time.Sleep(50 * time.Millisecond)
+	_, err = client.Write([]byte{1})
+	require.NoError(t, err)
+	select {
+	case err := <-read:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the netpoll-woken goroutine")
+	}
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	elfFile, err := elf.Open(exe)
+	require.NoError(t, err)
+	defer elfFile.Close()
+	text := elfFile.Section(".text")
+	require.NotNil(t, text)
+	pclntab, err := elfFile.Section(".gopclntab").Data()
+	require.NoError(t, err)
+	symTab, err := gosym.NewTable(nil, gosym.NewLineTable(pclntab, text.Addr))
+	require.NoError(t, err)
+	casgstatus := symTab.LookupFunc("runtime.casgstatus")
+	require.NotNil(t, casgstatus)
+	injectglist := symTab.LookupFunc("runtime.injectglist")
+	require.NotNil(t, injectglist)
+
+	calls := calledFunctions(t, text, injectglist)
+	assert.Contains(t, calls, casgstatus.Entry,
+		"injectglist should mark the goroutines as runnable with casgstatus")
+	if runqput := symTab.LookupFunc("runtime.runqput"); runqput != nil {
+		assert.NotContains(t, calls, runqput.Entry,
+			"injectglist isn't expected to enqueue the goroutines with runqput")
+	}
+}
+
+// calledFunctions returns the addresses of the functions that are directly called from fn
+func calledFunctions(t *testing.T, text *elf.Section, fn *gosym.Func) []uint64 {
+	t.Helper()
+	data, err := text.Data()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, fn.Entry, text.Addr)
+	code := data[fn.Entry-text.Addr : fn.End-text.Addr]
+
+	var calls []uint64
+	for pc := 0; pc < len(code); {
+		inst, err := x86asm.Decode(code[pc:], 64)
+		if err != nil {
+			// skip undecodable bytes, such as the padding between functions
+			pc++
+			continue
+		}
+		pc += inst.Len
+		if rel, ok := inst.Args[0].(x86asm.Rel); ok && inst.Op == x86asm.CALL {
+			calls = append(calls, uint64(int64(fn.Entry)+int64(pc)+int64(rel)))
+		}
+	}
+	return calls
+}
@@ -162,6 +162,12 @@ func (p *Tracer) RegisterOffsets(fileInfo *exec.FileInfo, offsets *goexec.Offset
 		goexec.GrpcTransportBufWriterConnPos,
 		// redis
 		goexec.RedisConnBwPos,
+		// pgx
+		goexec.PgxBatchQueuedQueriesPos,
+		goexec.PgxQueuedQuerySQLPos,
+		// mongo driver
+		goexec.MongoOperationDatabasePos,
+		goexec.MongoOperationNamePos,
 		// kafka go
 		goexec.KafkaGoWriterTopicPos,
 		goexec.KafkaGoProtocolConnPos,
@@ -181,6 +187,23 @@ func (p *Tracer) RegisterOffsets(fileInfo *exec.FileInfo, offsets *goexec.Offset
 		goexec.GoTracerDelegatePos,
 		// go jsonrpc
 		goexec.GoJsonrpcRequestHeaderServiceMethodPos,
+		// fasthttp
+		goexec.FasthttpRequestHeaderMethodPos,
+		goexec.FasthttpRequestHeaderRequestURIPos,
+		goexec.FasthttpRequestHeaderContentLengthPos,
+		goexec.FasthttpRequestHeaderHPos,
+		goexec.FasthttpResponseHeaderStatusCodePos,
+		goexec.FasthttpResponseHeaderContentLengthPos,
+		goexec.FasthttpRequestHeaderPos,
+		goexec.FasthttpRequestURIPos,
+		goexec.FasthttpResponseHeaderPos,
+		goexec.FasthttpURIPathPos,
+		goexec.FasthttpURIHostPos,
+		goexec.FasthttpURISchemePos,
+		goexec.FasthttpHostClientIsTLSPos,
+		goexec.FasthttpClientConnConnPos,
+		// go runtime versioning
+		goexec.GoStwReason,
 	} {
 		if val, ok := offsets.Field[field].(uint64); ok {
 			offTable.Table[field] = val
@@ -303,6 +326,29 @@ func (p *Tracer) GoProbes() map[string][]*ebpfcommon.ProbeDesc {
 		"net/http.(*persistConn).roundTrip": {{ // http client
 			Start: p.bpfObjects.ObiUprobePersistConnRoundTrip,
 		}},
+		// fasthttp
+		"github.com/valyala/fasthttp.(*Server).serveConn": {{ // server connection tracking
+			Start: p.bpfObjects.ObiUprobeConnServe,
+			End:   p.bpfObjects.ObiUprobeConnServeRet,
+		}},
+		"github.com/valyala/fasthttp.(*RequestHeader).readLoop": {{ // server request start
+			Start: p.bpfObjects.ObiUprobeFasthttpRequestHeaderRead,
+			End:   p.bpfObjects.ObiUprobeFasthttpRequestHeaderReadReturns,
+		}},
+		"github.com/valyala/fasthttp.(*Response).Write": {{ // server request end
+			Start: p.bpfObjects.ObiUprobeFasthttpResponseWrite,
+			End:   p.bpfObjects.ObiUprobeFasthttpResponseWriteReturns,
+		}},
+		"github.com/valyala/fasthttp.(*HostClient).doNonNilReqResp": {{ // client
+			Start: p.bpfObjects.ObiUprobeFasthttpDoNonNilReqResp,
+			End:   p.bpfObjects.ObiUprobeFasthttpDoNonNilReqRespReturns,
+		}},
+		"github.com/valyala/fasthttp.(*HostClient).acquireConn": {{ // client connection, before v1.65.0
+			End: p.bpfObjects.ObiUprobeFasthttpAcquireConnReturns,
+		}},
+		"github.com/valyala/fasthttp.(*HostClient).AcquireConn": {{ // client connection
+			End: p.bpfObjects.ObiUprobeFasthttpAcquireConnReturns,
+		}},
 		// sql
 		"database/sql.(*DB).queryDC": {{
 			Start: p.bpfObjects.ObiUprobeQueryDC,
@@ -312,6 +358,34 @@ func (p *Tracer) GoProbes() map[string][]*ebpfcommon.ProbeDesc {
 			Start: p.bpfObjects.ObiUprobeExecDC,
 			End:   p.bpfObjects.ObiUprobeQueryReturn,
 		}},
+		// pgx, when used directly instead of as a database/sql driver
+		"github.com/jackc/pgx/v5.(*Conn).Query": {{
+			Start: p.bpfObjects.ObiUprobePgxConnQuery,
+			End:   p.bpfObjects.ObiUprobePgxConnQueryReturn,
+		}},
+		"github.com/jackc/pgx/v5.(*Conn).Exec": {{
+			Start: p.bpfObjects.ObiUprobePgxConnQuery,
+			End:   p.bpfObjects.ObiUprobePgxConnQueryReturn,
+		}},
+		"github.com/jackc/pgx/v5.(*Conn).SendBatch": {{
+			Start: p.bpfObjects.ObiUprobePgxConnSendBatch,
+			End:   p.bpfObjects.ObiUprobePgxConnSendBatchReturn,
+		}},
+		// mongo driver
+		"go.mongodb.org/mongo-driver/x/mongo/driver.Operation.Execute": {{
+			Start: p.bpfObjects.ObiUprobeMongoOperationExecute,
+			End:   p.bpfObjects.ObiUprobeMongoOperationExecuteReturn,
+		}},
+		"go.mongodb.org/mongo-driver/x/mongo/driver.Operation.roundTrip": {{ // command name and collection
+			Start: p.bpfObjects.ObiUprobeMongoOperationRoundTrip,
+		}},
+		"go.mongodb.org/mongo-driver/v2/x/mongo/driver.Operation.Execute": {{
+			Start: p.bpfObjects.ObiUprobeMongoOperationExecute,
+			End:   p.bpfObjects.ObiUprobeMongoOperationExecuteReturn,
+		}},
+		"go.mongodb.org/mongo-driver/v2/x/mongo/driver.Operation.roundTrip": {{ // command name and collection
+			Start: p.bpfObjects.ObiUprobeMongoV2OperationRoundTrip,
+		}},
 		// Go gRPC
 		"google.golang.org/grpc.(*Server).handleStream": {{
 			Start: p.bpfObjects.ObiUprobeServerHandleStream,
@@ -449,6 +523,34 @@ func (p *Tracer) GoProbes() map[string][]*ebpfcommon.ProbeDesc {
 		}},
 	}
 
+	if p.cfg.InstrumentGoRuntime {
+		// GC pauses
+		m["runtime.stopTheWorldWithSema"] = []*ebpfcommon.ProbeDesc{{
+			Start: p.bpfObjects.ObiUprobeRuntimeStopTheWorldWithSema,
+		}}
+		m["runtime.startTheWorldWithSema"] = []*ebpfcommon.ProbeDesc{{
+			End: p.bpfObjects.ObiUprobeRuntimeStartTheWorldWithSemaReturn,
+		}}
+	}
+
+	if p.cfg.InstrumentGoScheduler {
+		// goroutine scheduling latency
+		m["runtime.casgstatus"] = []*ebpfcommon.ProbeDesc{{
+			Start: p.bpfObjects.ObiUprobeRuntimeCasgstatus,
+		}}
+		m["runtime.execute"] = []*ebpfcommon.ProbeDesc{{
+			Start: p.bpfObjects.ObiUprobeRuntimeExecute,
+		}}
+	}
+
+	// AWS SDK v2: each service client package generates its own invokeOperation method
+	for _, service := range request.AWSSDKServicePackages() {
+		m["github.com/aws/aws-sdk-go-v2/service/"+service+".(*Client).invokeOperation"] = []*ebpfcommon.ProbeDesc{{
+			Start: p.bpfObjects.ObiUprobeAwsInvokeOperation,
+			End:   p.bpfObjects.ObiUprobeAwsInvokeOperationReturn,
+		}}
+	}
+
 	if p.supportsContextPropagation() {
 		m["net/http.Header.writeSubset"] = []*ebpfcommon.ProbeDesc{{
 			Start: p.bpfObjects.ObiUprobeWriteSubset, // http 1.x context propagation
@@ -467,6 +569,10 @@ func (p *Tracer) GoProbes() map[string][]*ebpfcommon.ProbeDesc {
 			Start: p.bpfObjects.ObiUprobeHttp2FramerWriteHeaders,
 			End:   p.bpfObjects.ObiUprobeHttp2FramerWriteHeadersReturns,
 		}}
+		m["github.com/valyala/fasthttp.(*RequestHeader).Write"] = []*ebpfcommon.ProbeDesc{{ // fasthttp client context propagation
+			Start: p.bpfObjects.ObiUprobeFasthttpRequestHeaderWrite,
+			End:   p.bpfObjects.ObiUprobeFasthttpRequestHeaderWriteReturns,
+		}}
 	}
 
 	return m
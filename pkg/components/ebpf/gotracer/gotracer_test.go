@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package gotracer
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ebpfcommon "go.opentelemetry.io/obi/pkg/components/ebpf/common"
+	"go.opentelemetry.io/obi/pkg/obi"
+)
+
+func TestGoProbes_Fasthttp(t *testing.T) {
+	p := New(nil, &obi.Config{}, nil)
+	p.bpfObjects.ObiUprobeConnServe = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeConnServeRet = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpRequestHeaderRead = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpRequestHeaderReadReturns = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpResponseWrite = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpResponseWriteReturns = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpDoNonNilReqResp = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpDoNonNilReqRespReturns = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpAcquireConnReturns = &ebpf.Program{}
+
+	probes := p.GoProbes()
+
+	assertProbe := func(fn string, start, end *ebpf.Program) {
+		t.Helper()
+		require.Contains(t, probes, fn)
+		require.Len(t, probes[fn], 1)
+		assert.Same(t, start, probes[fn][0].Start, fn)
+		assert.Same(t, end, probes[fn][0].End, fn)
+	}
+
+	assertProbe("github.com/valyala/fasthttp.(*Server).serveConn",
+		p.bpfObjects.ObiUprobeConnServe, p.bpfObjects.ObiUprobeConnServeRet)
+	assertProbe("github.com/valyala/fasthttp.(*RequestHeader).readLoop",
+		p.bpfObjects.ObiUprobeFasthttpRequestHeaderRead, p.bpfObjects.ObiUprobeFasthttpRequestHeaderReadReturns)
+	assertProbe("github.com/valyala/fasthttp.(*Response).Write",
+		p.bpfObjects.ObiUprobeFasthttpResponseWrite, p.bpfObjects.ObiUprobeFasthttpResponseWriteReturns)
+	assertProbe("github.com/valyala/fasthttp.(*HostClient).doNonNilReqResp",
+		p.bpfObjects.ObiUprobeFasthttpDoNonNilReqResp, p.bpfObjects.ObiUprobeFasthttpDoNonNilReqRespReturns)
+	// the connection is acquired by an unexported method before v1.65.0, and an exported one since then
+	assertProbe("github.com/valyala/fasthttp.(*HostClient).acquireConn",
+		nil, p.bpfObjects.ObiUprobeFasthttpAcquireConnReturns)
+	assertProbe("github.com/valyala/fasthttp.(*HostClient).AcquireConn",
+		nil, p.bpfObjects.ObiUprobeFasthttpAcquireConnReturns)
+}
+
+func TestGoProbes_FasthttpHeaderPropagation(t *testing.T) {
+	const headerWrite = "github.com/valyala/fasthttp.(*RequestHeader).Write"
+
+	ebpfcommon.IntegrityModeOverride = true
+	t.Cleanup(func() { ebpfcommon.IntegrityModeOverride = false })
+	assert.NotContains(t, New(nil, &obi.Config{}, nil).GoProbes(), headerWrite,
+		"the header can't be written if the context propagation is not supported")
+
+	ebpfcommon.IntegrityModeOverride = false
+	p := New(nil, &obi.Config{}, nil)
+	if !p.supportsContextPropagation() {
+		t.Skip("the kernel lockdown mode doesn't allow the context propagation")
+	}
+	p.bpfObjects.ObiUprobeFasthttpRequestHeaderWrite = &ebpf.Program{}
+	p.bpfObjects.ObiUprobeFasthttpRequestHeaderWriteReturns = &ebpf.Program{}
+	probes := p.GoProbes()
+	require.Contains(t, probes, headerWrite)
+	require.Len(t, probes[headerWrite], 1)
+	assert.Same(t, p.bpfObjects.ObiUprobeFasthttpRequestHeaderWrite, probes[headerWrite][0].Start)
+	assert.Same(t, p.bpfObjects.ObiUprobeFasthttpRequestHeaderWriteReturns, probes[headerWrite][0].End)
+}
+
+func TestGoProbes_GoRuntime(t *testing.T) {
+	gcProbes := []string{"runtime.stopTheWorldWithSema", "runtime.startTheWorldWithSema"}
+	schedProbes := []string{"runtime.casgstatus", "runtime.execute"}
+
+	probes := New(nil, &obi.Config{}, nil).GoProbes()
+	for _, fn := range append(gcProbes, schedProbes...) {
+		assert.NotContains(t, probes, fn)
+	}
+
+	// GC pauses can be enabled without the scheduler probes
+	cfg := &obi.Config{}
+	cfg.EBPF.InstrumentGoRuntime = true
+	probes = New(nil, cfg, nil).GoProbes()
+	for _, fn := range gcProbes {
+		assert.Contains(t, probes, fn)
+	}
+	for _, fn := range schedProbes {
+		assert.NotContains(t, probes, fn)
+	}
+
+	cfg = &obi.Config{}
+	cfg.EBPF.InstrumentGoScheduler = true
+	probes = New(nil, cfg, nil).GoProbes()
+	for _, fn := range gcProbes {
+		assert.NotContains(t, probes, fn)
+	}
+	for _, fn := range schedProbes {
+		assert.Contains(t, probes, fn)
+	}
+}
@@ -28,6 +28,8 @@ const GoOffsetsTableSize = 30
 var (
 	grpcOneSixZero = version.Must(version.NewVersion("1.60.0"))
 	grpcOneSixNine = version.Must(version.NewVersion("1.69.0"))
+	// since Go 1.21, runtime.stopTheWorldWithSema receives the reason of the stop
+	goStwReason = version.Must(version.NewVersion("1.21.0"))
 )
 
 const (
@@ -78,6 +80,12 @@ const (
 	GrpcTransportBufWriterConnPos
 	// redis
 	RedisConnBwPos
+	// pgx
+	PgxBatchQueuedQueriesPos
+	PgxQueuedQuerySQLPos
+	// mongo driver
+	MongoOperationDatabasePos
+	MongoOperationNamePos
 	// kafka go
 	KafkaGoWriterTopicPos
 	KafkaGoProtocolConnPos
@@ -100,6 +108,23 @@ const (
 	GoErrorStringOffset
 	// go jsonrpc
 	GoJsonrpcRequestHeaderServiceMethodPos
+	// fasthttp
+	FasthttpRequestHeaderMethodPos
+	FasthttpRequestHeaderRequestURIPos
+	FasthttpRequestHeaderContentLengthPos
+	FasthttpRequestHeaderHPos
+	FasthttpResponseHeaderStatusCodePos
+	FasthttpResponseHeaderContentLengthPos
+	FasthttpRequestHeaderPos
+	FasthttpRequestURIPos
+	FasthttpResponseHeaderPos
+	FasthttpURIPathPos
+	FasthttpURIHostPos
+	FasthttpURISchemePos
+	FasthttpHostClientIsTLSPos
+	FasthttpClientConnConnPos
+	// go runtime versioning
+	GoStwReason
 )
 
 //go:embed offsets.json
@@ -352,6 +377,85 @@ var structMembers = map[string]structInfo{
 			"bw": RedisConnBwPos,
 		},
 	},
+	// pgx renamed these fields at version 5.6.0
+	"github.com/jackc/pgx/v5.Batch": {
+		lib: "github.com/jackc/pgx/v5",
+		fields: map[string]GoOffset{
+			"queuedQueries": PgxBatchQueuedQueriesPos,
+			"QueuedQueries": PgxBatchQueuedQueriesPos,
+		},
+	},
+	"github.com/jackc/pgx/v5.QueuedQuery": {
+		lib: "github.com/jackc/pgx/v5",
+		fields: map[string]GoOffset{
+			"query": PgxQueuedQuerySQLPos,
+			"SQL":   PgxQueuedQuerySQLPos,
+		},
+	},
+	// the v1 and v2 drivers share the offsets, as only one of them is expected in the binary
+	"go.mongodb.org/mongo-driver/x/mongo/driver.Operation": {
+		lib: "go.mongodb.org/mongo-driver",
+		fields: map[string]GoOffset{
+			"Database": MongoOperationDatabasePos,
+			"Name":     MongoOperationNamePos,
+		},
+	},
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver.Operation": {
+		lib: "go.mongodb.org/mongo-driver/v2",
+		fields: map[string]GoOffset{
+			"Database": MongoOperationDatabasePos,
+			"Name":     MongoOperationNamePos,
+		},
+	},
+	"github.com/valyala/fasthttp.RequestHeader": {
+		lib: "github.com/valyala/fasthttp",
+		fields: map[string]GoOffset{
+			"method":        FasthttpRequestHeaderMethodPos,
+			"requestURI":    FasthttpRequestHeaderRequestURIPos,
+			"contentLength": FasthttpRequestHeaderContentLengthPos,
+			"h":             FasthttpRequestHeaderHPos,
+		},
+	},
+	"github.com/valyala/fasthttp.ResponseHeader": {
+		lib: "github.com/valyala/fasthttp",
+		fields: map[string]GoOffset{
+			"statusCode":    FasthttpResponseHeaderStatusCodePos,
+			"contentLength": FasthttpResponseHeaderContentLengthPos,
+		},
+	},
+	"github.com/valyala/fasthttp.Request": {
+		lib: "github.com/valyala/fasthttp",
+		fields: map[string]GoOffset{
+			"Header": FasthttpRequestHeaderPos,
+			"uri":    FasthttpRequestURIPos,
+		},
+	},
+	"github.com/valyala/fasthttp.Response": {
+		lib: "github.com/valyala/fasthttp",
+		fields: map[string]GoOffset{
+			"Header": FasthttpResponseHeaderPos,
+		},
+	},
+	"github.com/valyala/fasthttp.URI": {
+		lib: "github.com/valyala/fasthttp",
+		fields: map[string]GoOffset{
+			"path":   FasthttpURIPathPos,
+			"host":   FasthttpURIHostPos,
+			"scheme": FasthttpURISchemePos,
+		},
+	},
+	"github.com/valyala/fasthttp.HostClient": {
+		lib: "github.com/valyala/fasthttp",
+		fields: map[string]GoOffset{
+			"IsTLS": FasthttpHostClientIsTLSPos,
+		},
+	},
+	"github.com/valyala/fasthttp.clientConn": {
+		lib: "github.com/valyala/fasthttp",
+		fields: map[string]GoOffset{
+			"c": FasthttpClientConnConnPos,
+		},
+	},
 	"github.com/segmentio/kafka-go.Writer": {
 		lib: "github.com/segmentio/kafka-go",
 		fields: map[string]GoOffset{
@@ -426,6 +530,19 @@ func offsetsForLibVersions(fieldOffsets FieldOffsets, libVersions map[string]str
 				log.Debug("can't parse version for", "library", lib)
 			}
 		}
+		if lib == "go" {
+			ver = cleanLibVersion(ver, true, lib, log)
+
+			if v, err := version.NewVersion(ver); err == nil {
+				if v.GreaterThanOrEqual(goStwReason) {
+					fieldOffsets[GoStwReason] = uint64(1)
+				} else {
+					fieldOffsets[GoStwReason] = uint64(0)
+				}
+			} else {
+				log.Debug("can't parse version for", "library", lib)
+			}
+		}
 	}
 
 	return fieldOffsets
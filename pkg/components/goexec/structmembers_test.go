@@ -13,6 +13,7 @@ import (
 	"path"
 	"testing"
 
+	"github.com/grafana/go-offsets-tracker/pkg/offsets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -181,3 +182,22 @@ func (f *fakeDwarfReader) Next() (*dwarf.Entry, error) {
 	f.entries = f.entries[1:]
 	return entry, nil
 }
+
+func TestFasthttpPrefetchedOffsets(t *testing.T) {
+	offs, err := offsets.Read(bytes.NewBufferString(prefetchedOffsets))
+	require.NoError(t, err)
+
+	// all the fields read by the fasthttp probes must be available from the oldest
+	// to the newest supported version, as fasthttp executables usually lack DWARF info
+	for _, version := range []string{"1.40.0", "1.56.0", "1.65.0", "1.74.0"} {
+		for strName, strInfo := range structMembers {
+			if strInfo.lib != "github.com/valyala/fasthttp" {
+				continue
+			}
+			for fieldName := range strInfo.fields {
+				_, ok := offs.Find(strName, fieldName, version)
+				assert.Truef(t, ok, "missing offset for %s.%s in version %s", strName, fieldName, version)
+			}
+		}
+	}
+}
@@ -5,15 +5,21 @@ package kube
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
+	"net"
+	"os"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"go.opentelemetry.io/obi/pkg/kubecache/informer"
 	"go.opentelemetry.io/obi/pkg/kubecache/meta"
+	"go.opentelemetry.io/obi/pkg/kubecache/shard"
 )
 
 // TODO: make configurable
@@ -25,9 +31,25 @@ func cslog() *slog.Logger {
 
 type cacheSvcClient struct {
 	meta.BaseNotifier
+	// address of the cache service. It can be a comma-separated list of addresses, and any
+	// host name resolving to multiple IPs (e.g. a headless service) is considered as a set of replicas
 	address string
 	log     *slog.Logger
 
+	// shardKey selects the preferred replica of the cache service. By default, it is the
+	// node name (if known) or the host name, so the clients are evenly distributed among replicas
+	shardKey string
+	// lookupHost resolves the IPs of the cache service replicas
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+
+	// nodeName, namespaces and kinds restrict the events that are received from the cache service
+	nodeName   string
+	namespaces []string
+	kinds      []string
+
+	// tlsConfig, if not nil, enables secure connections to the cache service
+	tlsConfig *tls.Config
+
 	lastEventTSEpoch       int64
 	ctx                    context.Context
 	syncTimeout            time.Duration
@@ -58,6 +80,15 @@ func (sc *cacheSvcClient) Start(ctx context.Context) {
 	if sc.reconnectTime == 0 {
 		sc.reconnectTime = defaultReconnectTime
 	}
+	if sc.lookupHost == nil {
+		sc.lookupHost = net.DefaultResolver.LookupHost
+	}
+	if sc.shardKey == "" {
+		sc.shardKey = sc.nodeName
+	}
+	if sc.shardKey == "" {
+		sc.shardKey, _ = os.Hostname()
+	}
 	// subscribe itself to each message from the cache, to keep track of the
 	// message timestamps for a more efficient reconnection
 	sc.BaseNotifier.Subscribe(sc)
@@ -76,25 +107,97 @@ func (sc *cacheSvcClient) Start(ctx context.Context) {
 				sc.log.Debug("context done, stopping client")
 				return
 			default:
-				// TODO: reconnection should include a timestamp
-				// with the last received event, to avoid unnecessarily
-				// receiving the whole metadata snapshot on each reconnection
-				err := sc.connect(ctx)
-				sc.log.Info("K8s cache service connection lost. Reconnecting...", "error", err)
-				// TODO: exponential backoff
-				time.Sleep(sc.reconnectTime)
+				// after losing an established connection, the client immediately reconnects to the
+				// preferred available replica. It only waits if none of the replicas were available
+				if !sc.connectReplicas(ctx) {
+					// TODO: exponential backoff
+					time.Sleep(sc.reconnectTime)
+				}
 			}
 		}
 	}()
 }
 
-func (sc *cacheSvcClient) connect(ctx context.Context) error {
+// cacheReplica is the address of a replica of the cache service
+type cacheReplica struct {
+	address string
+	// authority, if set, overrides the host name that is used by the gRPC requests and for
+	// the TLS verification, when the address is an IP resolved from the original host name
+	authority string
+}
+
+// replicas returns the replicas of the cache service, sorted by preference for this client
+// according to a consistent hash of its shardKey
+func (sc *cacheSvcClient) replicas(ctx context.Context) []cacheReplica {
+	byAddress := map[string]cacheReplica{}
+	for _, addr := range strings.Split(sc.address, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			byAddress[addr] = cacheReplica{address: addr}
+			continue
+		}
+		ips, err := sc.lookupHost(ctx, host)
+		if err != nil || len(ips) <= 1 {
+			// single-replica services are still resolved by gRPC
+			byAddress[addr] = cacheReplica{address: addr}
+			continue
+		}
+		for _, ip := range ips {
+			replica := cacheReplica{address: net.JoinHostPort(ip, port), authority: addr}
+			byAddress[replica.address] = replica
+		}
+	}
+	addresses := make([]string, 0, len(byAddress))
+	for addr := range byAddress {
+		addresses = append(addresses, addr)
+	}
+	replicas := make([]cacheReplica, 0, len(addresses))
+	for _, addr := range shard.NewRing(addresses).Owners(sc.shardKey) {
+		replicas = append(replicas, byAddress[addr])
+	}
+	return replicas
+}
+
+// connectReplicas tries to subscribe to the replicas of the cache service in order of preference,
+// and returns when the connection to any of them is lost. The subscription resumes from the
+// timestamp of the last received event, so a replica that takes over the client doesn't
+// need to resend the whole metadata snapshot. It returns false if no replica could be connected.
+func (sc *cacheSvcClient) connectReplicas(ctx context.Context) bool {
+	for _, replica := range sc.replicas(ctx) {
+		established, err := sc.connect(ctx, replica)
+		if established {
+			sc.log.Info("K8s cache service connection lost. Reconnecting...",
+				"address", replica.address, "error", err)
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		sc.log.Info("can't connect to K8s cache service. Trying next replica, if any",
+			"address", replica.address, "error", err)
+	}
+	return false
+}
+
+// connect subscribes to the given replica and forwards its events until the connection is lost.
+// It returns whether the connection was established (the replica accepted the subscription).
+func (sc *cacheSvcClient) connect(ctx context.Context, replica cacheReplica) (bool, error) {
 	// Set up a connection to the server.
-	conn, err := grpc.NewClient(sc.address,
-		// TODO: allow configuring the transport credentials
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds := insecure.NewCredentials()
+	if sc.tlsConfig != nil {
+		creds = credentials.NewTLS(sc.tlsConfig)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if replica.authority != "" {
+		opts = append(opts, grpc.WithAuthority(replica.authority))
+	}
+	conn, err := grpc.NewClient(replica.address, opts...)
 	if err != nil {
-		return fmt.Errorf("did not connect: %w", err)
+		return false, fmt.Errorf("did not connect: %w", err)
 	}
 	defer conn.Close()
 
@@ -103,16 +206,29 @@ func (sc *cacheSvcClient) connect(ctx context.Context) error {
 	// Subscribe to the event stream.
 	stream, err := client.Subscribe(ctx, &informer.SubscribeMessage{
 		FromTimestampEpoch: sc.lastEventTSEpoch,
+		NodeName:           sc.nodeName,
+		Namespaces:         sc.namespaces,
+		Kinds:              sc.kinds,
 	})
 	if err != nil {
-		return fmt.Errorf("could not subscribe: %w", err)
+		return false, fmt.Errorf("could not subscribe: %w", err)
+	}
+
+	// The replica sends the headers once it accepts the subscription (or, in previous versions,
+	// with the first event), so an idle replica is not blamed for the connection loss. Nil headers
+	// mean that the replica ended the stream without accepting it (e.g. it rejected the client),
+	// and the error is returned by Recv.
+	header, err := stream.Header()
+	if err != nil {
+		return false, fmt.Errorf("could not subscribe: %w", err)
 	}
+	established := header != nil
 
 	// Receive and print messages.
 	for {
 		event, err := stream.Recv()
 		if err != nil {
-			return fmt.Errorf("error receiving message: %w", err)
+			return established, fmt.Errorf("error receiving message: %w", err)
 		}
 		// send a notification about the client being synced with the K8s metadata service
 		// so Beyla can start processing/decorating the received flows and traces
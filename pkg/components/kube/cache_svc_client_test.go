@@ -4,6 +4,7 @@
 package kube
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"sync/atomic"
@@ -14,10 +15,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/obi/pkg/components/testutil"
 	"go.opentelemetry.io/obi/pkg/kubecache/informer"
 	"go.opentelemetry.io/obi/pkg/kubecache/meta"
+	"go.opentelemetry.io/obi/pkg/kubecache/shard"
 )
 
 const timeout = 5 * time.Second
@@ -71,6 +76,132 @@ func TestClientForwardsLastTimestamp(t *testing.T) {
 	assert.Equal(t, itemTime, secondSubscribe.FromTimestampEpoch)
 }
 
+func TestClientSendsSubscriptionFilters(t *testing.T) {
+	fcs := startFakeCacheService(t)
+	fcs.serverResponses <- &informer.Event{
+		Type: informer.EventType_SYNC_FINISHED,
+	}
+
+	svc := cacheSvcClient{
+		address:       fmt.Sprintf("127.0.0.1:%d", fcs.port),
+		BaseNotifier:  meta.NewBaseNotifier(klog()),
+		syncTimeout:   timeout,
+		reconnectTime: 10 * time.Millisecond,
+		nodeName:      "node-1",
+		namespaces:    []string{"shop"},
+		kinds:         subscribedKinds([]string{"nodes"}),
+	}
+	svc.Start(t.Context())
+	svc.Subscribe(dummySubscriber{})
+
+	subscribe := testutil.ReadChannel(t, fcs.clientMessages, timeout)
+	assert.Equal(t, "node-1", subscribe.NodeName)
+	assert.Equal(t, []string{"shop"}, subscribe.Namespaces)
+	assert.Equal(t, []string{"Pod", "Service"}, subscribe.Kinds)
+}
+
+func TestClientReplicasOrder(t *testing.T) {
+	svc := cacheSvcClient{
+		address:  "k8s-cache:50055, 10.0.0.9:50055",
+		shardKey: "node-1",
+		lookupHost: func(_ context.Context, host string) ([]string, error) {
+			assert.Equal(t, "k8s-cache", host)
+			return []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, nil
+		},
+	}
+	replicas := svc.replicas(t.Context())
+	require.Len(t, replicas, 4)
+	expected := shard.NewRing([]string{
+		"10.0.0.1:50055", "10.0.0.2:50055", "10.0.0.3:50055", "10.0.0.9:50055",
+	}).Owners("node-1")
+	for i, r := range replicas {
+		assert.Equal(t, expected[i], r.address)
+		if r.address == "10.0.0.9:50055" {
+			assert.Empty(t, r.authority)
+		} else {
+			// the original host name is kept for the gRPC requests and the TLS verification
+			assert.Equal(t, "k8s-cache:50055", r.authority)
+		}
+	}
+
+	// hosts resolving to a single IP are directly passed to gRPC
+	svc.lookupHost = func(_ context.Context, _ string) ([]string, error) {
+		return []string{"10.0.0.1"}, nil
+	}
+	svc.address = "k8s-cache:50055"
+	assert.Equal(t, []cacheReplica{{address: "k8s-cache:50055"}}, svc.replicas(t.Context()))
+}
+
+func TestClientFailover(t *testing.T) {
+	// PREREQUISITE: two replicas of the K8s metadata cache service
+	fcs1, fcs2 := startFakeCacheService(t), startFakeCacheService(t)
+	addr1, addr2 := fmt.Sprintf("127.0.0.1:%d", fcs1.port), fmt.Sprintf("127.0.0.1:%d", fcs2.port)
+	preferred, fallback := fcs1, fcs2
+	if shard.NewRing([]string{addr1, addr2}).Owners("node-1")[0] == addr2 {
+		preferred, fallback = fcs2, fcs1
+	}
+	itemTime := int64(1234567890)
+	preferred.serverResponses <- &informer.Event{
+		Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{
+			Name: "svc-1", Namespace: "default",
+			StatusTimeEpoch: itemTime,
+		},
+	}
+	preferred.serverResponses <- &informer.Event{Type: informer.EventType_SYNC_FINISHED}
+
+	// GIVEN a K8s cache client that is configured with both replicas
+	svc := cacheSvcClient{
+		address:       addr1 + "," + addr2,
+		shardKey:      "node-1",
+		BaseNotifier:  meta.NewBaseNotifier(klog()),
+		syncTimeout:   timeout,
+		reconnectTime: 10 * time.Millisecond,
+	}
+	svc.Start(t.Context())
+	svc.Subscribe(dummySubscriber{})
+
+	// THEN it connects to its preferred replica
+	assert.Zero(t, testutil.ReadChannel(t, preferred.clientMessages, timeout).FromTimestampEpoch)
+
+	// AND WHEN the preferred replica goes down
+	fallback.serverResponses <- &informer.Event{Type: informer.EventType_SYNC_FINISHED}
+	preferred.Stop()
+
+	// THEN the client fails over to the other replica, resuming from the last received event
+	assert.Equal(t, itemTime, testutil.ReadChannel(t, fallback.clientMessages, timeout).FromTimestampEpoch)
+}
+
+func TestClientConnectionEstablishedWithoutEvents(t *testing.T) {
+	fcs := startFakeCacheService(t)
+	svc := cacheSvcClient{BaseNotifier: meta.NewBaseNotifier(klog())}
+	replica := cacheReplica{address: fmt.Sprintf("127.0.0.1:%d", fcs.port)}
+
+	established := make(chan bool, 1)
+	go func() {
+		ok, _ := svc.connect(t.Context(), replica)
+		established <- ok
+	}()
+	testutil.ReadChannel(t, fcs.clientMessages, timeout)
+	// the replica accepted the subscription but closes the stream before sending any event
+	close(fcs.serverResponses)
+	assert.True(t, testutil.ReadChannel(t, established, timeout))
+
+	// a replica that rejects the subscription is not considered as established
+	rejecting := startFakeCacheService(t)
+	rejecting.reject.Store(true)
+	replica = cacheReplica{address: fmt.Sprintf("127.0.0.1:%d", rejecting.port)}
+	ok, err := svc.connect(t.Context(), replica)
+	require.Error(t, err)
+	assert.False(t, ok)
+}
+
+func TestSubscribedKinds(t *testing.T) {
+	assert.Nil(t, subscribedKinds(nil))
+	assert.Equal(t, []string{"Pod", "Node"}, subscribedKinds([]string{"Service"}))
+	assert.Equal(t, []string{"Pod"}, subscribedKinds([]string{"node", "services"}))
+}
+
 // cacheSvcClient requires a subscriber to start processing the events, so we provide a dummy here
 type dummySubscriber struct{}
 
@@ -88,6 +219,8 @@ type fakeCacheService struct {
 
 	clientMessages  chan *informer.SubscribeMessage
 	serverResponses chan *informer.Event
+	// reject the subscriptions, as a replica with too many connections does
+	reject atomic.Bool
 }
 
 func startFakeCacheService(t *testing.T) *fakeCacheService {
@@ -123,9 +256,13 @@ func (fcs *fakeCacheService) Start() {
 }
 
 func (fcs *fakeCacheService) Restart() {
+	fcs.Stop()
+	fcs.Start()
+}
+
+func (fcs *fakeCacheService) Stop() {
 	fcs.server.Stop()
 	fcs.listener.Close()
-	fcs.Start()
 }
 
 func (fcs *fakeCacheService) Err() error {
@@ -137,6 +274,12 @@ func (fcs *fakeCacheService) Err() error {
 
 func (fcs *fakeCacheService) Subscribe(message *informer.SubscribeMessage, g grpc.ServerStreamingServer[informer.Event]) error {
 	fcs.clientMessages <- message
+	if fcs.reject.Load() {
+		return status.Error(codes.ResourceExhausted, "maximum number of connections reached")
+	}
+	if err := g.SendHeader(metadata.MD{}); err != nil {
+		return fmt.Errorf("sending headers to client: %w", err)
+	}
 	for msg := range fcs.serverResponses {
 		if err := g.Send(msg); err != nil {
 			return fmt.Errorf("sending response to client: %w", err)
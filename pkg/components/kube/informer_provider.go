@@ -20,6 +20,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"go.opentelemetry.io/obi/pkg/kubecache"
 	"go.opentelemetry.io/obi/pkg/kubecache/meta"
 	"go.opentelemetry.io/obi/pkg/kubeflags"
 )
@@ -49,8 +50,10 @@ type MetadataConfig struct {
 	SyncTimeout         time.Duration
 	ResyncPeriod        time.Duration
 	MetaCacheAddr       string
+	MetaCacheTLS        kubecache.ClientTLSConfig
 	ResourceLabels      ResourceLabels
 	RestrictLocalNode   bool
+	Namespaces          []string
 	ServiceNameTemplate *template.Template
 }
 
@@ -134,7 +137,11 @@ func (mp *MetadataProvider) getInformer(ctx context.Context) (meta.Notifier, err
 		return mp.informer, nil
 	}
 	if mp.cfg.MetaCacheAddr != "" {
-		mp.informer = mp.initRemoteInformerCacheClient(ctx)
+		client, err := mp.initRemoteInformerCacheClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("can't connect to the k8s cache service: %w", err)
+		}
+		mp.informer = client
 	} else {
 		var err error
 		mp.informer, err = mp.initLocalInformers(ctx)
@@ -293,14 +300,30 @@ func (mp *MetadataProvider) initLocalInformers(ctx context.Context) (*meta.Infor
 
 // initRemoteInformerCacheClient connects via gRPC/Protobuf to a remote beyla-k8s-cache service, to avoid that
 // each Beyla instance connects to the Kube API informer on each node, which would overload the Kube API
-func (mp *MetadataProvider) initRemoteInformerCacheClient(ctx context.Context) *cacheSvcClient {
+func (mp *MetadataProvider) initRemoteInformerCacheClient(ctx context.Context) (*cacheSvcClient, error) {
 	client := &cacheSvcClient{
 		address:      mp.cfg.MetaCacheAddr,
 		BaseNotifier: meta.NewBaseNotifier(klog()),
 		syncTimeout:  mp.cfg.SyncTimeout,
+		namespaces:   mp.cfg.Namespaces,
+		kinds:        subscribedKinds(mp.cfg.DisabledInformers),
+	}
+	if mp.cfg.MetaCacheTLS.Enabled() {
+		tlsConfig, err := mp.cfg.MetaCacheTLS.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("configuring TLS: %w", err)
+		}
+		client.tlsConfig = tlsConfig
+	}
+	if mp.cfg.RestrictLocalNode {
+		if localNode, err := mp.CurrentNodeName(ctx); err != nil {
+			klog().Warn("can't get local node name. Receiving metadata from all the nodes", "error", err)
+		} else {
+			client.nodeName = localNode
+		}
 	}
 	client.Start(ctx)
-	return client
+	return client, nil
 }
 
 func loadKubeConfig(kubeConfigPath string) (*rest.Config, error) {
@@ -330,6 +353,31 @@ func loadKubeConfig(kubeConfigPath string) (*rest.Config, error) {
 	return config, nil
 }
 
+// subscribedKinds returns the kinds of objects to subscribe to in the k8s-cache service,
+// or nil if no informer is disabled
+func subscribedKinds(disabledInformers []string) []string {
+	nodes, services := true, true
+	for _, di := range disabledInformers {
+		switch strings.ToLower(di) {
+		case "node", "nodes":
+			nodes = false
+		case "service", "services":
+			services = false
+		}
+	}
+	if nodes && services {
+		return nil
+	}
+	kinds := []string{"Pod"}
+	if nodes {
+		kinds = append(kinds, "Node")
+	}
+	if services {
+		kinds = append(kinds, "Service")
+	}
+	return kinds
+}
+
 func disabledInformerOpts(disabledInformers []string) []meta.InformerOption {
 	var opts []meta.InformerOption
 	for _, di := range disabledInformers {
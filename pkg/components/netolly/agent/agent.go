@@ -103,6 +103,10 @@ type Flows struct {
 	// processing nodes to be wired in the buildPipeline method
 	mapTracer *flow.MapTracer
 	rbTracer  *flow.RingBufTracer
+	// connTracer is nil if the TCP connection metrics are not enabled
+	connTracer *flow.ConnTracer
+	// dropTracer is nil if the packet drop metrics are not enabled
+	dropTracer *flow.DropTracer
 
 	// elements used to decorate flows with extra information
 	interfaceNamer flow.InterfaceNamer
@@ -116,6 +120,9 @@ type ebpfFlowFetcher interface {
 	io.Closer
 
 	LookupAndDeleteMap() map[ebpf.NetFlowId][]ebpf.NetFlowMetrics
+	LookupAndDeleteConnMap() map[ebpf.NetFlowId][]ebpf.NetConnMetrics
+	LookupAndDeleteDropMap() map[ebpf.DropKey][]ebpf.NetDropMetrics
+	LookupAndDeletePayloadMap() map[ebpf.NetFlowId][]byte
 	ReadRingBuf() (ringbuf.Record, error)
 }
 
@@ -151,18 +158,38 @@ func newFetcher(cfg *obi.Config, alog *slog.Logger, ifaceManager *tcmanager.Inte
 	case obi.EbpfSourceSock:
 		alog.Info("using socket filter for collecting network events")
 
-		return ebpf.NewSockFlowFetcher(cfg.NetworkFlows.Sampling, cfg.NetworkFlows.CacheMaxFlows)
+		return ebpf.NewSockFlowFetcher(cfg.NetworkFlows.Sampling, cfg.NetworkFlows.CacheMaxFlows,
+			connectionMetricsEnabled(cfg), dropMetricsEnabled(cfg), cfg.NetworkFlows.L7ProtocolClassification)
 	case obi.EbpfSourceTC:
 		alog.Info("using kernel Traffic Control for collecting network events")
 		ingress, egress := flowDirections(&cfg.NetworkFlows)
 
 		return ebpf.NewFlowFetcher(cfg.NetworkFlows.Sampling, cfg.NetworkFlows.CacheMaxFlows,
-			ingress, egress, ifaceManager, cfg.EBPF.TCBackend)
+			ingress, egress, ifaceManager, cfg.EBPF.TCBackend, connectionMetricsEnabled(cfg),
+			dropMetricsEnabled(cfg), cfg.NetworkFlows.L7ProtocolClassification)
 	}
 
 	return nil, errors.New("unknown network configuration eBPF source specified, allowed options are [tc, socket_filter]")
 }
 
+// connectionMetricsEnabled returns whether any exporter requires tracking the lifecycle
+// of the TCP connections
+func connectionMetricsEnabled(cfg *obi.Config) bool {
+	return cfg.Metrics.NetworkConnectionMetricsEnabled() || cfg.Prometheus.NetworkConnectionMetricsEnabled()
+}
+
+// dropMetricsEnabled returns whether any exporter requires tracking the packets
+// dropped by the kernel
+func dropMetricsEnabled(cfg *obi.Config) bool {
+	return cfg.Metrics.NetworkDropMetricsEnabled() || cfg.Prometheus.NetworkDropMetricsEnabled()
+}
+
+// egressCostMetricsEnabled returns whether any exporter requires classifying the flows
+// according to their egress cost
+func egressCostMetricsEnabled(cfg *obi.Config) bool {
+	return cfg.Metrics.NetworkEgressCostMetricsEnabled() || cfg.Prometheus.NetworkEgressCostMetricsEnabled()
+}
+
 func monitorMode(cfg *obi.Config, alog *slog.Logger) tcmanager.MonitorMode {
 	switch cfg.NetworkFlows.ListenInterfaces {
 	case listenPoll:
@@ -208,6 +235,14 @@ func flowsAgent(
 
 	mapTracer := flow.NewMapTracer(fetcher, cfg.NetworkFlows.CacheActiveTimeout)
 	rbTracer := flow.NewRingBufTracer(fetcher, mapTracer, cfg.NetworkFlows.CacheActiveTimeout)
+	var connTracer *flow.ConnTracer
+	if connectionMetricsEnabled(cfg) {
+		connTracer = flow.NewConnTracer(fetcher, cfg.NetworkFlows.CacheActiveTimeout)
+	}
+	var dropTracer *flow.DropTracer
+	if dropMetricsEnabled(cfg) {
+		dropTracer = flow.NewDropTracer(fetcher, cfg.NetworkFlows.CacheActiveTimeout)
+	}
 
 	return &Flows{
 		ctxInfo:        ctxInfo,
@@ -216,6 +251,8 @@ func flowsAgent(
 		cfg:            cfg,
 		mapTracer:      mapTracer,
 		rbTracer:       rbTracer,
+		connTracer:     connTracer,
+		dropTracer:     dropTracer,
 		agentIP:        agentIP,
 		interfaceNamer: interfaceNamer,
 	}, nil
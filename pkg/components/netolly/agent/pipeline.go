@@ -8,8 +8,10 @@ import (
 
 	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
 	"go.opentelemetry.io/obi/pkg/components/netolly/export"
+	"go.opentelemetry.io/obi/pkg/components/netolly/export/ipfix"
 	"go.opentelemetry.io/obi/pkg/components/netolly/flow"
 	"go.opentelemetry.io/obi/pkg/components/netolly/transform/cidr"
+	"go.opentelemetry.io/obi/pkg/components/netolly/transform/cloud"
 	"go.opentelemetry.io/obi/pkg/components/netolly/transform/k8s"
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	"go.opentelemetry.io/obi/pkg/export/otel"
@@ -28,6 +30,20 @@ var newRingBufTracer = func(f *Flows, out *msg.Queue[[]*ebpf.Record]) swarm.RunF
 	return f.rbTracer.TraceLoop(out)
 }
 
+var newConnTracer = func(f *Flows, out *msg.Queue[[]*ebpf.Record]) swarm.RunFunc {
+	if f.connTracer == nil {
+		return func(_ context.Context) { out.MarkCloseable() }
+	}
+	return f.connTracer.TraceLoop(out)
+}
+
+var newDropTracer = func(f *Flows, out *msg.Queue[[]*ebpf.Record]) swarm.RunFunc {
+	if f.dropTracer == nil {
+		return func(_ context.Context) { out.MarkCloseable() }
+	}
+	return f.dropTracer.TraceLoop(out)
+}
+
 // buildPipeline defines the different nodes in the Beyla's NetO11y module,
 // as well as how they are interconnected (in its Connect() method)
 func (f *Flows) buildPipeline(ctx context.Context) (*swarm.Runner, error) {
@@ -44,10 +60,12 @@ func (f *Flows) buildPipeline(ctx context.Context) (*swarm.Runner, error) {
 	// Start nodes: those generating flow records (reading them from eBPF)
 	ebpfFlows := msg.NewQueue[[]*ebpf.Record](
 		msg.ChannelBufferLen(f.cfg.ChannelBufferLen),
-		msg.ClosingAttempts(2), // queue won't close until both tracers try to close it
+		msg.ClosingAttempts(4), // queue won't close until all the tracers try to close it
 	)
 	swi.Add(swarm.DirectInstance(newMapTracer(f, ebpfFlows)), swarm.WithID("MapTracer"))
 	swi.Add(swarm.DirectInstance(newRingBufTracer(f, ebpfFlows)), swarm.WithID("RingBufTracer"))
+	swi.Add(swarm.DirectInstance(newConnTracer(f, ebpfFlows)), swarm.WithID("ConnTracer"))
+	swi.Add(swarm.DirectInstance(newDropTracer(f, ebpfFlows)), swarm.WithID("DropTracer"))
 
 	// Middle nodes: transforming flow records and passing them to the next stage in the pipeline.
 	// Many of the nodes here are not mandatory. It's decision of each InstanceFunc to decide
@@ -63,9 +81,13 @@ func (f *Flows) buildPipeline(ctx context.Context) (*swarm.Runner, error) {
 		CacheActiveTimeout: f.cfg.NetworkFlows.CacheActiveTimeout,
 	}, protocolFilteredEbpfFlows, dedupedEBPFFlows), swarm.WithID("FlowDeduper"))
 
+	classifiedFlows := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(f.cfg.ChannelBufferLen))
+	swi.Add(flow.L7ClassifierProvider(f.cfg.NetworkFlows.L7ProtocolClassification, f.ebpf,
+		f.cfg.NetworkFlows.CacheMaxFlows, dedupedEBPFFlows, classifiedFlows), swarm.WithID("L7Classifier"))
+
 	kubeDecoratedFlows := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(f.cfg.ChannelBufferLen))
 	swi.Add(k8s.MetadataDecoratorProvider(ctx, &f.cfg.Attributes.Kubernetes, f.ctxInfo.K8sInformer,
-		dedupedEBPFFlows, kubeDecoratedFlows), swarm.WithID("K8sMetadataDecorator"))
+		classifiedFlows, kubeDecoratedFlows), swarm.WithID("K8sMetadataDecorator"))
 
 	dnsDecoratedFlows := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(f.cfg.ChannelBufferLen))
 	swi.Add(flow.ReverseDNSProvider(&f.cfg.NetworkFlows.ReverseDNS, kubeDecoratedFlows, dnsDecoratedFlows),
@@ -75,6 +97,14 @@ func (f *Flows) buildPipeline(ctx context.Context) (*swarm.Runner, error) {
 	swi.Add(cidr.DecoratorProvider(f.cfg.NetworkFlows.CIDRs, dnsDecoratedFlows, cidrDecoratedFlows),
 		swarm.WithID("CIDRDecorator"))
 
+	egressDecoratedFlows := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(f.cfg.ChannelBufferLen))
+	swi.Add(cloud.EgressDecoratorProvider(egressCostMetricsEnabled(f.cfg), &f.cfg.NetworkFlows.EgressCost,
+		cidrDecoratedFlows, egressDecoratedFlows), swarm.WithID("EgressDecorator"))
+
+	heavyHittersFlows := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(f.cfg.ChannelBufferLen))
+	swi.Add(flow.HeavyHittersProvider(&f.cfg.NetworkFlows.HeavyHitters, egressDecoratedFlows, heavyHittersFlows),
+		swarm.WithID("HeavyHitters"))
+
 	decoratedFlows := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(f.cfg.ChannelBufferLen))
 	swi.Add(func(_ context.Context) (swarm.RunFunc, error) {
 		// If deduper is enabled, we know that interfaces are unset.
@@ -85,29 +115,34 @@ func (f *Flows) buildPipeline(ctx context.Context) (*swarm.Runner, error) {
 				return ""
 			}
 		}
-		return flow.Decorate(f.agentIP, ifaceNamer, cidrDecoratedFlows, decoratedFlows), nil
+		return flow.Decorate(f.agentIP, ifaceNamer, heavyHittersFlows, decoratedFlows), nil
 	}, swarm.WithID("FlowDecorator"))
 
 	filteredFlows := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(f.cfg.ChannelBufferLen))
-	swi.Add(filter.ByAttribute(f.cfg.Filters.Network, nil, selectorCfg.ExtraGroupAttributesCfg, ebpf.RecordStringGetters, decoratedFlows, filteredFlows),
+	swi.Add(filter.ReloadableByAttribute(f.cfg.Filters.Network, f.cfg.Reloads.NetFiltersUpdates(),
+		nil, selectorCfg.ExtraGroupAttributesCfg, ebpf.RecordStringGetters, decoratedFlows, filteredFlows),
 		swarm.WithID("AttributeFilter"))
 
-	// Terminal nodes export the flow record information out of the pipeline: OTEL, Prom and printer.
+	// Terminal nodes export the flow record information out of the pipeline: OTEL, Prom, IPFIX and printer.
 	// Not all the nodes are mandatory here. Is the responsibility of each Provider function to decide
 	// whether each node is going to be instantiated or just ignored.
 	f.cfg.Attributes.Select.Normalize()
 	swi.Add(otel.NetMetricsExporterProvider(f.ctxInfo, &otel.NetMetricsConfig{
-		Metrics:         &f.cfg.Metrics,
-		SelectorCfg:     selectorCfg,
-		GloballyEnabled: f.cfg.NetworkFlows.Enable,
+		Metrics:                &f.cfg.Metrics,
+		SelectorCfg:            selectorCfg,
+		GloballyEnabled:        f.cfg.NetworkFlows.Enable,
+		EgressPricesConfigured: f.cfg.NetworkFlows.EgressCost.PricePerGB.Configured(),
 	}, filteredFlows), swarm.WithID("OTelExporter"))
 
 	swi.Add(prom.NetPrometheusEndpoint(f.ctxInfo, &prom.NetPrometheusConfig{
-		Config:          &f.cfg.Prometheus,
-		SelectorCfg:     selectorCfg,
-		GloballyEnabled: f.cfg.NetworkFlows.Enable,
+		Config:                 &f.cfg.Prometheus,
+		SelectorCfg:            selectorCfg,
+		GloballyEnabled:        f.cfg.NetworkFlows.Enable,
+		EgressPricesConfigured: f.cfg.NetworkFlows.EgressCost.PricePerGB.Configured(),
 	}, filteredFlows), swarm.WithID("PrometheusExporter"))
 
+	swi.Add(ipfix.ExporterProvider(&f.cfg.NetworkFlows.IPFIX, filteredFlows), swarm.WithID("IPFIXExporter"))
+
 	swi.Add(swarm.DirectInstance(export.FlowPrinterProvider(f.cfg.NetworkFlows.Print, filteredFlows)),
 		swarm.WithID("FlowPrinter"))
 
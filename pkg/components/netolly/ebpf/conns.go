@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package ebpf
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/link"
+)
+
+const connStatsMap = "conn_stats"
+
+// connTracker attaches the tracepoint that accounts the lifecycle of the TCP connections,
+// and reads the connection metrics from the conn_stats map. It is shared by the
+// TC and socket filter fetchers, as both load the same tracepoint program.
+type connTracker struct {
+	log          *slog.Logger
+	stats        *ebpf.Map
+	link         link.Link
+	cacheMaxSize int
+}
+
+// attachConnTracker returns nil if the connection tracking is not enabled
+func attachConnTracker(
+	log *slog.Logger, enable bool, prog *ebpf.Program, stats *ebpf.Map, cacheMaxSize int,
+) (*connTracker, error) {
+	if !enable {
+		return nil, nil
+	}
+	l, err := link.Tracepoint("sock", "inet_sock_set_state", prog, nil)
+	if err != nil {
+		return nil, fmt.Errorf("attaching TCP connection tracker: %w", err)
+	}
+	return &connTracker{log: log, stats: stats, link: l, cacheMaxSize: cacheMaxSize}, nil
+}
+
+func (c *connTracker) close() error {
+	if c == nil {
+		return nil
+	}
+	return c.link.Close()
+}
+
+// lookupAndDelete reads all the connection metrics from the eBPF map and removes them from it,
+// following the same approach as LookupAndDeleteMap for the flows
+func (c *connTracker) lookupAndDelete() map[NetFlowId][]NetConnMetrics {
+	if c == nil {
+		return nil
+	}
+	iterator := c.stats.Iterate()
+	conns := make(map[NetFlowId][]NetConnMetrics, c.cacheMaxSize)
+
+	id := NetFlowId{}
+	var metrics []NetConnMetrics
+	for iterator.Next(&id, &metrics) {
+		if err := c.stats.Delete(id); err != nil {
+			c.log.Debug("couldn't delete connection entry", "connId", id, "error", err)
+		}
+		conns[id] = append(conns[id], metrics...)
+	}
+	return conns
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package ebpf
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/btf"
+	"github.com/cilium/ebpf/link"
+)
+
+const (
+	// constant defined in drops.h as "volatile const"
+	constSkbConsumedReason = "skb_consumed_reason"
+	dropStatsMap           = "drop_stats"
+
+	dropReasonEnum        = "skb_drop_reason"
+	dropReasonPrefix      = "SKB_DROP_REASON_"
+	skbConsumedName       = "SKB_CONSUMED"
+	noSkbConsumed         = math.MaxUint32
+	dropReasonOther       = "_OTHER"
+	dropReasonUnspecified = "not_specified"
+)
+
+// dropReasons translates the values of the skb_drop_reason kernel enum to their names,
+// as the numeric values depend on the kernel version
+type dropReasons struct {
+	names map[uint32]string
+	// value of SKB_CONSUMED, or noSkbConsumed if the kernel doesn't define it
+	consumed uint32
+}
+
+// loadDropReasons reads the drop reasons from the BTF information of the running kernel.
+// Kernels that don't define them won't report the drop reasons, so all the drops are
+// reported as not specified.
+func loadDropReasons(log *slog.Logger) dropReasons {
+	spec, err := btf.LoadKernelSpec()
+	if err != nil {
+		log.Warn("can't load kernel BTF. Network drop reasons won't be reported", "error", err)
+		return parseDropReasons(nil)
+	}
+	var enum *btf.Enum
+	if err := spec.TypeByName(dropReasonEnum, &enum); err != nil {
+		log.Debug("kernel does not define the drop reasons", "error", err)
+		return parseDropReasons(nil)
+	}
+	return parseDropReasons(enum)
+}
+
+func parseDropReasons(enum *btf.Enum) dropReasons {
+	reasons := dropReasons{names: map[uint32]string{}, consumed: noSkbConsumed}
+	if enum == nil {
+		reasons.names[0] = dropReasonUnspecified
+		return reasons
+	}
+	for _, v := range enum.Values {
+		if v.Name == skbConsumedName {
+			reasons.consumed = uint32(v.Value)
+			continue
+		}
+		if name, ok := strings.CutPrefix(v.Name, dropReasonPrefix); ok {
+			reasons.names[uint32(v.Value)] = strings.ToLower(name)
+		}
+	}
+	return reasons
+}
+
+func (d *dropReasons) name(reason uint32) string {
+	if name, ok := d.names[reason]; ok {
+		return name
+	}
+	return dropReasonOther
+}
+
+// dropTracker attaches the tracepoint that accounts the packets dropped by the kernel,
+// and reads the dropped packets from the drop_stats map. It is shared by the
+// TC and socket filter fetchers, as both load the same tracepoint program.
+type dropTracker struct {
+	log          *slog.Logger
+	stats        *ebpf.Map
+	link         link.Link
+	reasons      dropReasons
+	cacheMaxSize int
+}
+
+// attachDropTracker returns nil if the packet drops tracking is not enabled
+func attachDropTracker(
+	log *slog.Logger, enable bool, prog *ebpf.Program, stats *ebpf.Map, reasons dropReasons, cacheMaxSize int,
+) (*dropTracker, error) {
+	if !enable {
+		return nil, nil
+	}
+	l, err := link.Tracepoint("skb", "kfree_skb", prog, nil)
+	if err != nil {
+		return nil, fmt.Errorf("attaching packet drops tracker: %w", err)
+	}
+	return &dropTracker{log: log, stats: stats, link: l, reasons: reasons, cacheMaxSize: cacheMaxSize}, nil
+}
+
+func (d *dropTracker) close() error {
+	if d == nil {
+		return nil
+	}
+	return d.link.Close()
+}
+
+// lookupAndDelete reads all the dropped packets from the eBPF map and removes them from it,
+// following the same approach as LookupAndDeleteMap for the flows
+func (d *dropTracker) lookupAndDelete() map[DropKey][]NetDropMetrics {
+	if d == nil {
+		return nil
+	}
+	iterator := d.stats.Iterate()
+	drops := make(map[DropKey][]NetDropMetrics, d.cacheMaxSize)
+
+	id := NetDropId{}
+	var metrics []NetDropMetrics
+	for iterator.Next(&id, &metrics) {
+		if err := d.stats.Delete(id); err != nil {
+			d.log.Debug("couldn't delete drop entry", "dropId", id, "error", err)
+		}
+		key := DropKey{Id: id.Id, Reason: d.reasons.name(id.Reason)}
+		drops[key] = append(drops[key], metrics...)
+	}
+	return drops
+}
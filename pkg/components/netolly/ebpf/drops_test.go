@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package ebpf
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf/btf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDropReasons(t *testing.T) {
+	// values of the skb_drop_reason enum in newer kernels
+	reasons := parseDropReasons(&btf.Enum{Name: "skb_drop_reason", Values: []btf.EnumValue{
+		{Name: "SKB_NOT_DROPPED_YET", Value: 0},
+		{Name: "SKB_CONSUMED", Value: 1},
+		{Name: "SKB_DROP_REASON_NOT_SPECIFIED", Value: 2},
+		{Name: "SKB_DROP_REASON_NO_SOCKET", Value: 3},
+		{Name: "SKB_DROP_REASON_NETFILTER_DROP", Value: 8},
+	}})
+	assert.EqualValues(t, 1, reasons.consumed)
+	assert.Equal(t, "not_specified", reasons.name(2))
+	assert.Equal(t, "no_socket", reasons.name(3))
+	assert.Equal(t, "netfilter_drop", reasons.name(8))
+	assert.Equal(t, "_OTHER", reasons.name(0))
+	assert.Equal(t, "_OTHER", reasons.name(1234))
+
+	// kernels without drop reasons
+	reasons = parseDropReasons(nil)
+	assert.EqualValues(t, noSkbConsumed, reasons.consumed)
+	assert.Equal(t, "not_specified", reasons.name(0))
+}
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package ebpf
+
+import (
+	"log/slog"
+
+	"github.com/cilium/ebpf"
+)
+
+const (
+	// constant defined in flow_payload.h as "volatile const"
+	constSamplePayloads = "sample_payloads"
+	flowPayloadsMap     = "flow_payloads"
+	classifiedFlowsMap  = "classified_flows"
+)
+
+func samplePayloadsConst(enable bool) uint8 {
+	if enable {
+		return 1
+	}
+	return 0
+}
+
+// lookupAndDeletePayloads reads all the payload samples from the eBPF map and removes them from it.
+// The flows are not sampled again after the eviction, as the eBPF programs keep track of the
+// already sampled flows in the classified_flows map.
+func lookupAndDeletePayloads(log *slog.Logger, payloadsMap *ebpf.Map) map[NetFlowId][]byte {
+	iterator := payloadsMap.Iterate()
+	payloads := map[NetFlowId][]byte{}
+
+	id := NetFlowId{}
+	payload := NetFlowPayload{}
+	for iterator.Next(&id, &payload) {
+		if err := payloadsMap.Delete(id); err != nil {
+			log.Debug("couldn't delete flow payload entry", "flowId", id, "error", err)
+		}
+		payloads[id] = payload.Bytes()
+	}
+	return payloads
+}
@@ -22,9 +22,12 @@
 package ebpf
 
 import (
+	"bytes"
 	"encoding/binary"
 	"io"
 	"net"
+	"syscall"
+	"time"
 
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 )
@@ -44,6 +47,31 @@ type Record struct {
 
 	// Attrs of the flow record: source/destination, Interface, Beyla IP, etc...
 	Attrs RecordAttrs
+
+	// Conn is only set when the record does not describe the traffic of a network flow,
+	// but the lifecycle of the TCP connections from the source (local) endpoint to the
+	// destination (remote) endpoint. In that case, only the identifier, the times and the
+	// initiator of the flow metrics are set.
+	Conn *NetConnMetrics
+
+	// Drop is only set when the record does not describe the traffic of a network flow,
+	// but the packets of the flow that have been dropped by the kernel. In that case, only
+	// the identifier and the times of the flow metrics are set.
+	Drop *Drop
+}
+
+// DropKey identifies the packets of a flow that have been dropped for the same reason
+type DropKey struct {
+	Id NetFlowId
+	// Reason is the kernel drop reason, in lowercase and without the SKB_DROP_REASON_ prefix
+	// (e.g. no_socket, netfilter_drop, tcp_csum...)
+	Reason string
+}
+
+// Drop contains the packets of a flow that have been dropped by the kernel for the same reason
+type Drop struct {
+	NetDropMetrics
+	Reason string
 }
 
 type RecordAttrs struct {
@@ -58,6 +86,32 @@ type RecordAttrs struct {
 	SrcZone string
 	DstZone string
 
+	// SrcRegion and DstRegion represent the Cloud regions of the source and destination
+	SrcRegion string
+	DstRegion string
+
+	// SrcCloudZone, DstCloudZone, SrcCloudRegion and DstCloudRegion represent the Cloud location
+	// of the source and destination, as resolved by the egress decorator from the zones and regions
+	// above or, for the addresses out of the cluster, from the configured Cloud address ranges
+	SrcCloudZone   string
+	DstCloudZone   string
+	SrcCloudRegion string
+	DstCloudRegion string
+
+	// EgressType is the billing type of the traffic from the source to the destination:
+	// cross_zone, cross_region, internet, or empty if it's not billed as egress traffic
+	EgressType string
+	// EgressPricePerGB is the configured price for the EgressType of the flow
+	EgressPricePerGB float64
+
+	// L7Protocol is the application-layer protocol of the flow (e.g. http, grpc, kafka...),
+	// as classified from the first payload bytes of the flow. Empty if unknown.
+	L7Protocol string
+
+	// Folded is true if the flow doesn't belong to the top talkers, so its source and destination
+	// addresses are reported as "other" and its source, destination and client ports as zero
+	Folded bool
+
 	Interface string
 	// OBIIP provides information about the source of the flow (the Agent that traced it)
 	OBIIP    string
@@ -76,6 +130,62 @@ func NewRecord(
 	}
 }
 
+// NewConnRecord creates a record with the lifecycle metrics of the TCP connections
+// identified by the key
+func NewConnRecord(key NetFlowId, conn NetConnMetrics) *Record {
+	key.IfIndex = InterfaceUnset
+	return &Record{
+		NetFlowRecordT: NetFlowRecordT{
+			Id: key,
+			Metrics: NetFlowMetrics{
+				StartMonoTimeNs: conn.StartMonoTimeNs,
+				EndMonoTimeNs:   conn.EndMonoTimeNs,
+				IfaceDirection:  DirectionUnset,
+				Initiator:       conn.Initiator,
+			},
+		},
+		Conn: &conn,
+	}
+}
+
+// IsConnection returns whether the record contains TCP connection lifecycle metrics
+// instead of network flow metrics
+func (r *Record) IsConnection() bool {
+	return r.Conn != nil
+}
+
+// NewDropRecord creates a record with the packets that have been dropped by the kernel
+// for the flow and reason identified by the key
+func NewDropRecord(key DropKey, drop NetDropMetrics) *Record {
+	return &Record{
+		NetFlowRecordT: NetFlowRecordT{
+			Id: key.Id,
+			Metrics: NetFlowMetrics{
+				StartMonoTimeNs: drop.StartMonoTimeNs,
+				EndMonoTimeNs:   drop.EndMonoTimeNs,
+				IfaceDirection:  DirectionUnset,
+			},
+		},
+		Drop: &Drop{NetDropMetrics: drop, Reason: key.Reason},
+	}
+}
+
+// EgressCost returns the cost of the bytes of the flow, according to the price per GB of its
+// egress type. Cloud providers bill the transferred data in gibibytes.
+func (r *Record) EgressCost() float64 {
+	return float64(r.Metrics.Bytes) * r.Attrs.EgressPricePerGB / (1 << 30)
+}
+
+// IsDrop returns whether the record contains dropped packets instead of network flow metrics
+func (r *Record) IsDrop() bool {
+	return r.Drop != nil
+}
+
+// Bytes returns a copy of the sampled payload bytes
+func (fp *NetFlowPayload) Bytes() []byte {
+	return bytes.Clone(fp.Buf[:min(int(fp.Len), len(fp.Buf))])
+}
+
 func (fm *NetFlowMetrics) Accumulate(src *NetFlowMetrics) {
 	// time == 0 if the value has not been yet set
 	if fm.StartMonoTimeNs == 0 || fm.StartMonoTimeNs > src.StartMonoTimeNs {
@@ -84,12 +194,81 @@ func (fm *NetFlowMetrics) Accumulate(src *NetFlowMetrics) {
 		fm.IfaceDirection = src.IfaceDirection
 		fm.Initiator = src.Initiator
 	}
+	// keep the most recent smoothed RTT
+	if src.SrttUs != 0 && (fm.SrttUs == 0 || fm.EndMonoTimeNs <= src.EndMonoTimeNs) {
+		fm.SrttUs = src.SrttUs
+	}
 	if fm.EndMonoTimeNs == 0 || fm.EndMonoTimeNs < src.EndMonoTimeNs {
 		fm.EndMonoTimeNs = src.EndMonoTimeNs
 	}
 	fm.Bytes += src.Bytes
 	fm.Packets += src.Packets
 	fm.Flags |= src.Flags
+	fm.Retransmits += src.Retransmits
+	fm.Resets += src.Resets
+}
+
+// SmoothedRTT returns the latest smoothed round-trip time of a TCP flow, or zero if it is unknown
+func (fm *NetFlowMetrics) SmoothedRTT() time.Duration {
+	return time.Duration(fm.SrttUs) * time.Microsecond
+}
+
+func (cm *NetConnMetrics) Accumulate(src *NetConnMetrics) {
+	if cm.StartMonoTimeNs == 0 || cm.StartMonoTimeNs > src.StartMonoTimeNs {
+		cm.StartMonoTimeNs = src.StartMonoTimeNs
+	}
+	if cm.EndMonoTimeNs == 0 || cm.EndMonoTimeNs < src.EndMonoTimeNs {
+		cm.EndMonoTimeNs = src.EndMonoTimeNs
+		cm.Initiator = src.Initiator
+		if src.Failed > 0 {
+			cm.Errno = src.Errno
+		}
+	}
+	if cm.Errno == 0 {
+		cm.Errno = src.Errno
+	}
+	cm.DurationNs += src.DurationNs
+	cm.Opened += src.Opened
+	cm.Failed += src.Failed
+	cm.Closed += src.Closed
+}
+
+func (dm *NetDropMetrics) Accumulate(src *NetDropMetrics) {
+	if dm.StartMonoTimeNs == 0 || dm.StartMonoTimeNs > src.StartMonoTimeNs {
+		dm.StartMonoTimeNs = src.StartMonoTimeNs
+	}
+	if dm.EndMonoTimeNs == 0 || dm.EndMonoTimeNs < src.EndMonoTimeNs {
+		dm.EndMonoTimeNs = src.EndMonoTimeNs
+	}
+	dm.Bytes += src.Bytes
+	dm.Packets += src.Packets
+}
+
+// AvgDuration returns the average lifetime of the closed connections, or zero if no connection
+// has been closed
+func (cm *NetConnMetrics) AvgDuration() time.Duration {
+	if cm.Closed == 0 {
+		return 0
+	}
+	return time.Duration(cm.DurationNs / uint64(cm.Closed))
+}
+
+// FailureReason returns the error.type of the failed connection attempts, according to the
+// errno of the last failure
+func (cm *NetConnMetrics) FailureReason() string {
+	switch syscall.Errno(cm.Errno) {
+	case syscall.ECONNREFUSED:
+		return "connection_refused"
+	case syscall.ETIMEDOUT:
+		return "timed_out"
+	case syscall.EHOSTUNREACH:
+		return "host_unreachable"
+	case syscall.ENETUNREACH:
+		return "network_unreachable"
+	case syscall.ECONNRESET:
+		return "connection_reset"
+	}
+	return "_OTHER"
 }
 
 // SrcIP is never null. Returned as pointer for efficiency.
@@ -14,6 +14,10 @@ import (
 const (
 	DirectionRequest  = "request"
 	DirectionResponse = "response"
+
+	// FoldedValue is reported as source and destination address and name of the flows
+	// that don't belong to the top talkers
+	FoldedValue = "other"
 )
 
 // RecordGetters returns the attributes.Getter function that returns the string value of a given
@@ -31,16 +35,26 @@ func RecordGetters(name attr.Name) (attributes.Getter[*Record, attribute.KeyValu
 		}
 	case attr.SrcAddress:
 		getter = func(r *Record) attribute.KeyValue {
+			if r.Attrs.Folded {
+				return attribute.String(string(attr.SrcAddress), FoldedValue)
+			}
 			return attribute.String(string(attr.SrcAddress), r.Id.SrcIP().IP().String())
 		}
 	case attr.DstAddres:
 		getter = func(r *Record) attribute.KeyValue {
+			if r.Attrs.Folded {
+				return attribute.String(string(attr.DstAddres), FoldedValue)
+			}
 			return attribute.String(string(attr.DstAddres), r.Id.DstIP().IP().String())
 		}
 	case attr.SrcPort:
-		getter = func(r *Record) attribute.KeyValue { return attribute.Int(string(attr.SrcPort), int(r.Id.SrcPort)) }
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.Int(string(attr.SrcPort), r.foldedPort(r.Id.SrcPort))
+		}
 	case attr.DstPort:
-		getter = func(r *Record) attribute.KeyValue { return attribute.Int(string(attr.DstPort), int(r.Id.DstPort)) }
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.Int(string(attr.DstPort), r.foldedPort(r.Id.DstPort))
+		}
 	case attr.SrcName:
 		getter = func(r *Record) attribute.KeyValue { return attribute.String(string(attr.SrcName), r.Attrs.SrcName) }
 	case attr.DstName:
@@ -63,7 +77,7 @@ func RecordGetters(name attr.Name) (attributes.Getter[*Record, attribute.KeyValu
 				// guess it, assuming that ephemeral ports for clients would be usually higher
 				clientPort = max(r.Id.DstPort, r.Id.SrcPort)
 			}
-			return attribute.Int(string(attr.ClientPort), int(clientPort))
+			return attribute.Int(string(attr.ClientPort), r.foldedPort(clientPort))
 		}
 	case attr.Direction:
 		getter = func(r *Record) attribute.KeyValue {
@@ -101,6 +115,50 @@ func RecordGetters(name attr.Name) (attributes.Getter[*Record, attribute.KeyValu
 		getter = func(r *Record) attribute.KeyValue { return attribute.String(string(attr.SrcZone), r.Attrs.SrcZone) }
 	case attr.DstZone:
 		getter = func(r *Record) attribute.KeyValue { return attribute.String(string(attr.DstZone), r.Attrs.DstZone) }
+	case attr.L7Protocol:
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.String(string(attr.L7Protocol), r.Attrs.L7Protocol)
+		}
+	case attr.SrcRegion:
+		getter = func(r *Record) attribute.KeyValue { return attribute.String(string(attr.SrcRegion), r.Attrs.SrcRegion) }
+	case attr.DstRegion:
+		getter = func(r *Record) attribute.KeyValue { return attribute.String(string(attr.DstRegion), r.Attrs.DstRegion) }
+	case attr.SrcCloudZone:
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.String(string(attr.SrcCloudZone), r.Attrs.SrcCloudZone)
+		}
+	case attr.DstCloudZone:
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.String(string(attr.DstCloudZone), r.Attrs.DstCloudZone)
+		}
+	case attr.SrcCloudRegion:
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.String(string(attr.SrcCloudRegion), r.Attrs.SrcCloudRegion)
+		}
+	case attr.DstCloudRegion:
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.String(string(attr.DstCloudRegion), r.Attrs.DstCloudRegion)
+		}
+	case attr.EgressType:
+		getter = func(r *Record) attribute.KeyValue {
+			return attribute.String(string(attr.EgressType), r.Attrs.EgressType)
+		}
+	case attr.ErrorType:
+		getter = func(r *Record) attribute.KeyValue {
+			var errType string
+			if r.Conn != nil && r.Conn.Failed > 0 {
+				errType = r.Conn.FailureReason()
+			}
+			return attribute.String(string(attr.ErrorType), errType)
+		}
+	case attr.DropReason:
+		getter = func(r *Record) attribute.KeyValue {
+			var reason string
+			if r.Drop != nil {
+				reason = r.Drop.Reason
+			}
+			return attribute.String(string(attr.DropReason), reason)
+		}
 	default:
 		getter = func(r *Record) attribute.KeyValue { return attribute.String(string(name), r.Attrs.Metadata[name]) }
 	}
@@ -114,6 +172,14 @@ func RecordStringGetters(name attr.Name) (attributes.Getter[*Record, string], bo
 	return nil, false
 }
 
+// foldedPort returns zero for the ports of the flows that don't belong to the top talkers
+func (r *Record) foldedPort(port uint16) int {
+	if r.Attrs.Folded {
+		return 0
+	}
+	return int(port)
+}
+
 func ifaceDirectionStr(direction uint8) string {
 	switch direction {
 	case DirectionIngress:
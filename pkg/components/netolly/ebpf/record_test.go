@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpf
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccumulate_TCPHealth(t *testing.T) {
+	fm := NetFlowMetrics{
+		StartMonoTimeNs: 100, EndMonoTimeNs: 200,
+		Packets: 2, SrttUs: 1000, Retransmits: 1, Resets: 0,
+	}
+	// per-CPU entries are accumulated in any order
+	fm.Accumulate(&NetFlowMetrics{
+		StartMonoTimeNs: 150, EndMonoTimeNs: 300,
+		Packets: 1, SrttUs: 2000, Retransmits: 2, Resets: 1,
+	})
+	fm.Accumulate(&NetFlowMetrics{
+		StartMonoTimeNs: 120, EndMonoTimeNs: 250,
+		Packets: 1, SrttUs: 3000,
+	})
+	// entries without RTT (e.g. ingress packets) don't override it
+	fm.Accumulate(&NetFlowMetrics{
+		StartMonoTimeNs: 130, EndMonoTimeNs: 400,
+		Packets: 1, Resets: 1,
+	})
+
+	assert.EqualValues(t, 5, fm.Packets)
+	assert.EqualValues(t, 3, fm.Retransmits)
+	assert.EqualValues(t, 2, fm.Resets)
+	// the most recent RTT is kept
+	assert.Equal(t, 2*time.Millisecond, fm.SmoothedRTT())
+	assert.EqualValues(t, 400, fm.EndMonoTimeNs)
+}
+
+func TestAccumulate_Connections(t *testing.T) {
+	cm := NetConnMetrics{StartMonoTimeNs: 100, EndMonoTimeNs: 200, Opened: 2, Initiator: InitiatorDst}
+	cm.Accumulate(&NetConnMetrics{
+		StartMonoTimeNs: 150, EndMonoTimeNs: 300,
+		Failed: 1, Errno: uint8(syscall.ECONNREFUSED), Initiator: InitiatorSrc,
+	})
+	cm.Accumulate(&NetConnMetrics{
+		StartMonoTimeNs: 50, EndMonoTimeNs: 250,
+		Closed: 2, DurationNs: uint64(3 * time.Second),
+	})
+
+	assert.EqualValues(t, 50, cm.StartMonoTimeNs)
+	assert.EqualValues(t, 300, cm.EndMonoTimeNs)
+	assert.EqualValues(t, 2, cm.Opened)
+	assert.EqualValues(t, 1, cm.Failed)
+	assert.EqualValues(t, 2, cm.Closed)
+	assert.EqualValues(t, InitiatorSrc, cm.Initiator)
+	assert.Equal(t, 1500*time.Millisecond, cm.AvgDuration())
+	assert.Equal(t, "connection_refused", cm.FailureReason())
+
+	assert.Zero(t, (&NetConnMetrics{}).AvgDuration())
+	assert.Equal(t, "timed_out", (&NetConnMetrics{Errno: uint8(syscall.ETIMEDOUT)}).FailureReason())
+	assert.Equal(t, "_OTHER", (&NetConnMetrics{}).FailureReason())
+}
+
+func TestNewConnRecord(t *testing.T) {
+	r := NewConnRecord(NetFlowId{IfIndex: 0, SrcPort: 34567, DstPort: 80, TransportProtocol: 6},
+		NetConnMetrics{StartMonoTimeNs: 10, EndMonoTimeNs: 20, Opened: 1, Initiator: InitiatorSrc})
+	assert.True(t, r.IsConnection())
+	assert.EqualValues(t, InterfaceUnset, r.Id.IfIndex)
+	assert.EqualValues(t, DirectionUnset, r.Metrics.IfaceDirection)
+	assert.EqualValues(t, InitiatorSrc, r.Metrics.Initiator)
+	assert.Zero(t, r.Metrics.Bytes)
+	assert.False(t, NewRecord(NetFlowId{}, NetFlowMetrics{}).IsConnection())
+}
+
+func TestNewDropRecord(t *testing.T) {
+	dm := NetDropMetrics{StartMonoTimeNs: 100, EndMonoTimeNs: 200, Packets: 2, Bytes: 120}
+	dm.Accumulate(&NetDropMetrics{StartMonoTimeNs: 50, EndMonoTimeNs: 150, Packets: 1, Bytes: 60})
+	assert.EqualValues(t, 50, dm.StartMonoTimeNs)
+	assert.EqualValues(t, 200, dm.EndMonoTimeNs)
+	assert.EqualValues(t, 3, dm.Packets)
+	assert.EqualValues(t, 180, dm.Bytes)
+
+	r := NewDropRecord(DropKey{Id: NetFlowId{IfIndex: 3, DstPort: 80}, Reason: "no_socket"}, dm)
+	assert.True(t, r.IsDrop())
+	assert.False(t, r.IsConnection())
+	assert.EqualValues(t, 3, r.Id.IfIndex)
+	assert.EqualValues(t, DirectionUnset, r.Metrics.IfaceDirection)
+	assert.EqualValues(t, 3, r.Drop.Packets)
+	assert.Zero(t, r.Metrics.Packets)
+
+	reason, ok := RecordStringGetters("drop.reason")
+	require.True(t, ok)
+	assert.Equal(t, "no_socket", reason(r))
+	assert.Empty(t, reason(NewRecord(NetFlowId{}, NetFlowMetrics{})))
+	assert.False(t, NewRecord(NetFlowId{}, NetFlowMetrics{}).IsDrop())
+}
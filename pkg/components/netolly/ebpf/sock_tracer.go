@@ -39,7 +39,7 @@ import (
 )
 
 // $BPF_CLANG and $BPF_CFLAGS are set by the Makefile.
-//go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -type flow_metrics_t -type flow_id_t  -type flow_record_t -target amd64,arm64 NetSk ../../../../bpf/netolly/flows_sock.c -- -I../../../../bpf
+//go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -type flow_metrics_t -type flow_id_t  -type flow_record_t -type conn_metrics_t -type drop_id_t -type drop_metrics_t -type flow_payload_t -target amd64,arm64 NetSk ../../../../bpf/netolly/flows_sock.c -- -I../../../../bpf
 
 // SockFlowFetcher reads and forwards the Flows from the eBPF kernel space with a socket filter implementation.
 // It provides access both to flows that are aggregated in the kernel space (via PerfCPU hashmap)
@@ -49,11 +49,18 @@ type SockFlowFetcher struct {
 	log           *slog.Logger
 	objects       *NetSkObjects
 	ringbufReader *ringbuf.Reader
+	connTracker   *connTracker
+	dropTracker   *dropTracker
 	cacheMaxSize  int
+	// samplePayloads is true if the flow payloads are sampled for the L7 protocol classification
+	samplePayloads bool
 }
 
 func NewSockFlowFetcher(
 	sampling, cacheMaxSize int,
+	trackConnections bool,
+	trackDrops bool,
+	samplePayloads bool,
 ) (*SockFlowFetcher, error) {
 	tlog := tlog()
 	if err := rlimit.RemoveMemlock(); err != nil {
@@ -71,14 +78,25 @@ func NewSockFlowFetcher(
 	spec.Maps[aggregatedFlowsMap].MaxEntries = uint32(cacheMaxSize)
 	spec.Maps[flowDirectionsMap].MaxEntries = uint32(cacheMaxSize)
 	spec.Maps[connInitiatorsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[connStatsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[dropStatsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[flowPayloadsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[classifiedFlowsMap].MaxEntries = uint32(cacheMaxSize)
+
+	reasons := dropReasons{consumed: noSkbConsumed}
+	if trackDrops {
+		reasons = loadDropReasons(tlog)
+	}
 
 	traceMsgs := 0
 	if tlog.Enabled(context.TODO(), slog.LevelDebug) {
 		traceMsgs = 1
 	}
 	if err := convenience.RewriteConstants(spec, map[string]any{
-		constSampling:      uint32(sampling),
-		constTraceMessages: uint8(traceMsgs),
+		constSampling:          uint32(sampling),
+		constTraceMessages:     uint8(traceMsgs),
+		constSamplePayloads:    samplePayloadsConst(samplePayloads),
+		constSkbConsumedReason: reasons.consumed,
 	}); err != nil {
 		return nil, fmt.Errorf("rewriting BPF constants definition: %w", err)
 	}
@@ -89,27 +107,43 @@ func NewSockFlowFetcher(
 		return nil, fmt.Errorf("loading and assigning BPF objects: %w", err)
 	}
 
+	fetcher := &SockFlowFetcher{
+		log:            tlog,
+		objects:        &objects,
+		cacheMaxSize:   cacheMaxSize,
+		samplePayloads: samplePayloads,
+	}
+
 	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, int(htons(unix.ETH_P_ALL)))
 	if err == nil {
 		ssoErr := syscall.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_ATTACH_BPF, objects.ObiSocketFilter.FD())
 		if ssoErr != nil {
+			_ = unix.Close(fd)
+			_ = fetcher.Close()
 			return nil, fmt.Errorf("loading and assigning BPF objects: %w", ssoErr)
 		}
 	} else {
+		_ = fetcher.Close()
 		return nil, fmt.Errorf("loading and assigning BPF objects: %w", err)
 	}
 
 	// read events from socket filter ringbuffer
-	flows, err := ringbuf.NewReader(objects.DirectFlows)
-	if err != nil {
+	if fetcher.ringbufReader, err = ringbuf.NewReader(objects.DirectFlows); err != nil {
+		_ = fetcher.Close()
 		return nil, fmt.Errorf("accessing to ringbuffer: %w", err)
 	}
-	return &SockFlowFetcher{
-		log:           tlog,
-		objects:       &objects,
-		ringbufReader: flows,
-		cacheMaxSize:  cacheMaxSize,
-	}, nil
+	// on error, the fetcher is closed to release the already attached trackers
+	if fetcher.connTracker, err = attachConnTracker(tlog, trackConnections,
+		objects.ObiInetSockSetState, objects.ConnStats, cacheMaxSize); err != nil {
+		_ = fetcher.Close()
+		return nil, err
+	}
+	if fetcher.dropTracker, err = attachDropTracker(tlog, trackDrops,
+		objects.ObiKfreeSkb, objects.DropStats, reasons, cacheMaxSize); err != nil {
+		_ = fetcher.Close()
+		return nil, err
+	}
+	return fetcher, nil
 }
 
 func printVerifierErrorInfo(err error) {
@@ -132,6 +166,12 @@ func (m *SockFlowFetcher) Close() error {
 			errs = append(errs, err)
 		}
 	}
+	if err := m.connTracker.close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.dropTracker.close(); err != nil {
+		errs = append(errs, err)
+	}
 	if m.objects != nil {
 		errs = append(errs, m.closeObjects()...)
 	}
@@ -157,6 +197,27 @@ func (m *SockFlowFetcher) closeObjects() []error {
 	if err := m.objects.DirectFlows.Close(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := m.objects.ObiInetSockSetState.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.ConnStats.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.ObiKfreeSkb.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.DropStats.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.FlowPayloads.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.ClassifiedFlows.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.FlowPayloadStorage.Close(); err != nil {
+		errs = append(errs, err)
+	}
 	m.objects = nil
 	return errs
 }
@@ -196,6 +257,27 @@ func (m *SockFlowFetcher) LookupAndDeleteMap() map[NetFlowId][]NetFlowMetrics {
 	return flows
 }
 
+// LookupAndDeleteConnMap reads and removes all the entries from the TCP connection metrics map.
+// It returns nil if the connection tracking is not enabled.
+func (m *SockFlowFetcher) LookupAndDeleteConnMap() map[NetFlowId][]NetConnMetrics {
+	return m.connTracker.lookupAndDelete()
+}
+
+// LookupAndDeleteDropMap reads and removes all the entries from the dropped packets map.
+// It returns nil if the packet drops tracking is not enabled.
+func (m *SockFlowFetcher) LookupAndDeleteDropMap() map[DropKey][]NetDropMetrics {
+	return m.dropTracker.lookupAndDelete()
+}
+
+// LookupAndDeletePayloadMap reads and removes all the flow payload samples from the eBPF map.
+// It returns nil if the payload sampling is not enabled.
+func (m *SockFlowFetcher) LookupAndDeletePayloadMap() map[NetFlowId][]byte {
+	if !m.samplePayloads {
+		return nil
+	}
+	return lookupAndDeletePayloads(m.log, m.objects.FlowPayloads)
+}
+
 func isLittleEndian() bool {
 	var a uint16 = 1
 
@@ -19,10 +19,22 @@ func (s *SockFlowFetcher) LookupAndDeleteMap() map[NetFlowId][]NetFlowMetrics {
 	panic("this is never going to be executed")
 }
 
+func (s *SockFlowFetcher) LookupAndDeleteConnMap() map[NetFlowId][]NetConnMetrics {
+	panic("this is never going to be executed")
+}
+
+func (s *SockFlowFetcher) LookupAndDeleteDropMap() map[DropKey][]NetDropMetrics {
+	panic("this is never going to be executed")
+}
+
+func (s *SockFlowFetcher) LookupAndDeletePayloadMap() map[NetFlowId][]byte {
+	panic("this is never going to be executed")
+}
+
 func (s *SockFlowFetcher) ReadRingBuf() (ringbuf.Record, error) {
 	panic("this is never going to be executed")
 }
 
-func NewSockFlowFetcher(_, _ int) (*SockFlowFetcher, error) {
+func NewSockFlowFetcher(_, _ int, _, _, _ bool) (*SockFlowFetcher, error) {
 	return nil, nil
 }
@@ -38,7 +38,7 @@ import (
 )
 
 // $BPF_CLANG and $BPF_CFLAGS are set by the Makefile.
-//go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -type flow_metrics_t -type flow_id_t  -type flow_record_t -target amd64,arm64 Net ../../../../bpf/netolly/flows.c -- -I../../../../bpf
+//go:generate $BPF2GO -cc $BPF_CLANG -cflags $BPF_CFLAGS -type flow_metrics_t -type flow_id_t  -type flow_record_t -type conn_metrics_t -type drop_id_t -type drop_metrics_t -type flow_payload_t -target amd64,arm64 Net ../../../../bpf/netolly/flows.c -- -I../../../../bpf
 
 const (
 	// constants defined in flows.c as "volatile const"
@@ -62,9 +62,13 @@ type FlowFetcher struct {
 	objects       *NetObjects
 	ringbufReader *ringbuf.Reader
 	tcManager     tcmanager.TCManager
+	connTracker   *connTracker
+	dropTracker   *dropTracker
 	cacheMaxSize  int
-	enableIngress bool
-	enableEgress  bool
+	// samplePayloads is true if the flow payloads are sampled for the L7 protocol classification
+	samplePayloads bool
+	enableIngress  bool
+	enableEgress   bool
 }
 
 func NewFlowFetcher(
@@ -72,6 +76,9 @@ func NewFlowFetcher(
 	ingress, egress bool,
 	ifaceManager *tcmanager.InterfaceManager,
 	tcBackend tcmanager.TCBackend,
+	trackConnections bool,
+	trackDrops bool,
+	samplePayloads bool,
 ) (*FlowFetcher, error) {
 	tlog := tlog()
 	if err := rlimit.RemoveMemlock(); err != nil {
@@ -89,14 +96,25 @@ func NewFlowFetcher(
 	spec.Maps[aggregatedFlowsMap].MaxEntries = uint32(cacheMaxSize)
 	spec.Maps[flowDirectionsMap].MaxEntries = uint32(cacheMaxSize)
 	spec.Maps[connInitiatorsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[connStatsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[dropStatsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[flowPayloadsMap].MaxEntries = uint32(cacheMaxSize)
+	spec.Maps[classifiedFlowsMap].MaxEntries = uint32(cacheMaxSize)
+
+	reasons := dropReasons{consumed: noSkbConsumed}
+	if trackDrops {
+		reasons = loadDropReasons(tlog)
+	}
 
 	traceMsgs := 0
 	if tlog.Enabled(context.TODO(), slog.LevelDebug) {
 		traceMsgs = 1
 	}
 	if err := convenience.RewriteConstants(spec, map[string]any{
-		constSampling:      uint32(sampling),
-		constTraceMessages: uint8(traceMsgs),
+		constSampling:          uint32(sampling),
+		constTraceMessages:     uint8(traceMsgs),
+		constSamplePayloads:    samplePayloadsConst(samplePayloads),
+		constSkbConsumedReason: reasons.consumed,
 	}); err != nil {
 		return nil, fmt.Errorf("rewriting BPF constants definition: %w", err)
 	}
@@ -104,35 +122,46 @@ func NewFlowFetcher(
 		return nil, fmt.Errorf("loading and assigning BPF objects: %w", err)
 	}
 
+	fetcher := &FlowFetcher{
+		log:            tlog,
+		objects:        &objects,
+		cacheMaxSize:   cacheMaxSize,
+		samplePayloads: samplePayloads,
+		enableIngress:  ingress,
+		enableEgress:   egress,
+	}
+
 	// read events from igress+egress ringbuffer
-	flows, err := ringbuf.NewReader(objects.DirectFlows)
-	if err != nil {
+	if fetcher.ringbufReader, err = ringbuf.NewReader(objects.DirectFlows); err != nil {
+		_ = fetcher.Close()
 		return nil, fmt.Errorf("accessing to ringbuffer: %w", err)
 	}
 
-	tcManager := tcmanager.NewTCManager(tcBackend)
-	tcManager.SetInterfaceManager(ifaceManager)
+	// on error, the fetcher is closed to release the already attached trackers
+	if fetcher.connTracker, err = attachConnTracker(tlog, trackConnections,
+		objects.ObiInetSockSetState, objects.ConnStats, cacheMaxSize); err != nil {
+		_ = fetcher.Close()
+		return nil, err
+	}
+	if fetcher.dropTracker, err = attachDropTracker(tlog, trackDrops,
+		objects.ObiKfreeSkb, objects.DropStats, reasons, cacheMaxSize); err != nil {
+		_ = fetcher.Close()
+		return nil, err
+	}
+
+	fetcher.tcManager = tcmanager.NewTCManager(tcBackend)
+	fetcher.tcManager.SetInterfaceManager(ifaceManager)
 
 	if egress {
-		tcManager.AddProgram("tc/egress_flow_parse", objects.ObiEgressFlowParse, tcmanager.AttachmentEgress)
+		fetcher.tcManager.AddProgram("tc/egress_flow_parse", objects.ObiEgressFlowParse, tcmanager.AttachmentEgress)
 	}
 
 	if ingress {
-		tcManager.AddProgram("tc/ingress_flow_parse", objects.ObiIngressFlowParse, tcmanager.AttachmentIngress)
-	}
-
-	fetcher := &FlowFetcher{
-		log:           tlog,
-		objects:       &objects,
-		ringbufReader: flows,
-		tcManager:     tcManager,
-		cacheMaxSize:  cacheMaxSize,
-		enableIngress: ingress,
-		enableEgress:  egress,
+		fetcher.tcManager.AddProgram("tc/ingress_flow_parse", objects.ObiIngressFlowParse, tcmanager.AttachmentIngress)
 	}
 
 	// errors are not critical for this tracer
-	go fetcher.logTCErrors(tcManager.Errors())
+	go fetcher.logTCErrors(fetcher.tcManager.Errors())
 
 	return fetcher, nil
 }
@@ -144,7 +173,9 @@ func (m *FlowFetcher) Close() error {
 	log := tlog()
 	log.Debug("unregistering eBPF objects")
 
-	m.tcManager.Shutdown()
+	if m.tcManager != nil {
+		m.tcManager.Shutdown()
+	}
 
 	var errs []error
 	// m.ringbufReader.Read is a blocking operation, so we need to close the ring buffer
@@ -156,6 +187,13 @@ func (m *FlowFetcher) Close() error {
 		}
 	}
 
+	if err := m.connTracker.close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.dropTracker.close(); err != nil {
+		errs = append(errs, err)
+	}
+
 	if m.objects != nil {
 		errs = append(errs, m.closeObjects()...)
 	}
@@ -186,6 +224,27 @@ func (m *FlowFetcher) closeObjects() []error {
 	if err := m.objects.DirectFlows.Close(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := m.objects.ObiInetSockSetState.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.ConnStats.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.ObiKfreeSkb.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.DropStats.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.FlowPayloads.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.ClassifiedFlows.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.objects.FlowPayloadStorage.Close(); err != nil {
+		errs = append(errs, err)
+	}
 	m.objects = nil
 	return errs
 }
@@ -225,6 +284,27 @@ func (m *FlowFetcher) LookupAndDeleteMap() map[NetFlowId][]NetFlowMetrics {
 	return flows
 }
 
+// LookupAndDeleteConnMap reads and removes all the entries from the TCP connection metrics map.
+// It returns nil if the connection tracking is not enabled.
+func (m *FlowFetcher) LookupAndDeleteConnMap() map[NetFlowId][]NetConnMetrics {
+	return m.connTracker.lookupAndDelete()
+}
+
+// LookupAndDeleteDropMap reads and removes all the entries from the dropped packets map.
+// It returns nil if the packet drops tracking is not enabled.
+func (m *FlowFetcher) LookupAndDeleteDropMap() map[DropKey][]NetDropMetrics {
+	return m.dropTracker.lookupAndDelete()
+}
+
+// LookupAndDeletePayloadMap reads and removes all the flow payload samples from the eBPF map.
+// It returns nil if the payload sampling is not enabled.
+func (m *FlowFetcher) LookupAndDeletePayloadMap() map[NetFlowId][]byte {
+	if !m.samplePayloads {
+		return nil
+	}
+	return lookupAndDeletePayloads(m.log, m.objects.FlowPayloads)
+}
+
 func (m *FlowFetcher) logTCErrors(errors chan error) {
 	for err := range errors {
 		m.log.Warn("TCManager error", "error", err)
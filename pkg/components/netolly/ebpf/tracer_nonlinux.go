@@ -12,7 +12,7 @@ import (
 
 type FlowFetcher struct{}
 
-func NewFlowFetcher(_, _ int, _, _ bool, _ *tcmanager.InterfaceManager, _ tcmanager.TCBackend) (*FlowFetcher, error) {
+func NewFlowFetcher(_, _ int, _, _ bool, _ *tcmanager.InterfaceManager, _ tcmanager.TCBackend, _, _, _ bool) (*FlowFetcher, error) {
 	return nil, nil
 }
 
@@ -27,3 +27,15 @@ func (m *FlowFetcher) ReadRingBuf() (ringbuf.Record, error) {
 func (m *FlowFetcher) LookupAndDeleteMap() map[NetFlowId][]NetFlowMetrics {
 	return nil
 }
+
+func (m *FlowFetcher) LookupAndDeleteConnMap() map[NetFlowId][]NetConnMetrics {
+	return nil
+}
+
+func (m *FlowFetcher) LookupAndDeleteDropMap() map[DropKey][]NetDropMetrics {
+	return nil
+}
+
+func (m *FlowFetcher) LookupAndDeletePayloadMap() map[NetFlowId][]byte {
+	return nil
+}
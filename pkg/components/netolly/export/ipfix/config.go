@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ipfix exports the network flow records to an IPFIX (RFC 7011) collector.
+package ipfix
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	TransportUDP = "udp"
+	TransportTCP = "tcp"
+)
+
+// Config of the IPFIX exporter
+type Config struct {
+	// Endpoint is the host:port address of the IPFIX collector. The exporter is disabled if empty.
+	Endpoint string `yaml:"endpoint" env:"OTEL_EBPF_NETWORK_IPFIX_ENDPOINT"`
+	// Transport protocol to submit the IPFIX messages: udp or tcp
+	Transport string `yaml:"transport" env:"OTEL_EBPF_NETWORK_IPFIX_TRANSPORT"`
+	// ObservationDomainID identifies this exporter in the collector
+	ObservationDomainID uint32 `yaml:"observation_domain_id" env:"OTEL_EBPF_NETWORK_IPFIX_OBSERVATION_DOMAIN_ID"`
+	// EnterpriseID is the Private Enterprise Number of the information elements that carry the
+	// Kubernetes and CIDR decoration of the flows. It must be configured when the exporter is
+	// enabled, as there is no number that identifies them for any user.
+	EnterpriseID uint32 `yaml:"enterprise_id" env:"OTEL_EBPF_NETWORK_IPFIX_ENTERPRISE_ID"`
+	// TemplateRefresh is the interval for resending the templates over UDP, as the collector
+	// might have been restarted or have lost some of the previous messages
+	TemplateRefresh time.Duration `yaml:"template_refresh" env:"OTEL_EBPF_NETWORK_IPFIX_TEMPLATE_REFRESH"`
+	// MaxMessageSize limits the size of each IPFIX message. It should fit in the path MTU when
+	// the UDP transport is used
+	MaxMessageSize int `yaml:"max_message_size" env:"OTEL_EBPF_NETWORK_IPFIX_MAX_MESSAGE_SIZE"`
+}
+
+var DefaultConfig = Config{
+	Transport:       TransportUDP,
+	TemplateRefresh: time.Minute,
+	MaxMessageSize:  1400,
+}
+
+func (c *Config) Enabled() bool {
+	return c != nil && c.Endpoint != ""
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(c.Endpoint); err != nil {
+		return fmt.Errorf("invalid IPFIX endpoint %q: %w", c.Endpoint, err)
+	}
+	if c.Transport != TransportUDP && c.Transport != TransportTCP {
+		return fmt.Errorf("invalid IPFIX transport %q. Accepted values: %s, %s",
+			c.Transport, TransportUDP, TransportTCP)
+	}
+	if c.MaxMessageSize < minMessageSize || c.MaxMessageSize > maxMessageSize {
+		return fmt.Errorf("IPFIX max_message_size must be between %d and %d", minMessageSize, maxMessageSize)
+	}
+	if c.EnterpriseID == 0 {
+		return errors.New("IPFIX enterprise_id must be set to the Private Enterprise Number of your organization")
+	}
+	return nil
+}
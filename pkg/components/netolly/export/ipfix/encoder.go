@@ -0,0 +1,359 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipfix
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+)
+
+const (
+	ipfixVersion = 10
+
+	messageHeaderLen = 16
+	setHeaderLen     = 4
+	templateSetID    = 2
+
+	templateIDv4 = 256
+	templateIDv6 = 257
+	// templates for the flows whose direction is unknown, which don't have the flowDirection field
+	templateIDv4NoDirection = 258
+	templateIDv6NoDirection = 259
+
+	// the message that carries the template set is about 800 bytes long
+	minMessageSize = 1024
+	maxMessageSize = 65535
+
+	// length of the variable-length information elements
+	variableLength = 65535
+	// longer strings are truncated, so any data record fits in a message
+	maxStringLen = 1024
+
+	enterpriseBit = 0x8000
+)
+
+// TCP flags according to the tcpControlBits information element, and the
+// custom flags that are set by the eBPF programs in the flow records
+const (
+	tcpFIN = 0x01
+	tcpRST = 0x04
+	tcpACK = 0x10
+
+	customSynAck = 0x100
+	customFinAck = 0x200
+	customRstAck = 0x400
+)
+
+// field of a template, and the function that appends its value to a data record
+type field struct {
+	id         uint16
+	length     uint16
+	enterprise bool
+	appendTo   func(b []byte, r *ebpf.Record, clk *clock) []byte
+}
+
+// IANA-assigned information elements: https://www.iana.org/assignments/ipfix/ipfix.xhtml
+func ianaFields(ipv6, withDirection bool) []field {
+	srcIP, dstIP := field{id: 8, length: 4, appendTo: appendSrcIPv4}, field{id: 12, length: 4, appendTo: appendDstIPv4}
+	if ipv6 {
+		srcIP, dstIP = field{id: 27, length: 16, appendTo: appendSrcIPv6}, field{id: 28, length: 16, appendTo: appendDstIPv6}
+	}
+	fields := []field{
+		srcIP,
+		dstIP,
+		// sourceTransportPort
+		{id: 7, length: 2, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return binary.BigEndian.AppendUint16(b, r.Id.SrcPort)
+		}},
+		// destinationTransportPort
+		{id: 11, length: 2, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return binary.BigEndian.AppendUint16(b, r.Id.DstPort)
+		}},
+		// protocolIdentifier
+		{id: 4, length: 1, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return append(b, r.Id.TransportProtocol)
+		}},
+		// tcpControlBits
+		{id: 6, length: 2, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return binary.BigEndian.AppendUint16(b, tcpControlBits(r.Metrics.Flags))
+		}},
+		// octetDeltaCount
+		{id: 1, length: 8, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return binary.BigEndian.AppendUint64(b, r.Metrics.Bytes)
+		}},
+		// packetDeltaCount
+		{id: 2, length: 8, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return binary.BigEndian.AppendUint64(b, uint64(r.Metrics.Packets))
+		}},
+		// flowStartMilliseconds
+		{id: 152, length: 8, appendTo: func(b []byte, r *ebpf.Record, clk *clock) []byte {
+			return binary.BigEndian.AppendUint64(b, clk.unixMillis(r.Metrics.StartMonoTimeNs))
+		}},
+		// flowEndMilliseconds
+		{id: 153, length: 8, appendTo: func(b []byte, r *ebpf.Record, clk *clock) []byte {
+			return binary.BigEndian.AppendUint64(b, clk.unixMillis(r.Metrics.EndMonoTimeNs))
+		}},
+		// ingressInterface. It is the interface where the flow was captured, for both directions
+		{id: 10, length: 4, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return binary.BigEndian.AppendUint32(b, r.Id.IfIndex)
+		}},
+		// interfaceName
+		{id: 82, length: variableLength, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return appendString(b, r.Attrs.Interface)
+		}},
+	}
+	if withDirection {
+		// flowDirection. ebpf.DirectionIngress and ebpf.DirectionEgress have the same values
+		fields = append(fields, field{id: 61, length: 1, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte {
+			return append(b, r.Metrics.IfaceDirection)
+		}})
+	}
+	return fields
+}
+
+// enterpriseFields are the enterprise-specific information elements, which carry the
+// decoration of the flows. Their IDs must not change, as collectors are configured with them.
+func enterpriseFields() []field {
+	fields := []field{
+		{id: 1, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte { return appendString(b, r.Attrs.SrcName) }},
+		{id: 2, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte { return appendString(b, r.Attrs.DstName) }},
+		{id: 3, appendTo: metadataField(attr.K8sClusterName)},
+		{id: 4, appendTo: metadataField(attr.K8sSrcNamespace)},
+		{id: 5, appendTo: metadataField(attr.K8sSrcName)},
+		{id: 6, appendTo: metadataField(attr.K8sSrcType)},
+		{id: 7, appendTo: metadataField(attr.K8sSrcOwnerName)},
+		{id: 8, appendTo: metadataField(attr.K8sSrcOwnerType)},
+		{id: 9, appendTo: metadataField(attr.K8sDstNamespace)},
+		{id: 10, appendTo: metadataField(attr.K8sDstName)},
+		{id: 11, appendTo: metadataField(attr.K8sDstType)},
+		{id: 12, appendTo: metadataField(attr.K8sDstOwnerName)},
+		{id: 13, appendTo: metadataField(attr.K8sDstOwnerType)},
+		{id: 14, appendTo: metadataField(attr.SrcCIDR)},
+		{id: 15, appendTo: metadataField(attr.DstCIDR)},
+		{id: 16, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte { return appendString(b, r.Attrs.SrcZone) }},
+		{id: 17, appendTo: func(b []byte, r *ebpf.Record, _ *clock) []byte { return appendString(b, r.Attrs.DstZone) }},
+	}
+	for i := range fields {
+		fields[i].enterprise = true
+		fields[i].length = variableLength
+	}
+	return fields
+}
+
+func metadataField(name attr.Name) func(b []byte, r *ebpf.Record, _ *clock) []byte {
+	return func(b []byte, r *ebpf.Record, _ *clock) []byte {
+		return appendString(b, r.Attrs.Metadata[name])
+	}
+}
+
+type template struct {
+	id     uint16
+	fields []field
+}
+
+// clock converts the monotonic timestamps of the flows to wall-clock time
+type clock struct {
+	wall time.Time
+	// monotonic time at the moment of the wall time
+	mono time.Duration
+}
+
+func (c *clock) unixMillis(monoNs uint64) uint64 {
+	if monoNs == 0 {
+		return 0
+	}
+	return uint64(c.wall.Add(time.Duration(monoNs) - c.mono).UnixMilli())
+}
+
+// encoder creates IPFIX messages from the flow records
+type encoder struct {
+	domainID     uint32
+	enterpriseID uint32
+	maxSize      int
+	// sequence is the number of data records that have been sent in the current transport session
+	sequence  uint32
+	templates []template
+}
+
+func newEncoder(cfg *Config) *encoder {
+	enterprise := enterpriseFields()
+	return &encoder{
+		domainID:     cfg.ObservationDomainID,
+		enterpriseID: cfg.EnterpriseID,
+		maxSize:      cfg.MaxMessageSize,
+		// the order must match the indices returned by templateIndex
+		templates: []template{
+			{id: templateIDv4, fields: append(ianaFields(false, true), enterprise...)},
+			{id: templateIDv6, fields: append(ianaFields(true, true), enterprise...)},
+			{id: templateIDv4NoDirection, fields: append(ianaFields(false, false), enterprise...)},
+			{id: templateIDv6NoDirection, fields: append(ianaFields(true, false), enterprise...)},
+		},
+	}
+}
+
+// encode the flow records into IPFIX messages whose size doesn't exceed the configured
+// maximum, unless a single record does not fit. If withTemplates is true, the first message
+// starts with the template set, and the data records go to the next message if they don't fit
+// after it.
+func (e *encoder) encode(records []*ebpf.Record, clk *clock, withTemplates bool) [][]byte {
+	var messages [][]byte
+	var msg []byte
+	var msgRecords uint32
+	setStart, setTemplate := -1, uint16(0)
+
+	closeSet := func() {
+		if setStart >= 0 {
+			binary.BigEndian.PutUint16(msg[setStart+2:], uint16(len(msg)-setStart))
+			setStart = -1
+		}
+	}
+	closeMessage := func() {
+		closeSet()
+		binary.BigEndian.PutUint16(msg[2:], uint16(len(msg)))
+		messages = append(messages, msg)
+		e.sequence += msgRecords
+		msg, msgRecords = nil, 0
+	}
+	openMessage := func() {
+		msg = e.appendMessageHeader(make([]byte, 0, e.maxSize), clk.wall)
+	}
+
+	openMessage()
+	if withTemplates {
+		msg = e.appendTemplateSet(msg)
+	}
+	var dataRecord []byte
+	for _, r := range records {
+		// TCP connection lifecycle and packet drop records have no IPFIX representation
+		if r.IsConnection() || r.IsDrop() {
+			continue
+		}
+		tmpl := &e.templates[templateIndex(r)]
+		dataRecord = dataRecord[:0]
+		for i := range tmpl.fields {
+			dataRecord = tmpl.fields[i].appendTo(dataRecord, r, clk)
+		}
+		needed := len(dataRecord)
+		if setStart < 0 || setTemplate != tmpl.id {
+			needed += setHeaderLen
+		}
+		if len(msg) > messageHeaderLen && len(msg)+needed > e.maxSize {
+			closeMessage()
+			openMessage()
+		}
+		if setStart < 0 || setTemplate != tmpl.id {
+			closeSet()
+			setStart, setTemplate = len(msg), tmpl.id
+			msg = binary.BigEndian.AppendUint16(msg, tmpl.id)
+			// length is set when the set is closed
+			msg = append(msg, 0, 0)
+		}
+		msg = append(msg, dataRecord...)
+		msgRecords++
+	}
+	if msgRecords > 0 || withTemplates {
+		closeMessage()
+	}
+	return messages
+}
+
+// appendMessageHeader appends the header of the message. The length is set after
+// adding all the sets.
+func (e *encoder) appendMessageHeader(b []byte, now time.Time) []byte {
+	b = binary.BigEndian.AppendUint16(b, ipfixVersion)
+	b = append(b, 0, 0)
+	b = binary.BigEndian.AppendUint32(b, uint32(now.Unix()))
+	b = binary.BigEndian.AppendUint32(b, e.sequence)
+	return binary.BigEndian.AppendUint32(b, e.domainID)
+}
+
+func (e *encoder) appendTemplateSet(b []byte) []byte {
+	start := len(b)
+	b = binary.BigEndian.AppendUint16(b, templateSetID)
+	b = append(b, 0, 0)
+	for i := range e.templates {
+		t := &e.templates[i]
+		b = binary.BigEndian.AppendUint16(b, t.id)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(t.fields)))
+		for j := range t.fields {
+			f := &t.fields[j]
+			if f.enterprise {
+				b = binary.BigEndian.AppendUint16(b, f.id|enterpriseBit)
+				b = binary.BigEndian.AppendUint16(b, f.length)
+				b = binary.BigEndian.AppendUint32(b, e.enterpriseID)
+			} else {
+				b = binary.BigEndian.AppendUint16(b, f.id)
+				b = binary.BigEndian.AppendUint16(b, f.length)
+			}
+		}
+	}
+	binary.BigEndian.PutUint16(b[start+2:], uint16(len(b)-start))
+	return b
+}
+
+// templateIndex returns the template of a record according to its IP version and whether
+// its direction is known, as flowDirection only accepts the ingress (0) and egress (1) values
+func templateIndex(r *ebpf.Record) int {
+	idx := 0
+	if !isIPv4(r) {
+		idx++
+	}
+	if r.Metrics.IfaceDirection != ebpf.DirectionIngress && r.Metrics.IfaceDirection != ebpf.DirectionEgress {
+		idx += 2
+	}
+	return idx
+}
+
+func isIPv4(r *ebpf.Record) bool {
+	return r.Id.SrcIP().IP().To4() != nil && r.Id.DstIP().IP().To4() != nil
+}
+
+func appendSrcIPv4(b []byte, r *ebpf.Record, _ *clock) []byte {
+	return append(b, r.Id.SrcIP()[net.IPv6len-net.IPv4len:]...)
+}
+
+func appendDstIPv4(b []byte, r *ebpf.Record, _ *clock) []byte {
+	return append(b, r.Id.DstIP()[net.IPv6len-net.IPv4len:]...)
+}
+
+func appendSrcIPv6(b []byte, r *ebpf.Record, _ *clock) []byte {
+	return append(b, r.Id.SrcIP()[:]...)
+}
+
+func appendDstIPv6(b []byte, r *ebpf.Record, _ *clock) []byte {
+	return append(b, r.Id.DstIP()[:]...)
+}
+
+// appendString encodes a variable-length string according to RFC 7011, section 7
+func appendString(b []byte, s string) []byte {
+	if len(s) > maxStringLen {
+		s = s[:maxStringLen]
+	}
+	if len(s) < 255 {
+		b = append(b, uint8(len(s)))
+	} else {
+		b = append(b, 255)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	}
+	return append(b, s...)
+}
+
+// tcpControlBits converts the custom flags of the flow records to the standard TCP flags
+func tcpControlBits(flags uint16) uint16 {
+	bits := flags & 0xff
+	if flags&customSynAck != 0 {
+		bits |= tcpACK | 0x02
+	}
+	if flags&customFinAck != 0 {
+		bits |= tcpACK | tcpFIN
+	}
+	if flags&customRstAck != 0 {
+		bits |= tcpACK | tcpRST
+	}
+	return bits
+}
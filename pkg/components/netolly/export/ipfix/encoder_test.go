@@ -0,0 +1,262 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipfix
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+)
+
+// Private Enterprise Number reserved for documentation use (RFC 5612)
+const testEnterpriseID = 32473
+
+func TestEncode_TemplatesAndRecords(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.EnterpriseID = testEnterpriseID
+	cfg.ObservationDomainID = 33
+	enc := newEncoder(&cfg)
+	clk := &clock{wall: time.Unix(1_700_000_000, 0), mono: 10 * time.Second}
+
+	v4 := flow("10.0.0.1", "10.0.0.2", 1234, 80)
+	v4.Metrics.StartMonoTimeNs = uint64(9 * time.Second)
+	v4.Metrics.EndMonoTimeNs = uint64(9*time.Second + 500*time.Millisecond)
+	v4.Metrics.Flags = 0x02 | customFinAck
+	v4.Attrs.Metadata = map[attr.Name]string{
+		attr.K8sSrcNamespace: "default",
+		attr.K8sSrcOwnerName: "frontend",
+		attr.DstCIDR:         "10.0.0.0/8",
+	}
+	v6 := flow("2001:db8::1", "2001:db8::2", 4321, 443)
+
+	messages := enc.encode([]*ebpf.Record{v4, v6}, clk, true)
+	require.Len(t, messages, 1)
+	m := decode(t, messages[0])
+	assert.EqualValues(t, 0, m.sequence)
+	assert.EqualValues(t, 33, m.domainID)
+	assert.EqualValues(t, 1_700_000_000, m.exportTime)
+
+	require.Contains(t, m.templates, uint16(templateIDv4))
+	require.Contains(t, m.templates, uint16(templateIDv6))
+	enterpriseIEs := 0
+	for _, ie := range m.templates[templateIDv4] {
+		if ie.enterprise != 0 {
+			enterpriseIEs++
+			assert.EqualValues(t, testEnterpriseID, ie.enterprise)
+		}
+	}
+	assert.Equal(t, 17, enterpriseIEs)
+
+	require.Len(t, m.records[templateIDv4], 1)
+	rec := m.records[templateIDv4][0]
+	assert.Equal(t, net.ParseIP("10.0.0.1").To4(), net.IP(rec[ie{id: 8}]))
+	assert.Equal(t, net.ParseIP("10.0.0.2").To4(), net.IP(rec[ie{id: 12}]))
+	assert.EqualValues(t, 1234, binary.BigEndian.Uint16(rec[ie{id: 7}]))
+	assert.EqualValues(t, 80, binary.BigEndian.Uint16(rec[ie{id: 11}]))
+	assert.EqualValues(t, 6, rec[ie{id: 4}][0])
+	assert.EqualValues(t, 0x02|tcpFIN|tcpACK, binary.BigEndian.Uint16(rec[ie{id: 6}]))
+	assert.EqualValues(t, 1_699_999_999_000, binary.BigEndian.Uint64(rec[ie{id: 152}]))
+	assert.EqualValues(t, 1_699_999_999_500, binary.BigEndian.Uint64(rec[ie{id: 153}]))
+	assert.EqualValues(t, 123, binary.BigEndian.Uint64(rec[ie{id: 1}]))
+	assert.EqualValues(t, 3, binary.BigEndian.Uint64(rec[ie{id: 2}]))
+	assert.Equal(t, "eth0", string(rec[ie{id: 82}]))
+	assert.Equal(t, []byte{ebpf.DirectionIngress}, rec[ie{id: 61}])
+	pen := uint32(testEnterpriseID)
+	assert.Equal(t, "src-name", string(rec[ie{id: 1, enterprise: pen}]))
+	assert.Equal(t, "default", string(rec[ie{id: 4, enterprise: pen}]))
+	assert.Equal(t, "frontend", string(rec[ie{id: 7, enterprise: pen}]))
+	assert.Equal(t, "10.0.0.0/8", string(rec[ie{id: 15, enterprise: pen}]))
+	assert.Empty(t, rec[ie{id: 9, enterprise: pen}])
+
+	require.Len(t, m.records[templateIDv6], 1)
+	rec = m.records[templateIDv6][0]
+	assert.Equal(t, net.ParseIP("2001:db8::1"), net.IP(rec[ie{id: 27}]))
+	assert.Equal(t, net.ParseIP("2001:db8::2"), net.IP(rec[ie{id: 28}]))
+
+	// records with unknown direction are sent without flowDirection
+	noDir := flow("10.0.0.3", "10.0.0.4", 1234, 80)
+	noDir.Metrics.IfaceDirection = ebpf.DirectionUnset
+	m = decodeWith(t, enc.encode([]*ebpf.Record{noDir}, clk, false)[0], enc.templates)
+	require.Len(t, m.records[templateIDv4NoDirection], 1)
+	assert.NotContains(t, m.records[templateIDv4NoDirection][0], ie{id: 61})
+
+	// the sequence number accounts the previously sent data records
+	messages = enc.encode([]*ebpf.Record{v4}, clk, false)
+	require.Len(t, messages, 1)
+	assert.EqualValues(t, 3, decodeWith(t, messages[0], enc.templates).sequence)
+}
+
+func TestEncode_SplitMessages(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.EnterpriseID = testEnterpriseID
+	cfg.MaxMessageSize = minMessageSize
+	enc := newEncoder(&cfg)
+	clk := &clock{wall: time.Now(), mono: time.Second}
+
+	var records []*ebpf.Record
+	for i := 0; i < 50; i++ {
+		r := flow("10.0.0.1", "10.0.0.2", uint16(i), 80)
+		r.Attrs.DstName = strings.Repeat("a", 100)
+		records = append(records, r)
+	}
+	messages := enc.encode(records, clk, false)
+	require.Greater(t, len(messages), 1)
+
+	total := 0
+	for _, msg := range messages {
+		assert.LessOrEqual(t, len(msg), minMessageSize)
+		// data sets can't be decoded without the templates
+		m := decodeWith(t, msg, enc.templates)
+		assert.EqualValues(t, total, m.sequence)
+		total += len(m.records[templateIDv4])
+	}
+	assert.Equal(t, len(records), total)
+}
+
+func TestEncode_TemplatesFitMinMessageSize(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.EnterpriseID = testEnterpriseID
+	cfg.MaxMessageSize = minMessageSize
+	enc := newEncoder(&cfg)
+	clk := &clock{wall: time.Now(), mono: time.Second}
+
+	var records []*ebpf.Record
+	for i := 0; i < 10; i++ {
+		r := flow("10.0.0.1", "10.0.0.2", uint16(i), 80)
+		r.Attrs.DstName = strings.Repeat("a", 100)
+		records = append(records, r)
+	}
+	messages := enc.encode(records, clk, true)
+	require.Greater(t, len(messages), 1)
+
+	m := decode(t, messages[0])
+	assert.Len(t, m.templates, 4)
+	total := len(m.records[templateIDv4])
+	for i, msg := range messages {
+		assert.LessOrEqual(t, len(msg), minMessageSize)
+		if i > 0 {
+			total += len(decodeWith(t, msg, enc.templates).records[templateIDv4])
+		}
+	}
+	assert.Equal(t, len(records), total)
+}
+
+func TestAppendString(t *testing.T) {
+	assert.Equal(t, []byte{3, 'a', 'b', 'c'}, appendString(nil, "abc"))
+	long := appendString(nil, strings.Repeat("x", 300))
+	assert.Equal(t, []byte{255, 1, 44}, long[:3])
+	assert.Len(t, long, 303)
+	assert.Len(t, appendString(nil, strings.Repeat("x", 5000)), 3+maxStringLen)
+}
+
+func flow(src, dst string, srcPort, dstPort uint16) *ebpf.Record {
+	r := &ebpf.Record{}
+	copy(r.Id.SrcIp.In6U.U6Addr8[:], net.ParseIP(src).To16())
+	copy(r.Id.DstIp.In6U.U6Addr8[:], net.ParseIP(dst).To16())
+	r.Id.SrcPort = srcPort
+	r.Id.DstPort = dstPort
+	r.Id.TransportProtocol = 6
+	r.Metrics.Bytes = 123
+	r.Metrics.Packets = 3
+	r.Attrs.Interface = "eth0"
+	r.Attrs.SrcName = "src-name"
+	return r
+}
+
+type ie struct {
+	id         uint16
+	enterprise uint32
+}
+
+type templateIE struct {
+	ie
+	length uint16
+}
+
+type message struct {
+	exportTime uint32
+	sequence   uint32
+	domainID   uint32
+	templates  map[uint16][]templateIE
+	records    map[uint16][]map[ie][]byte
+}
+
+func decode(t *testing.T, b []byte) *message {
+	return decodeWith(t, b, nil)
+}
+
+// decodeWith decodes an IPFIX message, using the previously known templates
+// when the message does not include them
+func decodeWith(t *testing.T, b []byte, known []template) *message {
+	t.Helper()
+	require.GreaterOrEqual(t, len(b), messageHeaderLen)
+	require.EqualValues(t, ipfixVersion, binary.BigEndian.Uint16(b))
+	require.EqualValues(t, len(b), binary.BigEndian.Uint16(b[2:]))
+	m := &message{
+		exportTime: binary.BigEndian.Uint32(b[4:]),
+		sequence:   binary.BigEndian.Uint32(b[8:]),
+		domainID:   binary.BigEndian.Uint32(b[12:]),
+		templates:  map[uint16][]templateIE{},
+		records:    map[uint16][]map[ie][]byte{},
+	}
+	for _, tmpl := range known {
+		for _, f := range tmpl.fields {
+			fie := templateIE{ie: ie{id: f.id}, length: f.length}
+			if f.enterprise {
+				fie.enterprise = testEnterpriseID
+			}
+			m.templates[tmpl.id] = append(m.templates[tmpl.id], fie)
+		}
+	}
+	b = b[messageHeaderLen:]
+	for len(b) > 0 {
+		require.GreaterOrEqual(t, len(b), setHeaderLen)
+		setID, setLen := binary.BigEndian.Uint16(b), int(binary.BigEndian.Uint16(b[2:]))
+		require.LessOrEqual(t, setLen, len(b))
+		set := b[setHeaderLen:setLen]
+		b = b[setLen:]
+		if setID == templateSetID {
+			for len(set) > 0 {
+				id, count := binary.BigEndian.Uint16(set), int(binary.BigEndian.Uint16(set[2:]))
+				set = set[4:]
+				for i := 0; i < count; i++ {
+					fie := templateIE{ie: ie{id: binary.BigEndian.Uint16(set)}, length: binary.BigEndian.Uint16(set[2:])}
+					set = set[4:]
+					if fie.id&enterpriseBit != 0 {
+						fie.id &^= enterpriseBit
+						fie.enterprise = binary.BigEndian.Uint32(set)
+						set = set[4:]
+					}
+					m.templates[id] = append(m.templates[id], fie)
+				}
+			}
+			continue
+		}
+		fields, ok := m.templates[setID]
+		require.Truef(t, ok, "unknown template %d", setID)
+		for len(set) > 0 {
+			rec := map[ie][]byte{}
+			for _, f := range fields {
+				l := int(f.length)
+				if f.length == variableLength {
+					l, set = int(set[0]), set[1:]
+					if l == 255 {
+						l, set = int(binary.BigEndian.Uint16(set)), set[2:]
+					}
+				}
+				rec[f.ie], set = set[:l], set[l:]
+			}
+			m.records[setID] = append(m.records[setID], rec)
+		}
+	}
+	return m
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipfix
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/gavv/monotime"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+const (
+	dialTimeout  = 5 * time.Second
+	writeTimeout = 5 * time.Second
+
+	// sendQueueLen is the number of flow batches that are buffered while the previous batches are
+	// being submitted. Newer batches are dropped when the buffer is full, so a slow or unreachable
+	// collector does not block the other exporters that read the same flows queue.
+	sendQueueLen = 16
+
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = time.Minute
+)
+
+func elog() *slog.Logger {
+	return slog.With("component", "ipfix.Exporter")
+}
+
+// ExporterProvider returns a terminal node that submits the flow records to an IPFIX collector.
+// Flow records are dropped while the collector is not reachable or can't keep up with them.
+func ExporterProvider(cfg *Config, input *msg.Queue[[]*ebpf.Record]) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled() {
+			return swarm.EmptyRunFunc()
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, fmt.Errorf("instantiating IPFIX exporter: %w", err)
+		}
+		e := &exporter{
+			log: elog().With("endpoint", cfg.Endpoint, "transport", cfg.Transport),
+			cfg: cfg,
+			enc: newEncoder(cfg),
+			in:  input.Subscribe(),
+		}
+		return e.run, nil
+	}
+}
+
+type exporter struct {
+	log  *slog.Logger
+	cfg  *Config
+	enc  *encoder
+	in   <-chan []*ebpf.Record
+	conn net.Conn
+	// lastTemplates is the time when the templates were sent for the last time
+	lastTemplates time.Time
+	// backoff and nextDial delay the reconnections while the collector is not reachable
+	backoff  time.Duration
+	nextDial time.Time
+}
+
+func (e *exporter) run(ctx context.Context) {
+	e.log.Debug("starting IPFIX exporter")
+	pending := make(chan []*ebpf.Record, sendQueueLen)
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		e.send(ctx, pending)
+	}()
+	e.forward(ctx, pending)
+	close(pending)
+	<-sent
+}
+
+// forward the flows from the input to the pending batches, without blocking the input
+// when the sender does not keep up with them
+func (e *exporter) forward(ctx context.Context, pending chan<- []*ebpf.Record) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case flows, ok := <-e.in:
+			if !ok {
+				return
+			}
+			select {
+			case pending <- flows:
+			default:
+				e.log.Debug("IPFIX exporter is not keeping up with the flows. Dropping them", "flows", len(flows))
+			}
+		}
+	}
+}
+
+func (e *exporter) send(ctx context.Context, pending <-chan []*ebpf.Record) {
+	defer e.closeConn()
+	for flows := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		e.export(ctx, flows)
+	}
+}
+
+func (e *exporter) export(ctx context.Context, flows []*ebpf.Record) {
+	if e.conn == nil {
+		if time.Now().Before(e.nextDial) {
+			e.log.Debug("waiting to reconnect to the IPFIX collector. Dropping flows", "flows", len(flows))
+			return
+		}
+		if err := e.dial(ctx); err != nil {
+			e.backoff = min(max(2*e.backoff, minReconnectBackoff), maxReconnectBackoff)
+			e.nextDial = time.Now().Add(e.backoff)
+			e.log.Debug("can't connect to the IPFIX collector. Dropping flows",
+				"error", err, "flows", len(flows), "retryIn", e.backoff)
+			return
+		}
+		e.backoff = 0
+	}
+	clk := &clock{wall: time.Now(), mono: monotime.Now()}
+	// over TCP, the templates are only sent once per connection
+	withTemplates := e.lastTemplates.IsZero() ||
+		(e.cfg.Transport == TransportUDP && clk.wall.Sub(e.lastTemplates) >= e.cfg.TemplateRefresh)
+	if withTemplates {
+		e.lastTemplates = clk.wall
+	}
+	for _, message := range e.enc.encode(flows, clk, withTemplates) {
+		err := e.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err == nil {
+			_, err = e.conn.Write(message)
+		}
+		if err != nil {
+			e.log.Debug("error submitting IPFIX message. Reconnecting", "error", err)
+			e.closeConn()
+			return
+		}
+	}
+}
+
+func (e *exporter) dial(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, e.cfg.Transport, e.cfg.Endpoint)
+	if err != nil {
+		return err
+	}
+	e.conn = conn
+	// templates must be sent again to the new connection, and the sequence number
+	// restarts with each transport session
+	e.lastTemplates = time.Time{}
+	e.enc.sequence = 0
+	return nil
+}
+
+func (e *exporter) closeConn() {
+	if e.conn != nil {
+		_ = e.conn.Close()
+		e.conn = nil
+	}
+}
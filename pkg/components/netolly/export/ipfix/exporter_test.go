@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipfix
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+const timeout = 5 * time.Second
+
+func TestExporter_UDP(t *testing.T) {
+	collector, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer collector.Close()
+
+	cfg := DefaultConfig
+	cfg.EnterpriseID = testEnterpriseID
+	cfg.Endpoint = collector.LocalAddr().String()
+	input := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	run, err := ExporterProvider(&cfg, input)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+
+	input.Send([]*ebpf.Record{flow("10.0.0.1", "10.0.0.2", 1234, 80)})
+	input.Send([]*ebpf.Record{flow("10.0.0.3", "10.0.0.4", 1234, 80)})
+
+	buf := make([]byte, maxMessageSize)
+	require.NoError(t, collector.SetReadDeadline(time.Now().Add(timeout)))
+	n, _, err := collector.ReadFrom(buf)
+	require.NoError(t, err)
+	m := decode(t, buf[:n])
+	assert.Len(t, m.templates, 4)
+	require.Len(t, m.records[templateIDv4], 1)
+	assert.Equal(t, net.ParseIP("10.0.0.1").To4(), net.IP(m.records[templateIDv4][0][ie{id: 8}]))
+
+	// templates are not resent until the refresh period expires
+	n, _, err = collector.ReadFrom(buf)
+	require.NoError(t, err)
+	m = decodeWith(t, buf[:n], newEncoder(&cfg).templates)
+	assert.EqualValues(t, 1, m.sequence)
+	require.Len(t, m.records[templateIDv4], 1)
+	assert.Equal(t, net.ParseIP("10.0.0.3").To4(), net.IP(m.records[templateIDv4][0][ie{id: 8}]))
+}
+
+func TestExporter_TCPReconnect(t *testing.T) {
+	collector, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer collector.Close()
+
+	cfg := DefaultConfig
+	cfg.EnterpriseID = testEnterpriseID
+	cfg.Transport = TransportTCP
+	cfg.Endpoint = collector.Addr().String()
+	input := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	run, err := ExporterProvider(&cfg, input)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+
+	input.Send([]*ebpf.Record{flow("10.0.0.1", "10.0.0.2", 1234, 80)})
+	conn := accept(t, collector)
+	m := decode(t, readMessage(t, conn))
+	assert.Len(t, m.templates, 4)
+	assert.Len(t, m.records[templateIDv4], 1)
+
+	// after the collector closes the connection, the exporter connects again
+	// and resends the templates
+	require.NoError(t, conn.Close())
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				input.Send([]*ebpf.Record{flow("10.0.0.1", "10.0.0.2", 1234, 80)})
+				time.Sleep(50 * time.Millisecond)
+			}
+		}
+	}()
+	reconnected := accept(t, collector)
+	close(done)
+	defer reconnected.Close()
+	m = decode(t, readMessage(t, reconnected))
+	assert.Len(t, m.templates, 4)
+	// the sequence number restarts with the new transport session
+	assert.EqualValues(t, 0, m.sequence)
+}
+
+func TestExporter_DropsWhenBusy(t *testing.T) {
+	in := make(chan []*ebpf.Record, 10)
+	e := &exporter{log: elog(), in: in}
+	for i := 0; i < 5; i++ {
+		in <- []*ebpf.Record{flow("10.0.0.1", "10.0.0.2", uint16(i), 80)}
+	}
+	close(in)
+
+	// the input is not blocked when nobody is consuming the pending batches
+	pending := make(chan []*ebpf.Record, 2)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.forward(t.Context(), pending)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		require.Fail(t, "forward blocked while the sender was busy")
+	}
+	require.Len(t, pending, 2)
+	assert.EqualValues(t, 0, (<-pending)[0].Id.SrcPort)
+	assert.EqualValues(t, 1, (<-pending)[0].Id.SrcPort)
+}
+
+func accept(t *testing.T, l net.Listener) net.Conn {
+	t.Helper()
+	require.NoError(t, l.(*net.TCPListener).SetDeadline(time.Now().Add(timeout)))
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	return conn
+}
+
+func readMessage(t *testing.T, conn net.Conn) []byte {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(timeout)))
+	header := make([]byte, messageHeaderLen)
+	_, err := io.ReadFull(conn, header)
+	require.NoError(t, err)
+	body := make([]byte, int(binary.BigEndian.Uint16(header[2:]))-messageHeaderLen)
+	_, err = io.ReadFull(conn, body)
+	require.NoError(t, err)
+	return append(header, body...)
+}
@@ -25,6 +25,14 @@ func FlowPrinterProvider(enabled bool, input *msg.Queue[[]*ebpf.Record]) swarm.R
 	return func(_ context.Context) {
 		for flows := range in {
 			for _, flow := range flows {
+				if flow.IsConnection() {
+					printConnection(flow)
+					continue
+				}
+				if flow.IsDrop() {
+					printDrop(flow)
+					continue
+				}
 				printFlow(flow)
 			}
 		}
@@ -55,6 +63,10 @@ func printFlow(f *ebpf.Record) {
 	sb.WriteString(strconv.FormatUint(uint64(f.Id.SrcPort), 10))
 	sb.WriteString(" dst.port=")
 	sb.WriteString(strconv.FormatUint(uint64(f.Id.DstPort), 10))
+	if f.Attrs.L7Protocol != "" {
+		sb.WriteString(" l7.protocol=")
+		sb.WriteString(f.Attrs.L7Protocol)
+	}
 
 	for k, v := range f.Attrs.Metadata {
 		sb.WriteString(" ")
@@ -65,3 +77,73 @@ func printFlow(f *ebpf.Record) {
 
 	fmt.Println("network_flow:", sb.String())
 }
+
+func printConnection(c *ebpf.Record) {
+	sb := strings.Builder{}
+	sb.WriteString("src.address=")
+	sb.WriteString(c.Id.SrcIP().IP().String())
+	sb.WriteString(" dst.address=")
+	sb.WriteString(c.Id.DstIP().IP().String())
+	sb.WriteString(" src.name=")
+	sb.WriteString(c.Attrs.SrcName)
+	sb.WriteString(" dst.name=")
+	sb.WriteString(c.Attrs.DstName)
+	sb.WriteString(" src.port=")
+	sb.WriteString(strconv.FormatUint(uint64(c.Id.SrcPort), 10))
+	sb.WriteString(" dst.port=")
+	sb.WriteString(strconv.FormatUint(uint64(c.Id.DstPort), 10))
+	sb.WriteString(" opened=")
+	sb.WriteString(strconv.FormatUint(uint64(c.Conn.Opened), 10))
+	sb.WriteString(" failed=")
+	sb.WriteString(strconv.FormatUint(uint64(c.Conn.Failed), 10))
+	if c.Conn.Failed > 0 {
+		sb.WriteString(" error.type=")
+		sb.WriteString(c.Conn.FailureReason())
+	}
+	sb.WriteString(" closed=")
+	sb.WriteString(strconv.FormatUint(uint64(c.Conn.Closed), 10))
+	sb.WriteString(" duration=")
+	sb.WriteString(c.Conn.AvgDuration().String())
+
+	for k, v := range c.Attrs.Metadata {
+		sb.WriteString(" ")
+		sb.WriteString(string(k))
+		sb.WriteString("=")
+		sb.WriteString(v)
+	}
+
+	fmt.Println("network_connection:", sb.String())
+}
+
+func printDrop(d *ebpf.Record) {
+	sb := strings.Builder{}
+	sb.WriteString("src.address=")
+	sb.WriteString(d.Id.SrcIP().IP().String())
+	sb.WriteString(" dst.address=")
+	sb.WriteString(d.Id.DstIP().IP().String())
+	sb.WriteString(" src.name=")
+	sb.WriteString(d.Attrs.SrcName)
+	sb.WriteString(" dst.name=")
+	sb.WriteString(d.Attrs.DstName)
+	sb.WriteString(" src.port=")
+	sb.WriteString(strconv.FormatUint(uint64(d.Id.SrcPort), 10))
+	sb.WriteString(" dst.port=")
+	sb.WriteString(strconv.FormatUint(uint64(d.Id.DstPort), 10))
+	sb.WriteString(" iface=")
+	sb.WriteString(d.Attrs.Interface)
+	sb.WriteString(" drop.reason=")
+	sb.WriteString(d.Drop.Reason)
+	sb.WriteString(" packets=")
+	sb.WriteString(strconv.FormatUint(uint64(d.Drop.Packets), 10))
+	sb.WriteString(" bytes=")
+	sb.WriteString(strconv.FormatUint(d.Drop.Bytes, 10))
+
+	for k, v := range d.Attrs.Metadata {
+		sb.WriteString(" ")
+		sb.WriteString(string(k))
+		sb.WriteString("=")
+		sb.WriteString(v)
+	}
+
+	fmt.Println("network_drop:", sb.String())
+}
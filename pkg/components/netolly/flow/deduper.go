@@ -97,6 +97,11 @@ func DeduperProvider(dd *Deduper, input, output *msg.Queue[[]*ebpf.Record]) swar
 				cache.removeExpired()
 				fwd := make([]*ebpf.Record, 0, len(records))
 				for _, record := range records {
+					// connection and drop records are not captured per interface, so they can't be duplicate
+					if record.IsConnection() || record.IsDrop() {
+						fwd = append(fwd, record)
+						continue
+					}
 					if cache.isDupe(&record.Id) {
 						continue
 					}
@@ -130,6 +130,25 @@ func TestDedupe_EvictFlows(t *testing.T) {
 		testutil.ReadChannel(t, output, timeout))
 }
 
+func TestDedupe_ForwardsConnections(t *testing.T) {
+	input := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(100))
+	outputQueue := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(100))
+	output := outputQueue.Subscribe()
+	dedupe, err := DeduperProvider(&Deduper{Type: DeduperFirstCome, FCTTL: time.Minute}, input, outputQueue)(t.Context())
+	require.NoError(t, err)
+	go dedupe(t.Context())
+
+	// connection records have no interface, so they are never considered duplicates
+	key := ebpf.NetFlowId{EthProtocol: 1, SrcPort: 789, DstPort: 80, IfIndex: 1}
+	flowIf1 := &ebpf.Record{NetFlowRecordT: ebpf.NetFlowRecordT{Id: key}}
+	key.IfIndex = 2
+	flowIf2 := &ebpf.Record{NetFlowRecordT: ebpf.NetFlowRecordT{Id: key}}
+	conn := ebpf.NewConnRecord(key, ebpf.NetConnMetrics{EndMonoTimeNs: 1, Opened: 1})
+	input.Send([]*ebpf.Record{clone(flowIf1), conn, clone(flowIf2), conn})
+	assert.Equal(t, []*ebpf.Record{unset(flowIf1), conn, conn},
+		testutil.ReadChannel(t, output, timeout))
+}
+
 type timerMock struct {
 	// avoids data races in tests
 	sync.RWMutex
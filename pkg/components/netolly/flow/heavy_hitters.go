@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+// the Space-Saving summary monitors more pairs than the reported top-K, to improve
+// the accuracy of the estimated top talkers
+const heavyHittersCapacityFactor = 10
+
+func hhlog() *slog.Logger {
+	return slog.With("component", "flow.HeavyHitters")
+}
+
+// HeavyHitters bounds the cardinality of the per-address network metrics. It keeps the
+// source and destination addresses, names and ports only for the flows between the top-K
+// pairs of endpoints that exchanged more bytes, and folds the rest into an "other" bucket.
+// The TCP connection lifecycle and packet drop records are folded according to the same
+// top-K pairs.
+type HeavyHitters struct {
+	// TopK is the number of source/destination address pairs whose flows are reported with
+	// their exact addresses during each interval. Zero (default) disables the heavy hitters detection.
+	TopK int `yaml:"top_k" env:"OTEL_EBPF_NETWORK_HEAVY_HITTERS_TOP_K"`
+
+	// Interval for the calculation of the top talkers. The top talkers of an interval are
+	// applied to the flows of the next interval. During the first interval, the top talkers
+	// are calculated from the flows observed so far. Default: 1m
+	Interval time.Duration `yaml:"interval" env:"OTEL_EBPF_NETWORK_HEAVY_HITTERS_INTERVAL"`
+}
+
+func (h HeavyHitters) Enabled() bool {
+	return h.TopK > 0
+}
+
+func HeavyHittersProvider(cfg *HeavyHitters, input, output *msg.Queue[[]*ebpf.Record]) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled() {
+			return swarm.Bypass(input, output)
+		}
+		hh := newHeavyHitters(cfg)
+		in := input.Subscribe()
+		return func(_ context.Context) {
+			defer output.Close()
+			for flows := range in {
+				hh.fold(flows)
+				output.Send(flows)
+			}
+		}, nil
+	}
+}
+
+// pairKey identifies the pair of endpoints of a flow, regardless of its direction
+type pairKey struct {
+	low, high ebpf.IPAddr
+}
+
+func pairKeyOf(id *ebpf.NetFlowId) pairKey {
+	src, dst := id.SrcIp.In6U.U6Addr8, id.DstIp.In6U.U6Addr8
+	if bytes.Compare(src[:], dst[:]) > 0 {
+		src, dst = dst, src
+	}
+	return pairKey{low: src, high: dst}
+}
+
+type heavyHitters struct {
+	log           *slog.Logger
+	topK          int
+	interval      time.Duration
+	intervalStart time.Time
+	summary       *spaceSaving
+	// top talkers from the previous interval. Nil during the first interval
+	top map[pairKey]struct{}
+}
+
+func newHeavyHitters(cfg *HeavyHitters) *heavyHitters {
+	return &heavyHitters{
+		log:           hhlog(),
+		topK:          cfg.TopK,
+		interval:      cfg.Interval,
+		intervalStart: timeNow(),
+		summary:       newSpaceSaving(cfg.TopK * heavyHittersCapacityFactor),
+	}
+}
+
+func (h *heavyHitters) topSet() map[pairKey]struct{} {
+	top := map[pairKey]struct{}{}
+	for _, key := range h.summary.top(h.topK) {
+		top[key] = struct{}{}
+	}
+	return top
+}
+
+func (h *heavyHitters) fold(flows []*ebpf.Record) {
+	if now := timeNow(); now.Sub(h.intervalStart) >= h.interval {
+		h.top = h.topSet()
+		h.summary = newSpaceSaving(h.topK * heavyHittersCapacityFactor)
+		h.intervalStart = now
+		h.log.Debug("top talkers updated", "len", len(h.top))
+	}
+	for _, flow := range flows {
+		// connection lifecycle and drop records don't carry the bytes of the flows
+		if flow.IsConnection() || flow.IsDrop() {
+			continue
+		}
+		h.summary.add(pairKeyOf(&flow.Id), flow.Metrics.Bytes)
+	}
+	top := h.top
+	if top == nil {
+		top = h.topSet()
+	}
+	// connection lifecycle and drop records are also folded, as their metrics report the
+	// same addresses and ports than the flows
+	for _, flow := range flows {
+		if _, ok := top[pairKeyOf(&flow.Id)]; ok {
+			continue
+		}
+		flow.Attrs.Folded = true
+		// names that have been decorated from the Kubernetes metadata or the reverse DNS
+		// don't have unbounded cardinality, so only the names that would be later
+		// decorated with the IP addresses are folded
+		if flow.Attrs.SrcName == "" {
+			flow.Attrs.SrcName = ebpf.FoldedValue
+		}
+		if flow.Attrs.DstName == "" {
+			flow.Attrs.DstName = ebpf.FoldedValue
+		}
+	}
+}
+
+// spaceSaving implements the Space-Saving algorithm to find the most frequent items
+// of a stream with bounded memory: https://doi.org/10.1007/978-3-540-30570-5_27
+// When the summary is full, a new item replaces the monitored item with the lowest count,
+// inheriting its count as an overestimation error.
+type spaceSaving struct {
+	capacity int
+	counters counterHeap
+	index    map[pairKey]*ssCounter
+}
+
+type ssCounter struct {
+	key   pairKey
+	count uint64
+	// position in the heap
+	pos int
+}
+
+func newSpaceSaving(capacity int) *spaceSaving {
+	return &spaceSaving{
+		capacity: capacity,
+		counters: make(counterHeap, 0, capacity),
+		index:    make(map[pairKey]*ssCounter, capacity),
+	}
+}
+
+func (s *spaceSaving) add(key pairKey, weight uint64) {
+	if c, ok := s.index[key]; ok {
+		c.count += weight
+		heap.Fix(&s.counters, c.pos)
+		return
+	}
+	if len(s.counters) < s.capacity {
+		c := &ssCounter{key: key, count: weight}
+		s.index[key] = c
+		heap.Push(&s.counters, c)
+		return
+	}
+	// replace the least frequent item
+	minimum := s.counters[0]
+	delete(s.index, minimum.key)
+	minimum.key = key
+	minimum.count += weight
+	s.index[key] = minimum
+	heap.Fix(&s.counters, minimum.pos)
+}
+
+// top returns the k most frequent items, sorted by descending count
+func (s *spaceSaving) top(k int) []pairKey {
+	sorted := slices.Clone(s.counters)
+	slices.SortFunc(sorted, func(a, b *ssCounter) int {
+		switch {
+		case a.count > b.count:
+			return -1
+		case a.count < b.count:
+			return 1
+		}
+		return 0
+	})
+	keys := make([]pairKey, 0, min(k, len(sorted)))
+	for i := 0; i < k && i < len(sorted); i++ {
+		keys = append(keys, sorted[i].key)
+	}
+	return keys
+}
+
+// counterHeap is a min-heap of counters, implementing heap.Interface
+type counterHeap []*ssCounter
+
+func (h counterHeap) Len() int           { return len(h) }
+func (h counterHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h counterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *counterHeap) Push(x any) {
+	c := x.(*ssCounter)
+	c.pos = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *counterHeap) Pop() any {
+	old := *h
+	n := len(old)
+	c := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return c
+}
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/components/testutil"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+func TestSpaceSaving(t *testing.T) {
+	key := func(i int) pairKey {
+		return pairKeyOf(&ebpf.NetFlowId{DstPort: uint16(i), SrcIp: ebpf.NetIn6Addr{In6U: struct{ U6Addr8 [16]uint8 }{
+			U6Addr8: [16]uint8{15: uint8(i)},
+		}}})
+	}
+	ss := newSpaceSaving(5)
+	// the three heavy hitters are interleaved with many small items
+	for i := 0; i < 100; i++ {
+		ss.add(key(1), 1000)
+		ss.add(key(2), 500)
+		ss.add(key(3), 200)
+		ss.add(key(10+i), 10)
+	}
+	assert.Equal(t, []pairKey{key(1), key(2), key(3)}, ss.top(3))
+	assert.Len(t, ss.index, 5)
+	assert.Len(t, ss.top(10), 5)
+}
+
+func TestHeavyHitters(t *testing.T) {
+	tm := &timerMock{now: time.Now()}
+	timeNow = tm.Now
+	t.Cleanup(func() { timeNow = time.Now })
+
+	input := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	outputQueue := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	output := outputQueue.Subscribe()
+	hh, err := HeavyHittersProvider(&HeavyHitters{TopK: 2, Interval: time.Minute}, input, outputQueue)(t.Context())
+	require.NoError(t, err)
+	go hh(t.Context())
+
+	flows := func() []*ebpf.Record {
+		var records []*ebpf.Record
+		for i, bytes := range []uint64{1000, 5000, 20, 3000} {
+			src := fmt.Sprintf("10.0.0.%d", i+1)
+			records = append(records, &ebpf.Record{
+				NetFlowRecordT: ebpf.NetFlowRecordT{
+					Id:      flowID(src, 34567, "10.0.1.1", 80, 1),
+					Metrics: ebpf.NetFlowMetrics{Bytes: bytes},
+				},
+			})
+		}
+		// response flow of the top talker
+		records = append(records, &ebpf.Record{NetFlowRecordT: ebpf.NetFlowRecordT{
+			Id:      flowID("10.0.1.1", 80, "10.0.0.2", 34567, 1),
+			Metrics: ebpf.NetFlowMetrics{Bytes: 10},
+		}})
+		// names from other decorators are kept
+		records[0].Attrs.DstName = "frontend"
+		return records
+	}
+
+	// during the first interval, the top talkers are calculated from the flows observed so far
+	input.Send(flows())
+	out := testutil.ReadChannel(t, output, timeout)
+	require.Len(t, out, 5)
+	assert.True(t, out[0].Attrs.Folded)
+	assert.Equal(t, ebpf.FoldedValue, out[0].Attrs.SrcName)
+	assert.Equal(t, "frontend", out[0].Attrs.DstName)
+	assert.False(t, out[1].Attrs.Folded)
+	assert.True(t, out[2].Attrs.Folded)
+	assert.Equal(t, ebpf.FoldedValue, out[2].Attrs.DstName)
+	assert.False(t, out[3].Attrs.Folded)
+	assert.False(t, out[4].Attrs.Folded)
+
+	// connection and drop records are not accounted, but they are folded if they
+	// don't belong to the top talkers
+	conns := []*ebpf.Record{
+		ebpf.NewConnRecord(flowID("10.0.0.9", 34567, "10.0.1.1", 80, 1), ebpf.NetConnMetrics{}),
+		ebpf.NewDropRecord(ebpf.DropKey{Id: flowID("10.0.0.8", 34567, "10.0.1.1", 80, 1)}, ebpf.NetDropMetrics{}),
+		ebpf.NewConnRecord(flowID("10.0.1.1", 80, "10.0.0.2", 34567, 1), ebpf.NetConnMetrics{}),
+		ebpf.NewDropRecord(ebpf.DropKey{Id: flowID("10.0.0.4", 34567, "10.0.1.1", 80, 1)}, ebpf.NetDropMetrics{}),
+	}
+	input.Send(conns)
+	out = testutil.ReadChannel(t, output, timeout)
+	require.Len(t, out, 4)
+	assert.True(t, out[0].Attrs.Folded)
+	assert.True(t, out[1].Attrs.Folded)
+	assert.False(t, out[2].Attrs.Folded)
+	assert.False(t, out[3].Attrs.Folded)
+
+	// the top talkers of the previous interval are applied to the next one
+	tm.Add(time.Minute)
+	input.Send(flows())
+	out = testutil.ReadChannel(t, output, timeout)
+	require.Len(t, out, 5)
+	assert.True(t, out[0].Attrs.Folded)
+	assert.False(t, out[1].Attrs.Folded)
+	assert.Empty(t, out[1].Attrs.SrcName)
+	assert.True(t, out[2].Attrs.Folded)
+	assert.False(t, out[3].Attrs.Folded)
+	assert.False(t, out[4].Attrs.Folded)
+
+	srcAddr, _ := ebpf.RecordStringGetters("src.address")
+	srcPort, _ := ebpf.RecordStringGetters("src.port")
+	assert.Equal(t, "other", srcAddr(out[0]))
+	assert.Equal(t, "0", srcPort(out[0]))
+	assert.Equal(t, "10.0.0.2", srcAddr(out[1]))
+	assert.Equal(t, "34567", srcPort(out[1]))
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	ebpfcommon "go.opentelemetry.io/obi/pkg/components/ebpf/common"
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+func l7log() *slog.Logger {
+	return slog.With("component", "flow.L7Classifier")
+}
+
+type payloadFetcher interface {
+	LookupAndDeletePayloadMap() map[ebpf.NetFlowId][]byte
+}
+
+// endpoint of a connection, used to build the connection key
+type endpoint struct {
+	ip   ebpf.IPAddr
+	port uint16
+}
+
+// connKey identifies a connection regardless of the direction and the interface of the flow,
+// so the request and response flows of the same connection share the same L7 protocol
+type connKey struct {
+	low, high endpoint
+	transport uint8
+}
+
+func connKeyOf(id *ebpf.NetFlowId) connKey {
+	src := endpoint{ip: id.SrcIp.In6U.U6Addr8, port: id.SrcPort}
+	dst := endpoint{ip: id.DstIp.In6U.U6Addr8, port: id.DstPort}
+	if cmp := bytes.Compare(src.ip[:], dst.ip[:]); cmp > 0 || (cmp == 0 && src.port > dst.port) {
+		src, dst = dst, src
+	}
+	return connKey{low: src, high: dst, transport: id.TransportProtocol}
+}
+
+// L7Classifier decorates the flows with the application-layer protocol. It classifies
+// the payload samples that the eBPF programs capture for each flow, and remembers the
+// protocol of each connection, as the payload is only sampled until it is classified.
+type L7Classifier struct {
+	fetcher   payloadFetcher
+	protocols *simplelru.LRU[connKey, string]
+}
+
+// L7ClassifierProvider returns a pipeline node that sets the L7Protocol attribute of the flows.
+// If the classification is not enabled, the node is bypassed.
+func L7ClassifierProvider(
+	enabled bool, fetcher payloadFetcher, cacheSize int,
+	input, output *msg.Queue[[]*ebpf.Record],
+) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !enabled {
+			return swarm.Bypass(input, output)
+		}
+		classifier, err := newL7Classifier(fetcher, cacheSize)
+		if err != nil {
+			return nil, err
+		}
+		in := input.Subscribe()
+		return func(_ context.Context) {
+			defer output.Close()
+			for flows := range in {
+				classifier.classify(flows)
+				output.Send(flows)
+			}
+		}, nil
+	}
+}
+
+func newL7Classifier(fetcher payloadFetcher, cacheSize int) (*L7Classifier, error) {
+	protocols, err := simplelru.NewLRU[connKey, string](cacheSize, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &L7Classifier{fetcher: fetcher, protocols: protocols}, nil
+}
+
+func (c *L7Classifier) classify(flows []*ebpf.Record) {
+	// the payloads map is read after each batch of flows, since the payload of a flow
+	// is sampled at the same time that the flow is created in the flows map
+	for id, payload := range c.fetcher.LookupAndDeletePayloadMap() {
+		key := connKeyOf(&id)
+		// once the protocol of a connection is known, it's only refined from HTTP2 to gRPC,
+		// as gRPC is only distinguishable when a headers frame is sampled
+		if proto, ok := c.protocols.Get(key); ok && proto != ebpfcommon.L7ProtocolHTTP2 {
+			continue
+		}
+		if proto := ebpfcommon.ClassifyPayload(payload); proto != "" {
+			c.protocols.Add(key, proto)
+		}
+	}
+	for _, flow := range flows {
+		if proto, ok := c.protocols.Get(connKeyOf(&flow.Id)); ok {
+			flow.Attrs.L7Protocol = proto
+		}
+	}
+	l7log().Debug("flows classified", "len", len(flows), "connections", c.protocols.Len())
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/components/testutil"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+type fakePayloadFetcher struct {
+	payloads chan map[ebpf.NetFlowId][]byte
+}
+
+func (f *fakePayloadFetcher) LookupAndDeletePayloadMap() map[ebpf.NetFlowId][]byte {
+	select {
+	case p := <-f.payloads:
+		return p
+	default:
+		return nil
+	}
+}
+
+func flowID(src string, srcPort uint16, dst string, dstPort uint16, ifIndex uint32) ebpf.NetFlowId {
+	id := ebpf.NetFlowId{SrcPort: srcPort, DstPort: dstPort, TransportProtocol: 6, IfIndex: ifIndex}
+	copy(id.SrcIp.In6U.U6Addr8[:], net.ParseIP(src).To16())
+	copy(id.DstIp.In6U.U6Addr8[:], net.ParseIP(dst).To16())
+	return id
+}
+
+func TestL7Classifier(t *testing.T) {
+	fetcher := &fakePayloadFetcher{payloads: make(chan map[ebpf.NetFlowId][]byte, 10)}
+	input := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	outputQueue := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	output := outputQueue.Subscribe()
+	classifier, err := L7ClassifierProvider(true, fetcher, 100, input, outputQueue)(t.Context())
+	require.NoError(t, err)
+	go classifier(t.Context())
+
+	httpReq := flowID("10.0.0.1", 34567, "10.0.0.2", 80, 1)
+	httpResp := flowID("10.0.0.2", 80, "10.0.0.1", 34567, 2)
+	h2Req := flowID("10.0.0.1", 45678, "10.0.0.3", 8080, 1)
+	other := flowID("10.0.0.1", 56789, "10.0.0.4", 443, 1)
+
+	fetcher.payloads <- map[ebpf.NetFlowId][]byte{
+		httpReq: []byte("GET / HTTP/1.1\r\n"),
+		h2Req:   []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"),
+		other:   {0x16, 0x03, 0x01},
+	}
+	input.Send([]*ebpf.Record{
+		{NetFlowRecordT: ebpf.NetFlowRecordT{Id: httpReq}},
+		// the response flow has the same protocol as the request
+		{NetFlowRecordT: ebpf.NetFlowRecordT{Id: httpResp}},
+		{NetFlowRecordT: ebpf.NetFlowRecordT{Id: h2Req}},
+		{NetFlowRecordT: ebpf.NetFlowRecordT{Id: other}},
+	})
+	flows := testutil.ReadChannel(t, output, timeout)
+	require.Len(t, flows, 4)
+	assert.Equal(t, "http", flows[0].Attrs.L7Protocol)
+	assert.Equal(t, "http", flows[1].Attrs.L7Protocol)
+	assert.Equal(t, "http2", flows[2].Attrs.L7Protocol)
+	assert.Empty(t, flows[3].Attrs.L7Protocol)
+
+	// later flows from the same connections keep the protocol even if they aren't sampled anymore,
+	// and HTTP2 connections are refined to gRPC if a gRPC payload is sampled
+	fetcher.payloads <- map[ebpf.NetFlowId][]byte{
+		httpReq: []byte("*1\r\n$4\r\nPING\r\n"),
+		h2Req:   grpcHeaders(t),
+	}
+	input.Send([]*ebpf.Record{
+		{NetFlowRecordT: ebpf.NetFlowRecordT{Id: httpResp}},
+		{NetFlowRecordT: ebpf.NetFlowRecordT{Id: h2Req}},
+	})
+	flows = testutil.ReadChannel(t, output, timeout)
+	require.Len(t, flows, 2)
+	assert.Equal(t, "http", flows[0].Attrs.L7Protocol)
+	assert.Equal(t, "grpc", flows[1].Attrs.L7Protocol)
+}
+
+// HEADERS frame with the ":method: POST" and "content-type: application/grpc"
+// literal header fields, without indexing
+func grpcHeaders(t *testing.T) []byte {
+	t.Helper()
+	block := []byte{0x83} // :method: POST, from the static table
+	block = append(block, 0x0f, 0x10, 16)
+	block = append(block, "application/grpc"...)
+	frame := []byte{0, 0, byte(len(block)), 0x01, 0x04, 0, 0, 0, 1}
+	return append(frame, block...)
+}
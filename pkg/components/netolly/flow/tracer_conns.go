@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gavv/monotime"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+func ctlog() *slog.Logger {
+	return slog.With("component", "flow.ConnTracer")
+}
+
+// ConnTracer periodically reads the TCP connection lifecycle metrics from the eBPF map,
+// and forwards them as connection records through the flows pipeline, so they are decorated
+// as any other flow.
+type ConnTracer struct {
+	connFetcher     connFetcher
+	evictionTimeout time.Duration
+	lastEvictionNs  uint64
+}
+
+type connFetcher interface {
+	LookupAndDeleteConnMap() map[ebpf.NetFlowId][]ebpf.NetConnMetrics
+}
+
+func NewConnTracer(fetcher connFetcher, evictionTimeout time.Duration) *ConnTracer {
+	return &ConnTracer{
+		connFetcher:     fetcher,
+		evictionTimeout: evictionTimeout,
+		lastEvictionNs:  uint64(monotime.Now()),
+	}
+}
+
+func (c *ConnTracer) TraceLoop(out *msg.Queue[[]*ebpf.Record]) swarm.RunFunc {
+	return func(ctx context.Context) {
+		defer out.MarkCloseable()
+		log := ctlog()
+		ticker := time.NewTicker(c.evictionTimeout)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("exiting trace loop due to context cancellation")
+				return
+			case <-ticker.C:
+				if records := c.evict(); len(records) > 0 {
+					out.Send(records)
+				}
+			}
+		}
+	}
+}
+
+func (c *ConnTracer) evict() []*ebpf.Record {
+	var records []*ebpf.Record
+	laterEventNs := c.lastEvictionNs
+	for connKey, connMetrics := range c.connFetcher.LookupAndDeleteConnMap() {
+		aggr := ebpf.NetConnMetrics{}
+		for i := range connMetrics {
+			// as for the flows, PerCPU hashmap values are not zeroed when the entry
+			// is removed, so old values from previous evictions are discarded
+			if connMetrics[i].EndMonoTimeNs <= c.lastEvictionNs {
+				continue
+			}
+			aggr.Accumulate(&connMetrics[i])
+		}
+		if aggr.EndMonoTimeNs == 0 {
+			continue
+		}
+		if aggr.EndMonoTimeNs > laterEventNs {
+			laterEventNs = aggr.EndMonoTimeNs
+		}
+		records = append(records, ebpf.NewConnRecord(connKey, aggr))
+	}
+	c.lastEvictionNs = laterEventNs
+	ctlog().Debug("connections evicted", "len", len(records))
+	return records
+}
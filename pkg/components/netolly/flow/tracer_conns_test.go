@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+)
+
+type fakeConnFetcher struct {
+	conns map[ebpf.NetFlowId][]ebpf.NetConnMetrics
+}
+
+func (f *fakeConnFetcher) LookupAndDeleteConnMap() map[ebpf.NetFlowId][]ebpf.NetConnMetrics {
+	conns := f.conns
+	f.conns = nil
+	return conns
+}
+
+func TestConnTracer_Evict(t *testing.T) {
+	opened := ebpf.NetFlowId{SrcPort: 1234, DstPort: 80, TransportProtocol: 6, IfIndex: 3}
+	stale := ebpf.NetFlowId{SrcPort: 4321, DstPort: 80, TransportProtocol: 6}
+	fetcher := &fakeConnFetcher{conns: map[ebpf.NetFlowId][]ebpf.NetConnMetrics{
+		opened: {
+			{StartMonoTimeNs: 150, EndMonoTimeNs: 200, Opened: 1, Initiator: 1},
+			// leftover from a previous eviction in another CPU
+			{StartMonoTimeNs: 10, EndMonoTimeNs: 50, Closed: 7, DurationNs: 1000},
+			{StartMonoTimeNs: 120, EndMonoTimeNs: 300, Closed: 2, DurationNs: 80},
+		},
+		stale: {{StartMonoTimeNs: 10, EndMonoTimeNs: 90, Failed: 1}},
+	}}
+	tracer := &ConnTracer{connFetcher: fetcher, lastEvictionNs: 100}
+
+	records := tracer.evict()
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.True(t, r.IsConnection())
+	assert.EqualValues(t, ebpf.InterfaceUnset, r.Id.IfIndex)
+	assert.EqualValues(t, 120, r.Metrics.StartMonoTimeNs)
+	assert.EqualValues(t, 300, r.Metrics.EndMonoTimeNs)
+	assert.EqualValues(t, 1, r.Conn.Opened)
+	assert.EqualValues(t, 2, r.Conn.Closed)
+	assert.EqualValues(t, 80, r.Conn.DurationNs)
+	assert.EqualValues(t, 300, tracer.lastEvictionNs)
+
+	// nothing new to evict
+	assert.Empty(t, tracer.evict())
+	assert.EqualValues(t, 300, tracer.lastEvictionNs)
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gavv/monotime"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+func dtlog() *slog.Logger {
+	return slog.With("component", "flow.DropTracer")
+}
+
+// DropTracer periodically reads the packets dropped by the kernel from the eBPF map,
+// and forwards them as drop records through the flows pipeline, so they are decorated
+// as any other flow.
+type DropTracer struct {
+	dropFetcher     dropFetcher
+	evictionTimeout time.Duration
+	lastEvictionNs  uint64
+}
+
+type dropFetcher interface {
+	LookupAndDeleteDropMap() map[ebpf.DropKey][]ebpf.NetDropMetrics
+}
+
+func NewDropTracer(fetcher dropFetcher, evictionTimeout time.Duration) *DropTracer {
+	return &DropTracer{
+		dropFetcher:     fetcher,
+		evictionTimeout: evictionTimeout,
+		lastEvictionNs:  uint64(monotime.Now()),
+	}
+}
+
+func (d *DropTracer) TraceLoop(out *msg.Queue[[]*ebpf.Record]) swarm.RunFunc {
+	return func(ctx context.Context) {
+		defer out.MarkCloseable()
+		log := dtlog()
+		ticker := time.NewTicker(d.evictionTimeout)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug("exiting trace loop due to context cancellation")
+				return
+			case <-ticker.C:
+				if records := d.evict(); len(records) > 0 {
+					out.Send(records)
+				}
+			}
+		}
+	}
+}
+
+func (d *DropTracer) evict() []*ebpf.Record {
+	var records []*ebpf.Record
+	laterEventNs := d.lastEvictionNs
+	for dropKey, dropMetrics := range d.dropFetcher.LookupAndDeleteDropMap() {
+		aggr := ebpf.NetDropMetrics{}
+		for i := range dropMetrics {
+			// as for the flows, PerCPU hashmap values are not zeroed when the entry
+			// is removed, so old values from previous evictions are discarded
+			if dropMetrics[i].EndMonoTimeNs <= d.lastEvictionNs {
+				continue
+			}
+			aggr.Accumulate(&dropMetrics[i])
+		}
+		if aggr.EndMonoTimeNs == 0 {
+			continue
+		}
+		if aggr.EndMonoTimeNs > laterEventNs {
+			laterEventNs = aggr.EndMonoTimeNs
+		}
+		records = append(records, ebpf.NewDropRecord(dropKey, aggr))
+	}
+	d.lastEvictionNs = laterEventNs
+	dtlog().Debug("drops evicted", "len", len(records))
+	return records
+}
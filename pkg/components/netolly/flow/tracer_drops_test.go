@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+)
+
+type fakeDropFetcher struct {
+	drops map[ebpf.DropKey][]ebpf.NetDropMetrics
+}
+
+func (f *fakeDropFetcher) LookupAndDeleteDropMap() map[ebpf.DropKey][]ebpf.NetDropMetrics {
+	drops := f.drops
+	f.drops = nil
+	return drops
+}
+
+func TestDropTracer_Evict(t *testing.T) {
+	dropped := ebpf.DropKey{Id: ebpf.NetFlowId{SrcPort: 1234, DstPort: 80, TransportProtocol: 6, IfIndex: 3}, Reason: "no_socket"}
+	stale := ebpf.DropKey{Id: ebpf.NetFlowId{SrcPort: 4321, DstPort: 80, TransportProtocol: 6}, Reason: "tcp_csum"}
+	fetcher := &fakeDropFetcher{drops: map[ebpf.DropKey][]ebpf.NetDropMetrics{
+		dropped: {
+			{StartMonoTimeNs: 150, EndMonoTimeNs: 200, Packets: 1, Bytes: 60},
+			// leftover from a previous eviction in another CPU
+			{StartMonoTimeNs: 10, EndMonoTimeNs: 50, Packets: 7, Bytes: 1000},
+			{StartMonoTimeNs: 120, EndMonoTimeNs: 300, Packets: 2, Bytes: 120},
+		},
+		stale: {{StartMonoTimeNs: 10, EndMonoTimeNs: 90, Packets: 1}},
+	}}
+	tracer := &DropTracer{dropFetcher: fetcher, lastEvictionNs: 100}
+
+	records := tracer.evict()
+	require.Len(t, records, 1)
+	r := records[0]
+	assert.True(t, r.IsDrop())
+	assert.EqualValues(t, 3, r.Id.IfIndex)
+	assert.EqualValues(t, 120, r.Metrics.StartMonoTimeNs)
+	assert.EqualValues(t, 300, r.Metrics.EndMonoTimeNs)
+	assert.EqualValues(t, 3, r.Drop.Packets)
+	assert.EqualValues(t, 180, r.Drop.Bytes)
+	assert.Equal(t, "no_socket", r.Drop.Reason)
+	assert.EqualValues(t, 300, tracer.lastEvictionNs)
+
+	// nothing new to evict
+	assert.Empty(t, tracer.evict())
+	assert.EqualValues(t, 300, tracer.lastEvictionNs)
+}
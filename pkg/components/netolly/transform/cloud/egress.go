@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+
+	"github.com/yl2chen/cidranger"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+// Egress types, as reported in the egress.type attribute
+const (
+	EgressCrossZone   = "cross_zone"
+	EgressCrossRegion = "cross_region"
+	EgressInternet    = "internet"
+)
+
+func clog() *slog.Logger {
+	return slog.With("component", "cloud.EgressDecorator")
+}
+
+// EgressCost configures the classification of the network traffic according to its
+// billing as cloud egress traffic
+type EgressCost struct {
+	// RangesFile is the path to a YAML file with the address ranges of the cloud providers,
+	// used to locate the zone and region of the addresses out of the Kubernetes cluster.
+	// Each entry must specify a "cidr", and optionally the "provider", "region" and "zone"
+	// of the addresses in that range. Addresses that are public and don't belong to any
+	// of the ranges are considered Internet traffic.
+	RangesFile string `yaml:"ranges_file" env:"OTEL_EBPF_NETWORK_EGRESS_COST_RANGES_FILE"`
+
+	// PricePerGB of each type of egress traffic, used to calculate the egress cost metric.
+	// Prices default to zero, as they depend on the provider and the contract. The egress cost
+	// metric is only reported when at least one of the prices is set.
+	PricePerGB Prices `yaml:"price_per_gb"`
+}
+
+// Prices per transferred GB, in any currency unit
+type Prices struct {
+	CrossZone   float64 `yaml:"cross_zone" env:"OTEL_EBPF_NETWORK_EGRESS_COST_CROSS_ZONE_PRICE"`
+	CrossRegion float64 `yaml:"cross_region" env:"OTEL_EBPF_NETWORK_EGRESS_COST_CROSS_REGION_PRICE"`
+	Internet    float64 `yaml:"internet" env:"OTEL_EBPF_NETWORK_EGRESS_COST_INTERNET_PRICE"`
+}
+
+// Configured returns whether any of the egress prices has been set
+func (p *Prices) Configured() bool {
+	return p.CrossZone > 0 || p.CrossRegion > 0 || p.Internet > 0
+}
+
+func (p *Prices) forType(egressType string) float64 {
+	switch egressType {
+	case EgressCrossZone:
+		return p.CrossZone
+	case EgressCrossRegion:
+		return p.CrossRegion
+	case EgressInternet:
+		return p.Internet
+	}
+	return 0
+}
+
+// Range of addresses of a cloud provider, as defined in the ranges file
+type Range struct {
+	CIDR     string `yaml:"cidr"`
+	Provider string `yaml:"provider"`
+	Region   string `yaml:"region"`
+	Zone     string `yaml:"zone"`
+}
+
+type rangerEntry struct {
+	ipNet net.IPNet
+	Range
+}
+
+func (r *rangerEntry) Network() net.IPNet {
+	return r.ipNet
+}
+
+// EgressDecoratorProvider returns a pipeline node that sets the egress type and price of each flow.
+// It must be placed after the Kubernetes decorator, which sets the zones and regions of the
+// cluster endpoints. If the egress cost metrics aren't enabled, the node is bypassed.
+func EgressDecoratorProvider(enabled bool, cfg *EgressCost, input, output *msg.Queue[[]*ebpf.Record]) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !enabled {
+			return swarm.Bypass(input, output)
+		}
+		ed, err := newEgressDecorator(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("instantiating cloud egress decorator: %w", err)
+		}
+		in := input.Subscribe()
+		return func(_ context.Context) {
+			defer output.Close()
+			clog().Debug("starting node")
+			for flows := range in {
+				for _, flow := range flows {
+					ed.decorate(flow)
+				}
+				output.Send(flows)
+			}
+			clog().Debug("stopping node")
+		}, nil
+	}
+}
+
+type egressDecorator struct {
+	prices Prices
+	ranger cidranger.Ranger
+}
+
+func newEgressDecorator(cfg *EgressCost) (*egressDecorator, error) {
+	ed := &egressDecorator{prices: cfg.PricePerGB, ranger: cidranger.NewPCTrieRanger()}
+	if cfg.RangesFile == "" {
+		return ed, nil
+	}
+	content, err := os.ReadFile(cfg.RangesFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading cloud ranges file: %w", err)
+	}
+	var ranges []Range
+	if err := yaml.Unmarshal(content, &ranges); err != nil {
+		return nil, fmt.Errorf("parsing cloud ranges file %s: %w", cfg.RangesFile, err)
+	}
+	for _, r := range ranges {
+		_, ipNet, err := net.ParseCIDR(r.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("parsing cloud range CIDR %s: %w", r.CIDR, err)
+		}
+		if err := ed.ranger.Insert(&rangerEntry{ipNet: *ipNet, Range: r}); err != nil {
+			return nil, fmt.Errorf("inserting cloud range CIDR %s: %w", r.CIDR, err)
+		}
+	}
+	clog().Debug("loaded cloud ranges", "file", cfg.RangesFile, "len", len(ranges))
+	return ed, nil
+}
+
+// lookup returns the narrowest cloud range containing the IP, or nil if there isn't any
+func (ed *egressDecorator) lookup(ip net.IP) *rangerEntry {
+	entries, _ := ed.ranger.ContainingNetworks(ip)
+	if len(entries) == 0 {
+		return nil
+	}
+	return entries[len(entries)-1].(*rangerEntry)
+}
+
+// locate returns the cloud zone and region of an address, taking the zone and region that
+// the Kubernetes decorator set from the node labels, or looking up the address in the cloud
+// ranges otherwise. It also returns whether the address is known to be in the cloud or in
+// a private network.
+func (ed *egressDecorator) locate(ip net.IP, zone, region string) (string, string, bool) {
+	if zone != "" || region != "" {
+		return zone, region, true
+	}
+	if r := ed.lookup(ip); r != nil {
+		return r.Zone, r.Region, true
+	}
+	return "", "", ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast()
+}
+
+func (ed *egressDecorator) decorate(flow *ebpf.Record) {
+	// connection lifecycle and dropped packets don't account transferred bytes
+	if flow.IsConnection() || flow.IsDrop() {
+		return
+	}
+	// the cloud location is stored apart from the zones and regions, as other
+	// metrics (e.g. inter-zone) must only account the cluster endpoints
+	var srcKnown, dstKnown bool
+	flow.Attrs.SrcCloudZone, flow.Attrs.SrcCloudRegion, srcKnown =
+		ed.locate(flow.Id.SrcIP().IP(), flow.Attrs.SrcZone, flow.Attrs.SrcRegion)
+	flow.Attrs.DstCloudZone, flow.Attrs.DstCloudRegion, dstKnown =
+		ed.locate(flow.Id.DstIP().IP(), flow.Attrs.DstZone, flow.Attrs.DstRegion)
+	flow.Attrs.EgressType = egressType(&flow.Attrs, srcKnown, dstKnown)
+	flow.Attrs.EgressPricePerGB = ed.prices.forType(flow.Attrs.EgressType)
+}
+
+// egressType returns the type of egress traffic from the source to the destination,
+// or an empty string if the traffic isn't billed as egress traffic.
+// Traffic coming from the Internet is considered ingress traffic.
+func egressType(attrs *ebpf.RecordAttrs, srcKnown, dstKnown bool) string {
+	switch {
+	case !srcKnown:
+		return ""
+	case !dstKnown:
+		return EgressInternet
+	case attrs.SrcCloudRegion != "" && attrs.DstCloudRegion != "" && attrs.SrcCloudRegion != attrs.DstCloudRegion:
+		return EgressCrossRegion
+	case attrs.SrcCloudZone != "" && attrs.DstCloudZone != "" && attrs.SrcCloudZone != attrs.DstCloudZone:
+		return EgressCrossZone
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cloud
+
+import (
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/components/testutil"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+const testTimeout = 5 * time.Second
+
+const testRanges = `
+- cidr: 3.5.0.0/16
+  provider: aws
+  region: us-east-1
+- cidr: 3.5.140.0/22
+  provider: aws
+  region: eu-west-1
+  zone: eu-west-1a
+- cidr: 2600:1f18::/32
+  provider: aws
+  region: us-east-1
+`
+
+func TestEgressDecorator(t *testing.T) {
+	rangesFile := path.Join(t.TempDir(), "ranges.yml")
+	require.NoError(t, os.WriteFile(rangesFile, []byte(testRanges), 0o644))
+
+	input := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	defer input.Close()
+	outputQu := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(10))
+	outCh := outputQu.Subscribe()
+	decorator, err := EgressDecoratorProvider(true, &EgressCost{
+		RangesFile: rangesFile,
+		PricePerGB: Prices{CrossZone: 0.01, CrossRegion: 0.02, Internet: 0.09},
+	}, input, outputQu)(t.Context())
+	require.NoError(t, err)
+	go decorator(t.Context())
+
+	// zones and regions of the cluster endpoints, as decorated from the Kubernetes node labels
+	crossZone := flow("10.0.0.1", "10.0.0.2", 1<<30)
+	crossZone.Attrs.SrcZone, crossZone.Attrs.SrcRegion = "us-east-1a", "us-east-1"
+	crossZone.Attrs.DstZone, crossZone.Attrs.DstRegion = "us-east-1b", "us-east-1"
+	sameZone := flow("10.0.0.1", "10.0.0.3", 1000)
+	sameZone.Attrs.SrcZone, sameZone.Attrs.SrcRegion = "us-east-1a", "us-east-1"
+	sameZone.Attrs.DstZone, sameZone.Attrs.DstRegion = "us-east-1a", "us-east-1"
+	crossRegion := flow("10.0.0.1", "3.5.141.1", 1000)
+	crossRegion.Attrs.SrcZone, crossRegion.Attrs.SrcRegion = "us-east-1a", "us-east-1"
+	sameRegion := flow("10.0.0.1", "3.5.1.1", 1000)
+	sameRegion.Attrs.SrcZone, sameRegion.Attrs.SrcRegion = "us-east-1a", "us-east-1"
+	internet := flow("10.0.0.1", "140.82.121.4", 2<<30)
+	ingress := flow("140.82.121.4", "10.0.0.1", 1000)
+	conn := ebpf.NewConnRecord(ebpf.NetFlowId{}, ebpf.NetConnMetrics{Opened: 1})
+
+	input.Send([]*ebpf.Record{crossZone, sameZone, crossRegion, sameRegion, internet, ingress, conn})
+	decorated := testutil.ReadChannel(t, outCh, testTimeout)
+	require.Len(t, decorated, 7)
+
+	assert.Equal(t, EgressCrossZone, crossZone.Attrs.EgressType)
+	assert.InDelta(t, 0.01, crossZone.EgressCost(), 1e-9)
+
+	assert.Empty(t, sameZone.Attrs.EgressType)
+	assert.Zero(t, sameZone.EgressCost())
+
+	assert.Equal(t, EgressCrossRegion, crossRegion.Attrs.EgressType)
+	assert.Equal(t, "us-east-1a", crossRegion.Attrs.SrcCloudZone)
+	assert.Equal(t, "us-east-1", crossRegion.Attrs.SrcCloudRegion)
+	assert.Equal(t, "eu-west-1", crossRegion.Attrs.DstCloudRegion)
+	assert.Equal(t, "eu-west-1a", crossRegion.Attrs.DstCloudZone)
+	// the zone and region fields are only set by the Kubernetes decorator
+	assert.Empty(t, crossRegion.Attrs.DstRegion)
+	assert.Empty(t, crossRegion.Attrs.DstZone)
+	assert.InDelta(t, 0.02, crossRegion.Attrs.EgressPricePerGB, 1e-9)
+
+	assert.Empty(t, sameRegion.Attrs.EgressType)
+	assert.Equal(t, "us-east-1", sameRegion.Attrs.DstCloudRegion)
+	assert.Empty(t, sameRegion.Attrs.DstRegion)
+
+	assert.Equal(t, EgressInternet, internet.Attrs.EgressType)
+	assert.InDelta(t, 0.18, internet.EgressCost(), 1e-9)
+
+	assert.Empty(t, ingress.Attrs.EgressType)
+	assert.Empty(t, conn.Attrs.EgressType)
+}
+
+func TestEgressDecorator_WrongRanges(t *testing.T) {
+	rangesFile := path.Join(t.TempDir(), "ranges.yml")
+	require.NoError(t, os.WriteFile(rangesFile, []byte("- cidr: 3.5.0.0/99"), 0o644))
+	_, err := EgressDecoratorProvider(true, &EgressCost{RangesFile: rangesFile},
+		msg.NewQueue[[]*ebpf.Record](), msg.NewQueue[[]*ebpf.Record]())(t.Context())
+	require.Error(t, err)
+}
+
+func flow(srcIP, dstIP string, bytes uint64) *ebpf.Record {
+	r := &ebpf.Record{}
+	r.Metrics.Bytes = bytes
+	copy(r.Id.SrcIp.In6U.U6Addr8[:], net.ParseIP(srcIP).To16())
+	copy(r.Id.DstIp.In6U.U6Addr8[:], net.ParseIP(dstIP).To16())
+	return r
+}
+
+func TestPricesConfigured(t *testing.T) {
+	assert.False(t, (&Prices{}).Configured())
+	assert.True(t, (&Prices{Internet: 0.05}).Configured())
+}
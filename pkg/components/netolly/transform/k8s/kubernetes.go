@@ -48,7 +48,8 @@ const (
 	attrSuffixHostIP    = ".node.ip"
 	attrSuffixHostName  = ".node.name"
 
-	cloudZoneLabel = "topology.kubernetes.io/zone"
+	cloudZoneLabel   = "topology.kubernetes.io/zone"
+	cloudRegionLabel = "topology.kubernetes.io/region"
 )
 
 const alreadyLoggedIPsCacheLen = 256
@@ -186,6 +187,13 @@ func (n *decorator) nodeLabels(flow *ebpf.Record, prefix string, meta *informer.
 				flow.Attrs.SrcZone = zone
 			}
 		}
+		if region, ok := nodeLabels[cloudRegionLabel]; ok {
+			if prefix == attrPrefixDst {
+				flow.Attrs.DstRegion = region
+			} else {
+				flow.Attrs.SrcRegion = region
+			}
+		}
 	}
 }
 
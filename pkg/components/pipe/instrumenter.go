@@ -15,8 +15,10 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 	"go.opentelemetry.io/obi/pkg/export/debug"
+	"go.opentelemetry.io/obi/pkg/export/fileexport"
 	"go.opentelemetry.io/obi/pkg/export/otel"
 	"go.opentelemetry.io/obi/pkg/export/prom"
+	"go.opentelemetry.io/obi/pkg/export/zipkin"
 	"go.opentelemetry.io/obi/pkg/filter"
 	"go.opentelemetry.io/obi/pkg/obi"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
@@ -79,8 +81,9 @@ func newGraphBuilder(
 	}), swarm.WithID("ReadFromChannel"))
 
 	routerToKubeDecorator := newQueue()
-	swi.Add(transform.RoutesProvider(
+	swi.Add(transform.ReloadableRoutesProvider(
 		config.Routes,
+		config.Reloads.RoutesUpdates(),
 		tracesReaderToRouter,
 		routerToKubeDecorator,
 	), swarm.WithID("Routes"))
@@ -102,7 +105,8 @@ func newGraphBuilder(
 	if exportableSpans == nil {
 		exportableSpans = newQueue()
 	}
-	swi.Add(filter.ByAttribute(config.Filters.Application, nil, selectorCfg.ExtraGroupAttributesCfg, spanPtrPromGetters,
+	swi.Add(filter.ReloadableByAttribute(config.Filters.Application, config.Reloads.AppFiltersUpdates(),
+		nil, selectorCfg.ExtraGroupAttributesCfg, spanPtrPromGetters,
 		nameResolverToAttrFilter, exportableSpans),
 		swarm.WithID("AttributesFilter"))
 
@@ -117,19 +121,33 @@ func newGraphBuilder(
 	swi.Add(otel.ReportSvcGraphMetrics(
 		ctxInfo,
 		&config.Metrics,
+		selectorCfg,
 		exportableSpans,
 		processEventsCh,
 	), swarm.WithID("OTELSvcGraphMetricsExport"))
 
+	// tail sampling only applies to the exported traces. Metrics are still
+	// calculated from all the spans
+	sampledSpans := exportableSpans
+	if config.TailSampling.Enabled {
+		sampledSpans = newQueue()
+		swi.Add(transform.TailSamplingProvider(&config.TailSampling, exportableSpans, sampledSpans),
+			swarm.WithID("TailSampling"))
+	}
+
 	swi.Add(otel.TracesReceiver(
-		ctxInfo, config.Traces, config.SpanMetricsEnabledForTraces(), selectorCfg, exportableSpans,
+		ctxInfo, config.Traces, config.SpanMetricsEnabledForTraces(), selectorCfg, sampledSpans,
 	), swarm.WithID("OTELTracesReceiver"))
+	swi.Add(zipkin.TracesReceiver(ctxInfo, &config.ZipkinTraces, selectorCfg, sampledSpans),
+		swarm.WithID("ZipkinTracesReceiver"))
 	swi.Add(prom.PrometheusEndpoint(ctxInfo, &config.Prometheus, selectorCfg, exportableSpans, processEventsCh),
 		swarm.WithID("PrometheusEndpoint"))
 	swi.Add(prom.BPFMetrics(ctxInfo, &config.Prometheus),
 		swarm.WithID("BPFMetrics"))
 	swi.Add(debug.PrinterNode(config.TracePrinter, exportableSpans),
 		swarm.WithID("PrinterNode"))
+	swi.Add(fileexport.SpanFileExporter(ctxInfo, &config.FileExport, &config.Traces, selectorCfg, exportableSpans),
+		swarm.WithID("SpanFileExporter"))
 
 	// The returned builder later invokes its "Build" function that, given
 	// the contents of the nodesMap struct, will instantiate
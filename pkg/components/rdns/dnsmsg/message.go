@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnsmsg
+
+import "strconv"
+
+const (
+	TypeA     = 1
+	TypeNS    = 2
+	TypeMD    = 3
+	TypeMF    = 4
+	TypeCNAME = 5
+	TypeSOA   = 6
+	TypeMB    = 7
+	TypeMG    = 8
+	TypeMR    = 9
+	TypeNULL  = 10
+	TypeWKS   = 11
+	TypePTR   = 12
+	TypeHINFO = 13
+	TypeMINFO = 14
+	TypeMX    = 15
+	TypeTXT   = 16
+	TypeAAAA  = 28
+	TypeSRV   = 33
+	TypeHTTPS = 65
+)
+
+const (
+	RCodeNoError  = 0
+	RCodeFormErr  = 1
+	RCodeServFail = 2
+	RCodeNXDomain = 3
+	RCodeNotImp   = 4
+	RCodeRefused  = 5
+)
+
+var typeNames = map[uint16]string{
+	TypeA:     "A",
+	TypeNS:    "NS",
+	TypeMD:    "MD",
+	TypeMF:    "MF",
+	TypeCNAME: "CNAME",
+	TypeSOA:   "SOA",
+	TypeMB:    "MB",
+	TypeMG:    "MG",
+	TypeMR:    "MR",
+	TypeNULL:  "NULL",
+	TypeWKS:   "WKS",
+	TypePTR:   "PTR",
+	TypeHINFO: "HINFO",
+	TypeMINFO: "MINFO",
+	TypeMX:    "MX",
+	TypeTXT:   "TXT",
+	TypeAAAA:  "AAAA",
+	TypeSRV:   "SRV",
+	TypeHTTPS: "HTTPS",
+}
+
+// TypeName returns the mnemonic of a resource record type, or TYPE<n>
+// for unknown types, as defined in RFC 3597
+func TypeName(t uint16) string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return "TYPE" + strconv.Itoa(int(t))
+}
+
+// Question is an entry of the question section of a DNS message
+type Question struct {
+	Name  string
+	Type  uint16
+	Class uint16
+}
+
+// Record is a resource record of the answer section of a DNS message
+type Record struct {
+	Name  string
+	Type  uint16
+	Class uint16
+	TTL   uint32
+	Data  []byte
+}
+
+// Message is a parsed DNS message. Only the header, question and answer
+// sections are parsed.
+type Message struct {
+	id      uint16
+	flagsHi uint8
+	flagsLo uint8
+
+	Questions []*Question
+
+	Answers []*Record
+}
+
+func getBit(word uint8, offset uint8) bool {
+	return ((word >> offset) & 0x1) == 1
+}
+
+func (d *Message) ID() uint16 {
+	return d.id
+}
+
+// IsQuery returns true when the QR bit is unset
+func (d *Message) IsQuery() bool {
+	return !d.IsResponse()
+}
+
+// IsResponse returns true when the QR bit is set
+func (d *Message) IsResponse() bool {
+	return getBit(d.flagsHi, 7)
+}
+
+func (d *Message) Opcode() uint8 {
+	return (d.flagsHi >> 3) & 0xf
+}
+
+func (d *Message) AuthoritativeAnswer() bool {
+	return getBit(d.flagsHi, 2)
+}
+
+func (d *Message) Truncation() bool {
+	return getBit(d.flagsHi, 1)
+}
+
+func (d *Message) RecursionDesired() bool {
+	return getBit(d.flagsHi, 0)
+}
+
+func (d *Message) RecursionAvailable() bool {
+	return getBit(d.flagsLo, 7)
+}
+
+func (d *Message) Z() uint8 {
+	return (d.flagsLo >> 4) & 0x7
+}
+
+func (d *Message) RCode() uint8 {
+	return d.flagsLo & 0xf
+}
@@ -1,8 +1,9 @@
 // Copyright The OpenTelemetry Authors
 // SPDX-License-Identifier: Apache-2.0
 
-// Package xdp provides DNS message parsing functionality for the XDP-based DNS response tracker.
-package xdp
+// Package dnsmsg provides DNS message parsing functionality, shared by the XDP-based
+// DNS response tracker and the DNS client lookup spans.
+package dnsmsg
 
 import (
 	"bytes"
@@ -28,17 +29,18 @@ func readDWord(b *bytes.Buffer) []byte {
 	return b.Next(2 * wordSize)
 }
 
-// parseDNSMessage parses a raw DNS message into a structured dnsMessage object.
+// Parse parses a raw DNS message into a structured Message object.
 // It handles the DNS message header, questions, and answer sections.
-// Returns nil if the message is malformed or incomplete.
-func parseDNSMessage(rawData []byte) *dnsMessage {
+// Returns nil if the message is malformed or has no questions. Messages
+// without answers (e.g. NXDOMAIN responses) are still returned.
+func Parse(rawData []byte) *Message {
 	data := bytes.NewBuffer(rawData)
 
 	if data.Len() < wordSize {
 		return nil
 	}
 
-	r := dnsMessage{}
+	r := Message{}
 
 	r.id = binary.BigEndian.Uint16(readWord(data))
 
@@ -59,17 +61,13 @@ func parseDNSMessage(rawData []byte) *dnsMessage {
 	arcount := binary.BigEndian.Uint16(readWord(data))
 	_, _ = nscount, arcount
 
-	r.questions = parseQSections(data, qdcount)
+	r.Questions = parseQSections(data, qdcount)
 
-	if len(r.questions) == 0 {
+	if len(r.Questions) == 0 {
 		return nil
 	}
 
-	r.answers = parseRecords(data, rawData, ancount)
-
-	if len(r.answers) == 0 {
-		return nil
-	}
+	r.Answers = parseRecords(data, rawData, ancount)
 
 	return &r
 }
@@ -77,8 +75,8 @@ func parseDNSMessage(rawData []byte) *dnsMessage {
 // parseQSections parses the question section of a DNS message.
 // It processes the specified number of questions and returns them as a slice.
 // Returns nil if any question is malformed.
-func parseQSections(data *bytes.Buffer, qdcount uint16) []*question {
-	questions := make([]*question, 0, qdcount)
+func parseQSections(data *bytes.Buffer, qdcount uint16) []*Question {
+	questions := make([]*Question, 0, qdcount)
 	for i := uint16(0); i < qdcount; i++ {
 		q := parseQSection(data)
 		if q == nil {
@@ -92,12 +90,12 @@ func parseQSections(data *bytes.Buffer, qdcount uint16) []*question {
 // parseQSection parses a single question section from a DNS message.
 // It extracts the query name, type, and class.
 // Returns nil if the section is malformed.
-func parseQSection(data *bytes.Buffer) *question {
-	s := question{}
+func parseQSection(data *bytes.Buffer) *Question {
+	s := Question{}
 
-	s.qName = parseSectionLabel(data)
+	s.Name = parseSectionLabel(data)
 
-	if s.qName == "" {
+	if s.Name == "" {
 		return nil
 	}
 
@@ -105,8 +103,8 @@ func parseQSection(data *bytes.Buffer) *question {
 		return nil
 	}
 
-	s.qType = binary.BigEndian.Uint16(readWord(data))
-	s.qClass = binary.BigEndian.Uint16(readWord(data))
+	s.Type = binary.BigEndian.Uint16(readWord(data))
+	s.Class = binary.BigEndian.Uint16(readWord(data))
 
 	return &s
 }
@@ -141,8 +139,8 @@ func parseSectionLabel(data *bytes.Buffer) string {
 // parseRecords parses the answer records section of a DNS message.
 // It processes the specified number of records and returns them as a slice.
 // Returns nil if any record is malformed.
-func parseRecords(data *bytes.Buffer, base []byte, count uint16) []*record {
-	records := make([]*record, 0, count)
+func parseRecords(data *bytes.Buffer, base []byte, count uint16) []*Record {
+	records := make([]*Record, 0, count)
 
 	for i := uint16(0); i < count; i++ {
 		r := parseRecord(data, base)
@@ -159,12 +157,12 @@ func parseRecords(data *bytes.Buffer, base []byte, count uint16) []*record {
 // It handles both normal and compressed labels, and extracts record type,
 // class, TTL, and record data.
 // Returns nil if the record is malformed.
-func parseRecord(data *bytes.Buffer, base []byte) *record {
+func parseRecord(data *bytes.Buffer, base []byte) *Record {
 	if data.Len() == 0 {
 		return nil
 	}
 
-	r := record{}
+	r := Record{}
 
 	labelLen := readByte(data)
 
@@ -184,26 +182,26 @@ func parseRecord(data *bytes.Buffer, base []byte) *record {
 			return nil
 		}
 
-		r.name = parseSectionLabel(bytes.NewBuffer(base[offset:]))
+		r.Name = parseSectionLabel(bytes.NewBuffer(base[offset:]))
 	} else {
 		_ = data.UnreadByte()
-		r.name = parseSectionLabel(data)
+		r.Name = parseSectionLabel(data)
 	}
 
 	if data.Len() < 5*wordSize {
 		return nil
 	}
 
-	r.typ = binary.BigEndian.Uint16(readWord(data))
-	r.class = binary.BigEndian.Uint16(readWord(data))
-	r.ttl = binary.BigEndian.Uint32(readDWord(data))
+	r.Type = binary.BigEndian.Uint16(readWord(data))
+	r.Class = binary.BigEndian.Uint16(readWord(data))
+	r.TTL = binary.BigEndian.Uint32(readDWord(data))
 	rdlength := binary.BigEndian.Uint16(readWord(data))
 
 	if data.Len() < 0 || uint16(data.Len()) < rdlength {
 		return nil
 	}
 
-	r.data = data.Next(int(rdlength))
+	r.Data = data.Next(int(rdlength))
 
 	return &r
 }
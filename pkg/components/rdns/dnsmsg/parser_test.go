@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnsmsg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// response to an A query for example.com, with a compressed answer name
+	exampleResponse = []byte{
+		0x12, 0x34, // id
+		0x81, 0x80, // response, RD, RA, NOERROR
+		0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		0x00, 0x01, 0x00, 0x01, // A, IN
+		0xc0, 0x0c, // pointer to the question name
+		0x00, 0x01, 0x00, 0x01, // A, IN
+		0x00, 0x00, 0x0e, 0x10, // TTL
+		0x00, 0x04, 93, 184, 216, 34,
+	}
+	// NXDOMAIN response to an AAAA query, without answers
+	nxdomainResponse = []byte{
+		0xab, 0xcd,
+		0x81, 0x83,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		7, 'm', 'i', 's', 's', 'i', 'n', 'g', 5, 'l', 'o', 'c', 'a', 'l', 0,
+		0x00, 0x1c, 0x00, 0x01,
+	}
+)
+
+func TestParse(t *testing.T) {
+	msg := Parse(exampleResponse)
+	require.NotNil(t, msg)
+
+	assert.Equal(t, uint16(0x1234), msg.ID())
+	assert.True(t, msg.IsResponse())
+	assert.False(t, msg.IsQuery())
+	assert.Equal(t, uint8(RCodeNoError), msg.RCode())
+	require.Len(t, msg.Questions, 1)
+	assert.Equal(t, Question{Name: "example.com", Type: TypeA, Class: 1}, *msg.Questions[0])
+	require.Len(t, msg.Answers, 1)
+	assert.Equal(t, "example.com", msg.Answers[0].Name)
+	assert.Equal(t, uint16(TypeA), msg.Answers[0].Type)
+	assert.Equal(t, uint32(3600), msg.Answers[0].TTL)
+	assert.Equal(t, []byte{93, 184, 216, 34}, msg.Answers[0].Data)
+}
+
+func TestParseWithoutAnswers(t *testing.T) {
+	msg := Parse(nxdomainResponse)
+	require.NotNil(t, msg)
+
+	assert.Equal(t, uint8(RCodeNXDomain), msg.RCode())
+	assert.Equal(t, "missing.local", msg.Questions[0].Name)
+	assert.Equal(t, "AAAA", TypeName(msg.Questions[0].Type))
+	assert.Empty(t, msg.Answers)
+}
+
+func TestParseMalformed(t *testing.T) {
+	assert.Nil(t, Parse(nil))
+	assert.Nil(t, Parse(exampleResponse[:8]))
+	// header without questions
+	assert.Nil(t, Parse(exampleResponse[:12]))
+	// truncated question
+	assert.Nil(t, Parse(exampleResponse[:20]))
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "A", TypeName(TypeA))
+	assert.Equal(t, "HTTPS", TypeName(TypeHTTPS))
+	assert.Equal(t, "TYPE99", TypeName(99))
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/obi/pkg/components/ebpf/ringbuf"
+	"go.opentelemetry.io/obi/pkg/components/rdns/dnsmsg"
 	"go.opentelemetry.io/obi/pkg/components/rdns/store"
 )
 
@@ -58,7 +59,7 @@ func tracerLoop(ctx context.Context, storage storage, tracer *tracer) {
 		// the idea here is to avoid copying 'record' when passing it to a
 		// channel - this allows its allocated memory to be reused by
 		// subsequent ReadInto() calls, allowing the record data to be parsed
-		// in place by parseDNSMessage()
+		// in place by dnsmsg.Parse()
 		tracer.ringbuf.SetDeadline(time.Now().Add(time.Second))
 		err := tracer.ringbuf.ReadInto(&record)
 		if err != nil {
@@ -81,23 +82,23 @@ func tracerLoop(ctx context.Context, storage storage, tracer *tracer) {
 }
 
 func handleDNSMessage(rd *ringbuf.Record) *store.DNSEntry {
-	dnsMessage := parseDNSMessage(rd.RawSample)
+	dnsMessage := dnsmsg.Parse(rd.RawSample)
 
-	if dnsMessage == nil || len(dnsMessage.questions) == 0 {
+	if dnsMessage == nil || len(dnsMessage.Answers) == 0 {
 		return nil
 	}
 
 	entry := store.DNSEntry{
-		HostName: dnsMessage.questions[0].qName,
-		IPs:      make([]string, 0, len(dnsMessage.answers)),
+		HostName: dnsMessage.Questions[0].Name,
+		IPs:      make([]string, 0, len(dnsMessage.Answers)),
 	}
 
-	for _, answer := range dnsMessage.answers {
-		if answer.typ != TypeA {
+	for _, answer := range dnsMessage.Answers {
+		if answer.Type != dnsmsg.TypeA {
 			continue
 		}
 
-		ipStr := net.IP(answer.data).String()
+		ipStr := net.IP(answer.Data).String()
 		entry.IPs = append(entry.IPs, ipStr)
 	}
 
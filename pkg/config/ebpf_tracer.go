@@ -78,6 +78,15 @@ type EBPFTracer struct {
 	// Enables GPU instrumentation for CUDA kernel launches and allocations
 	InstrumentGPU bool `yaml:"instrument_gpu" env:"OTEL_EBPF_INSTRUMENT_GPU"`
 
+	// Enables the Go runtime probes for GC stop the world pauses. They only trigger once per pause.
+	InstrumentGoRuntime bool `yaml:"instrument_go_runtime" env:"OTEL_EBPF_INSTRUMENT_GO_RUNTIME"`
+
+	// Enables the Go runtime probes for goroutine scheduling latencies. The latency is only
+	// reported for one of every 8 scheduled goroutines, but the uprobes trigger every time a
+	// goroutine changes its status or is executed, which adds a noticeable overhead to programs
+	// that schedule goroutines at a high rate.
+	InstrumentGoScheduler bool `yaml:"instrument_go_scheduler" env:"OTEL_EBPF_INSTRUMENT_GO_SCHEDULER"`
+
 	// Enables debug printing of the protocol data
 	ProtocolDebug bool `yaml:"protocol_debug_print" env:"OTEL_EBPF_PROTOCOL_DEBUG_PRINT"`
 
@@ -95,6 +104,16 @@ type EBPFTracer struct {
 	// Postgres prepared statements cache size.
 	PostgresPreparedStatementsCacheSize int `yaml:"postgres_prepared_statements_cache_size" env:"OTEL_EBPF_BPF_POSTGRES_PREPARED_STATEMENTS_CACHE_SIZE"`
 
+	// Cassandra prepared statements cache size.
+	CassandraPreparedStatementsCacheSize int `yaml:"cassandra_prepared_statements_cache_size" env:"OTEL_EBPF_BPF_CASSANDRA_PREPARED_STATEMENTS_CACHE_SIZE"`
+
+	// Number of Cassandra connections whose current keyspace, as set by the USE statement, is remembered.
+	CassandraKeyspacesCacheSize int `yaml:"cassandra_keyspaces_cache_size" env:"OTEL_EBPF_BPF_CASSANDRA_KEYSPACES_CACHE_SIZE"`
+
+	// CassandraDetection enables the detection of the Cassandra CQL protocol in the TCP events.
+	// It's not configurable: it's set when the "cassandra" instrumentation is selected by any exporter.
+	CassandraDetection bool `yaml:"-"`
+
 	// MongoDB requests cache size.
 	MongoRequestsCacheSize int `yaml:"mongo_requests_cache_size" env:"OTEL_EBPF_BPF_MONGO_REQUESTS_CACHE_SIZE"`
 }
@@ -95,6 +95,9 @@ func getDefinitions(
 			attr.ClientPort:     false,
 			attr.SrcZone:        false,
 			attr.DstZone:        false,
+			attr.SrcRegion:      false,
+			attr.DstRegion:      false,
+			attr.L7Protocol:     false,
 			attr.IfaceDirection: Default(ifaceDirEnabled),
 			attr.Iface:          Default(ifaceDirEnabled),
 		},
@@ -203,6 +206,19 @@ func getDefinitions(
 		extraGroupAttributes[GroupHTTPClientInfo],
 	)
 
+	// RPC attributes of the HTTP client requests sent from the AWS SDK
+	awsSDKAttributes := NewAttrReportGroup(
+		false,
+		nil,
+		map[attr.Name]Default{
+			attr.RPCSystem:  false,
+			attr.RPCService: false,
+			attr.RPCMethod:  false,
+			attr.AWSRegion:  false,
+		},
+		nil,
+	)
+
 	grpcClientInfo := NewAttrReportGroup(
 		false,
 		nil,
@@ -240,6 +256,45 @@ func getDefinitions(
 		NetworkInterZone.Section: {
 			SubGroups: []*AttrReportGroup{&networkInterZone, &networkInterZoneCIDR, &networkInterZoneKube},
 		},
+		NetworkTCPRTT.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+		},
+		NetworkTCPRetransmits.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+		},
+		NetworkTCPResets.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+		},
+		NetworkConnsOpened.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+		},
+		NetworkConnsFailed.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.ErrorType: true,
+			},
+		},
+		NetworkConnsClosed.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+		},
+		NetworkConnsDuration.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+		},
+		NetworkEgressBytes.Section: {
+			SubGroups:  []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+			Attributes: networkEgressAttributes(),
+		},
+		NetworkEgressCost.Section: {
+			SubGroups:  []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+			Attributes: networkEgressAttributes(),
+		},
+		NetworkDrops.Section: {
+			SubGroups: []*AttrReportGroup{&networkAttributes, &networkCIDR, &networkKubeAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.DropReason: true,
+				attr.Iface:      true,
+			},
+		},
 		HTTPServerDuration.Section: {
 			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &serverInfo},
 		},
@@ -250,13 +305,13 @@ func getDefinitions(
 			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &serverInfo},
 		},
 		HTTPClientDuration.Section: {
-			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &httpClientInfo},
+			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &httpClientInfo, &awsSDKAttributes},
 		},
 		HTTPClientRequestSize.Section: {
-			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &httpClientInfo},
+			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &httpClientInfo, &awsSDKAttributes},
 		},
 		HTTPClientResponseSize.Section: {
-			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &httpClientInfo},
+			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &httpCommon, &httpClientInfo, &awsSDKAttributes},
 		},
 		RPCClientDuration.Section: {
 			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes, &grpcClientInfo},
@@ -288,6 +343,22 @@ func getDefinitions(
 		MessagingProcessDuration.Section: {
 			SubGroups: []*AttrReportGroup{&messagingAttributes},
 		},
+		DNSLookupDuration.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.DNSQuestionName: false,
+				attr.ErrorType:       true,
+			},
+		},
+		ServiceGraphRequest.Section: {
+			SubGroups: []*AttrReportGroup{&awsSDKAttributes},
+		},
+		GoGCPauseDuration.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes},
+		},
+		GoScheduleLatency.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes, &appKubeAttributes},
+		},
 		Traces.Section: {
 			Attributes: map[attr.Name]Default{
 				attr.DBQueryText: false,
@@ -321,9 +392,9 @@ func getDefinitions(
 				attr.CudaMemcpyKind: true,
 			},
 		},
-		// span and service graph metrics don't yet implement attribute selection,
-		// but their values can still be filtered, so we list them here just to
-		// make the filter recognize its attributes
+		// span metrics don't yet implement attribute selection, and service graph metrics
+		// only implement it for their extra attributes, but their values can still be filtered,
+		// so we list them here just to make the filter recognize its attributes
 		// TODO: when service graph and span metrics implement attribute selection, replace this section by proper metric names
 		"---- temporary placeholder for span and service graph metrics ----": {
 			Attributes: map[attr.Name]Default{
@@ -373,3 +444,15 @@ func AllAttributeNames(
 	}
 	return names
 }
+
+// networkEgressAttributes are reported by default in the egress cost metrics,
+// along with the Kubernetes owners of the source and destination of the traffic
+func networkEgressAttributes() map[attr.Name]Default {
+	return map[attr.Name]Default{
+		attr.EgressType:     true,
+		attr.SrcCloudZone:   true,
+		attr.DstCloudZone:   true,
+		attr.SrcCloudRegion: true,
+		attr.DstCloudRegion: true,
+	}
+}
@@ -31,7 +31,7 @@ func TestFor(t *testing.T) {
 		SelectionCfg: Selection{
 			"obi_network_flow_bytes_total": InclusionLists{
 				Include: []string{"obi_ip", "src.*", "k8s.*"},
-				Exclude: []string{"k8s_*_name", "k8s.*.type", "*zone"},
+				Exclude: []string{"k8s_*_name", "k8s.*.type", "*zone", "*region"},
 			},
 		},
 	})
@@ -60,7 +60,7 @@ func TestFor_GlobEntries(t *testing.T) {
 			},
 			"obi_network_flow_bytes_total": InclusionLists{
 				Include: []string{"src.*", "k8s.*"},
-				Exclude: []string{"k8s.*.name", "*zone"},
+				Exclude: []string{"k8s.*.name", "*zone", "*region"},
 			},
 		},
 	})
@@ -89,7 +89,7 @@ func TestFor_GlobEntries_NoInclusion(t *testing.T) {
 				Exclude: []string{"*dst*"},
 			},
 			"obi_network_flow_bytes_total": InclusionLists{
-				Exclude: []string{"k8s.*.namespace", "*zone"},
+				Exclude: []string{"k8s.*.namespace", "*zone", "*region"},
 			},
 		},
 	})
@@ -111,7 +111,7 @@ func TestFor_GlobEntries_Order(t *testing.T) {
 				Include: []string{"*"},
 			},
 			"obi_network_*": InclusionLists{
-				Exclude: []string{"dst.*", "transport", "*direction", "iface", "*zone"},
+				Exclude: []string{"dst.*", "transport", "*direction", "iface", "*zone", "*region"},
 			},
 			"obi_network_flow_bytes_total": InclusionLists{
 				Include: []string{"dst.name"},
@@ -122,6 +122,7 @@ func TestFor_GlobEntries_Order(t *testing.T) {
 	assert.Equal(t, []attr.Name{
 		"client.port",
 		"dst.name",
+		"l7.protocol",
 		"obi.ip",
 		"server.port",
 		"src.address",
@@ -157,7 +158,7 @@ func TestFor_KubeDisabled(t *testing.T) {
 		SelectionCfg: Selection{
 			"obi_network_flow_bytes_total": InclusionLists{
 				Include: []string{"target.instance", "obi_ip", "src.*", "k8s.*"},
-				Exclude: []string{"src.port", "*zone"},
+				Exclude: []string{"src.port", "*zone", "*region"},
 			},
 		},
 	})
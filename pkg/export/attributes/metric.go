@@ -34,6 +34,56 @@ var (
 		Prom:    "obi_network_inter_zone_bytes_total",
 		OTEL:    "obi.network.inter.zone.bytes",
 	}
+	NetworkTCPRTT = Name{
+		Section: "obi.network.tcp.rtt",
+		Prom:    "obi_network_tcp_rtt_seconds",
+		OTEL:    "obi.network.tcp.rtt",
+	}
+	NetworkTCPRetransmits = Name{
+		Section: "obi.network.tcp.retransmits",
+		Prom:    "obi_network_tcp_retransmits_total",
+		OTEL:    "obi.network.tcp.retransmits",
+	}
+	NetworkTCPResets = Name{
+		Section: "obi.network.tcp.resets",
+		Prom:    "obi_network_tcp_resets_total",
+		OTEL:    "obi.network.tcp.resets",
+	}
+	NetworkConnsOpened = Name{
+		Section: "obi.network.connections.opened",
+		Prom:    "obi_network_connections_opened_total",
+		OTEL:    "obi.network.connections.opened",
+	}
+	NetworkConnsFailed = Name{
+		Section: "obi.network.connections.failed",
+		Prom:    "obi_network_connections_failed_total",
+		OTEL:    "obi.network.connections.failed",
+	}
+	NetworkConnsClosed = Name{
+		Section: "obi.network.connections.closed",
+		Prom:    "obi_network_connections_closed_total",
+		OTEL:    "obi.network.connections.closed",
+	}
+	NetworkConnsDuration = Name{
+		Section: "obi.network.connections.duration",
+		Prom:    "obi_network_connections_duration_seconds",
+		OTEL:    "obi.network.connections.duration",
+	}
+	NetworkEgressBytes = Name{
+		Section: "obi.network.egress.bytes",
+		Prom:    "obi_network_egress_bytes_total",
+		OTEL:    "obi.network.egress.bytes",
+	}
+	NetworkEgressCost = Name{
+		Section: "obi.network.egress.cost",
+		Prom:    "obi_network_egress_cost_total",
+		OTEL:    "obi.network.egress.cost",
+	}
+	NetworkDrops = Name{
+		Section: "obi.network.drops",
+		Prom:    "obi_network_drops_total",
+		OTEL:    "obi.network.drops",
+	}
 	HTTPServerRequestSize = Name{
 		Section: "http.server.request.body.size",
 		Prom:    "http_server_request_body_size_bytes",
@@ -89,6 +139,28 @@ var (
 		Prom:    "messaging_process_duration_seconds",
 		OTEL:    "messaging.process.duration",
 	}
+	DNSLookupDuration = Name{
+		Section: "dns.lookup.duration",
+		Prom:    "dns_lookup_duration_seconds",
+		OTEL:    "dns.lookup.duration",
+	}
+	// ServiceGraphRequest selects the attributes of all the traces_service_graph_request_* metrics,
+	// which are added to their fixed client, server and namespace attributes
+	ServiceGraphRequest = Name{
+		Section: "traces.service.graph.request",
+		Prom:    "traces_service_graph_request_total",
+		OTEL:    "traces_service_graph_request_total",
+	}
+	GoGCPauseDuration = Name{
+		Section: "go.gc.pause.duration",
+		Prom:    "go_gc_pause_duration_seconds",
+		OTEL:    "go.gc.pause.duration",
+	}
+	GoScheduleLatency = Name{
+		Section: "go.schedule.latency",
+		Prom:    "go_schedule_latency_seconds",
+		OTEL:    "go.schedule.latency",
+	}
 	GPUKernelLaunchCalls = Name{
 		Section: "gpu.kernel.launch.calls",
 		Prom:    "gpu_kernel_launch_calls_total",
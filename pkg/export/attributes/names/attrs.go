@@ -55,6 +55,7 @@ const (
 	MessagingOpType        = Name("messaging.operation.type")
 	MessagingSystem        = Name(semconv.MessagingSystemKey)
 	MessagingDestination   = Name(semconv.MessagingDestinationNameKey)
+	DNSQuestionName        = Name("dns.question.name")
 
 	K8sNamespaceName   = Name("k8s.namespace.name")
 	K8sPodName         = Name("k8s.pod.name")
@@ -96,6 +97,16 @@ const (
 	DstCIDR    = Name("dst.cidr")
 	SrcZone    = Name("src.zone")
 	DstZone    = Name("dst.zone")
+	SrcRegion  = Name("src.region")
+	DstRegion  = Name("dst.region")
+	EgressType = Name("egress.type")
+	L7Protocol = Name("l7.protocol")
+	DropReason = Name("drop.reason")
+
+	SrcCloudZone   = Name("src.cloud.zone")
+	DstCloudZone   = Name("dst.cloud.zone")
+	SrcCloudRegion = Name("src.cloud.region")
+	DstCloudRegion = Name("dst.cloud.region")
 
 	ClientPort = Name("client.port")
 
@@ -153,6 +164,25 @@ const (
 	DBQueryText          = Name("db.query.text")
 	DBResponseStatusCode = Name("db.response.status_code")
 	DBNamespace          = Name("db.namespace")
+
+	// RabbitMQ
+	MessagingRabbitMQRoutingKey = Name("messaging.rabbitmq.destination.routing_key")
+
+	// MQTT
+	MessagingMQTTQoS        = Name("messaging.mqtt.qos")
+	MessagingMQTTReasonCode = Name("messaging.mqtt.reason_code")
+
+	// DNS
+	DNSQuestionType = Name("dns.question.type")
+	DNSResponseCode = Name("dns.response_code")
+
+	// Memcached
+	MemcachedKey = Name("db.memcached.key")
+	MemcachedHit = Name("db.memcached.hit")
+
+	// AWS SDK
+	RPCService = Name(semconv.RPCServiceKey)
+	AWSRegion  = Name("aws.region")
 )
 
 // Beyla specific GPU events
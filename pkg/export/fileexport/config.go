@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexport
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Format string
+
+const (
+	// FormatJSONL writes each request.Span as a JSON object in its own line
+	FormatJSONL = Format("jsonl")
+	// FormatOTLPJSON writes, for each service in a span batch, an OTLP-JSON
+	// traces payload in its own line, as the OpenTelemetry Collector file exporter does
+	FormatOTLPJSON = Format("otlp_json")
+)
+
+func (f Format) Valid() bool {
+	switch f {
+	case FormatJSONL, FormatOTLPJSON:
+		return true
+	}
+	return false
+}
+
+type Config struct {
+	// Path of the file where the spans are written. The file exporter is disabled if empty.
+	// Rotated files are stored in the same directory, with the rotation timestamp
+	// appended to the file name.
+	Path   string `yaml:"path" env:"OTEL_EBPF_FILE_EXPORT_PATH"`
+	Format Format `yaml:"format" env:"OTEL_EBPF_FILE_EXPORT_FORMAT"`
+
+	// MaxSizeMB is the size, in megabytes, that triggers the rotation of the current file.
+	// Zero disables size-based rotation.
+	MaxSizeMB int `yaml:"max_size_mb" env:"OTEL_EBPF_FILE_EXPORT_MAX_SIZE_MB"`
+	// RotationInterval is the maximum time a file is written before being rotated.
+	// Zero disables time-based rotation.
+	RotationInterval time.Duration `yaml:"rotation_interval" env:"OTEL_EBPF_FILE_EXPORT_ROTATION_INTERVAL"`
+	// Compress rotated files with gzip
+	Compress bool `yaml:"compress" env:"OTEL_EBPF_FILE_EXPORT_COMPRESS"`
+	// MaxFiles is the maximum number of rotated files to keep. Older files are removed.
+	// Zero keeps all the rotated files.
+	MaxFiles int `yaml:"max_files" env:"OTEL_EBPF_FILE_EXPORT_MAX_FILES"`
+}
+
+func (c *Config) Enabled() bool {
+	return c.Path != ""
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if !c.Format.Valid() {
+		return fmt.Errorf("invalid file_export format: %q. Accepted values: %q, %q", c.Format, FormatJSONL, FormatOTLPJSON)
+	}
+	if c.MaxSizeMB < 0 || c.MaxFiles < 0 || c.RotationInterval < 0 {
+		return errors.New("file_export max_size_mb, max_files and rotation_interval can't be negative")
+	}
+	return nil
+}
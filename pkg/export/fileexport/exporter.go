@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package fileexport provides an export node that writes the spans into a local file,
+// for offline analysis of the captured data.
+package fileexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	expirable2 "github.com/hashicorp/golang-lru/v2/expirable"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/pipe/global"
+	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+	"go.opentelemetry.io/obi/pkg/export/otel/tracesgen"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+const reporterName = "go.opentelemetry.io/obi"
+
+func flog() *slog.Logger {
+	return slog.With("component", "fileexport.SpanFileExporter")
+}
+
+// SpanFileExporter creates a terminal node that writes the received request.Spans
+// into the file configured in the Config, rotating it when required.
+// The OTLP-JSON format follows the sampler and the instrumentations selection of
+// the OTEL traces exporter configuration.
+func SpanFileExporter(
+	ctxInfo *global.ContextInfo,
+	cfg *Config,
+	tracesCfg *otelcfg.TracesConfig,
+	selectorCfg *attributes.SelectorConfig,
+	input *msg.Queue[[]request.Span],
+) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled() {
+			return swarm.EmptyRunFunc()
+		}
+		exp, err := newSpanFileExporter(ctxInfo, cfg, tracesCfg, selectorCfg, input, time.Now)
+		if err != nil {
+			return nil, err
+		}
+		return exp.run, nil
+	}
+}
+
+type spanFileExporter struct {
+	cfg     *Config
+	ctxInfo *global.ContextInfo
+	input   <-chan []request.Span
+	file    *rotatingFile
+	buf     bytes.Buffer
+
+	// only used by the OTLP-JSON format
+	traceAttrs     map[attr.Name]struct{}
+	sampler        trace.Sampler
+	is             instrumentations.InstrumentationSelection
+	attributeCache *expirable2.LRU[svc.UID, []attribute.KeyValue]
+	marshaler      ptrace.JSONMarshaler
+}
+
+func newSpanFileExporter(
+	ctxInfo *global.ContextInfo,
+	cfg *Config,
+	tracesCfg *otelcfg.TracesConfig,
+	selectorCfg *attributes.SelectorConfig,
+	input *msg.Queue[[]request.Span],
+	now func() time.Time,
+) (*spanFileExporter, error) {
+	exp := &spanFileExporter{
+		cfg:     cfg,
+		ctxInfo: ctxInfo,
+	}
+	if cfg.Format == FormatOTLPJSON {
+		traceAttrs, err := tracesgen.UserSelectedAttributes(selectorCfg)
+		if err != nil {
+			return nil, fmt.Errorf("selecting user trace attributes: %w", err)
+		}
+		exp.traceAttrs = traceAttrs
+		exp.sampler = tracesCfg.SamplerConfig.Implementation()
+		exp.is = instrumentations.NewInstrumentationSelection(tracesCfg.Instrumentations)
+		exp.attributeCache = expirable2.NewLRU[svc.UID, []attribute.KeyValue](1024, nil, 5*time.Minute)
+	}
+	file, err := openRotatingFile(cfg, now)
+	if err != nil {
+		return nil, err
+	}
+	exp.file = file
+	exp.input = input.Subscribe()
+	return exp, nil
+}
+
+func (e *spanFileExporter) run(ctx context.Context) {
+	defer func() {
+		if err := e.file.Close(); err != nil {
+			flog().Warn("error closing span file", "error", err)
+		}
+	}()
+
+	// a ticker makes sure that the time-based rotation is done even if no spans are received
+	var rotationTick <-chan time.Time
+	if e.cfg.RotationInterval > 0 {
+		ticker := time.NewTicker(e.cfg.RotationInterval)
+		defer ticker.Stop()
+		rotationTick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case spans, ok := <-e.input:
+			if !ok {
+				return
+			}
+			e.write(ctx, spans)
+		case <-rotationTick:
+			if err := e.file.RotateIfExpired(); err != nil {
+				flog().Error("error rotating span file", "error", err)
+			}
+		}
+	}
+}
+
+func (e *spanFileExporter) write(ctx context.Context, spans []request.Span) {
+	e.buf.Reset()
+	var err error
+	if e.cfg.Format == FormatOTLPJSON {
+		err = e.encodeOTLPJSON(ctx, spans)
+	} else {
+		err = e.encodeJSONL(spans)
+	}
+	if err != nil {
+		flog().Error("error serializing spans", "format", e.cfg.Format, "error", err)
+		return
+	}
+	if e.buf.Len() == 0 {
+		return
+	}
+	// the whole batch is written at once, so it is never split between rotated files
+	if _, err := e.file.Write(e.buf.Bytes()); err != nil {
+		flog().Error("error writing spans to file", "error", err)
+	}
+}
+
+func (e *spanFileExporter) encodeJSONL(spans []request.Span) error {
+	for i := range spans {
+		if spans[i].InternalSignal() || spans[i].GoRuntimeSignal() {
+			continue
+		}
+		line, err := json.Marshal(&spans[i])
+		if err != nil {
+			return err
+		}
+		e.buf.Write(line)
+		e.buf.WriteByte('\n')
+	}
+	return nil
+}
+
+// encodeOTLPJSON writes a line for each service in the batch, containing an OTLP-JSON
+// payload with the same contents that the OTEL traces exporter would submit
+func (e *spanFileExporter) encodeOTLPJSON(ctx context.Context, spans []request.Span) error {
+	spanGroups := tracesgen.GroupSpans(ctx, spans, e.traceAttrs, e.sampler, e.is)
+
+	for _, spanGroup := range spanGroups {
+		if len(spanGroup) == 0 {
+			continue
+		}
+		service := &spanGroup[0].Span.Service
+		traces := tracesgen.GenerateTracesWithAttributes(e.attributeCache, service, otelcfg.ResourceAttrsFromEnv(service),
+			e.ctxInfo.HostID, spanGroup, reporterName, e.ctxInfo.ExtraResourceAttributes...)
+		line, err := e.marshaler.MarshalTraces(traces)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(line)
+		e.buf.WriteByte('\n')
+	}
+	return nil
+}
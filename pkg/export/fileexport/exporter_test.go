@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexport
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/pipe/global"
+	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/services"
+)
+
+var testSpans = []request.Span{
+	{Service: svc.Attrs{UID: svc.UID{Name: "frontend", Instance: "frontend-1"}}, Type: request.EventTypeHTTP, Method: "GET", Route: "/users", Status: 200, RequestStart: 100, Start: 100, End: 200},
+	{Service: svc.Attrs{UID: svc.UID{Name: "frontend", Instance: "frontend-1"}}, Type: request.EventTypeProcessAlive},
+	{Service: svc.Attrs{UID: svc.UID{Name: "backend", Instance: "backend-1"}}, Type: request.EventTypeSQLClient, Method: "SELECT", Path: "users", RequestStart: 150, Start: 150, End: 175},
+}
+
+var allInstrumentations = &otelcfg.TracesConfig{
+	Instrumentations: []string{instrumentations.InstrumentationALL},
+}
+
+func exportSpans(t *testing.T, cfg *Config, tracesCfg *otelcfg.TracesConfig, spans []request.Span) []string {
+	t.Helper()
+	input := msg.NewQueue[[]request.Span]()
+	exp, err := newSpanFileExporter(&global.ContextInfo{HostID: "host-id"}, cfg, tracesCfg, &attributes.SelectorConfig{}, input, time.Now)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		exp.run(t.Context())
+		close(done)
+	}()
+	input.Send(spans)
+	input.Close()
+	<-done
+
+	file, err := os.Open(cfg.Path)
+	require.NoError(t, err)
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestExportJSONL(t *testing.T) {
+	cfg := &Config{Path: filepath.Join(t.TempDir(), "spans", "spans.jsonl"), Format: FormatJSONL}
+	lines := exportSpans(t, cfg, allInstrumentations, testSpans)
+
+	// internal signals are not exported
+	require.Len(t, lines, 2)
+
+	var span struct {
+		Type       string            `json:"type"`
+		Kind       string            `json:"kind"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &span))
+	assert.Equal(t, "HTTP", span.Type)
+	assert.Equal(t, "SPAN_KIND_SERVER", span.Kind)
+	assert.Equal(t, "GET", span.Attributes["method"])
+	assert.Equal(t, "/users", span.Attributes["route"])
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &span))
+	assert.Equal(t, "SQLClient", span.Type)
+	assert.Equal(t, "SPAN_KIND_CLIENT", span.Kind)
+}
+
+func TestExportOTLPJSON(t *testing.T) {
+	cfg := &Config{Path: filepath.Join(t.TempDir(), "spans.json"), Format: FormatOTLPJSON}
+	lines := exportSpans(t, cfg, allInstrumentations, testSpans)
+
+	// one line for each service
+	require.Len(t, lines, 2)
+
+	names := map[string]string{}
+	unmarshaler := ptrace.JSONUnmarshaler{}
+	for _, line := range lines {
+		traces, err := unmarshaler.UnmarshalTraces([]byte(line))
+		require.NoError(t, err)
+		require.Equal(t, 1, traces.ResourceSpans().Len())
+		rs := traces.ResourceSpans().At(0)
+		svcName, ok := rs.Resource().Attributes().Get("service.name")
+		require.True(t, ok)
+		spans := rs.ScopeSpans().At(0).Spans()
+		require.Equal(t, 1, spans.Len())
+		names[svcName.Str()] = spans.At(0).Name()
+	}
+	assert.Equal(t, map[string]string{"frontend": "GET /users", "backend": "SELECT users"}, names)
+}
+
+func TestExportOTLPJSON_TracesConfig(t *testing.T) {
+	// only the instrumentations that are selected for the traces are exported
+	cfg := &Config{Path: filepath.Join(t.TempDir(), "spans.json"), Format: FormatOTLPJSON}
+	lines := exportSpans(t, cfg, &otelcfg.TracesConfig{
+		Instrumentations: []string{instrumentations.InstrumentationHTTP},
+	}, testSpans)
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "GET /users")
+
+	// the traces sampler is applied
+	cfg = &Config{Path: filepath.Join(t.TempDir(), "spans.json"), Format: FormatOTLPJSON}
+	lines = exportSpans(t, cfg, &otelcfg.TracesConfig{
+		Instrumentations: []string{instrumentations.InstrumentationALL},
+		SamplerConfig:    services.SamplerConfig{Name: "always_off"},
+	}, testSpans)
+	assert.Empty(t, lines)
+}
+
+func TestSpanFileExporterDisabled(t *testing.T) {
+	run, err := SpanFileExporter(&global.ContextInfo{}, &Config{}, allInstrumentations, &attributes.SelectorConfig{}, msg.NewQueue[[]request.Span]())(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, run)
+}
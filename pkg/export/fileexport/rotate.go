@@ -0,0 +1,268 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexport
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedTimeFormat is appended to the name of the rotated files. It sorts
+// lexicographically in the same order as the rotation times.
+const rotatedTimeFormat = "20060102T150405.000"
+
+// rotatedCounterSep precedes the counter that is appended to the rotation time of the files
+// that are rotated within the same millisecond. It sorts after the extension dot, so the
+// files keep sorting in the order of rotation.
+const rotatedCounterSep = "_"
+
+const compressedExt = ".gz"
+
+// renameFile is overridden by the tests
+var renameFile = os.Rename
+
+// rotatingFile is an io.Writer that rotates the underlying file when it exceeds
+// a maximum size or a maximum age. Each Write invocation is never split
+// across different files.
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	maxAge   time.Duration
+	compress bool
+	maxFiles int
+	now      func() time.Time
+
+	// file is nil if it couldn't be reopened after a rotation
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// compression and removal of the rotated files run in the background,
+	// one rotation at a time, so they don't block the writes
+	cleanupMu sync.Mutex
+	cleanups  sync.WaitGroup
+}
+
+func openRotatingFile(cfg *Config, now func() time.Time) (*rotatingFile, error) {
+	rf := &rotatingFile{
+		path:     cfg.Path,
+		maxSize:  int64(cfg.MaxSizeMB) * 1024 * 1024,
+		maxAge:   cfg.RotationInterval,
+		compress: cfg.Compress,
+		maxFiles: cfg.MaxFiles,
+		now:      now,
+	}
+	if err := os.MkdirAll(filepath.Dir(rf.path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating file export directory: %w", err)
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening file export: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("reading file export size: %w", err)
+	}
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = rf.now()
+	return nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	if rf.file == nil {
+		if err := rf.open(); err != nil {
+			return 0, err
+		}
+	}
+	if rf.size == 0 && rf.expired() {
+		// an empty file is not rotated, but its age counts from its first write
+		rf.openedAt = rf.now()
+	}
+	if rf.shouldRotate(len(p)) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// RotateIfExpired rotates the file if it contains data and has been open for
+// longer than the rotation interval. It allows rotating files that didn't
+// receive any write since their expiration.
+func (rf *rotatingFile) RotateIfExpired() error {
+	if rf.file == nil {
+		return rf.open()
+	}
+	if rf.size == 0 || !rf.expired() {
+		return nil
+	}
+	return rf.rotate()
+}
+
+// Close closes the current file and waits for the rotated files to be compressed
+func (rf *rotatingFile) Close() error {
+	defer rf.cleanups.Wait()
+	if rf.file == nil {
+		return nil
+	}
+	return rf.file.Close()
+}
+
+func (rf *rotatingFile) expired() bool {
+	return rf.maxAge > 0 && rf.now().Sub(rf.openedAt) >= rf.maxAge
+}
+
+func (rf *rotatingFile) shouldRotate(nextWrite int) bool {
+	// empty files are never rotated, even if a single write exceeds the maximum size
+	if rf.size == 0 {
+		return false
+	}
+	if rf.maxSize > 0 && rf.size+int64(nextWrite) > rf.maxSize {
+		return true
+	}
+	return rf.expired()
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return fmt.Errorf("closing file export: %w", err)
+	}
+	rf.file = nil
+	rotated := rf.rotatedName(rf.now())
+	if err := renameFile(rf.path, rotated); err != nil {
+		// keep appending to the current file. Next writes will retry the rotation
+		flog().Warn("can't rotate file export", "file", rf.path, "error", err)
+		return rf.open()
+	}
+	if err := rf.open(); err != nil {
+		// next writes will retry opening the file
+		return err
+	}
+	// errors from here are not fatal, as the new file is ready to be written
+	rf.cleanups.Add(1)
+	go func() {
+		defer rf.cleanups.Done()
+		rf.cleanupMu.Lock()
+		defer rf.cleanupMu.Unlock()
+		if rf.compress {
+			if err := compressFile(rotated); err != nil {
+				flog().Warn("can't compress rotated file", "file", rotated, "error", err)
+			}
+		}
+		if err := rf.removeOldFiles(); err != nil {
+			flog().Warn("can't remove old rotated files", "error", err)
+		}
+	}()
+	return nil
+}
+
+// rotatedName returns the name of a rotated file that doesn't exist yet. For example,
+// /var/log/spans.jsonl would be rotated as /var/log/spans-20250102T150405.000.jsonl, or as
+// /var/log/spans-20250102T150405.000_001.jsonl if it was already rotated in the same millisecond.
+func (rf *rotatingFile) rotatedName(t time.Time) string {
+	ext := filepath.Ext(rf.path)
+	base := strings.TrimSuffix(rf.path, ext) + "-" + t.UTC().Format(rotatedTimeFormat)
+	name := base + ext
+	for i := 1; fileExists(name) || fileExists(name+compressedExt); i++ {
+		name = fmt.Sprintf("%s%s%03d%s", base, rotatedCounterSep, i, ext)
+	}
+	return name
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// rotatedFiles returns the names of the rotated files, from the oldest to the newest
+func (rf *rotatingFile) rotatedFiles() ([]string, error) {
+	dir := filepath.Dir(rf.path)
+	ext := filepath.Ext(rf.path)
+	prefix := strings.TrimSuffix(filepath.Base(rf.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressedExt), ext)
+		ts, _, _ = strings.Cut(ts, rotatedCounterSep)
+		if _, err := time.Parse(rotatedTimeFormat, ts); err != nil {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func (rf *rotatingFile) removeOldFiles() error {
+	if rf.maxFiles <= 0 {
+		return nil
+	}
+	files, err := rf.rotatedFiles()
+	if err != nil {
+		return err
+	}
+	for len(files) > rf.maxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// compressFile replaces the provided file by a gzipped copy with the .gz extension
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressedExt, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + compressedExt)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + compressedExt)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
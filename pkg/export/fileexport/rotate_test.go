@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fileexport
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock()
+	rf, err := openRotatingFile(&Config{Path: filepath.Join(dir, "spans.jsonl")}, clock.Now)
+	require.NoError(t, err)
+	defer rf.Close()
+	rf.maxSize = 10
+
+	// writes exceeding the maximum size are not split
+	_, err = rf.Write([]byte("0123456789abc\n"))
+	require.NoError(t, err)
+	clock.Advance(time.Second)
+	_, err = rf.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	files, err := rf.rotatedFiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "spans-20250102T150406.000.jsonl")}, files)
+	assert.Equal(t, "0123456789abc\n", readFile(t, files[0]))
+	assert.Equal(t, "second\n", readFile(t, filepath.Join(dir, "spans.jsonl")))
+
+	// writes fitting in the current file don't rotate it
+	_, err = rf.Write([]byte("3\n"))
+	require.NoError(t, err)
+	files, err = rf.rotatedFiles()
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestRotateByTime(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock()
+	rf, err := openRotatingFile(&Config{Path: filepath.Join(dir, "spans.jsonl"), RotationInterval: time.Minute}, clock.Now)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	// empty files are not rotated
+	clock.Advance(2 * time.Minute)
+	require.NoError(t, rf.RotateIfExpired())
+
+	// the age of the file counts from the first write
+	_, err = rf.Write([]byte("first\n"))
+	require.NoError(t, err)
+	clock.Advance(30 * time.Second)
+	_, err = rf.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, rf.RotateIfExpired())
+	files, err := rf.rotatedFiles()
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	clock.Advance(30 * time.Second)
+	require.NoError(t, rf.RotateIfExpired())
+	files, err = rf.rotatedFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "first\nsecond\n", readFile(t, files[0]))
+	assert.Empty(t, readFile(t, filepath.Join(dir, "spans.jsonl")))
+}
+
+func TestRotateCompressAndRemoveOldFiles(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock()
+	rf, err := openRotatingFile(&Config{Path: filepath.Join(dir, "spans.jsonl"), Compress: true, MaxFiles: 2}, clock.Now)
+	require.NoError(t, err)
+	defer rf.Close()
+	rf.maxSize = 1
+
+	// files not matching the rotated names pattern are never removed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spans-other.jsonl"), []byte("other"), 0o644))
+
+	for _, line := range []string{"1\n", "2\n", "3\n", "4\n"} {
+		_, err = rf.Write([]byte(line))
+		require.NoError(t, err)
+		clock.Advance(time.Second)
+	}
+	// wait for the background compression
+	rf.cleanups.Wait()
+
+	files, err := rf.rotatedFiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "spans-20250102T150407.000.jsonl.gz"),
+		filepath.Join(dir, "spans-20250102T150408.000.jsonl.gz"),
+	}, files)
+
+	gzFile, err := os.Open(files[1])
+	require.NoError(t, err)
+	defer gzFile.Close()
+	gz, err := gzip.NewReader(gzFile)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "3\n", string(content))
+
+	assert.Equal(t, "4\n", readFile(t, filepath.Join(dir, "spans.jsonl")))
+	assert.FileExists(t, filepath.Join(dir, "spans-other.jsonl"))
+}
+
+func TestAppendToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("previous\n"), 0o644))
+
+	rf, err := openRotatingFile(&Config{Path: path}, time.Now)
+	require.NoError(t, err)
+	_, err = rf.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, rf.Close())
+
+	assert.Equal(t, "previous\nnew\n", readFile(t, path))
+}
+
+func TestRotateFailureKeepsWriting(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock()
+	rf, err := openRotatingFile(&Config{Path: filepath.Join(dir, "spans.jsonl")}, clock.Now)
+	require.NoError(t, err)
+	defer rf.Close()
+	rf.maxSize = 1
+
+	renameFile = func(_, _ string) error { return os.ErrPermission }
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	_, err = rf.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = rf.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", readFile(t, filepath.Join(dir, "spans.jsonl")))
+
+	// the rotation is retried in the next writes
+	renameFile = os.Rename
+	clock.Advance(time.Second)
+	_, err = rf.Write([]byte("third\n"))
+	require.NoError(t, err)
+	files, err := rf.rotatedFiles()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "first\nsecond\n", readFile(t, files[0]))
+	assert.Equal(t, "third\n", readFile(t, filepath.Join(dir, "spans.jsonl")))
+}
+
+func TestRotateWithinSameMillisecond(t *testing.T) {
+	dir := t.TempDir()
+	clock := newFakeClock()
+	rf, err := openRotatingFile(&Config{Path: filepath.Join(dir, "spans.jsonl"), MaxFiles: 2}, clock.Now)
+	require.NoError(t, err)
+	defer rf.Close()
+	rf.maxSize = 1
+
+	// the clock doesn't advance, so all the rotations happen in the same millisecond
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = rf.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	rf.cleanups.Wait()
+
+	// previously rotated files are not overwritten, and the oldest ones are removed
+	files, err := rf.rotatedFiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "spans-20250102T150405.000_001.jsonl"),
+		filepath.Join(dir, "spans-20250102T150405.000_002.jsonl"),
+	}, files)
+	assert.Equal(t, "second\n", readFile(t, files[0]))
+	assert.Equal(t, "third\n", readFile(t, files[1]))
+	assert.Equal(t, "fourth\n", readFile(t, filepath.Join(dir, "spans.jsonl")))
+}
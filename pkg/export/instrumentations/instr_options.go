@@ -6,14 +6,21 @@ package instrumentations
 type InstrumentationSelection uint64
 
 const (
-	InstrumentationALL   = "*"
-	InstrumentationHTTP  = "http"
-	InstrumentationGRPC  = "grpc"
-	InstrumentationSQL   = "sql"
-	InstrumentationRedis = "redis"
-	InstrumentationKafka = "kafka"
-	InstrumentationGPU   = "gpu"
-	InstrumentationMongo = "mongo"
+	InstrumentationALL       = "*"
+	InstrumentationHTTP      = "http"
+	InstrumentationGRPC      = "grpc"
+	InstrumentationSQL       = "sql"
+	InstrumentationRedis     = "redis"
+	InstrumentationKafka     = "kafka"
+	InstrumentationGPU       = "gpu"
+	InstrumentationMongo     = "mongo"
+	InstrumentationMemcached = "memcached"
+	InstrumentationAMQP      = "amqp"
+	InstrumentationNATS      = "nats"
+	InstrumentationCassandra = "cassandra"
+	InstrumentationMQTT      = "mqtt"
+	InstrumentationDNS       = "dns"
+	InstrumentationGoRuntime = "goruntime"
 )
 
 const (
@@ -25,6 +32,13 @@ const (
 	flagKafka
 	flagGPU
 	flagMongo
+	flagMemcached
+	flagAMQP
+	flagNATS
+	flagCassandra
+	flagMQTT
+	flagDNS
+	flagGoRuntime
 )
 
 func strToFlag(str string) InstrumentationSelection {
@@ -45,6 +59,20 @@ func strToFlag(str string) InstrumentationSelection {
 		return flagGPU
 	case InstrumentationMongo:
 		return flagMongo
+	case InstrumentationMemcached:
+		return flagMemcached
+	case InstrumentationAMQP:
+		return flagAMQP
+	case InstrumentationNATS:
+		return flagNATS
+	case InstrumentationCassandra:
+		return flagCassandra
+	case InstrumentationMQTT:
+		return flagMQTT
+	case InstrumentationDNS:
+		return flagDNS
+	case InstrumentationGoRuntime:
+		return flagGoRuntime
 	}
 	return 0
 }
@@ -75,7 +103,7 @@ func (s InstrumentationSelection) RedisEnabled() bool {
 }
 
 func (s InstrumentationSelection) DBEnabled() bool {
-	return s.SQLEnabled() || s.RedisEnabled() || s.MongoEnabled()
+	return s.SQLEnabled() || s.RedisEnabled() || s.MongoEnabled() || s.MemcachedEnabled() || s.CassandraEnabled()
 }
 
 func (s InstrumentationSelection) KafkaEnabled() bool {
@@ -83,7 +111,7 @@ func (s InstrumentationSelection) KafkaEnabled() bool {
 }
 
 func (s InstrumentationSelection) MQEnabled() bool {
-	return s.KafkaEnabled()
+	return s.KafkaEnabled() || s.AMQPEnabled() || s.NATSEnabled() || s.MQTTEnabled()
 }
 
 func (s InstrumentationSelection) GPUEnabled() bool {
@@ -93,3 +121,31 @@ func (s InstrumentationSelection) GPUEnabled() bool {
 func (s InstrumentationSelection) MongoEnabled() bool {
 	return s&flagMongo != 0
 }
+
+func (s InstrumentationSelection) MemcachedEnabled() bool {
+	return s&flagMemcached != 0
+}
+
+func (s InstrumentationSelection) AMQPEnabled() bool {
+	return s&flagAMQP != 0
+}
+
+func (s InstrumentationSelection) NATSEnabled() bool {
+	return s&flagNATS != 0
+}
+
+func (s InstrumentationSelection) CassandraEnabled() bool {
+	return s&flagCassandra != 0
+}
+
+func (s InstrumentationSelection) MQTTEnabled() bool {
+	return s&flagMQTT != 0
+}
+
+func (s InstrumentationSelection) DNSEnabled() bool {
+	return s&flagDNS != 0
+}
+
+func (s InstrumentationSelection) GoRuntimeEnabled() bool {
+	return s&flagGoRuntime != 0
+}
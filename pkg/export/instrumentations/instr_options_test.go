@@ -19,6 +19,42 @@ func TestInstrumentationSelection(t *testing.T) {
 	assert.False(t, is.KafkaEnabled())
 	assert.False(t, is.MQEnabled())
 
+	is = NewInstrumentationSelection([]string{"memcached"})
+	assert.True(t, is.MemcachedEnabled())
+	assert.True(t, is.DBEnabled())
+	assert.False(t, is.SQLEnabled())
+	assert.False(t, is.RedisEnabled())
+
+	is = NewInstrumentationSelection([]string{"amqp"})
+	assert.True(t, is.AMQPEnabled())
+	assert.True(t, is.MQEnabled())
+	assert.False(t, is.KafkaEnabled())
+
+	is = NewInstrumentationSelection([]string{"nats"})
+	assert.True(t, is.NATSEnabled())
+	assert.True(t, is.MQEnabled())
+	assert.False(t, is.AMQPEnabled())
+
+	is = NewInstrumentationSelection([]string{"cassandra"})
+	assert.True(t, is.CassandraEnabled())
+	assert.True(t, is.DBEnabled())
+	assert.False(t, is.SQLEnabled())
+
+	is = NewInstrumentationSelection([]string{"mqtt"})
+	assert.True(t, is.MQTTEnabled())
+	assert.True(t, is.MQEnabled())
+	assert.False(t, is.NATSEnabled())
+
+	is = NewInstrumentationSelection([]string{"dns"})
+	assert.True(t, is.DNSEnabled())
+	assert.False(t, is.DBEnabled())
+	assert.False(t, is.MQEnabled())
+
+	is = NewInstrumentationSelection([]string{"goruntime"})
+	assert.True(t, is.GoRuntimeEnabled())
+	assert.False(t, is.HTTPEnabled())
+	assert.False(t, is.DNSEnabled())
+
 	is = NewInstrumentationSelection([]string{"grpc", "kafka"})
 	assert.False(t, is.HTTPEnabled())
 	assert.False(t, is.SQLEnabled())
@@ -87,6 +87,9 @@ type MetricsReporter struct {
 	attrDBClient               []attributes.Field[*request.Span, attribute.KeyValue]
 	attrMessagingPublish       []attributes.Field[*request.Span, attribute.KeyValue]
 	attrMessagingProcess       []attributes.Field[*request.Span, attribute.KeyValue]
+	attrDNSLookup              []attributes.Field[*request.Span, attribute.KeyValue]
+	attrGoGCPause              []attributes.Field[*request.Span, attribute.KeyValue]
+	attrGoScheduleLatency      []attributes.Field[*request.Span, attribute.KeyValue]
 	attrHTTPRequestSize        []attributes.Field[*request.Span, attribute.KeyValue]
 	attrHTTPResponseSize       []attributes.Field[*request.Span, attribute.KeyValue]
 	attrHTTPClientRequestSize  []attributes.Field[*request.Span, attribute.KeyValue]
@@ -120,6 +123,9 @@ type Metrics struct {
 	dbClientDuration       *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	msgPublishDuration     *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	msgProcessDuration     *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	dnsLookupDuration      *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	goGCPauseDuration      *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	goScheduleLatency      *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	httpRequestSize        *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	httpResponseSize       *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	httpClientRequestSize  *Expirer[*request.Span, instrument.Float64Histogram, float64]
@@ -231,6 +237,18 @@ func newMetricsReporter(
 			request.SpanOTELGetters, mr.attributes.For(attributes.MessagingProcessDuration))
 	}
 
+	if is.DNSEnabled() {
+		mr.attrDNSLookup = attributes.OpenTelemetryGetters(
+			request.SpanOTELGetters, mr.attributes.For(attributes.DNSLookupDuration))
+	}
+
+	if is.GoRuntimeEnabled() {
+		mr.attrGoGCPause = attributes.OpenTelemetryGetters(
+			request.SpanOTELGetters, mr.attributes.For(attributes.GoGCPauseDuration))
+		mr.attrGoScheduleLatency = attributes.OpenTelemetryGetters(
+			request.SpanOTELGetters, mr.attributes.For(attributes.GoScheduleLatency))
+	}
+
 	if is.GPUEnabled() {
 		mr.attrGPUKernelCalls = attributes.OpenTelemetryGetters(
 			request.SpanOTELGetters, mr.attributes.For(attributes.GPUKernelLaunchCalls))
@@ -321,6 +339,23 @@ func (mr *MetricsReporter) otelMetricOptions(mlog *slog.Logger) []metric.Option
 		)
 	}
 
+	if mr.is.DNSEnabled() {
+		opts = append(opts,
+			metric.WithView(otelHistogramConfig(attributes.DNSLookupDuration.OTEL, mr.cfg.Buckets.DurationHistogram, useExponentialHistograms)),
+		)
+	}
+
+	if mr.is.GoRuntimeEnabled() {
+		goRuntimeBuckets := mr.cfg.Buckets.GoRuntimeHistogram
+		if len(goRuntimeBuckets) == 0 {
+			goRuntimeBuckets = otelcfg.DefaultBuckets.GoRuntimeHistogram
+		}
+		opts = append(opts,
+			metric.WithView(otelHistogramConfig(attributes.GoGCPauseDuration.OTEL, goRuntimeBuckets, useExponentialHistograms)),
+			metric.WithView(otelHistogramConfig(attributes.GoScheduleLatency.OTEL, goRuntimeBuckets, useExponentialHistograms)),
+		)
+	}
+
 	return opts
 }
 
@@ -449,6 +484,31 @@ func (mr *MetricsReporter) setupOtelMeters(m *Metrics, meter instrument.Meter) e
 			m.ctx, msgProcessDuration, mr.attrMessagingProcess, timeNow, mr.cfg.TTL)
 	}
 
+	if mr.is.DNSEnabled() {
+		dnsLookupDuration, err := meter.Float64Histogram(attributes.DNSLookupDuration.OTEL, instrument.WithUnit("s"))
+		if err != nil {
+			return fmt.Errorf("creating dns lookup duration histogram metric: %w", err)
+		}
+		m.dnsLookupDuration = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
+			m.ctx, dnsLookupDuration, mr.attrDNSLookup, timeNow, mr.cfg.TTL)
+	}
+
+	if mr.is.GoRuntimeEnabled() {
+		goGCPauseDuration, err := meter.Float64Histogram(attributes.GoGCPauseDuration.OTEL, instrument.WithUnit("s"))
+		if err != nil {
+			return fmt.Errorf("creating go gc pause duration histogram metric: %w", err)
+		}
+		m.goGCPauseDuration = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
+			m.ctx, goGCPauseDuration, mr.attrGoGCPause, timeNow, mr.cfg.TTL)
+
+		goScheduleLatency, err := meter.Float64Histogram(attributes.GoScheduleLatency.OTEL, instrument.WithUnit("s"))
+		if err != nil {
+			return fmt.Errorf("creating go schedule latency histogram metric: %w", err)
+		}
+		m.goScheduleLatency = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
+			m.ctx, goScheduleLatency, mr.attrGoScheduleLatency, timeNow, mr.cfg.TTL)
+	}
+
 	if mr.is.GPUEnabled() {
 		gpuKernelCallsTotal, err := meter.Int64Counter(attributes.GPUKernelLaunchCalls.OTEL)
 		if err != nil {
@@ -784,7 +844,7 @@ func otelMetricsAccepted(span *request.Span, mr *MetricsReporter) bool {
 }
 
 func otelSpanMetricsAccepted(span *request.Span, mr *MetricsReporter) bool {
-	return mr.cfg.AnySpanMetricsEnabled() && !span.Service.ExportsOTelMetricsSpan()
+	return mr.cfg.AnySpanMetricsEnabled() && !span.Service.ExportsOTelMetricsSpan() && !span.GoRuntimeSignal()
 }
 
 //nolint:cyclop
@@ -827,12 +887,14 @@ func (r *Metrics) record(span *request.Span, mr *MetricsReporter) {
 				httpClientResponseSize, attrs := r.httpClientResponseSize.ForRecord(span)
 				httpClientResponseSize.Record(ctx, float64(span.ResponseBodyLength()), instrument.WithAttributeSet(attrs))
 			}
-		case request.EventTypeRedisServer, request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeMongoClient:
+		case request.EventTypeRedisServer, request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeMongoClient,
+			request.EventTypeMemcachedClient, request.EventTypeCassandraClient:
 			if mr.is.DBEnabled() {
 				dbClientDuration, attrs := r.dbClientDuration.ForRecord(span)
 				dbClientDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
 			}
-		case request.EventTypeKafkaClient, request.EventTypeKafkaServer:
+		case request.EventTypeKafkaClient, request.EventTypeKafkaServer, request.EventTypeAMQPClient,
+			request.EventTypeNATSClient, request.EventTypeNATSServer, request.EventTypeMQTTClient:
 			if mr.is.MQEnabled() {
 				switch span.Method {
 				case request.MessagingPublish:
@@ -843,6 +905,21 @@ func (r *Metrics) record(span *request.Span, mr *MetricsReporter) {
 					msgProcessDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
 				}
 			}
+		case request.EventTypeDNSClient:
+			if mr.is.DNSEnabled() {
+				dnsLookupDuration, attrs := r.dnsLookupDuration.ForRecord(span)
+				dnsLookupDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
+			}
+		case request.EventTypeGoGCPause:
+			if mr.is.GoRuntimeEnabled() {
+				goGCPauseDuration, attrs := r.goGCPauseDuration.ForRecord(span)
+				goGCPauseDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
+			}
+		case request.EventTypeGoScheduleLatency:
+			if mr.is.GoRuntimeEnabled() {
+				goScheduleLatency, attrs := r.goScheduleLatency.ForRecord(span)
+				goScheduleLatency.Record(ctx, duration, instrument.WithAttributeSet(attrs))
+			}
 		case request.EventTypeGPUKernelLaunch:
 			if mr.is.GPUEnabled() {
 				gcalls, attrs := r.gpuKernelCallsTotal.ForRecord(span)
@@ -1039,6 +1116,9 @@ func (r *Metrics) cleanupAllMetricsInstances() {
 	cleanupMetrics(r.ctx, r.dbClientDuration)
 	cleanupMetrics(r.ctx, r.msgPublishDuration)
 	cleanupMetrics(r.ctx, r.msgProcessDuration)
+	cleanupMetrics(r.ctx, r.dnsLookupDuration)
+	cleanupMetrics(r.ctx, r.goGCPauseDuration)
+	cleanupMetrics(r.ctx, r.goScheduleLatency)
 	cleanupMetrics(r.ctx, r.httpRequestSize)
 	cleanupMetrics(r.ctx, r.httpResponseSize)
 	cleanupMetrics(r.ctx, r.httpClientRequestSize)
@@ -16,6 +16,7 @@ import (
 
 	"go.opentelemetry.io/obi/pkg/buildinfo"
 	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/components/netolly/flow/transport"
 	"go.opentelemetry.io/obi/pkg/components/pipe/global"
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
@@ -33,6 +34,8 @@ type NetMetricsConfig struct {
 	SelectorCfg *attributes.SelectorConfig
 	// Deprecated: to be removed in Beyla 3.0 with OTEL_EBPF_NETWORK_METRICS bool flag
 	GloballyEnabled bool
+	// EgressPricesConfigured enables the egress cost metric. Otherwise, only the egress bytes are reported
+	EgressPricesConfigured bool
 }
 
 func (mc NetMetricsConfig) Enabled() bool {
@@ -73,6 +76,16 @@ func newMeterProvider(res *resource.Resource, exporter *sdkmetric.Exporter, inte
 type netMetricsExporter struct {
 	flowBytes      *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
 	interZoneBytes *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	tcpRTT         *Expirer[*ebpf.Record, metric2.Float64Histogram, float64]
+	tcpRetransmits *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	tcpResets      *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	connsOpened    *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	connsFailed    *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	connsClosed    *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	connsDuration  *Expirer[*ebpf.Record, metric2.Float64Histogram, float64]
+	drops          *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	egressBytes    *Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	egressCost     *Expirer[*ebpf.Record, metric2.Float64Counter, float64]
 	clock          *expire.CachedClock
 	expireTTL      time.Duration
 	in             <-chan []*ebpf.Record
@@ -161,14 +174,183 @@ func newMetricsExporter(
 		nme.interZoneBytes = NewExpirer[*ebpf.Record, metric2.Int64Counter, float64](ctx, bytesMetric, attrs, clock.Time, cfg.Metrics.TTL)
 	}
 
+	if cfg.Metrics.NetworkTCPHealthMetricsEnabled() {
+		if err := nme.setupTCPHealthMetrics(ctx, cfg, ebpfEvents, attrProv); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Metrics.NetworkConnectionMetricsEnabled() {
+		if err := nme.setupConnectionMetrics(ctx, cfg, ebpfEvents, attrProv); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Metrics.NetworkDropMetricsEnabled() {
+		log := log.With("metricFamily", "Drops")
+		drops, err := ebpfEvents.Int64Counter(attributes.NetworkDrops.OTEL,
+			metric2.WithDescription("total packets dropped by the kernel"),
+			metric2.WithUnit("{packet}"),
+		)
+		if err != nil {
+			log.Error("creating counter", "error", err)
+			return nil, err
+		}
+		nme.drops = NewExpirer[*ebpf.Record, metric2.Int64Counter, float64](ctx, drops,
+			attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(attributes.NetworkDrops)),
+			clock.Time, cfg.Metrics.TTL)
+	}
+
+	if cfg.Metrics.NetworkEgressCostMetricsEnabled() {
+		if err := nme.setupEgressCostMetrics(ctx, cfg, ebpfEvents, attrProv); err != nil {
+			return nil, err
+		}
+	}
+
 	nme.in = input.Subscribe()
 	return nme, nil
 }
 
+func (me *netMetricsExporter) setupTCPHealthMetrics(
+	ctx context.Context, cfg *NetMetricsConfig, ebpfEvents metric2.Meter, attrProv *attributes.AttrSelector,
+) error {
+	log := nmlog().With("metricFamily", "TCPHealth")
+	rttBuckets := cfg.Metrics.Buckets.TCPRTTHistogram
+	if len(rttBuckets) == 0 {
+		rttBuckets = otelcfg.DefaultBuckets.TCPRTTHistogram
+	}
+	rttMetric, err := ebpfEvents.Float64Histogram(attributes.NetworkTCPRTT.OTEL,
+		metric2.WithDescription("smoothed round-trip time of TCP network flows"),
+		metric2.WithUnit("s"),
+		metric2.WithExplicitBucketBoundaries(rttBuckets...),
+	)
+	if err != nil {
+		log.Error("creating histogram", "error", err)
+		return err
+	}
+	me.tcpRTT = NewExpirer[*ebpf.Record, metric2.Float64Histogram, float64](ctx, rttMetric,
+		attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(attributes.NetworkTCPRTT)),
+		me.clock.Time, cfg.Metrics.TTL)
+
+	retransmitsMetric, err := ebpfEvents.Int64Counter(attributes.NetworkTCPRetransmits.OTEL,
+		metric2.WithDescription("total retransmitted segments of TCP network flows"),
+		metric2.WithUnit("{segment}"),
+	)
+	if err != nil {
+		log.Error("creating counter", "error", err)
+		return err
+	}
+	me.tcpRetransmits = NewExpirer[*ebpf.Record, metric2.Int64Counter, float64](ctx, retransmitsMetric,
+		attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(attributes.NetworkTCPRetransmits)),
+		me.clock.Time, cfg.Metrics.TTL)
+
+	resetsMetric, err := ebpfEvents.Int64Counter(attributes.NetworkTCPResets.OTEL,
+		metric2.WithDescription("total packets with the RST flag in TCP network flows"),
+		metric2.WithUnit("{packet}"),
+	)
+	if err != nil {
+		log.Error("creating counter", "error", err)
+		return err
+	}
+	me.tcpResets = NewExpirer[*ebpf.Record, metric2.Int64Counter, float64](ctx, resetsMetric,
+		attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(attributes.NetworkTCPResets)),
+		me.clock.Time, cfg.Metrics.TTL)
+	return nil
+}
+
+func (me *netMetricsExporter) setupConnectionMetrics(
+	ctx context.Context, cfg *NetMetricsConfig, ebpfEvents metric2.Meter, attrProv *attributes.AttrSelector,
+) error {
+	log := nmlog().With("metricFamily", "Connections")
+	counters := []struct {
+		name        attributes.Name
+		description string
+		expirer     **Expirer[*ebpf.Record, metric2.Int64Counter, float64]
+	}{
+		{name: attributes.NetworkConnsOpened, description: "total established TCP connections", expirer: &me.connsOpened},
+		{name: attributes.NetworkConnsFailed, description: "total failed TCP connection attempts", expirer: &me.connsFailed},
+		{name: attributes.NetworkConnsClosed, description: "total closed TCP connections", expirer: &me.connsClosed},
+	}
+	for _, c := range counters {
+		counter, err := ebpfEvents.Int64Counter(c.name.OTEL,
+			metric2.WithDescription(c.description),
+			metric2.WithUnit("{connection}"),
+		)
+		if err != nil {
+			log.Error("creating counter", "error", err)
+			return err
+		}
+		*c.expirer = NewExpirer[*ebpf.Record, metric2.Int64Counter, float64](ctx, counter,
+			attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(c.name)),
+			me.clock.Time, cfg.Metrics.TTL)
+	}
+
+	durationBuckets := cfg.Metrics.Buckets.ConnDurationHistogram
+	if len(durationBuckets) == 0 {
+		durationBuckets = otelcfg.DefaultBuckets.ConnDurationHistogram
+	}
+	durationMetric, err := ebpfEvents.Float64Histogram(attributes.NetworkConnsDuration.OTEL,
+		metric2.WithDescription("lifetime of the closed TCP connections"),
+		metric2.WithUnit("s"),
+		metric2.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	if err != nil {
+		log.Error("creating histogram", "error", err)
+		return err
+	}
+	me.connsDuration = NewExpirer[*ebpf.Record, metric2.Float64Histogram, float64](ctx, durationMetric,
+		attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(attributes.NetworkConnsDuration)),
+		me.clock.Time, cfg.Metrics.TTL)
+	return nil
+}
+
+func (me *netMetricsExporter) setupEgressCostMetrics(
+	ctx context.Context, cfg *NetMetricsConfig, ebpfEvents metric2.Meter, attrProv *attributes.AttrSelector,
+) error {
+	log := nmlog().With("metricFamily", "EgressCost")
+	bytesMetric, err := ebpfEvents.Int64Counter(attributes.NetworkEgressBytes.OTEL,
+		metric2.WithDescription("total bytes billed as cross-zone, cross-region or internet egress traffic"),
+		metric2.WithUnit("By"),
+	)
+	if err != nil {
+		log.Error("creating counter", "error", err)
+		return err
+	}
+	me.egressBytes = NewExpirer[*ebpf.Record, metric2.Int64Counter, float64](ctx, bytesMetric,
+		attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(attributes.NetworkEgressBytes)),
+		me.clock.Time, cfg.Metrics.TTL)
+
+	if !cfg.EgressPricesConfigured {
+		log.Info("no egress prices configured. Skipping egress cost metric")
+		return nil
+	}
+
+	costMetric, err := ebpfEvents.Float64Counter(attributes.NetworkEgressCost.OTEL,
+		metric2.WithDescription("total cost of the egress traffic, according to the configured price per GB"),
+		metric2.WithUnit("{currency}"),
+	)
+	if err != nil {
+		log.Error("creating counter", "error", err)
+		return err
+	}
+	me.egressCost = NewExpirer[*ebpf.Record, metric2.Float64Counter, float64](ctx, costMetric,
+		attributes.OpenTelemetryGetters(ebpf.RecordGetters, attrProv.For(attributes.NetworkEgressCost)),
+		me.clock.Time, cfg.Metrics.TTL)
+	return nil
+}
+
 func (me *netMetricsExporter) Do(ctx context.Context) {
 	for i := range me.in {
 		me.clock.Update()
 		for _, v := range i {
+			if v.IsConnection() {
+				me.observeConnections(ctx, v)
+				continue
+			}
+			if v.IsDrop() {
+				me.observeDrops(ctx, v)
+				continue
+			}
 			if me.flowBytes != nil {
 				flowBytes, attrs := me.flowBytes.ForRecord(v)
 				flowBytes.Add(ctx, int64(v.Metrics.Bytes), metric2.WithAttributeSet(attrs))
@@ -177,6 +359,71 @@ func (me *netMetricsExporter) Do(ctx context.Context) {
 				izBytes, attrs := me.interZoneBytes.ForRecord(v)
 				izBytes.Add(ctx, int64(v.Metrics.Bytes), metric2.WithAttributeSet(attrs))
 			}
+			me.observeTCPHealth(ctx, v)
+			me.observeEgressCost(ctx, v)
+		}
+	}
+}
+
+func (me *netMetricsExporter) observeTCPHealth(ctx context.Context, v *ebpf.Record) {
+	if me.tcpRTT == nil || v.Id.TransportProtocol != uint8(transport.TCP) {
+		return
+	}
+	if v.Metrics.SrttUs != 0 {
+		rtt, attrs := me.tcpRTT.ForRecord(v)
+		rtt.Record(ctx, v.Metrics.SmoothedRTT().Seconds(), metric2.WithAttributeSet(attrs))
+	}
+	if v.Metrics.Retransmits != 0 {
+		retransmits, attrs := me.tcpRetransmits.ForRecord(v)
+		retransmits.Add(ctx, int64(v.Metrics.Retransmits), metric2.WithAttributeSet(attrs))
+	}
+	if v.Metrics.Resets != 0 {
+		resets, attrs := me.tcpResets.ForRecord(v)
+		resets.Add(ctx, int64(v.Metrics.Resets), metric2.WithAttributeSet(attrs))
+	}
+}
+
+func (me *netMetricsExporter) observeConnections(ctx context.Context, v *ebpf.Record) {
+	if me.connsOpened == nil {
+		return
+	}
+	if v.Conn.Opened != 0 {
+		opened, attrs := me.connsOpened.ForRecord(v)
+		opened.Add(ctx, int64(v.Conn.Opened), metric2.WithAttributeSet(attrs))
+	}
+	if v.Conn.Failed != 0 {
+		failed, attrs := me.connsFailed.ForRecord(v)
+		failed.Add(ctx, int64(v.Conn.Failed), metric2.WithAttributeSet(attrs))
+	}
+	if v.Conn.Closed != 0 {
+		closed, attrs := me.connsClosed.ForRecord(v)
+		closed.Add(ctx, int64(v.Conn.Closed), metric2.WithAttributeSet(attrs))
+		// connections closed within the same eviction period are observed with their average lifetime
+		duration, attrs := me.connsDuration.ForRecord(v)
+		avg := v.Conn.AvgDuration().Seconds()
+		for range v.Conn.Closed {
+			duration.Record(ctx, avg, metric2.WithAttributeSet(attrs))
 		}
 	}
 }
+
+func (me *netMetricsExporter) observeDrops(ctx context.Context, v *ebpf.Record) {
+	if me.drops == nil {
+		return
+	}
+	drops, attrs := me.drops.ForRecord(v)
+	drops.Add(ctx, int64(v.Drop.Packets), metric2.WithAttributeSet(attrs))
+}
+
+func (me *netMetricsExporter) observeEgressCost(ctx context.Context, v *ebpf.Record) {
+	if me.egressBytes == nil || v.Attrs.EgressType == "" {
+		return
+	}
+	egressBytes, attrs := me.egressBytes.ForRecord(v)
+	egressBytes.Add(ctx, int64(v.Metrics.Bytes), metric2.WithAttributeSet(attrs))
+	if me.egressCost == nil {
+		return
+	}
+	egressCost, attrs := me.egressCost.ForRecord(v)
+	egressCost.Add(ctx, v.EgressCost(), metric2.WithAttributeSet(attrs))
+}
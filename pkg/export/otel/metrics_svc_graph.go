@@ -75,6 +75,7 @@ type SvcGraphMetrics struct {
 func ReportSvcGraphMetrics(
 	ctxInfo *global.ContextInfo,
 	cfg *otelcfg.MetricsConfig,
+	selectorCfg *attributes.SelectorConfig,
 	input *msg.Queue[[]request.Span],
 	processEvents *msg.Queue[exec.ProcessEvent],
 ) swarm.InstanceFunc {
@@ -88,6 +89,7 @@ func ReportSvcGraphMetrics(
 			ctx,
 			ctxInfo,
 			cfg,
+			selectorCfg,
 			input,
 			processEvents,
 		)
@@ -103,11 +105,17 @@ func newSvcGraphMetricsReporter(
 	ctx context.Context,
 	ctxInfo *global.ContextInfo,
 	cfg *otelcfg.MetricsConfig,
+	selectorCfg *attributes.SelectorConfig,
 	input *msg.Queue[[]request.Span],
 	processEventCh *msg.Queue[exec.ProcessEvent],
 ) (*SvcGraphMetricsReporter, error) {
 	log := sglog()
 
+	attribProvider, err := attributes.NewAttrSelector(ctxInfo.MetricAttributeGroups, selectorCfg)
+	if err != nil {
+		return nil, fmt.Errorf("attributes select: %w", err)
+	}
+
 	is := instrumentations.NewInstrumentationSelection(cfg.Instrumentations)
 
 	mr := SvcGraphMetricsReporter{
@@ -117,7 +125,7 @@ func newSvcGraphMetricsReporter(
 		hostID:           ctxInfo.HostID,
 		input:            input.Subscribe(),
 		processEvents:    processEventCh.Subscribe(),
-		metricAttributes: serviceGraphGetters(),
+		metricAttributes: serviceGraphGetters(attribProvider.For(attributes.ServiceGraphRequest)),
 		log:              log,
 	}
 
@@ -272,15 +280,17 @@ func (mr *SvcGraphMetricsReporter) metricHostAttributes() attribute.Set {
 	return attribute.NewSet(attrs...)
 }
 
-func serviceGraphGetters() []attributes.Field[*request.Span, attribute.KeyValue] {
+// serviceGraphGetters returns the getters of the service graph attributes, followed
+// by the user-selected extra attributes
+func serviceGraphGetters(extraAttrs []attr.Name) []attributes.Field[*request.Span, attribute.KeyValue] {
 	return attributes.OpenTelemetryGetters(
-		request.SpanOTELGetters, []attr.Name{
+		request.SpanOTELGetters, append([]attr.Name{
 			attr.Client,
 			attr.ClientNamespace,
 			attr.Server,
 			attr.ServerNamespace,
 			attr.Source,
-		})
+		}, extraAttrs...))
 }
 
 func (r *SvcGraphMetrics) record(span *request.Span, mr *SvcGraphMetricsReporter) {
@@ -374,7 +384,7 @@ func (mr *SvcGraphMetricsReporter) onProcessEvent(pe *exec.ProcessEvent) {
 func (mr *SvcGraphMetricsReporter) onSpan(spans []request.Span) {
 	for i := range spans {
 		s := &spans[i]
-		if s.InternalSignal() {
+		if s.InternalSignal() || s.GoRuntimeSignal() {
 			continue
 		}
 		if !s.Service.ExportModes.CanExportMetrics() {
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mariomac/guara/pkg/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +18,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/components/exec"
 	"go.opentelemetry.io/obi/pkg/components/pipe/global"
 	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
@@ -36,7 +38,7 @@ func TestServiceGraphMetrics(t *testing.T) {
 	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
 	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
 
-	otelExporter := makeSvcGraphExporter(ctx, t, otlp, metrics, processEvents)
+	otelExporter := makeSvcGraphExporter(ctx, t, otlp, &attributes.SelectorConfig{}, metrics, processEvents)
 
 	require.NoError(t, err)
 	go func() {
@@ -77,8 +79,53 @@ func TestServiceGraphMetrics(t *testing.T) {
 	}, reported)
 }
 
+func TestServiceGraphMetrics_AWSAttributes(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+
+	ctx := t.Context()
+
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+
+	otelExporter := makeSvcGraphExporter(ctx, t, otlp, &attributes.SelectorConfig{
+		SelectionCfg: attributes.Selection{
+			attributes.ServiceGraphRequest.Section: attributes.InclusionLists{
+				Include: []string{"rpc.*", "aws.region"},
+			},
+		},
+	}, metrics, processEvents)
+	go otelExporter(ctx)
+
+	clientID := svc.Attrs{ProcPID: 33, UID: svc.UID{Name: "client", Instance: "the-client"}}
+	processEvents.Send(exec.ProcessEvent{
+		Type: exec.ProcessEventCreated,
+		File: &exec.FileInfo{Service: clientID, Pid: clientID.ProcPID},
+	})
+
+	metrics.Send([]request.Span{
+		{
+			Service: clientID, Type: request.EventTypeHTTPClient, Method: "POST", Status: 200,
+			Peer: "client-host", Statement: "https;sqs.us-east-1.amazonaws.com", AWSOperation: "SendMessage",
+			RequestStart: 150, End: 175,
+		},
+	})
+
+	test.Eventually(t, timeout, func(t require.TestingT) {
+		m := readChan(t, otlp.Records())
+		require.Equal(t, "traces_service_graph_request_client", m.Name)
+		assert.Equal(t, "aws-api", m.Attributes["rpc.system"])
+		assert.Equal(t, "SQS", m.Attributes["rpc.service"])
+		assert.Equal(t, "SendMessage", m.Attributes["rpc.method"])
+		assert.Equal(t, "us-east-1", m.Attributes["aws.region"])
+	})
+}
+
 func makeSvcGraphExporter(
 	ctx context.Context, t *testing.T, otlp *collector.TestCollector,
+	selectorCfg *attributes.SelectorConfig,
 	input *msg.Queue[[]request.Span],
 	processEvents *msg.Queue[exec.ProcessEvent],
 ) swarm.RunFunc {
@@ -93,7 +140,7 @@ func makeSvcGraphExporter(
 	}
 	otelExporter, err := ReportSvcGraphMetrics(
 		&global.ContextInfo{OTELMetricsExporter: &otelcfg.MetricsExporterInstancer{Cfg: mcfg}},
-		mcfg, input, processEvents)(ctx)
+		mcfg, selectorCfg, input, processEvents)(ctx)
 	require.NoError(t, err)
 
 	return otelExporter
@@ -397,6 +397,11 @@ func TestSpanMetricsDiscarded(t *testing.T) {
 			span:      request.Span{Service: svcExportSpanMetrics, Type: request.EventTypeHTTPClient, Method: "GET", Route: "/v1/traces", RequestStart: 100, End: 200},
 			discarded: true,
 		},
+		{
+			name:      "Go runtime events are not span metrics",
+			span:      request.Span{Service: svcNoExport, Type: request.EventTypeGoGCPause, RequestStart: 100, End: 200},
+			discarded: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -62,6 +62,10 @@ const (
 
 	FeatureNetwork          = "network"
 	FeatureNetworkInterZone = "network_inter_zone"
+	FeatureNetworkTCPHealth = "network_tcp_health"
+	FeatureNetworkConns     = "network_connections"
+	FeatureNetworkDrops     = "network_drops"
+	FeatureNetworkEgress    = "network_egress_cost"
 	FeatureApplication      = "application"
 	FeatureSpan             = "application_span"
 	FeatureSpanOTel         = "application_span_otel"
@@ -108,6 +112,9 @@ type Buckets struct {
 	DurationHistogram     []float64 `yaml:"duration_histogram"`
 	RequestSizeHistogram  []float64 `yaml:"request_size_histogram"`
 	ResponseSizeHistogram []float64 `yaml:"response_size_histogram"`
+	TCPRTTHistogram       []float64 `yaml:"tcp_rtt_histogram"`
+	ConnDurationHistogram []float64 `yaml:"connection_duration_histogram"`
+	GoRuntimeHistogram    []float64 `yaml:"go_runtime_histogram"`
 }
 
 var DefaultBuckets = Buckets{
@@ -117,6 +124,15 @@ var DefaultBuckets = Buckets{
 
 	RequestSizeHistogram:  []float64{0, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192},
 	ResponseSizeHistogram: []float64{0, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192},
+
+	// TCP round-trip times, in seconds
+	TCPRTTHistogram: []float64{0, 0.0001, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+
+	// TCP connection lifetimes, in seconds
+	ConnDurationHistogram: []float64{0, 0.01, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 900, 1800, 3600},
+
+	// Go GC pauses and goroutine scheduling latencies, in seconds
+	GoRuntimeHistogram: []float64{0, 0.00001, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1},
 }
 
 func GetAppResourceAttrs(hostID string, service *svc.Attrs) []attribute.KeyValue {
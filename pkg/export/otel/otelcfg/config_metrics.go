@@ -149,7 +149,8 @@ func (m *MetricsConfig) OTelMetricsEnabled() bool {
 }
 
 func (m *MetricsConfig) NetworkMetricsEnabled() bool {
-	return m.NetworkFlowBytesEnabled() || m.NetworkInterzoneMetricsEnabled()
+	return m.NetworkFlowBytesEnabled() || m.NetworkInterzoneMetricsEnabled() || m.NetworkTCPHealthMetricsEnabled() ||
+		m.NetworkConnectionMetricsEnabled() || m.NetworkDropMetricsEnabled() || m.NetworkEgressCostMetricsEnabled()
 }
 
 func (m *MetricsConfig) NetworkFlowBytesEnabled() bool {
@@ -160,6 +161,22 @@ func (m *MetricsConfig) NetworkInterzoneMetricsEnabled() bool {
 	return slices.Contains(m.Features, FeatureNetworkInterZone)
 }
 
+func (m *MetricsConfig) NetworkTCPHealthMetricsEnabled() bool {
+	return slices.Contains(m.Features, FeatureNetworkTCPHealth)
+}
+
+func (m *MetricsConfig) NetworkConnectionMetricsEnabled() bool {
+	return slices.Contains(m.Features, FeatureNetworkConns)
+}
+
+func (m *MetricsConfig) NetworkDropMetricsEnabled() bool {
+	return slices.Contains(m.Features, FeatureNetworkDrops)
+}
+
+func (m *MetricsConfig) NetworkEgressCostMetricsEnabled() bool {
+	return slices.Contains(m.Features, FeatureNetworkEgress)
+}
+
 func (m *MetricsConfig) Enabled() bool {
 	return m.EndpointEnabled() && (m.OTelMetricsEnabled() || m.AnySpanMetricsEnabled() || m.NetworkMetricsEnabled())
 }
@@ -391,6 +391,126 @@ func TestGenerateTracesAttributes(t *testing.T) {
 		ensureTraceStrAttr(t, attrs, semconv.MessagingClientIDKey, "test")
 	})
 
+	t.Run("test AMQP trace generation", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeAMQPClient, Method: "publish", Path: "orders:created", Statement: "created"}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		assert.Equal(t, 1, traces.ResourceSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().Len())
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+
+		assert.Equal(t, "orders:created publish", spans.At(0).Name())
+		assert.Equal(t, ptrace.SpanKindProducer, spans.At(0).Kind())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MessagingOpType), "publish")
+		ensureTraceStrAttr(t, attrs, semconv.MessagingSystemKey, "rabbitmq")
+		ensureTraceStrAttr(t, attrs, semconv.MessagingDestinationNameKey, "orders:created")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MessagingRabbitMQRoutingKey), "created")
+	})
+
+	t.Run("test NATS trace generation", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeNATSClient, Method: "process", Path: "orders.created"}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		assert.Equal(t, 1, traces.ResourceSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().Len())
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+
+		assert.Equal(t, "orders.created process", spans.At(0).Name())
+		assert.Equal(t, ptrace.SpanKindConsumer, spans.At(0).Kind())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MessagingOpType), "process")
+		ensureTraceStrAttr(t, attrs, semconv.MessagingSystemKey, "nats")
+		ensureTraceStrAttr(t, attrs, semconv.MessagingDestinationNameKey, "orders.created")
+	})
+
+	t.Run("test MQTT trace generation", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeMQTTClient, Method: "publish", Path: "sensors/temperature", SubType: 1, Status: 0x87}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		assert.Equal(t, 1, traces.ResourceSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().Len())
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+
+		assert.Equal(t, "sensors/temperature publish", spans.At(0).Name())
+		assert.Equal(t, ptrace.SpanKindProducer, spans.At(0).Kind())
+		assert.Equal(t, ptrace.StatusCodeError, spans.At(0).Status().Code())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MessagingOpType), "publish")
+		ensureTraceStrAttr(t, attrs, semconv.MessagingSystemKey, "mqtt")
+		ensureTraceStrAttr(t, attrs, semconv.MessagingDestinationNameKey, "sensors/temperature")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MessagingMQTTQoS), "1")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MessagingMQTTReasonCode), "135")
+	})
+
+	t.Run("test MQTT trace generation without acknowledgement", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeMQTTClient, Method: "process", Path: "sensors/temperature", Status: request.MQTTReasonCodeNone}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		assert.Equal(t, ptrace.SpanKindConsumer, spans.At(0).Kind())
+		assert.Equal(t, ptrace.StatusCodeUnset, spans.At(0).Status().Code())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MessagingMQTTQoS), "0")
+		ensureTraceAttrNotExists(t, attrs, attribute.Key(attr.MessagingMQTTReasonCode))
+	})
+
+	t.Run("test DNS trace generation", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeDNSClient, Method: "AAAA", Path: "missing.local", Host: "10.96.0.10", HostPort: 53, Status: 3}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		assert.Equal(t, "DNS AAAA", spans.At(0).Name())
+		assert.Equal(t, ptrace.SpanKindClient, spans.At(0).Kind())
+		assert.Equal(t, ptrace.StatusCodeError, spans.At(0).Status().Code())
+		assert.Equal(t, "NXDOMAIN", spans.At(0).Status().Message())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DNSQuestionName), "missing.local")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DNSQuestionType), "AAAA")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DNSResponseCode), "NXDOMAIN")
+		ensureTraceStrAttr(t, attrs, semconv.ServerAddressKey, "10.96.0.10")
+	})
+
+	t.Run("test Cassandra trace generation", func(t *testing.T) {
+		span := request.Span{
+			Type: request.EventTypeCassandraClient, Method: "SELECT", Path: "users", DBNamespace: "shop",
+			Statement: "SELECT * FROM users", Status: 1, DBError: request.DBError{ErrorCode: "0x2200", Description: "unconfigured table users"},
+		}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{attr.DBQueryText: {}})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		assert.Equal(t, 1, traces.ResourceSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().Len())
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+
+		assert.Equal(t, "SELECT users", spans.At(0).Name())
+		assert.Equal(t, ptrace.SpanKindClient, spans.At(0).Kind())
+		assert.Equal(t, ptrace.StatusCodeError, spans.At(0).Status().Code())
+		assert.Equal(t, "unconfigured table users", spans.At(0).Status().Message())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBSystemName), "cassandra")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBOperation), "SELECT")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBCollectionName), "users")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBNamespace), "shop")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBQueryText), "SELECT * FROM users")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBResponseStatusCode), "0x2200")
+	})
+
 	t.Run("test Mongo trace generation", func(t *testing.T) {
 		span := request.Span{Type: request.EventTypeMongoClient, Method: "insert", Path: "mycollection", DBNamespace: "mydatabase", Status: 0}
 		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{"db.operation.name": {}})
@@ -439,6 +559,78 @@ func TestGenerateTracesAttributes(t *testing.T) {
 		assert.Equal(t, ptrace.StatusCodeError, spans.At(0).Status().Code())
 		assert.Equal(t, "Internal MongoDB error", spans.At(0).Status().Message())
 	})
+	t.Run("test AWS SDK trace generation", func(t *testing.T) {
+		span := request.Span{
+			Type: request.EventTypeHTTPClient, Method: "POST", Path: "/", Status: 200,
+			Statement: "https;dynamodb.eu-west-1.amazonaws.com", AWSOperation: "GetItem",
+		}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		assert.Equal(t, 1, traces.ResourceSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().Len())
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+
+		assert.Equal(t, "DynamoDB.GetItem", spans.At(0).Name())
+		assert.Equal(t, ptrace.SpanKindClient, spans.At(0).Kind())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.RPCSystem), "aws-api")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.RPCService), "DynamoDB")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.RPCMethod), "GetItem")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.AWSRegion), "eu-west-1")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.HTTPRequestMethod), "POST")
+	})
+	t.Run("test HTTP client trace generation without AWS SDK", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeHTTPClient, Method: "GET", Path: "/", Status: 200, Statement: "https;example.com"}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		assert.Equal(t, "GET", spans.At(0).Name())
+
+		attrs := spans.At(0).Attributes()
+		ensureTraceAttrNotExists(t, attrs, attribute.Key(attr.RPCSystem))
+		ensureTraceAttrNotExists(t, attrs, attribute.Key(attr.AWSRegion))
+	})
+	t.Run("test Memcached trace generation", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeMemcachedClient, Method: "get", Path: "session:1234", SubType: request.MemcachedResultMiss}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		assert.Equal(t, 1, traces.ResourceSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().Len())
+		assert.Equal(t, 1, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().Len())
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+
+		assert.Equal(t, "get", spans.At(0).Name())
+		assert.Equal(t, ptrace.SpanKindClient, spans.At(0).Kind())
+
+		attrs := spans.At(0).Attributes()
+
+		assert.Equal(t, 6, attrs.Len())
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBOperation), "get")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.MemcachedKey), "session:1234")
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBSystemName), "memcached")
+		hit, ok := attrs.Get(string(attr.MemcachedHit))
+		assert.True(t, ok)
+		assert.False(t, hit.Bool())
+		assert.Equal(t, ptrace.StatusCodeUnset, spans.At(0).Status().Code())
+	})
+	t.Run("test Memcached trace generation with error", func(t *testing.T) {
+		span := request.Span{Type: request.EventTypeMemcachedClient, Method: "set", Path: "session:1234", Status: 1, DBError: request.DBError{ErrorCode: "SERVER_ERROR", Description: "SERVER_ERROR out of memory storing object"}}
+		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, "host-id", groupFromSpanAndAttributes(&span, tAttrs), reporterName)
+
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		attrs := spans.At(0).Attributes()
+
+		ensureTraceStrAttr(t, attrs, attribute.Key(attr.DBResponseStatusCode), "SERVER_ERROR")
+		ensureTraceAttrNotExists(t, attrs, attribute.Key(attr.MemcachedHit))
+		assert.Equal(t, ptrace.StatusCodeError, spans.At(0).Status().Code())
+		assert.Equal(t, "SERVER_ERROR out of memory storing object", spans.At(0).Status().Message())
+	})
 	t.Run("test env var resource attributes", func(t *testing.T) {
 		defer otelcfg.RestoreEnvAfterExecution()()
 		t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=productions,source.upstream=beyla")
@@ -751,7 +943,7 @@ func TestTracesInstrumentations(t *testing.T) {
 		{
 			name:     "all instrumentations",
 			instr:    []string{instrumentations.InstrumentationALL},
-			expected: []string{"GET /foo", "PUT /bar", "/grpcFoo", "/grpcGoo", "SELECT credentials", "SET", "GET", "important-topic publish", "important-topic process", "insert mycollection"},
+			expected: []string{"GET /foo", "PUT /bar", "/grpcFoo", "/grpcGoo", "SELECT credentials", "SET", "GET", "important-topic publish", "important-topic process", "insert mycollection", "gets", "orders:created publish", "orders.created process", "orders.created publish", "SELECT users", "sensors/temperature publish", "DNS A"},
 		},
 		{
 			name:     "http only",
@@ -798,6 +990,36 @@ func TestTracesInstrumentations(t *testing.T) {
 			instr:    []string{instrumentations.InstrumentationMongo},
 			expected: []string{"insert mycollection"},
 		},
+		{
+			name:     "memcached",
+			instr:    []string{instrumentations.InstrumentationMemcached},
+			expected: []string{"gets"},
+		},
+		{
+			name:     "amqp",
+			instr:    []string{instrumentations.InstrumentationAMQP},
+			expected: []string{"orders:created publish"},
+		},
+		{
+			name:     "nats",
+			instr:    []string{instrumentations.InstrumentationNATS},
+			expected: []string{"orders.created process", "orders.created publish"},
+		},
+		{
+			name:     "cassandra",
+			instr:    []string{instrumentations.InstrumentationCassandra},
+			expected: []string{"SELECT users"},
+		},
+		{
+			name:     "mqtt",
+			instr:    []string{instrumentations.InstrumentationMQTT},
+			expected: []string{"sensors/temperature publish"},
+		},
+		{
+			name:     "dns",
+			instr:    []string{instrumentations.InstrumentationDNS},
+			expected: []string{"DNS A"},
+		},
 	}
 
 	spans := []request.Span{
@@ -811,6 +1033,13 @@ func TestTracesInstrumentations(t *testing.T) {
 		{Type: request.EventTypeKafkaClient, Method: "process", Path: "important-topic", Statement: "test"},
 		{Type: request.EventTypeKafkaServer, Method: "publish", Path: "important-topic", Statement: "test"},
 		{Type: request.EventTypeMongoClient, Method: "insert", Path: "mycollection", DBNamespace: "mydatabase"},
+		{Type: request.EventTypeMemcachedClient, Method: "gets", Path: "session:1234"},
+		{Type: request.EventTypeAMQPClient, Method: "publish", Path: "orders:created", Statement: "created"},
+		{Type: request.EventTypeNATSClient, Method: "process", Path: "orders.created"},
+		{Type: request.EventTypeNATSServer, Method: "publish", Path: "orders.created"},
+		{Type: request.EventTypeCassandraClient, Method: "SELECT", Path: "users", DBNamespace: "shop"},
+		{Type: request.EventTypeMQTTClient, Method: "publish", Path: "sensors/temperature", Status: request.MQTTReasonCodeNone},
+		{Type: request.EventTypeDNSClient, Method: "A", Path: "example.com"},
 	}
 
 	for _, tt := range tests {
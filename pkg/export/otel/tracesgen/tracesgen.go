@@ -276,6 +276,18 @@ func acceptSpan(is instrumentations.InstrumentationSelection, span *request.Span
 		return is.KafkaEnabled()
 	case request.EventTypeMongoClient:
 		return is.MongoEnabled()
+	case request.EventTypeMemcachedClient:
+		return is.MemcachedEnabled()
+	case request.EventTypeAMQPClient:
+		return is.AMQPEnabled()
+	case request.EventTypeNATSClient, request.EventTypeNATSServer:
+		return is.NATSEnabled()
+	case request.EventTypeMQTTClient:
+		return is.MQTTEnabled()
+	case request.EventTypeCassandraClient:
+		return is.CassandraEnabled()
+	case request.EventTypeDNSClient:
+		return is.DNSEnabled()
 	case request.EventTypeManualSpan:
 		return true
 	}
@@ -285,9 +297,13 @@ func acceptSpan(is instrumentations.InstrumentationSelection, span *request.Span
 
 // TODO use semconv.DBSystemRedis when we update to OTEL semantic conventions library 1.30
 var (
-	dbSystemRedis   = attribute.String(string(attr.DBSystemName), semconv.DBSystemRedis.Value.AsString())
-	dbSystemMongo   = attribute.String(string(attr.DBSystemName), semconv.DBSystemMongoDB.Value.AsString())
-	spanMetricsSkip = attribute.Bool(string(attr.SkipSpanMetrics), true)
+	dbSystemRedis       = attribute.String(string(attr.DBSystemName), semconv.DBSystemRedis.Value.AsString())
+	dbSystemMongo       = attribute.String(string(attr.DBSystemName), semconv.DBSystemMongoDB.Value.AsString())
+	dbSystemCassandra   = attribute.String(string(attr.DBSystemName), semconv.DBSystemCassandra.Value.AsString())
+	dbSystemMemcached   = attribute.String(string(attr.DBSystemName), semconv.DBSystemMemcached.Value.AsString())
+	messagingSystemNATS = semconv.MessagingSystemKey.String("nats")
+	messagingSystemMQTT = semconv.MessagingSystemKey.String("mqtt")
+	spanMetricsSkip     = attribute.Bool(string(attr.SkipSpanMetrics), true)
 )
 
 //nolint:cyclop
@@ -336,6 +352,18 @@ func TraceAttributesSelector(span *request.Span, optionalAttrs map[attr.Name]str
 			request.HTTPRequestBodySize(int(span.RequestBodyLength())),
 			request.HTTPResponseBodySize(span.ResponseBodyLength()),
 		}
+		if aws, ok := request.SpanAWSService(span); ok {
+			attrs = append(attrs, semconv.RPCSystemKey.String(request.RPCSystemAWSAPI))
+			if aws.Name != "" {
+				attrs = append(attrs, semconv.RPCService(aws.Name))
+			}
+			if span.AWSOperation != "" {
+				attrs = append(attrs, semconv.RPCMethod(span.AWSOperation))
+			}
+			if aws.Region != "" {
+				attrs = append(attrs, request.AWSRegion(aws.Region))
+			}
+		}
 	case request.EventTypeGRPCClient:
 		attrs = []attribute.KeyValue{
 			semconv.RPCMethod(span.Path),
@@ -397,6 +425,45 @@ func TraceAttributesSelector(span *request.Span, optionalAttrs map[attr.Name]str
 			semconv.MessagingClientID(span.Statement),
 			operation,
 		}
+	case request.EventTypeAMQPClient:
+		attrs = []attribute.KeyValue{
+			request.ServerAddr(request.HostAsServer(span)),
+			request.ServerPort(span.HostPort),
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingDestinationName(span.Path),
+			request.MessagingOperationType(span.Method),
+		}
+		if span.Statement != "" {
+			attrs = append(attrs, request.MessagingRabbitMQRoutingKey(span.Statement))
+		}
+	case request.EventTypeNATSClient, request.EventTypeNATSServer:
+		attrs = []attribute.KeyValue{
+			request.ServerAddr(request.HostAsServer(span)),
+			request.ServerPort(span.HostPort),
+			messagingSystemNATS,
+			semconv.MessagingDestinationName(span.Path),
+			request.MessagingOperationType(span.Method),
+		}
+	case request.EventTypeMQTTClient:
+		attrs = []attribute.KeyValue{
+			request.ServerAddr(request.HostAsServer(span)),
+			request.ServerPort(span.HostPort),
+			messagingSystemMQTT,
+			semconv.MessagingDestinationName(span.Path),
+			request.MessagingOperationType(span.Method),
+			request.MessagingMQTTQoS(span.SubType),
+		}
+		if span.Status != request.MQTTReasonCodeNone {
+			attrs = append(attrs, request.MessagingMQTTReasonCode(span.Status))
+		}
+	case request.EventTypeDNSClient:
+		attrs = []attribute.KeyValue{
+			request.ServerAddr(request.HostAsServer(span)),
+			request.ServerPort(span.HostPort),
+			request.DNSQuestionName(span.Path),
+			request.DNSQuestionType(span.Method),
+			request.DNSResponseCode(span.Status),
+		}
 	case request.EventTypeMongoClient:
 		attrs = []attribute.KeyValue{
 			request.ServerAddr(request.HostAsServer(span)),
@@ -416,6 +483,48 @@ func TraceAttributesSelector(span *request.Span, optionalAttrs map[attr.Name]str
 		if span.DBNamespace != "" {
 			attrs = append(attrs, request.DBNamespace(span.DBNamespace))
 		}
+	case request.EventTypeMemcachedClient:
+		attrs = []attribute.KeyValue{
+			request.ServerAddr(request.HostAsServer(span)),
+			request.ServerPort(span.HostPort),
+			dbSystemMemcached,
+		}
+		if span.Method != "" {
+			attrs = append(attrs, request.DBOperationName(span.Method))
+		}
+		if span.Path != "" {
+			attrs = append(attrs, request.MemcachedKey(span.Path))
+		}
+		switch span.SubType {
+		case request.MemcachedResultHit:
+			attrs = append(attrs, request.MemcachedHit(true))
+		case request.MemcachedResultMiss:
+			attrs = append(attrs, request.MemcachedHit(false))
+		}
+		if span.Status == 1 {
+			attrs = append(attrs, request.DBResponseStatusCode(span.DBError.ErrorCode))
+		}
+	case request.EventTypeCassandraClient:
+		attrs = []attribute.KeyValue{
+			request.ServerAddr(request.HostAsServer(span)),
+			request.ServerPort(span.HostPort),
+			dbSystemCassandra,
+		}
+		if span.Method != "" {
+			attrs = append(attrs, request.DBOperationName(span.Method))
+		}
+		if span.Path != "" {
+			attrs = append(attrs, request.DBCollectionName(span.Path))
+		}
+		if _, ok := optionalAttrs[attr.DBQueryText]; ok && span.Statement != "" {
+			attrs = append(attrs, request.DBQueryText(span.Statement))
+		}
+		if span.DBNamespace != "" {
+			attrs = append(attrs, request.DBNamespace(span.DBNamespace))
+		}
+		if span.Status == 1 {
+			attrs = append(attrs, request.DBResponseStatusCode(span.DBError.ErrorCode))
+		}
 	case request.EventTypeManualSpan:
 		attrs = manualSpanAttributes(span)
 	}
@@ -429,17 +538,27 @@ func TraceAttributesSelector(span *request.Span, optionalAttrs map[attr.Name]str
 
 func spanKind(span *request.Span) trace2.SpanKind {
 	switch span.Type {
-	case request.EventTypeHTTP, request.EventTypeGRPC, request.EventTypeRedisServer, request.EventTypeKafkaServer:
+	case request.EventTypeHTTP, request.EventTypeGRPC, request.EventTypeRedisServer, request.EventTypeKafkaServer,
+		request.EventTypeNATSServer:
 		return trace2.SpanKindServer
-	case request.EventTypeHTTPClient, request.EventTypeGRPCClient, request.EventTypeSQLClient, request.EventTypeRedisClient, request.EventTypeMongoClient:
+	case request.EventTypeHTTPClient, request.EventTypeGRPCClient, request.EventTypeSQLClient, request.EventTypeRedisClient, request.EventTypeMongoClient,
+		request.EventTypeMemcachedClient, request.EventTypeCassandraClient, request.EventTypeDNSClient:
 		return trace2.SpanKindClient
-	case request.EventTypeKafkaClient:
+	case request.EventTypeKafkaClient, request.EventTypeAMQPClient:
 		switch span.Method {
 		case request.MessagingPublish:
 			return trace2.SpanKindProducer
 		case request.MessagingProcess:
 			return trace2.SpanKindConsumer
 		}
+	case request.EventTypeNATSClient, request.EventTypeMQTTClient:
+		switch span.Method {
+		case request.MessagingPublish:
+			return trace2.SpanKindProducer
+		case request.MessagingProcess:
+			return trace2.SpanKindConsumer
+		}
+		return trace2.SpanKindClient
 	}
 	return trace2.SpanKindInternal
 }
@@ -175,7 +175,8 @@ func (p *PrometheusConfig) ServiceGraphMetricsEnabled() bool {
 }
 
 func (p *PrometheusConfig) NetworkMetricsEnabled() bool {
-	return p.NetworkFlowBytesEnabled() || p.NetworkInterzoneMetricsEnabled()
+	return p.NetworkFlowBytesEnabled() || p.NetworkInterzoneMetricsEnabled() || p.NetworkTCPHealthMetricsEnabled() ||
+		p.NetworkConnectionMetricsEnabled() || p.NetworkDropMetricsEnabled() || p.NetworkEgressCostMetricsEnabled()
 }
 
 func (p *PrometheusConfig) NetworkFlowBytesEnabled() bool {
@@ -186,6 +187,22 @@ func (p *PrometheusConfig) NetworkInterzoneMetricsEnabled() bool {
 	return slices.Contains(p.Features, otelcfg.FeatureNetworkInterZone)
 }
 
+func (p *PrometheusConfig) NetworkTCPHealthMetricsEnabled() bool {
+	return slices.Contains(p.Features, otelcfg.FeatureNetworkTCPHealth)
+}
+
+func (p *PrometheusConfig) NetworkConnectionMetricsEnabled() bool {
+	return slices.Contains(p.Features, otelcfg.FeatureNetworkConns)
+}
+
+func (p *PrometheusConfig) NetworkDropMetricsEnabled() bool {
+	return slices.Contains(p.Features, otelcfg.FeatureNetworkDrops)
+}
+
+func (p *PrometheusConfig) NetworkEgressCostMetricsEnabled() bool {
+	return slices.Contains(p.Features, otelcfg.FeatureNetworkEgress)
+}
+
 func (p *PrometheusConfig) EBPFEnabled() bool {
 	return slices.Contains(p.Features, otelcfg.FeatureEBPF)
 }
@@ -213,6 +230,9 @@ type metricsReporter struct {
 	dbClientDuration       *Expirer[prometheus.Histogram]
 	msgPublishDuration     *Expirer[prometheus.Histogram]
 	msgProcessDuration     *Expirer[prometheus.Histogram]
+	dnsLookupDuration      *Expirer[prometheus.Histogram]
+	goGCPauseDuration      *Expirer[prometheus.Histogram]
+	goScheduleLatency      *Expirer[prometheus.Histogram]
 	httpRequestSize        *Expirer[prometheus.Histogram]
 	httpResponseSize       *Expirer[prometheus.Histogram]
 	httpClientRequestSize  *Expirer[prometheus.Histogram]
@@ -227,6 +247,9 @@ type metricsReporter struct {
 	attrDBClientDuration       []attributes.Field[*request.Span, string]
 	attrMsgPublishDuration     []attributes.Field[*request.Span, string]
 	attrMsgProcessDuration     []attributes.Field[*request.Span, string]
+	attrDNSLookupDuration      []attributes.Field[*request.Span, string]
+	attrGoGCPauseDuration      []attributes.Field[*request.Span, string]
+	attrGoScheduleLatency      []attributes.Field[*request.Span, string]
 	attrHTTPRequestSize        []attributes.Field[*request.Span, string]
 	attrHTTPResponseSize       []attributes.Field[*request.Span, string]
 	attrHTTPClientRequestSize  []attributes.Field[*request.Span, string]
@@ -236,6 +259,8 @@ type metricsReporter struct {
 	attrGPUKernelGridSize      []attributes.Field[*request.Span, string]
 	attrGPUKernelBlockSize     []attributes.Field[*request.Span, string]
 	attrGPUMemoryCopies        []attributes.Field[*request.Span, string]
+	// user-selected attributes, added to the fixed attributes of the service graph metrics
+	attrServiceGraph []attributes.Field[*request.Span, string]
 
 	// trace span metrics
 	spanMetricsLatency           *Expirer[prometheus.Histogram]
@@ -370,6 +395,27 @@ func newReporter(
 			attrsProvider.For(attributes.MessagingProcessDuration))
 	}
 
+	var attrDNSLookupDuration []attributes.Field[*request.Span, string]
+
+	if is.DNSEnabled() {
+		attrDNSLookupDuration = attributes.PrometheusGetters(request.SpanPromGetters,
+			attrsProvider.For(attributes.DNSLookupDuration))
+	}
+
+	var attrGoGCPauseDuration []attributes.Field[*request.Span, string]
+	var attrGoScheduleLatency []attributes.Field[*request.Span, string]
+
+	if is.GoRuntimeEnabled() {
+		attrGoGCPauseDuration = attributes.PrometheusGetters(request.SpanPromGetters,
+			attrsProvider.For(attributes.GoGCPauseDuration))
+		attrGoScheduleLatency = attributes.PrometheusGetters(request.SpanPromGetters,
+			attrsProvider.For(attributes.GoScheduleLatency))
+	}
+	goRuntimeBuckets := cfg.Buckets.GoRuntimeHistogram
+	if len(goRuntimeBuckets) == 0 {
+		goRuntimeBuckets = otelcfg.DefaultBuckets.GoRuntimeHistogram
+	}
+
 	var attrGPUKernelLaunchCalls []attributes.Field[*request.Span, string]
 	var attrGPUMemoryAllocations []attributes.Field[*request.Span, string]
 	var attrGPUKernelGridSize []attributes.Field[*request.Span, string]
@@ -389,6 +435,13 @@ func newReporter(
 			attrsProvider.For(attributes.GPUMemoryCopies))
 	}
 
+	var attrServiceGraph []attributes.Field[*request.Span, string]
+
+	if cfg.ServiceGraphMetricsEnabled() {
+		attrServiceGraph = attributes.PrometheusGetters(request.SpanPromGetters,
+			attrsProvider.For(attributes.ServiceGraphRequest))
+	}
+
 	clock := expire.NewCachedClock(timeNow)
 	kubeEnabled := ctxInfo.K8sInformer.IsKubeEnabled()
 	// If service name is not explicitly set, we take the service name as set by the
@@ -414,6 +467,9 @@ func newReporter(
 		attrDBClientDuration:       attrDBClientDuration,
 		attrMsgPublishDuration:     attrMessagingPublishDuration,
 		attrMsgProcessDuration:     attrMessagingProcessDuration,
+		attrDNSLookupDuration:      attrDNSLookupDuration,
+		attrGoGCPauseDuration:      attrGoGCPauseDuration,
+		attrGoScheduleLatency:      attrGoScheduleLatency,
 		attrHTTPRequestSize:        attrHTTPRequestSize,
 		attrHTTPResponseSize:       attrHTTPResponseSize,
 		attrHTTPClientRequestSize:  attrHTTPClientRequestSize,
@@ -423,6 +479,7 @@ func newReporter(
 		attrGPUKernelGridSize:      attrGPUKernelGridSize,
 		attrGPUKernelBlockSize:     attrGPUKernelBlockSize,
 		attrGPUMemoryCopies:        attrGPUMemoryCopies,
+		attrServiceGraph:           attrServiceGraph,
 		beylaInfo: NewExpirer[prometheus.Gauge](prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: attr.VendorPrefix + buildInfoSuffix,
 			Help: "A metric with a constant '1' value labeled by version, revision, branch, " +
@@ -506,6 +563,36 @@ func newReporter(
 				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
 			}, labelNames(attrMessagingProcessDuration)).MetricVec, clock.Time, cfg.TTL)
 		}),
+		dnsLookupDuration: optionalHistogramProvider(is.DNSEnabled(), func() *Expirer[prometheus.Histogram] {
+			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:                            attributes.DNSLookupDuration.Prom,
+				Help:                            "duration of DNS lookups, in seconds",
+				Buckets:                         cfg.Buckets.DurationHistogram,
+				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
+			}, labelNames(attrDNSLookupDuration)).MetricVec, clock.Time, cfg.TTL)
+		}),
+		goGCPauseDuration: optionalHistogramProvider(is.GoRuntimeEnabled(), func() *Expirer[prometheus.Histogram] {
+			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:                            attributes.GoGCPauseDuration.Prom,
+				Help:                            "duration of the stop-the-world pauses of the Go garbage collector, in seconds",
+				Buckets:                         goRuntimeBuckets,
+				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
+			}, labelNames(attrGoGCPauseDuration)).MetricVec, clock.Time, cfg.TTL)
+		}),
+		goScheduleLatency: optionalHistogramProvider(is.GoRuntimeEnabled(), func() *Expirer[prometheus.Histogram] {
+			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:                            attributes.GoScheduleLatency.Prom,
+				Help:                            "time that runnable goroutines wait to be scheduled, in seconds",
+				Buckets:                         goRuntimeBuckets,
+				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
+				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
+			}, labelNames(attrGoScheduleLatency)).MetricVec, clock.Time, cfg.TTL)
+		}),
 		httpRequestSize: optionalHistogramProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Histogram] {
 			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
 				Name:                            attributes.HTTPServerRequestSize.Prom,
@@ -594,7 +681,7 @@ func newReporter(
 				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
 				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
 				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNamesServiceGraph()).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesServiceGraph(attrServiceGraph)).MetricVec, clock.Time, cfg.TTL)
 		}),
 		serviceGraphServer: optionalHistogramProvider(cfg.ServiceGraphMetricsEnabled(), func() *Expirer[prometheus.Histogram] {
 			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
@@ -604,19 +691,19 @@ func newReporter(
 				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
 				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
 				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNamesServiceGraph()).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesServiceGraph(attrServiceGraph)).MetricVec, clock.Time, cfg.TTL)
 		}),
 		serviceGraphFailed: optionalCounterProvider(cfg.ServiceGraphMetricsEnabled(), func() *Expirer[prometheus.Counter] {
 			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
 				Name: ServiceGraphFailed,
 				Help: "number of failed service calls in trace service graph metrics format",
-			}, labelNamesServiceGraph()).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesServiceGraph(attrServiceGraph)).MetricVec, clock.Time, cfg.TTL)
 		}),
 		serviceGraphTotal: optionalCounterProvider(cfg.ServiceGraphMetricsEnabled(), func() *Expirer[prometheus.Counter] {
 			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
 				Name: ServiceGraphTotal,
 				Help: "number of service calls in trace service graph metrics format",
-			}, labelNamesServiceGraph()).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesServiceGraph(attrServiceGraph)).MetricVec, clock.Time, cfg.TTL)
 		}),
 		targetInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: TargetInfo,
@@ -703,6 +790,19 @@ func newReporter(
 				mr.msgPublishDuration,
 			)
 		}
+
+		if is.DNSEnabled() {
+			registeredMetrics = append(registeredMetrics,
+				mr.dnsLookupDuration,
+			)
+		}
+
+		if is.GoRuntimeEnabled() {
+			registeredMetrics = append(registeredMetrics,
+				mr.goGCPauseDuration,
+				mr.goScheduleLatency,
+			)
+		}
 	}
 
 	if cfg.SpanMetricsEnabled() {
@@ -827,7 +927,7 @@ func (r *metricsReporter) otelMetricsObserved(span *request.Span) bool {
 }
 
 func (r *metricsReporter) otelSpanMetricsObserved(span *request.Span) bool {
-	return r.cfg.AnySpanMetricsEnabled() && !span.Service.ExportsOTelMetricsSpan()
+	return r.cfg.AnySpanMetricsEnabled() && !span.Service.ExportsOTelMetricsSpan() && !span.GoRuntimeSignal()
 }
 
 func (r *metricsReporter) otelSpanFiltered(span *request.Span) bool {
@@ -887,13 +987,15 @@ func (r *metricsReporter) observe(span *request.Span) {
 					labelValues(span, r.attrGRPCClientDuration)...,
 				).Metric.Observe(duration)
 			}
-		case request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeRedisServer, request.EventTypeMongoClient:
+		case request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeRedisServer, request.EventTypeMongoClient,
+			request.EventTypeMemcachedClient, request.EventTypeCassandraClient:
 			if r.is.DBEnabled() {
 				r.dbClientDuration.WithLabelValues(
 					labelValues(span, r.attrDBClientDuration)...,
 				).Metric.Observe(duration)
 			}
-		case request.EventTypeKafkaClient, request.EventTypeKafkaServer:
+		case request.EventTypeKafkaClient, request.EventTypeKafkaServer, request.EventTypeAMQPClient,
+			request.EventTypeNATSClient, request.EventTypeNATSServer, request.EventTypeMQTTClient:
 			if r.is.MQEnabled() {
 				switch span.Method {
 				case request.MessagingPublish:
@@ -906,6 +1008,24 @@ func (r *metricsReporter) observe(span *request.Span) {
 					).Metric.Observe(duration)
 				}
 			}
+		case request.EventTypeDNSClient:
+			if r.is.DNSEnabled() {
+				r.dnsLookupDuration.WithLabelValues(
+					labelValues(span, r.attrDNSLookupDuration)...,
+				).Metric.Observe(duration)
+			}
+		case request.EventTypeGoGCPause:
+			if r.is.GoRuntimeEnabled() {
+				r.goGCPauseDuration.WithLabelValues(
+					labelValues(span, r.attrGoGCPauseDuration)...,
+				).Metric.Observe(duration)
+			}
+		case request.EventTypeGoScheduleLatency:
+			if r.is.GoRuntimeEnabled() {
+				r.goScheduleLatency.WithLabelValues(
+					labelValues(span, r.attrGoScheduleLatency)...,
+				).Metric.Observe(duration)
+			}
 		case request.EventTypeGPUKernelLaunch:
 			if r.is.GPUEnabled() {
 				r.gpuKernelCallsTotal.WithLabelValues(
@@ -1064,27 +1184,31 @@ func (r *metricsReporter) labelValuesTargetInfo(service *svc.Attrs) []string {
 	return values
 }
 
-func labelNamesServiceGraph() []string {
-	return []string{clientKey, clientNamespaceKey, serverKey, serverNamespaceKey, sourceKey}
+func labelNamesServiceGraph(extraAttrs []attributes.Field[*request.Span, string]) []string {
+	return append([]string{clientKey, clientNamespaceKey, serverKey, serverNamespaceKey, sourceKey},
+		labelNames(extraAttrs)...)
 }
 
 func (r *metricsReporter) labelValuesServiceGraph(span *request.Span) []string {
+	var values []string
 	if span.IsClientSpan() {
-		return []string{
+		values = []string{
 			request.SpanPeer(span),
 			span.Service.UID.Namespace,
 			request.SpanHost(span),
 			span.OtherNamespace,
 			attr.VendorPrefix,
 		}
+	} else {
+		values = []string{
+			request.SpanPeer(span),
+			span.OtherNamespace,
+			request.SpanHost(span),
+			span.Service.UID.Namespace,
+			attr.VendorPrefix,
+		}
 	}
-	return []string{
-		request.SpanPeer(span),
-		span.OtherNamespace,
-		request.SpanHost(span),
-		span.Service.UID.Namespace,
-		attr.VendorPrefix,
-	}
+	return append(values, labelValues(span, r.attrServiceGraph)...)
 }
 
 func labelNames[T any](getters []attributes.Field[T, string]) []string {
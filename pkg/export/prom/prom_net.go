@@ -12,9 +12,11 @@ import (
 
 	"go.opentelemetry.io/obi/pkg/components/connector"
 	"go.opentelemetry.io/obi/pkg/components/netolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/components/netolly/flow/transport"
 	"go.opentelemetry.io/obi/pkg/components/pipe/global"
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	"go.opentelemetry.io/obi/pkg/export/expire"
+	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
 	"go.opentelemetry.io/obi/pkg/pipe/swarm"
 )
@@ -27,6 +29,8 @@ type NetPrometheusConfig struct {
 	SelectorCfg *attributes.SelectorConfig
 	// Deprecated: to be removed in Beyla 3.0 with OTEL_EBPF_NETWORK_METRICS bool flag
 	GloballyEnabled bool
+	// EgressPricesConfigured enables the egress cost metric. Otherwise, only the egress bytes are reported
+	EgressPricesConfigured bool
 }
 
 // Enabled returns whether the node needs to be activated
@@ -37,13 +41,33 @@ func (p NetPrometheusConfig) Enabled() bool {
 type netMetricsReporter struct {
 	cfg *PrometheusConfig
 
-	flowBytes *Expirer[prometheus.Counter]
-	interZone *Expirer[prometheus.Counter]
+	flowBytes      *Expirer[prometheus.Counter]
+	interZone      *Expirer[prometheus.Counter]
+	tcpRTT         *Expirer[prometheus.Histogram]
+	tcpRetransmits *Expirer[prometheus.Counter]
+	tcpResets      *Expirer[prometheus.Counter]
+	connsOpened    *Expirer[prometheus.Counter]
+	connsFailed    *Expirer[prometheus.Counter]
+	connsClosed    *Expirer[prometheus.Counter]
+	connsDuration  *Expirer[prometheus.Histogram]
+	drops          *Expirer[prometheus.Counter]
+	egressBytes    *Expirer[prometheus.Counter]
+	egressCost     *Expirer[prometheus.Counter]
 
 	promConnect *connector.PrometheusManager
 
-	flowAttrs      []attributes.Field[*ebpf.Record, string]
-	interZoneAttrs []attributes.Field[*ebpf.Record, string]
+	flowAttrs           []attributes.Field[*ebpf.Record, string]
+	interZoneAttrs      []attributes.Field[*ebpf.Record, string]
+	tcpRTTAttrs         []attributes.Field[*ebpf.Record, string]
+	tcpRetransmitsAttrs []attributes.Field[*ebpf.Record, string]
+	tcpResetsAttrs      []attributes.Field[*ebpf.Record, string]
+	connsOpenedAttrs    []attributes.Field[*ebpf.Record, string]
+	connsFailedAttrs    []attributes.Field[*ebpf.Record, string]
+	connsClosedAttrs    []attributes.Field[*ebpf.Record, string]
+	connsDurationAttrs  []attributes.Field[*ebpf.Record, string]
+	dropsAttrs          []attributes.Field[*ebpf.Record, string]
+	egressBytesAttrs    []attributes.Field[*ebpf.Record, string]
+	egressCostAttrs     []attributes.Field[*ebpf.Record, string]
 
 	clock *expire.CachedClock
 
@@ -123,6 +147,123 @@ func newNetReporter(
 		register = append(register, mr.interZone)
 	}
 
+	if mr.cfg.NetworkTCPHealthMetricsEnabled() {
+		log.Debug("registering network TCP health metrics")
+		mr.tcpRTTAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkTCPRTT))
+		rttBuckets := cfg.Config.Buckets.TCPRTTHistogram
+		if len(rttBuckets) == 0 {
+			rttBuckets = otelcfg.DefaultBuckets.TCPRTTHistogram
+		}
+		mr.tcpRTT = NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:                            attributes.NetworkTCPRTT.Prom,
+			Help:                            "smoothed round-trip time of TCP flows between network endpoints, in seconds",
+			Buckets:                         rttBuckets,
+			NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
+			NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
+			NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
+		}, labelNames(mr.tcpRTTAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+
+		mr.tcpRetransmitsAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkTCPRetransmits))
+		mr.tcpRetransmits = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attributes.NetworkTCPRetransmits.Prom,
+			Help: "TCP segments retransmitted from a source network endpoint to a destination network endpoint",
+		}, labelNames(mr.tcpRetransmitsAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+
+		mr.tcpResetsAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkTCPResets))
+		mr.tcpResets = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attributes.NetworkTCPResets.Prom,
+			Help: "TCP packets with the RST flag submitted from a source network endpoint to a destination network endpoint",
+		}, labelNames(mr.tcpResetsAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+		register = append(register, mr.tcpRTT, mr.tcpRetransmits, mr.tcpResets)
+	}
+
+	if mr.cfg.NetworkConnectionMetricsEnabled() {
+		log.Debug("registering network connection metrics")
+		mr.connsOpenedAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkConnsOpened))
+		mr.connsOpened = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attributes.NetworkConnsOpened.Prom,
+			Help: "TCP connections established between a source and a destination network endpoint",
+		}, labelNames(mr.connsOpenedAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+
+		mr.connsFailedAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkConnsFailed))
+		mr.connsFailed = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attributes.NetworkConnsFailed.Prom,
+			Help: "TCP connection attempts from a source to a destination network endpoint that could not be established",
+		}, labelNames(mr.connsFailedAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+
+		mr.connsClosedAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkConnsClosed))
+		mr.connsClosed = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attributes.NetworkConnsClosed.Prom,
+			Help: "established TCP connections between a source and a destination network endpoint that have been closed",
+		}, labelNames(mr.connsClosedAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+
+		mr.connsDurationAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkConnsDuration))
+		durationBuckets := cfg.Config.Buckets.ConnDurationHistogram
+		if len(durationBuckets) == 0 {
+			durationBuckets = otelcfg.DefaultBuckets.ConnDurationHistogram
+		}
+		mr.connsDuration = NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:                            attributes.NetworkConnsDuration.Prom,
+			Help:                            "lifetime of the closed TCP connections between network endpoints, in seconds",
+			Buckets:                         durationBuckets,
+			NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
+			NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
+			NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
+		}, labelNames(mr.connsDurationAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+		register = append(register, mr.connsOpened, mr.connsFailed, mr.connsClosed, mr.connsDuration)
+	}
+
+	if mr.cfg.NetworkDropMetricsEnabled() {
+		log.Debug("registering network drop metrics")
+		mr.dropsAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkDrops))
+		mr.drops = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attributes.NetworkDrops.Prom,
+			Help: "packets from a source to a destination network endpoint that have been dropped by the kernel",
+		}, labelNames(mr.dropsAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+		register = append(register, mr.drops)
+	}
+
+	if mr.cfg.NetworkEgressCostMetricsEnabled() {
+		log.Debug("registering network egress cost metrics")
+		mr.egressBytesAttrs = attributes.PrometheusGetters(
+			ebpf.RecordStringGetters,
+			provider.For(attributes.NetworkEgressBytes))
+		mr.egressBytes = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attributes.NetworkEgressBytes.Prom,
+			Help: "bytes from a source to a destination network endpoint billed as cross-zone, cross-region or internet egress traffic",
+		}, labelNames(mr.egressBytesAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+		register = append(register, mr.egressBytes)
+
+		if cfg.EgressPricesConfigured {
+			mr.egressCostAttrs = attributes.PrometheusGetters(
+				ebpf.RecordStringGetters,
+				provider.For(attributes.NetworkEgressCost))
+			mr.egressCost = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: attributes.NetworkEgressCost.Prom,
+				Help: "cost of the egress traffic from a source to a destination network endpoint, according to the configured price per GB",
+			}, labelNames(mr.egressCostAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+			register = append(register, mr.egressCost)
+		} else {
+			log.Info("no egress prices configured. Skipping egress cost metric")
+		}
+	}
+
 	if cfg.Config.Registry != nil {
 		cfg.Config.Registry.MustRegister(register...)
 	} else {
@@ -144,8 +285,18 @@ func (r *netMetricsReporter) collectMetrics(_ context.Context) {
 		// remove the old metrics
 		r.clock.Update()
 		for _, flow := range flows {
+			if flow.IsConnection() {
+				r.observeConnections(flow)
+				continue
+			}
+			if flow.IsDrop() {
+				r.observeDrops(flow)
+				continue
+			}
 			r.observeFlowBytes(flow)
 			r.observeInterZone(flow)
+			r.observeTCPHealth(flow)
+			r.observeEgressCost(flow)
 		}
 	}
 }
@@ -165,3 +316,66 @@ func (r *netMetricsReporter) observeInterZone(flow *ebpf.Record) {
 	r.interZone.WithLabelValues(labelValues(flow, r.interZoneAttrs)...).
 		Metric.Add(float64(flow.Metrics.Bytes))
 }
+
+func (r *netMetricsReporter) observeTCPHealth(flow *ebpf.Record) {
+	if r.tcpRTT == nil || flow.Id.TransportProtocol != uint8(transport.TCP) {
+		return
+	}
+	if flow.Metrics.SrttUs != 0 {
+		r.tcpRTT.WithLabelValues(labelValues(flow, r.tcpRTTAttrs)...).
+			Metric.Observe(flow.Metrics.SmoothedRTT().Seconds())
+	}
+	if flow.Metrics.Retransmits != 0 {
+		r.tcpRetransmits.WithLabelValues(labelValues(flow, r.tcpRetransmitsAttrs)...).
+			Metric.Add(float64(flow.Metrics.Retransmits))
+	}
+	if flow.Metrics.Resets != 0 {
+		r.tcpResets.WithLabelValues(labelValues(flow, r.tcpResetsAttrs)...).
+			Metric.Add(float64(flow.Metrics.Resets))
+	}
+}
+
+func (r *netMetricsReporter) observeConnections(conn *ebpf.Record) {
+	if r.connsOpened == nil {
+		return
+	}
+	if conn.Conn.Opened != 0 {
+		r.connsOpened.WithLabelValues(labelValues(conn, r.connsOpenedAttrs)...).
+			Metric.Add(float64(conn.Conn.Opened))
+	}
+	if conn.Conn.Failed != 0 {
+		r.connsFailed.WithLabelValues(labelValues(conn, r.connsFailedAttrs)...).
+			Metric.Add(float64(conn.Conn.Failed))
+	}
+	if conn.Conn.Closed != 0 {
+		r.connsClosed.WithLabelValues(labelValues(conn, r.connsClosedAttrs)...).
+			Metric.Add(float64(conn.Conn.Closed))
+		// connections closed within the same eviction period are observed with their average lifetime
+		duration := r.connsDuration.WithLabelValues(labelValues(conn, r.connsDurationAttrs)...).Metric
+		avg := conn.Conn.AvgDuration().Seconds()
+		for range conn.Conn.Closed {
+			duration.Observe(avg)
+		}
+	}
+}
+
+func (r *netMetricsReporter) observeDrops(drop *ebpf.Record) {
+	if r.drops == nil {
+		return
+	}
+	r.drops.WithLabelValues(labelValues(drop, r.dropsAttrs)...).
+		Metric.Add(float64(drop.Drop.Packets))
+}
+
+func (r *netMetricsReporter) observeEgressCost(flow *ebpf.Record) {
+	if r.egressBytes == nil || flow.Attrs.EgressType == "" {
+		return
+	}
+	r.egressBytes.WithLabelValues(labelValues(flow, r.egressBytesAttrs)...).
+		Metric.Add(float64(flow.Metrics.Bytes))
+	if r.egressCost == nil {
+		return
+	}
+	r.egressCost.WithLabelValues(labelValues(flow, r.egressCostAttrs)...).
+		Metric.Add(flow.EgressCost())
+}
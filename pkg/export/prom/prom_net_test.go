@@ -5,6 +5,7 @@ package prom
 
 import (
 	"fmt"
+	"syscall"
 	"testing"
 	"time"
 
@@ -108,3 +109,239 @@ func TestMetricsExpiration(t *testing.T) {
 	})
 	assert.NotContains(t, exported, `obi_network_flow_bytes_total{dst_name="bar",src_name="foo"}`)
 }
+
+func TestTCPHealthMetrics(t *testing.T) {
+	ctx := t.Context()
+
+	openPort, err := test.FreeTCPPort()
+	require.NoError(t, err)
+	promURL := fmt.Sprintf("http://127.0.0.1:%d/metrics", openPort)
+
+	tcpSection := attributes.InclusionLists{Include: []string{"src_name", "dst_name"}}
+	metrics := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(20))
+	exporter, err := NetPrometheusEndpoint(
+		&global.ContextInfo{Prometheus: &connector.PrometheusManager{}},
+		&NetPrometheusConfig{Config: &PrometheusConfig{
+			Port:                        openPort,
+			Path:                        "/metrics",
+			TTL:                         time.Minute,
+			SpanMetricsServiceCacheSize: 10,
+			Features:                    []string{otelcfg.FeatureNetworkTCPHealth},
+		}, SelectorCfg: &attributes.SelectorConfig{
+			SelectionCfg: attributes.Selection{
+				attributes.NetworkTCPRTT.Section:         tcpSection,
+				attributes.NetworkTCPRetransmits.Section: tcpSection,
+				attributes.NetworkTCPResets.Section:      tcpSection,
+			},
+		}}, metrics)(ctx)
+	require.NoError(t, err)
+
+	go exporter(ctx)
+
+	tcpFlow := ebpf.NetFlowRecordT{
+		Id:      ebpf.NetFlowId{TransportProtocol: 6},
+		Metrics: ebpf.NetFlowMetrics{Bytes: 123, SrttUs: 1500, Retransmits: 3, Resets: 1},
+	}
+	metrics.Send([]*ebpf.Record{
+		{Attrs: ebpf.RecordAttrs{SrcName: "foo", DstName: "bar"}, NetFlowRecordT: tcpFlow},
+		// flows without RTT nor retransmissions only report what they have
+		{
+			Attrs: ebpf.RecordAttrs{SrcName: "baz", DstName: "bae"},
+			NetFlowRecordT: ebpf.NetFlowRecordT{
+				Id:      ebpf.NetFlowId{TransportProtocol: 6},
+				Metrics: ebpf.NetFlowMetrics{Bytes: 456, Resets: 2},
+			},
+		},
+		// non-TCP flows are ignored
+		{
+			Attrs: ebpf.RecordAttrs{SrcName: "udp", DstName: "udp"},
+			NetFlowRecordT: ebpf.NetFlowRecordT{
+				Id:      ebpf.NetFlowId{TransportProtocol: 17},
+				Metrics: ebpf.NetFlowMetrics{Bytes: 789, Resets: 1},
+			},
+		},
+	})
+
+	test.Eventually(t, timeout, func(t require.TestingT) {
+		exported := getMetrics(t, promURL)
+		assert.Contains(t, exported, `obi_network_tcp_rtt_seconds_count{dst_name="bar",src_name="foo"} 1`)
+		assert.Contains(t, exported, `obi_network_tcp_rtt_seconds_sum{dst_name="bar",src_name="foo"} 0.0015`)
+		assert.Contains(t, exported, `obi_network_tcp_retransmits_total{dst_name="bar",src_name="foo"} 3`)
+		assert.Contains(t, exported, `obi_network_tcp_resets_total{dst_name="bar",src_name="foo"} 1`)
+		assert.Contains(t, exported, `obi_network_tcp_resets_total{dst_name="bae",src_name="baz"} 2`)
+		assert.NotContains(t, exported, `obi_network_tcp_rtt_seconds_count{dst_name="bae",src_name="baz"}`)
+		assert.NotContains(t, exported, `obi_network_tcp_retransmits_total{dst_name="bae",src_name="baz"}`)
+		assert.NotContains(t, exported, `src_name="udp"`)
+		assert.NotContains(t, exported, `obi_network_flow_bytes_total`)
+	})
+}
+
+func TestConnectionMetrics(t *testing.T) {
+	ctx := t.Context()
+
+	openPort, err := test.FreeTCPPort()
+	require.NoError(t, err)
+	promURL := fmt.Sprintf("http://127.0.0.1:%d/metrics", openPort)
+
+	connSection := attributes.InclusionLists{Include: []string{"src_name", "dst_name", "error_type"}}
+	metrics := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(20))
+	exporter, err := NetPrometheusEndpoint(
+		&global.ContextInfo{Prometheus: &connector.PrometheusManager{}},
+		&NetPrometheusConfig{Config: &PrometheusConfig{
+			Port:                        openPort,
+			Path:                        "/metrics",
+			TTL:                         time.Minute,
+			SpanMetricsServiceCacheSize: 10,
+			Features:                    []string{otelcfg.FeatureNetworkConns},
+		}, SelectorCfg: &attributes.SelectorConfig{
+			SelectionCfg: attributes.Selection{
+				attributes.NetworkConnsOpened.Section:   connSection,
+				attributes.NetworkConnsFailed.Section:   connSection,
+				attributes.NetworkConnsClosed.Section:   connSection,
+				attributes.NetworkConnsDuration.Section: connSection,
+			},
+		}}, metrics)(ctx)
+	require.NoError(t, err)
+
+	go exporter(ctx)
+
+	closed := ebpf.NewConnRecord(ebpf.NetFlowId{TransportProtocol: 6},
+		ebpf.NetConnMetrics{EndMonoTimeNs: 1, Opened: 2, Closed: 2, DurationNs: uint64(3 * time.Second)})
+	closed.Attrs = ebpf.RecordAttrs{SrcName: "foo", DstName: "bar"}
+	failed := ebpf.NewConnRecord(ebpf.NetFlowId{TransportProtocol: 6},
+		ebpf.NetConnMetrics{EndMonoTimeNs: 1, Failed: 3, Errno: uint8(syscall.ECONNREFUSED)})
+	failed.Attrs = ebpf.RecordAttrs{SrcName: "baz", DstName: "bae"}
+	metrics.Send([]*ebpf.Record{closed, failed})
+
+	test.Eventually(t, timeout, func(t require.TestingT) {
+		exported := getMetrics(t, promURL)
+		assert.Contains(t, exported, `obi_network_connections_opened_total{dst_name="bar",src_name="foo"} 2`)
+		assert.Contains(t, exported, `obi_network_connections_closed_total{dst_name="bar",src_name="foo"} 2`)
+		assert.Contains(t, exported, `obi_network_connections_duration_seconds_count{dst_name="bar",src_name="foo"} 2`)
+		assert.Contains(t, exported, `obi_network_connections_duration_seconds_sum{dst_name="bar",src_name="foo"} 3`)
+		assert.Contains(t, exported, `obi_network_connections_failed_total{dst_name="bae",error_type="connection_refused",src_name="baz"} 3`)
+		assert.NotContains(t, exported, `obi_network_connections_opened_total{dst_name="bae"`)
+		assert.NotContains(t, exported, `obi_network_flow_bytes_total`)
+	})
+}
+
+func TestDropMetrics(t *testing.T) {
+	ctx := t.Context()
+
+	openPort, err := test.FreeTCPPort()
+	require.NoError(t, err)
+	promURL := fmt.Sprintf("http://127.0.0.1:%d/metrics", openPort)
+
+	metrics := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(20))
+	exporter, err := NetPrometheusEndpoint(
+		&global.ContextInfo{Prometheus: &connector.PrometheusManager{}},
+		&NetPrometheusConfig{Config: &PrometheusConfig{
+			Port:                        openPort,
+			Path:                        "/metrics",
+			TTL:                         time.Minute,
+			SpanMetricsServiceCacheSize: 10,
+			Features:                    []string{otelcfg.FeatureNetworkDrops},
+		}, SelectorCfg: &attributes.SelectorConfig{
+			SelectionCfg: attributes.Selection{
+				attributes.NetworkDrops.Section: attributes.InclusionLists{
+					Include: []string{"src_name", "drop_reason", "iface"},
+				},
+			},
+		}}, metrics)(ctx)
+	require.NoError(t, err)
+
+	go exporter(ctx)
+
+	noSocket := ebpf.NewDropRecord(ebpf.DropKey{Reason: "no_socket"},
+		ebpf.NetDropMetrics{EndMonoTimeNs: 1, Packets: 3, Bytes: 180})
+	noSocket.Attrs = ebpf.RecordAttrs{SrcName: "foo", Interface: "eth0"}
+	netfilter := ebpf.NewDropRecord(ebpf.DropKey{Reason: "netfilter_drop"},
+		ebpf.NetDropMetrics{EndMonoTimeNs: 1, Packets: 2, Bytes: 120})
+	netfilter.Attrs = ebpf.RecordAttrs{SrcName: "foo", Interface: "eth0"}
+	flow := &ebpf.Record{NetFlowRecordT: ebpf.NetFlowRecordT{Metrics: ebpf.NetFlowMetrics{Bytes: 123}}}
+	metrics.Send([]*ebpf.Record{noSocket, netfilter, flow})
+
+	test.Eventually(t, timeout, func(t require.TestingT) {
+		exported := getMetrics(t, promURL)
+		assert.Contains(t, exported, `obi_network_drops_total{drop_reason="no_socket",iface="eth0",src_name="foo"} 3`)
+		assert.Contains(t, exported, `obi_network_drops_total{drop_reason="netfilter_drop",iface="eth0",src_name="foo"} 2`)
+		assert.NotContains(t, exported, `drop_reason=""`)
+		assert.NotContains(t, exported, `obi_network_flow_bytes_total`)
+	})
+}
+
+func TestEgressCostMetrics(t *testing.T) {
+	ctx := t.Context()
+
+	openPort, err := test.FreeTCPPort()
+	require.NoError(t, err)
+	promURL := fmt.Sprintf("http://127.0.0.1:%d/metrics", openPort)
+
+	metrics := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(20))
+	exporter, err := NetPrometheusEndpoint(
+		&global.ContextInfo{Prometheus: &connector.PrometheusManager{}},
+		&NetPrometheusConfig{Config: &PrometheusConfig{
+			Port:                        openPort,
+			Path:                        "/metrics",
+			TTL:                         time.Minute,
+			SpanMetricsServiceCacheSize: 10,
+			Features:                    []string{otelcfg.FeatureNetworkEgress},
+		}, SelectorCfg: &attributes.SelectorConfig{}, EgressPricesConfigured: true}, metrics)(ctx)
+	require.NoError(t, err)
+
+	go exporter(ctx)
+
+	crossZone := &ebpf.Record{NetFlowRecordT: ebpf.NetFlowRecordT{Metrics: ebpf.NetFlowMetrics{Bytes: 1 << 30}}}
+	crossZone.Attrs = ebpf.RecordAttrs{
+		SrcZone: "us-east-1a", DstZone: "us-east-1b", SrcRegion: "us-east-1", DstRegion: "us-east-1",
+		SrcCloudZone: "us-east-1a", DstCloudZone: "us-east-1b", SrcCloudRegion: "us-east-1", DstCloudRegion: "us-east-1",
+		EgressType: "cross_zone", EgressPricePerGB: 0.01,
+	}
+	sameZone := &ebpf.Record{NetFlowRecordT: ebpf.NetFlowRecordT{Metrics: ebpf.NetFlowMetrics{Bytes: 123}}}
+	sameZone.Attrs = ebpf.RecordAttrs{SrcZone: "us-east-1a", DstZone: "us-east-1a"}
+	metrics.Send([]*ebpf.Record{crossZone, sameZone})
+
+	test.Eventually(t, timeout, func(t require.TestingT) {
+		exported := getMetrics(t, promURL)
+		labels := `{direction="response",dst_cloud_region="us-east-1",dst_cloud_zone="us-east-1b",egress_type="cross_zone",src_cloud_region="us-east-1",src_cloud_zone="us-east-1a"}`
+		assert.Contains(t, exported, `obi_network_egress_bytes_total`+labels+` 1.073741824e+09`)
+		assert.Contains(t, exported, `obi_network_egress_cost_total`+labels+` 0.01`)
+		assert.NotContains(t, exported, `dst_cloud_zone="us-east-1a"`)
+		assert.NotContains(t, exported, `obi_network_flow_bytes_total`)
+	})
+}
+
+func TestEgressCostMetrics_NoPrices(t *testing.T) {
+	ctx := t.Context()
+
+	openPort, err := test.FreeTCPPort()
+	require.NoError(t, err)
+	promURL := fmt.Sprintf("http://127.0.0.1:%d/metrics", openPort)
+
+	metrics := msg.NewQueue[[]*ebpf.Record](msg.ChannelBufferLen(20))
+	exporter, err := NetPrometheusEndpoint(
+		&global.ContextInfo{Prometheus: &connector.PrometheusManager{}},
+		&NetPrometheusConfig{Config: &PrometheusConfig{
+			Port:                        openPort,
+			Path:                        "/metrics",
+			TTL:                         time.Minute,
+			SpanMetricsServiceCacheSize: 10,
+			Features:                    []string{otelcfg.FeatureNetworkEgress},
+		}, SelectorCfg: &attributes.SelectorConfig{}}, metrics)(ctx)
+	require.NoError(t, err)
+
+	go exporter(ctx)
+
+	crossZone := &ebpf.Record{NetFlowRecordT: ebpf.NetFlowRecordT{Metrics: ebpf.NetFlowMetrics{Bytes: 1 << 30}}}
+	crossZone.Attrs = ebpf.RecordAttrs{
+		SrcZone: "us-east-1a", DstZone: "us-east-1b", SrcRegion: "us-east-1", DstRegion: "us-east-1",
+		EgressType: "cross_zone",
+	}
+	metrics.Send([]*ebpf.Record{crossZone})
+
+	test.Eventually(t, timeout, func(t require.TestingT) {
+		exported := getMetrics(t, promURL)
+		assert.Contains(t, exported, `obi_network_egress_bytes_total`)
+		assert.NotContains(t, exported, `obi_network_egress_cost_total`)
+	})
+}
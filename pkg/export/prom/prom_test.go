@@ -196,6 +196,9 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 				"db_client_operation_duration_seconds",
 				"messaging_publish_duration_seconds",
 				"messaging_process_duration_seconds",
+				"dns_lookup_duration_seconds",
+				"go_gc_pause_duration_seconds",
+				"go_schedule_latency_seconds",
 			},
 			unexpected: []string{},
 		},
@@ -334,6 +337,51 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 				"messaging_process_duration_seconds",
 			},
 		},
+		{
+			name:  "memcached",
+			instr: []string{instrumentations.InstrumentationMemcached},
+			expected: []string{
+				"db_client_operation_duration_seconds",
+			},
+			unexpected: []string{
+				"http_server_request_duration_seconds",
+				"http_client_request_duration_seconds",
+				"rpc_server_duration_seconds",
+				"rpc_client_duration_seconds",
+				"messaging_publish_duration_seconds",
+				"messaging_process_duration_seconds",
+			},
+		},
+		{
+			name:  "dns",
+			instr: []string{instrumentations.InstrumentationDNS},
+			expected: []string{
+				"dns_lookup_duration_seconds",
+			},
+			unexpected: []string{
+				"http_server_request_duration_seconds",
+				"http_client_request_duration_seconds",
+				"rpc_server_duration_seconds",
+				"rpc_client_duration_seconds",
+				"db_client_operation_duration_seconds",
+				"messaging_publish_duration_seconds",
+				"messaging_process_duration_seconds",
+			},
+		},
+		{
+			name:  "go runtime",
+			instr: []string{instrumentations.InstrumentationGoRuntime},
+			expected: []string{
+				"go_gc_pause_duration_seconds",
+				"go_schedule_latency_seconds",
+			},
+			unexpected: []string{
+				"http_server_request_duration_seconds",
+				"http_client_request_duration_seconds",
+				"db_client_operation_duration_seconds",
+				"dns_lookup_duration_seconds",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -361,6 +409,10 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 				{Service: svc.Attrs{UID: svc.UID{Instance: "foo"}}, Type: request.EventTypeKafkaClient, Method: "publish", RequestStart: 150, End: 175},
 				{Service: svc.Attrs{UID: svc.UID{Instance: "foo"}}, Type: request.EventTypeKafkaServer, Method: "process", RequestStart: 150, End: 175},
 				{Service: svc.Attrs{UID: svc.UID{Instance: "foo"}}, Type: request.EventTypeMongoClient, Method: "find", RequestStart: 150, End: 175},
+				{Service: svc.Attrs{UID: svc.UID{Instance: "foo"}}, Type: request.EventTypeMemcachedClient, Method: "get", RequestStart: 150, End: 175},
+				{Service: svc.Attrs{UID: svc.UID{Instance: "foo"}}, Type: request.EventTypeDNSClient, Method: "A", Path: "example.com", RequestStart: 150, End: 175},
+				{Service: svc.Attrs{UID: svc.UID{Instance: "foo"}}, Type: request.EventTypeGoGCPause, RequestStart: 150, End: 151},
+				{Service: svc.Attrs{UID: svc.UID{Instance: "foo"}}, Type: request.EventTypeGoScheduleLatency, RequestStart: 150, End: 152},
 			})
 
 			var exported string
@@ -467,6 +519,11 @@ func TestSpanMetricsDiscarded(t *testing.T) {
 			span:      request.Span{Service: svcExportMetricsSpan, Type: request.EventTypeHTTPClient, Method: "GET", Route: "/v1/traces", RequestStart: 100, End: 200},
 			discarded: true,
 		},
+		{
+			name:      "Go runtime events are not span metrics",
+			span:      request.Span{Service: svcNoExport, Type: request.EventTypeGoScheduleLatency, RequestStart: 100, End: 200},
+			discarded: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -716,3 +773,57 @@ func TestSanitizeUTF8ForPrometheus(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSSDKAttributes(t *testing.T) {
+	ctx := t.Context()
+	openPort, err := test.FreeTCPPort()
+	require.NoError(t, err)
+	promURL := fmt.Sprintf("http://127.0.0.1:%d/metrics", openPort)
+
+	promInput := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+	exporter, err := PrometheusEndpoint(
+		&global.ContextInfo{Prometheus: &connector.PrometheusManager{}},
+		&PrometheusConfig{
+			Port:                        openPort,
+			Path:                        "/metrics",
+			TTL:                         time.Hour,
+			SpanMetricsServiceCacheSize: 10,
+			Features:                    []string{otelcfg.FeatureApplication, otelcfg.FeatureGraph},
+			Instrumentations:            []string{instrumentations.InstrumentationALL},
+		},
+		&attributes.SelectorConfig{
+			SelectionCfg: attributes.Selection{
+				attributes.HTTPClientDuration.Section: attributes.InclusionLists{
+					Include: []string{"http.request.method", "rpc.*", "aws.region"},
+				},
+				attributes.ServiceGraphRequest.Section: attributes.InclusionLists{
+					Include: []string{"rpc.system", "rpc.service"},
+				},
+			},
+		},
+		promInput,
+		processEvents,
+	)(ctx)
+	require.NoError(t, err)
+
+	go exporter(ctx)
+
+	promInput.Send([]request.Span{{
+		Type: request.EventTypeHTTPClient, Method: "POST", Status: 200, Peer: "client-host",
+		Statement: "https;dynamodb.eu-west-1.amazonaws.com", AWSOperation: "GetItem",
+		End: 2 * time.Second.Nanoseconds(),
+	}, {
+		// plain HTTP requests to AWS endpoints aren't AWS API calls
+		Type: request.EventTypeHTTPClient, Method: "GET", Status: 200, Peer: "client-host",
+		Statement: "https;my-bucket.s3.eu-west-1.amazonaws.com",
+		End:       3 * time.Second.Nanoseconds(),
+	}})
+
+	test.Eventually(t, timeout, func(t require.TestingT) {
+		exported := getMetrics(t, promURL)
+		assert.Contains(t, exported, `http_client_request_duration_seconds_sum{aws_region="eu-west-1",http_request_method="POST",rpc_method="GetItem",rpc_service="DynamoDB",rpc_system="aws-api"} 2`)
+		assert.Contains(t, exported, `http_client_request_duration_seconds_sum{aws_region="",http_request_method="GET",rpc_method="",rpc_service="",rpc_system=""} 3`)
+		assert.Regexp(t, `traces_service_graph_request_client_seconds_sum\{.*rpc_service="DynamoDB",rpc_system="aws-api".*\} 2`, exported)
+	})
+}
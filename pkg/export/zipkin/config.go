@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package zipkin
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/services"
+)
+
+type TracesConfig struct {
+	// Endpoint is the URL of the Zipkin v2 spans API (e.g. http://zipkin:9411/api/v2/spans).
+	// The Zipkin exporter is disabled if empty.
+	Endpoint string `yaml:"endpoint" env:"OTEL_EBPF_ZIPKIN_TRACES_ENDPOINT"`
+	// Headers are added to each HTTP request submitted to the Zipkin endpoint
+	Headers map[string]string `yaml:"headers" env:"OTEL_EBPF_ZIPKIN_TRACES_HEADERS" envSeparator:","`
+
+	// Allows configuration of which instrumentations should be enabled, e.g. http, grpc, sql...
+	Instrumentations []string `yaml:"instrumentations" env:"OTEL_EBPF_ZIPKIN_TRACES_INSTRUMENTATIONS" envSeparator:","`
+
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify" env:"OTEL_EBPF_ZIPKIN_INSECURE_SKIP_VERIFY"`
+
+	SamplerConfig services.SamplerConfig `yaml:"sampler"`
+
+	// Timeout of each HTTP request to the Zipkin endpoint
+	Timeout time.Duration `yaml:"timeout" env:"OTEL_EBPF_ZIPKIN_TRACES_TIMEOUT"`
+	// MaxBatchSize is the number of spans that triggers the submission of a batch
+	MaxBatchSize int `yaml:"max_batch_size" env:"OTEL_EBPF_ZIPKIN_TRACES_MAX_BATCH_SIZE"`
+	// BatchTimeout is the maximum time that a span is kept in a batch before being submitted
+	BatchTimeout time.Duration `yaml:"batch_timeout" env:"OTEL_EBPF_ZIPKIN_TRACES_BATCH_TIMEOUT"`
+	// MaxPendingBatches is the number of batches that can wait for submission. When
+	// it is reached, e.g. because the Zipkin endpoint is down, new batches are dropped.
+	MaxPendingBatches int `yaml:"max_pending_batches" env:"OTEL_EBPF_ZIPKIN_TRACES_MAX_PENDING_BATCHES"`
+
+	// BackOffInitialInterval the time to wait after the first failure before retrying.
+	BackOffInitialInterval time.Duration `yaml:"backoff_initial_interval" env:"OTEL_EBPF_ZIPKIN_BACKOFF_INITIAL_INTERVAL"`
+	// BackOffMaxInterval is the upper bound on backoff interval.
+	BackOffMaxInterval time.Duration `yaml:"backoff_max_interval" env:"OTEL_EBPF_ZIPKIN_BACKOFF_MAX_INTERVAL"`
+	// BackOffMaxElapsedTime is the maximum amount of time (including retries) spent trying to send a batch.
+	BackOffMaxElapsedTime time.Duration `yaml:"backoff_max_elapsed_time" env:"OTEL_EBPF_ZIPKIN_BACKOFF_MAX_ELAPSED_TIME"`
+}
+
+// Enabled specifies that the Zipkin traces node is enabled if and only if
+// the Zipkin endpoint is defined.
+func (c *TracesConfig) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+func (c *TracesConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid zipkin_traces_export endpoint: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid zipkin_traces_export endpoint %q: it must be an http or https URL", c.Endpoint)
+	}
+	if c.MaxBatchSize <= 0 {
+		return errors.New("zipkin_traces_export max_batch_size must be greater than zero")
+	}
+	if c.BatchTimeout <= 0 {
+		return errors.New("zipkin_traces_export batch_timeout must be greater than zero")
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package zipkin provides an export node that submits the traces to a Zipkin backend,
+// using the Zipkin v2 JSON format.
+package zipkin
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	expirable2 "github.com/hashicorp/golang-lru/v2/expirable"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/pipe/global"
+	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+	"go.opentelemetry.io/obi/pkg/export/otel/tracesgen"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+const reporterName = "go.opentelemetry.io/obi"
+
+func zlog() *slog.Logger {
+	return slog.With("component", "zipkin.TracesReceiver")
+}
+
+// errPermanent wraps the errors that won't be fixed by retrying the submission
+type errPermanent struct{ error }
+
+func (e errPermanent) Unwrap() error { return e.error }
+
+// TracesReceiver creates a terminal node that consumes request.Spans and submits them,
+// in batches, to the configured Zipkin endpoint.
+func TracesReceiver(
+	ctxInfo *global.ContextInfo,
+	cfg *TracesConfig,
+	selectorCfg *attributes.SelectorConfig,
+	input *msg.Queue[[]request.Span],
+) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled() {
+			return swarm.EmptyRunFunc()
+		}
+		traceAttrs, err := tracesgen.UserSelectedAttributes(selectorCfg)
+		if err != nil {
+			return nil, fmt.Errorf("selecting user trace attributes: %w", err)
+		}
+		tr := &tracesReceiver{
+			cfg:            cfg,
+			ctxInfo:        ctxInfo,
+			is:             instrumentations.NewInstrumentationSelection(cfg.Instrumentations),
+			traceAttrs:     traceAttrs,
+			sampler:        cfg.SamplerConfig.Implementation(),
+			attributeCache: expirable2.NewLRU[svc.UID, []attribute.KeyValue](1024, nil, 5*time.Minute),
+			input:          input.Subscribe(),
+			client: &http.Client{
+				Timeout: cfg.Timeout,
+				Transport: &http.Transport{
+					Proxy: http.ProxyFromEnvironment,
+					//nolint:gosec
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify},
+				},
+			},
+			batches: make(chan []Span, max(cfg.MaxPendingBatches, 1)),
+		}
+		return tr.run, nil
+	}
+}
+
+type tracesReceiver struct {
+	cfg            *TracesConfig
+	ctxInfo        *global.ContextInfo
+	is             instrumentations.InstrumentationSelection
+	traceAttrs     map[attr.Name]struct{}
+	sampler        trace.Sampler
+	attributeCache *expirable2.LRU[svc.UID, []attribute.KeyValue]
+	input          <-chan []request.Span
+	client         *http.Client
+
+	batch []Span
+	// batches pending to be submitted. The submission is done in a separate goroutine so
+	// the retries on a failing endpoint don't block the rest of the pipeline
+	batches chan []Span
+}
+
+func (tr *tracesReceiver) run(ctx context.Context) {
+	senderDone := make(chan struct{})
+	go func() {
+		defer close(senderDone)
+		for batch := range tr.batches {
+			tr.submit(ctx, batch)
+		}
+	}()
+	defer func() {
+		close(tr.batches)
+		<-senderDone
+	}()
+
+	ticker := time.NewTicker(tr.cfg.BatchTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case spans, ok := <-tr.input:
+			if !ok {
+				tr.flush()
+				return
+			}
+			tr.processSpans(ctx, spans)
+			if len(tr.batch) >= tr.cfg.MaxBatchSize {
+				tr.flush()
+			}
+		case <-ticker.C:
+			tr.flush()
+		}
+	}
+}
+
+func (tr *tracesReceiver) processSpans(ctx context.Context, spans []request.Span) {
+	spanGroups := tracesgen.GroupSpans(ctx, spans, tr.traceAttrs, tr.sampler, tr.is)
+	for _, spanGroup := range spanGroups {
+		if len(spanGroup) == 0 {
+			continue
+		}
+		service := &spanGroup[0].Span.Service
+		if !service.ExportModes.CanExportTraces() {
+			continue
+		}
+		traces := tracesgen.GenerateTracesWithAttributes(tr.attributeCache, service, otelcfg.ResourceAttrsFromEnv(service),
+			tr.ctxInfo.HostID, spanGroup, reporterName, tr.ctxInfo.ExtraResourceAttributes...)
+		tr.batch = append(tr.batch, ConvertTraces(traces, spanGroup)...)
+	}
+}
+
+func (tr *tracesReceiver) flush() {
+	if len(tr.batch) == 0 {
+		return
+	}
+	select {
+	case tr.batches <- tr.batch:
+	default:
+		zlog().Warn("too many pending Zipkin batches. Dropping spans", "spans", len(tr.batch))
+	}
+	tr.batch = nil
+}
+
+// submit sends the batch to the Zipkin endpoint, retrying with exponential backoff
+// until it succeeds, a permanent error is returned, or the maximum elapsed time is reached
+func (tr *tracesReceiver) submit(ctx context.Context, batch []Span) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		zlog().Error("can't serialize Zipkin spans", "error", err)
+		return
+	}
+	start := time.Now()
+	backoff := tr.cfg.BackOffInitialInterval
+	for {
+		err := tr.post(ctx, body)
+		if err == nil {
+			return
+		}
+		var perm errPermanent
+		if errors.As(err, &perm) {
+			zlog().Error("Zipkin endpoint rejected the spans", "spans", len(batch), "error", err)
+			return
+		}
+		if backoff <= 0 || time.Since(start)+backoff > tr.cfg.BackOffMaxElapsedTime {
+			zlog().Error("error sending spans to Zipkin. Dropping them", "spans", len(batch), "error", err)
+			return
+		}
+		zlog().Debug("error sending spans to Zipkin. Retrying", "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, max(tr.cfg.BackOffMaxInterval, tr.cfg.BackOffInitialInterval))
+	}
+}
+
+func (tr *tracesReceiver) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tr.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errPermanent{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range tr.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := tr.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	if resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500 {
+		return err
+	}
+	return errPermanent{err}
+}
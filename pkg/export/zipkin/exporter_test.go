@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package zipkin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/pipe/global"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+const testTimeout = 5 * time.Second
+
+func testConfig(endpoint string) *TracesConfig {
+	return &TracesConfig{
+		Endpoint:               endpoint,
+		Headers:                map[string]string{"X-Tenant": "acme"},
+		Instrumentations:       []string{instrumentations.InstrumentationALL},
+		Timeout:                time.Second,
+		MaxBatchSize:           2,
+		BatchTimeout:           time.Hour,
+		MaxPendingBatches:      10,
+		BackOffInitialInterval: time.Millisecond,
+		BackOffMaxInterval:     5 * time.Millisecond,
+		BackOffMaxElapsedTime:  time.Second,
+	}
+}
+
+func testSpan(path string) request.Span {
+	return request.Span{
+		Type:         request.EventTypeHTTP,
+		Method:       "GET",
+		Path:         path,
+		Status:       200,
+		RequestStart: 1000,
+		Start:        1000,
+		End:          2000,
+		Service:      testService,
+	}
+}
+
+func startReceiver(t *testing.T, cfg *TracesConfig) *msg.Queue[[]request.Span] {
+	t.Helper()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	run, err := TracesReceiver(&global.ContextInfo{HostID: "host-id"}, cfg, &attributes.SelectorConfig{}, input)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+	return input
+}
+
+func TestTracesReceiver(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(2)
+	received := make(chan []Span, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "acme", req.Header.Get("X-Tenant"))
+		// the first submissions fail, and must be retried
+		if failures.Add(-1) >= 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var spans []Span
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&spans))
+		received <- spans
+		rw.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	input := startReceiver(t, testConfig(server.URL+"/api/v2/spans"))
+	// the batch is not submitted until it reaches the maximum size
+	input.Send([]request.Span{testSpan("/foo")})
+	input.Send([]request.Span{testSpan("/bar"), {Type: request.EventTypeProcessAlive}})
+
+	select {
+	case spans := <-received:
+		require.Len(t, spans, 2)
+		assert.Equal(t, "GET", spans[0].Name)
+		assert.Equal(t, "/foo", spans[0].Tags["url.path"])
+		assert.Equal(t, "/bar", spans[1].Tags["url.path"])
+	case <-time.After(testTimeout):
+		require.Fail(t, "timeout while waiting for the Zipkin spans")
+	}
+	assert.Equal(t, int32(-1), failures.Load())
+}
+
+func TestTracesReceiver_PermanentError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	input := startReceiver(t, testConfig(server.URL))
+	input.Send([]request.Span{testSpan("/foo"), testSpan("/bar")})
+	input.Send([]request.Span{testSpan("/baz"), testSpan("/qux")})
+
+	// client errors are not retried, and the following batches are still submitted
+	assert.Eventually(t, func() bool { return requests.Load() >= 2 }, testTimeout, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestTracesConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&TracesConfig{}).Validate())
+	assert.NoError(t, testConfig("https://zipkin:9411/api/v2/spans").Validate())
+	assert.Error(t, testConfig("zipkin:9411").Validate())
+	assert.Error(t, testConfig("ftp://zipkin:9411").Validate())
+	cfg := testConfig("http://zipkin:9411/api/v2/spans")
+	cfg.MaxBatchSize = 0
+	assert.Error(t, cfg.Validate())
+}
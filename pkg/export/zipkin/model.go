@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package zipkin
+
+import (
+	"net"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/export/otel/tracesgen"
+)
+
+// Span in the Zipkin v2 JSON model.
+// See https://zipkin.io/zipkin-api/#/default/post_spans
+type Span struct {
+	TraceID        string            `json:"traceId"`
+	ID             string            `json:"id"`
+	ParentID       string            `json:"parentId,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Kind           string            `json:"kind,omitempty"`
+	Timestamp      int64             `json:"timestamp,omitempty"`
+	Duration       int64             `json:"duration,omitempty"`
+	LocalEndpoint  *Endpoint         `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *Endpoint         `json:"remoteEndpoint,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+}
+
+// Endpoint in the Zipkin v2 JSON model
+type Endpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+	IPv4        string `json:"ipv4,omitempty"`
+	IPv6        string `json:"ipv6,omitempty"`
+	Port        int    `json:"port,omitempty"`
+}
+
+const (
+	tagStatusCode = "otel.status_code"
+	tagError      = "error"
+)
+
+// ConvertTraces converts the traces that tracesgen.GenerateTracesWithAttributes generates from
+// the provided group of spans into Zipkin spans. The group must be the same that was used to
+// generate the traces, as the endpoints are taken from the original spans.
+func ConvertTraces(traces ptrace.Traces, group []tracesgen.TraceSpanAndAttributes) []Span {
+	var out []Span
+	for r := 0; r < traces.ResourceSpans().Len(); r++ {
+		rs := traces.ResourceSpans().At(r)
+		serviceName := ""
+		resourceTags := map[string]string{}
+		rs.Resource().Attributes().Range(func(k string, v pcommon.Value) bool {
+			if k == string(semconv.ServiceNameKey) {
+				serviceName = v.AsString()
+			} else {
+				resourceTags[k] = v.AsString()
+			}
+			return true
+		})
+		// GenerateTracesWithAttributes creates a ScopeSpans for each span in the group, which
+		// contains the synthetic sub-spans, if any, followed by the span of the request itself
+		for s := 0; s < rs.ScopeSpans().Len(); s++ {
+			spans := rs.ScopeSpans().At(s).Spans()
+			var reqSpan *request.Span
+			if s < len(group) {
+				reqSpan = group[s].Span
+			}
+			for i := 0; i < spans.Len(); i++ {
+				zs := convertSpan(spans.At(i), serviceName, resourceTags)
+				if reqSpan != nil && i == spans.Len()-1 {
+					setEndpoints(&zs, reqSpan)
+				}
+				out = append(out, zs)
+			}
+		}
+	}
+	return out
+}
+
+func convertSpan(s ptrace.Span, serviceName string, resourceTags map[string]string) Span {
+	zs := Span{
+		TraceID:       s.TraceID().String(),
+		ID:            s.SpanID().String(),
+		Name:          s.Name(),
+		Kind:          kind(s.Kind()),
+		LocalEndpoint: &Endpoint{ServiceName: serviceName},
+		Tags:          make(map[string]string, s.Attributes().Len()+len(resourceTags)+2),
+	}
+	if !s.ParentSpanID().IsEmpty() {
+		zs.ParentID = s.ParentSpanID().String()
+	}
+	if start := s.StartTimestamp().AsTime(); s.StartTimestamp() != 0 {
+		zs.Timestamp = start.UnixMicro()
+		// Zipkin requires a minimum duration of 1 microsecond
+		zs.Duration = max(s.EndTimestamp().AsTime().Sub(start).Microseconds(), 1)
+	}
+	for k, v := range resourceTags {
+		zs.Tags[k] = v
+	}
+	s.Attributes().Range(func(k string, v pcommon.Value) bool {
+		zs.Tags[k] = v.AsString()
+		return true
+	})
+	if s.Status().Code() == ptrace.StatusCodeError {
+		zs.Tags[tagStatusCode] = "ERROR"
+		zs.Tags[tagError] = s.Status().Message()
+		if zs.Tags[tagError] == "" {
+			zs.Tags[tagError] = "true"
+		}
+	}
+	return zs
+}
+
+func kind(k ptrace.SpanKind) string {
+	switch k {
+	case ptrace.SpanKindServer:
+		return "SERVER"
+	case ptrace.SpanKindClient:
+		return "CLIENT"
+	case ptrace.SpanKindProducer:
+		return "PRODUCER"
+	case ptrace.SpanKindConsumer:
+		return "CONSUMER"
+	}
+	return ""
+}
+
+// setEndpoints sets the IP and port of the local endpoint, and the remote endpoint.
+// Peer and PeerName always refer to the client side of the connection, so they
+// are the remote endpoint of server spans and the local endpoint of client spans.
+func setEndpoints(zs *Span, span *request.Span) {
+	if span.IsClientSpan() {
+		setAddress(zs.LocalEndpoint, span.Peer, span.PeerPort)
+		zs.RemoteEndpoint = endpoint(span.HostName, span.Host, span.HostPort)
+	} else {
+		setAddress(zs.LocalEndpoint, span.Host, span.HostPort)
+		zs.RemoteEndpoint = endpoint(span.PeerName, span.Peer, span.PeerPort)
+	}
+}
+
+func endpoint(name, addr string, port int) *Endpoint {
+	ep := &Endpoint{ServiceName: name}
+	if !setAddress(ep, addr, port) && ep.ServiceName == "" {
+		// addresses that aren't IPs (e.g. hostnames) can only be reported as service names
+		ep.ServiceName = addr
+	}
+	if *ep == (Endpoint{}) {
+		return nil
+	}
+	return ep
+}
+
+// setAddress sets the IP and port of the endpoint, and returns false if the address isn't an IP
+func setAddress(ep *Endpoint, addr string, port int) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ep.IPv4 = ip4.String()
+	} else {
+		ep.IPv6 = ip.String()
+	}
+	if port > 0 && port <= 65535 {
+		ep.Port = port
+	}
+	return true
+}
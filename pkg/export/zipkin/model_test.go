@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package zipkin
+
+import (
+	"testing"
+	"time"
+
+	expirable2 "github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	trace2 "go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/tracesgen"
+)
+
+var testService = svc.Attrs{UID: svc.UID{Name: "checkout", Namespace: "shop", Instance: "checkout-1"}}
+
+func convert(t *testing.T, spans ...request.Span) []Span {
+	t.Helper()
+	groups := tracesgen.GroupSpans(t.Context(), spans, nil, trace.AlwaysSample(),
+		instrumentations.NewInstrumentationSelection([]string{instrumentations.InstrumentationALL}))
+	require.Len(t, groups, 1)
+	group := groups[testService.UID]
+	traces := tracesgen.GenerateTracesWithAttributes(
+		expirable2.NewLRU[svc.UID, []attribute.KeyValue](10, nil, time.Minute),
+		&testService, nil, "host-id", group, reporterName)
+	return ConvertTraces(traces, group)
+}
+
+func TestConvertTraces_ServerSpan(t *testing.T) {
+	traceID, _ := trace2.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	parentID, _ := trace2.SpanIDFromHex("b7ad6b7169203331")
+	zs := convert(t, request.Span{
+		Type:         request.EventTypeHTTP,
+		Method:       "GET",
+		Path:         "/cart",
+		Route:        "/cart",
+		Status:       500,
+		Peer:         "10.0.0.2",
+		PeerName:     "frontend",
+		PeerPort:     43210,
+		Host:         "10.0.0.1",
+		HostPort:     8080,
+		RequestStart: 1_000_000,
+		Start:        2_000_000,
+		End:          5_000_000,
+		TraceID:      traceID,
+		ParentSpanID: parentID,
+		Service:      testService,
+	})
+
+	// the synthetic sub-spans come before the request span
+	require.Len(t, zs, 3)
+	inQueue, processing, server := zs[0], zs[1], zs[2]
+
+	assert.Equal(t, "in queue", inQueue.Name)
+	assert.Equal(t, "processing", processing.Name)
+	for _, sub := range []Span{inQueue, processing} {
+		assert.Empty(t, sub.Kind)
+		assert.Equal(t, server.ID, sub.ParentID)
+		assert.Equal(t, server.TraceID, sub.TraceID)
+		assert.Nil(t, sub.RemoteEndpoint)
+		assert.Equal(t, "checkout", sub.LocalEndpoint.ServiceName)
+	}
+	assert.Equal(t, int64(1000), inQueue.Duration)
+	assert.Equal(t, int64(3000), processing.Duration)
+
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", server.TraceID)
+	assert.Equal(t, "b7ad6b7169203331", server.ParentID)
+	assert.Equal(t, "GET /cart", server.Name)
+	assert.Equal(t, "SERVER", server.Kind)
+	assert.Equal(t, inQueue.Timestamp, server.Timestamp)
+	assert.Equal(t, int64(4000), server.Duration)
+	assert.Equal(t, &Endpoint{ServiceName: "checkout", IPv4: "10.0.0.1", Port: 8080}, server.LocalEndpoint)
+	assert.Equal(t, &Endpoint{ServiceName: "frontend", IPv4: "10.0.0.2", Port: 43210}, server.RemoteEndpoint)
+	assert.Equal(t, "GET", server.Tags["http.request.method"])
+	assert.Equal(t, "500", server.Tags["http.response.status_code"])
+	assert.Equal(t, "shop", server.Tags["service.namespace"])
+	assert.Equal(t, "ERROR", server.Tags[tagStatusCode])
+	assert.Equal(t, "true", server.Tags[tagError])
+	assert.NotContains(t, server.Tags, "service.name")
+}
+
+func TestConvertTraces_ClientSpan(t *testing.T) {
+	zs := convert(t, request.Span{
+		Type:         request.EventTypeHTTPClient,
+		Method:       "POST",
+		Path:         "/charge",
+		Status:       200,
+		Peer:         "10.0.0.1",
+		PeerPort:     51234,
+		Host:         "payments.shop.svc",
+		HostPort:     80,
+		RequestStart: 1_000_000,
+		Start:        1_000_000,
+		End:          2_500_000,
+		Service:      testService,
+	})
+
+	require.Len(t, zs, 1)
+	client := zs[0]
+	assert.Equal(t, "CLIENT", client.Kind)
+	assert.Empty(t, client.ParentID)
+	assert.Equal(t, int64(1500), client.Duration)
+	assert.Equal(t, &Endpoint{ServiceName: "checkout", IPv4: "10.0.0.1", Port: 51234}, client.LocalEndpoint)
+	// host names that are not IPs are reported as the remote service name
+	assert.Equal(t, &Endpoint{ServiceName: "payments.shop.svc"}, client.RemoteEndpoint)
+	assert.NotContains(t, client.Tags, tagError)
+}
+
+func TestEndpoint(t *testing.T) {
+	assert.Nil(t, endpoint("", "", 0))
+	assert.Equal(t, &Endpoint{IPv6: "2001:db8::1", Port: 443}, endpoint("", "2001:db8::1", 443))
+	assert.Equal(t, &Endpoint{ServiceName: "db", IPv4: "192.168.1.10"}, endpoint("db", "::ffff:192.168.1.10", 0))
+}
@@ -6,6 +6,7 @@ package filter
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/gobwas/glob"
 
@@ -33,9 +34,23 @@ func ByAttribute[T any](
 	extraGroupAttributeCfg map[string][]attr.Name,
 	getters attributes.NamedGetters[T, string],
 	input, output *msg.Queue[[]T],
+) swarm.InstanceFunc {
+	return ReloadableByAttribute(config, nil, extraDefinitionsProvider, extraGroupAttributeCfg, getters, input, output)
+}
+
+// ReloadableByAttribute works as ByAttribute, but it also listens for new filter configurations
+// in the updates queue, which replace the current filters without restarting the node.
+// The updates queue can be nil.
+func ReloadableByAttribute[T any](
+	config AttributeFamilyConfig,
+	updates *msg.Queue[AttributeFamilyConfig],
+	extraDefinitionsProvider func(groups attributes.AttrGroups, extraGroupAttributes attributes.GroupAttributes) map[attributes.Section]attributes.AttrReportGroup,
+	extraGroupAttributeCfg map[string][]attr.Name,
+	getters attributes.NamedGetters[T, string],
+	input, output *msg.Queue[[]T],
 ) swarm.InstanceFunc {
 	return func(_ context.Context) (swarm.RunFunc, error) {
-		if len(config) == 0 {
+		if len(config) == 0 && updates == nil {
 			// No filter configuration provided. The node will be ignored
 			return swarm.Bypass(input, output)
 		}
@@ -43,6 +58,9 @@ func ByAttribute[T any](
 		if err != nil {
 			return nil, err
 		}
+		if updates != nil {
+			f.updates = updates.Subscribe()
+		}
 		return f.doFilter, nil
 	}
 }
@@ -51,6 +69,9 @@ type filter[T any] struct {
 	matchers []Matcher[T]
 	input    <-chan []T
 	output   *msg.Queue[[]T]
+
+	updates       <-chan AttributeFamilyConfig
+	buildMatchers func(config AttributeFamilyConfig) ([]Matcher[T], error)
 }
 
 func newFilter[T any](
@@ -70,20 +91,32 @@ func newFilter[T any](
 	for normalizedName := range attributes.AllAttributeNames(extraDefinitionsProvider, extraGroupAttributesCfg) {
 		attrProm2Normal[normalizedName.Prom()] = normalizedName
 	}
-	// Validate and build Matcher implementations for the user-provided attributes.
-	var matchers []Matcher[T]
-	for attrStr, match := range config {
-		normalAttr, ok := attrProm2Normal[attr.Name(attrStr).Prom()]
-		if !ok {
-			return nil, fmt.Errorf("attribute filter: unknown attribute name %q", attrStr)
-		}
-		matcher, err := buildMatcher(getters, normalAttr, &match)
-		if err != nil {
-			return nil, fmt.Errorf("trying to filter by attribute %s: %w", attrStr, err)
+	buildMatchers := func(config AttributeFamilyConfig) ([]Matcher[T], error) {
+		// Validate and build Matcher implementations for the user-provided attributes.
+		var matchers []Matcher[T]
+		for attrStr, match := range config {
+			normalAttr, ok := attrProm2Normal[attr.Name(attrStr).Prom()]
+			if !ok {
+				return nil, fmt.Errorf("attribute filter: unknown attribute name %q", attrStr)
+			}
+			matcher, err := buildMatcher(getters, normalAttr, &match)
+			if err != nil {
+				return nil, fmt.Errorf("trying to filter by attribute %s: %w", attrStr, err)
+			}
+			matchers = append(matchers, matcher)
 		}
-		matchers = append(matchers, matcher)
+		return matchers, nil
 	}
-	return &filter[T]{matchers: matchers, input: input.Subscribe(), output: output}, nil
+	matchers, err := buildMatchers(config)
+	if err != nil {
+		return nil, err
+	}
+	return &filter[T]{
+		matchers:      matchers,
+		input:         input.Subscribe(),
+		output:        output,
+		buildMatchers: buildMatchers,
+	}, nil
 }
 
 // buildMatcher returns a Matcher given an attribute name, the user-provided MatchDefinition, and the provided
@@ -119,13 +152,38 @@ func (f *filter[T]) doFilter(_ context.Context) {
 	// output channel must be closed so later stages in the pipeline can finish in cascade
 	defer f.output.Close()
 
-	for i := range f.input {
-		if i = f.filterBatch(i); len(i) > 0 {
-			f.output.Send(i)
+	for {
+		select {
+		case cfg, ok := <-f.updates:
+			if !ok {
+				f.updates = nil
+				continue
+			}
+			f.reload(cfg)
+		case i, ok := <-f.input:
+			if !ok {
+				return
+			}
+			if i = f.filterBatch(i); len(i) > 0 {
+				f.output.Send(i)
+			}
 		}
 	}
 }
 
+// reload replaces the current matchers. If the new configuration is
+// invalid, the previous matchers are kept.
+func (f *filter[T]) reload(cfg AttributeFamilyConfig) {
+	log := slog.With("component", "filter.ByAttribute")
+	matchers, err := f.buildMatchers(cfg)
+	if err != nil {
+		log.Error("can't reload attribute filters. Keeping the previous ones", "error", err)
+		return
+	}
+	log.Info("attribute filters reloaded", "filters", len(matchers))
+	f.matchers = matchers
+}
+
 // filterBatch removes from the input slice the records that do not match
 // the user-provided attribute matchers
 func (f *filter[T]) filterBatch(batch []T) []T {
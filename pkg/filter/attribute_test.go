@@ -244,3 +244,61 @@ func TestAttributeFilter_SpanMetrics(t *testing.T) {
 		// ok!!
 	}
 }
+
+func TestAttributeFilter_Reload(t *testing.T) {
+	input := msg.NewQueue[[]*request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]*request.Span](msg.ChannelBufferLen(10))
+	updates := msg.NewQueue[AttributeFamilyConfig](msg.ChannelBufferLen(10))
+	filterFunc, err := ReloadableByAttribute[*request.Span](AttributeFamilyConfig{
+		"server": MatchDefinition{NotMatch: "filtered"},
+	}, updates, nil, map[string][]attr.Name{}, request.SpanPromGetters, input, output)(t.Context())
+	require.NoError(t, err)
+
+	out := output.Subscribe()
+	go filterFunc(t.Context())
+
+	batch := func() []*request.Span {
+		return []*request.Span{
+			{Type: request.EventTypeHTTP, Host: "allowed"},
+			{Type: request.EventTypeHTTP, Host: "other"},
+		}
+	}
+	input.Send(batch())
+	assert.Len(t, testutil.ReadChannel(t, out, timeout), 2)
+
+	// invalid configurations are ignored
+	updates.Send(AttributeFamilyConfig{"unexisting_attribute": MatchDefinition{Match: "foo"}})
+	updates.Send(AttributeFamilyConfig{"server": MatchDefinition{Match: "allowed"}})
+	assert.Eventually(t, func() bool {
+		input.Send(batch())
+		return len(testutil.ReadChannel(t, out, timeout)) == 1
+	}, timeout, 10*time.Millisecond)
+
+	// an empty configuration removes all the filters
+	updates.Send(AttributeFamilyConfig{})
+	assert.Eventually(t, func() bool {
+		input.Send(batch())
+		return len(testutil.ReadChannel(t, out, timeout)) == 2
+	}, timeout, 10*time.Millisecond)
+}
+
+func TestAttributeFilter_ReloadWithoutInitialConfig(t *testing.T) {
+	input := msg.NewQueue[[]*request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]*request.Span](msg.ChannelBufferLen(10))
+	updates := msg.NewQueue[AttributeFamilyConfig](msg.ChannelBufferLen(10))
+	filterFunc, err := ReloadableByAttribute[*request.Span](nil, updates, nil, map[string][]attr.Name{},
+		request.SpanPromGetters, input, output)(t.Context())
+	require.NoError(t, err)
+
+	out := output.Subscribe()
+	go filterFunc(t.Context())
+
+	updates.Send(AttributeFamilyConfig{"server": MatchDefinition{NotMatch: "filtered"}})
+	assert.Eventually(t, func() bool {
+		input.Send([]*request.Span{
+			{Type: request.EventTypeHTTP, Host: "filtered"},
+			{Type: request.EventTypeHTTP, Host: "other"},
+		})
+		return len(testutil.ReadChannel(t, out, timeout)) == 1
+	}, timeout, 10*time.Millisecond)
+}
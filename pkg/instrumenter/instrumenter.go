@@ -45,6 +45,13 @@ func Run(
 	// if one of nodes fail, the other should stop
 	g, ctx := errgroup.WithContext(ctx)
 
+	if cfg.ConfigReload.Enabled {
+		// must be created before building the pipelines, so their nodes
+		// can listen for the configuration changes
+		reloader := obi.NewConfigReloader(cfg, cfg.ConfigReload.Path)
+		go reloader.Run(ctx, cfg.ConfigReload.PollInterval)
+	}
+
 	if app {
 		g.Go(func() error {
 			if err := setupAppO11y(ctx, ctxInfo, cfg); err != nil {
@@ -169,8 +176,10 @@ func buildCommonContextInfo(
 			ResyncPeriod:        config.Attributes.Kubernetes.InformersResyncPeriod,
 			DisabledInformers:   config.Attributes.Kubernetes.DisableInformers,
 			MetaCacheAddr:       config.Attributes.Kubernetes.MetaCacheAddress,
+			MetaCacheTLS:        config.Attributes.Kubernetes.MetaCacheTLS,
 			ResourceLabels:      resourceLabels,
 			RestrictLocalNode:   config.Attributes.Kubernetes.MetaRestrictLocalNode,
+			Namespaces:          config.Attributes.Kubernetes.MetaNamespaces,
 			ServiceNameTemplate: templ,
 		}),
 		OTELMetricsExporter: &otelcfg.MetricsExporterInstancer{Cfg: &config.Metrics},
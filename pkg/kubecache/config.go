@@ -21,7 +21,8 @@ type Config struct {
 	LogLevel string `yaml:"log_level" env:"OTEL_EBPF_K8S_CACHE_LOG_LEVEL"`
 	// Port where the service is going to listen to
 	Port int `yaml:"port" env:"OTEL_EBPF_K8S_CACHE_PORT"`
-	// MaxConnection is the maximum number of concurrent clients that the service can handle at the same time
+	// MaxConnection is the maximum number of concurrent clients that the service can handle at the same time.
+	// Further clients are rejected, so they can connect to another replica of the service.
 	MaxConnections int `yaml:"max_connections" env:"OTEL_EBPF_K8S_CACHE_MAX_CONNECTIONS"`
 	// ProfilePort is the port where the pprof server is going to listen to. 0 (default) means disabled
 	ProfilePort int `yaml:"profile_port" env:"OTEL_EBPF_K8S_CACHE_PROFILE_PORT"`
@@ -29,6 +30,9 @@ type Config struct {
 	InformerResyncPeriod time.Duration `yaml:"informer_resync_period" env:"OTEL_EBPF_K8S_CACHE_INFORMER_RESYNC_PERIOD"`
 
 	InternalMetrics instrument.InternalMetricsConfig `yaml:"internal_metrics"`
+
+	// TLS configures the secure connections with the clients
+	TLS ServerTLSConfig `yaml:"tls"`
 }
 
 var DefaultConfig = Config{
@@ -59,5 +63,8 @@ func LoadConfig(file io.Reader) (*Config, error) {
 	if err := env.Parse(&cfg); err != nil {
 		return nil, fmt.Errorf("reading env vars: %w", err)
 	}
+	if err := cfg.TLS.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
 	return &cfg, nil
 }
@@ -21,6 +21,12 @@ type InternalMetrics interface {
 
 	ClientConnect()
 	ClientDisconnect()
+	// ClientReject accounts a client that is rejected because the service reached its maximum connections
+	ClientReject()
+	// ClientBacklog sets the number of messages that are pending to be sent to a given client
+	ClientBacklog(clientID string, messages int)
+	// ClientBacklogRemove stops reporting the backlog of a disconnected client
+	ClientBacklogRemove(clientID string)
 
 	MessageSubmit()
 	MessageSucceed()
@@ -30,20 +36,25 @@ type InternalMetrics interface {
 
 type noopMetrics struct{}
 
-func (n noopMetrics) InformerNew()      {}
-func (n noopMetrics) InformerUpdate()   {}
-func (n noopMetrics) InformerDelete()   {}
-func (n noopMetrics) ClientConnect()    {}
-func (n noopMetrics) ClientDisconnect() {}
-func (n noopMetrics) MessageSubmit()    {}
-func (n noopMetrics) MessageSucceed()   {}
-func (n noopMetrics) MessageTimeout()   {}
-func (n noopMetrics) MessageError()     {}
+func (n noopMetrics) InformerNew()               {}
+func (n noopMetrics) InformerUpdate()            {}
+func (n noopMetrics) InformerDelete()            {}
+func (n noopMetrics) ClientConnect()             {}
+func (n noopMetrics) ClientDisconnect()          {}
+func (n noopMetrics) ClientReject()              {}
+func (n noopMetrics) ClientBacklog(string, int)  {}
+func (n noopMetrics) ClientBacklogRemove(string) {}
+func (n noopMetrics) MessageSubmit()             {}
+func (n noopMetrics) MessageSucceed()            {}
+func (n noopMetrics) MessageTimeout()            {}
+func (n noopMetrics) MessageError()              {}
 
 type promInternalMetrics struct {
 	connector        *connector.PrometheusManager
 	informerEvents   *prometheus.CounterVec
 	connectedClients prometheus.Gauge
+	rejectedClients  prometheus.Counter
+	clientBacklog    *prometheus.GaugeVec
 	clientMessages   *prometheus.CounterVec
 	beylaCacheInfo   prometheus.Gauge
 }
@@ -62,6 +73,15 @@ func prometheusInternalMetrics(
 			Name: attr.VendorPrefix + "_kube_cache_connected_clients",
 			Help: "How many concurrent Beyla instances are connected to this cache service",
 		}),
+		rejectedClients: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: attr.VendorPrefix + "_kube_cache_rejected_clients_total",
+			Help: "How many Beyla instances have been rejected because the cache service" +
+				" reached its maximum number of connections",
+		}),
+		clientBacklog: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: attr.VendorPrefix + "_kube_cache_client_backlog_messages",
+			Help: "How many notifications are pending to be submitted to each subscriber client",
+		}, []string{"client"}),
 		clientMessages: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: attr.VendorPrefix + "_kube_cache_client_messages_total",
 			Help: "How many notifications have been started to be submitted to" +
@@ -84,6 +104,8 @@ func prometheusInternalMetrics(
 	manager.Register(cfg.Port, cfg.Path,
 		pr.informerEvents,
 		pr.connectedClients,
+		pr.rejectedClients,
+		pr.clientBacklog,
 		pr.clientMessages,
 		pr.beylaCacheInfo)
 
@@ -110,6 +132,18 @@ func (n *promInternalMetrics) ClientDisconnect() {
 	n.connectedClients.Dec()
 }
 
+func (n *promInternalMetrics) ClientReject() {
+	n.rejectedClients.Inc()
+}
+
+func (n *promInternalMetrics) ClientBacklog(clientID string, messages int) {
+	n.clientBacklog.WithLabelValues(clientID).Set(float64(messages))
+}
+
+func (n *promInternalMetrics) ClientBacklogRemove(clientID string) {
+	n.clientBacklog.DeleteLabelValues(clientID)
+}
+
 func (n *promInternalMetrics) MessageSubmit() {
 	n.clientMessages.WithLabelValues("submit").Inc()
 }
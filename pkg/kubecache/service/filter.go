@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/obi/pkg/kubecache/informer"
+)
+
+const kindPod = "Pod"
+
+// subscriptionFilter selects the events that are sent to a client, according to the
+// node name, namespaces and kinds provided in its informer.SubscribeMessage.
+// The node name and namespaces only restrict the objects that might be decorated by the
+// client. The objects outside them are still sent if they have IPs, as they are
+// required to decorate the peers of the decorated objects.
+type subscriptionFilter struct {
+	nodeName   string
+	namespaces map[string]struct{}
+	kinds      map[string]struct{}
+
+	// peers that have been sent to the client, so their updates and deletions
+	// are still forwarded even if they don't have IPs anymore
+	mt        sync.Mutex
+	sentPeers map[objectKey]struct{}
+}
+
+type objectKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func newSubscriptionFilter(msg *informer.SubscribeMessage) *subscriptionFilter {
+	return &subscriptionFilter{
+		nodeName:   msg.GetNodeName(),
+		namespaces: toSet(msg.GetNamespaces()),
+		kinds:      toSet(msg.GetKinds()),
+		sentPeers:  map[objectKey]struct{}{},
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// accept returns whether the event has to be sent to the client
+func (f *subscriptionFilter) accept(event *informer.Event) bool {
+	if event.Type == informer.EventType_SYNC_FINISHED {
+		return true
+	}
+	res := event.GetResource()
+	if f.kinds != nil {
+		if _, ok := f.kinds[res.GetKind()]; !ok {
+			return false
+		}
+	}
+	if f.isDecorated(res) {
+		return true
+	}
+	return f.acceptPeer(event)
+}
+
+// isDecorated returns whether the object is in the node and namespaces whose metadata
+// is used by the client to decorate its instrumented processes
+func (f *subscriptionFilter) isDecorated(res *informer.ObjectMeta) bool {
+	// nodes are not namespaced
+	if f.namespaces != nil && res.GetNamespace() != "" {
+		if _, ok := f.namespaces[res.GetNamespace()]; !ok {
+			return false
+		}
+	}
+	return f.nodeName == "" || res.GetKind() != kindPod || res.GetPod().GetNodeName() == f.nodeName
+}
+
+// acceptPeer only accepts the objects from other nodes or namespaces that have IPs, as they
+// are only needed to resolve peers by IP. Pods using the host network don't have their own IPs,
+// so they are resolved through their Node.
+func (f *subscriptionFilter) acceptPeer(event *informer.Event) bool {
+	key := objectKey{kind: event.Resource.Kind, namespace: event.Resource.Namespace, name: event.Resource.Name}
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	_, sent := f.sentPeers[key]
+	switch {
+	case event.Type == informer.EventType_DELETED:
+		delete(f.sentPeers, key)
+		return sent
+	case len(event.Resource.Ips) > 0:
+		f.sentPeers[key] = struct{}{}
+		return true
+	default:
+		return sent
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/obi/pkg/kubecache/informer"
+)
+
+func podEvent(tp informer.EventType, namespace, name, node string, ips ...string) *informer.Event {
+	return &informer.Event{Type: tp, Resource: &informer.ObjectMeta{
+		Name: name, Namespace: namespace, Kind: "Pod", Ips: ips,
+		Pod: &informer.PodInfo{NodeName: node},
+	}}
+}
+
+func TestSubscriptionFilter_Unfiltered(t *testing.T) {
+	f := newSubscriptionFilter(&informer.SubscribeMessage{})
+	assert.True(t, f.accept(podEvent(informer.EventType_CREATED, "ns", "pod", "node-2")))
+	assert.True(t, f.accept(&informer.Event{Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{Name: "node-2", Kind: "Node", Ips: []string{"10.0.0.2"}}}))
+	assert.True(t, f.accept(&informer.Event{Type: informer.EventType_SYNC_FINISHED}))
+}
+
+func TestSubscriptionFilter_NodeName(t *testing.T) {
+	f := newSubscriptionFilter(&informer.SubscribeMessage{NodeName: "node-1"})
+
+	// local pods are always accepted
+	assert.True(t, f.accept(podEvent(informer.EventType_CREATED, "ns", "local", "node-1")))
+	assert.True(t, f.accept(podEvent(informer.EventType_DELETED, "ns", "local", "node-1")))
+
+	// remote pods are only accepted if they have IPs
+	assert.False(t, f.accept(podEvent(informer.EventType_CREATED, "ns", "hostnet", "node-2")))
+	assert.False(t, f.accept(podEvent(informer.EventType_DELETED, "ns", "hostnet", "node-2")))
+	assert.False(t, f.accept(podEvent(informer.EventType_CREATED, "ns", "remote", "node-2")))
+	assert.True(t, f.accept(podEvent(informer.EventType_UPDATED, "ns", "remote", "node-2", "10.1.0.3")))
+	// once sent, they are forwarded until they are deleted, even if they lose their IPs
+	assert.True(t, f.accept(podEvent(informer.EventType_UPDATED, "ns", "remote", "node-2")))
+	assert.True(t, f.accept(podEvent(informer.EventType_DELETED, "ns", "remote", "node-2")))
+	assert.False(t, f.accept(podEvent(informer.EventType_UPDATED, "ns", "remote", "node-2")))
+
+	// services and nodes are required to resolve peers by IP
+	assert.True(t, f.accept(&informer.Event{Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{Name: "svc", Namespace: "ns", Kind: "Service", Ips: []string{"10.2.0.1"}}}))
+	assert.True(t, f.accept(&informer.Event{Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{Name: "node-2", Kind: "Node", Ips: []string{"10.0.0.2"}}}))
+}
+
+func TestSubscriptionFilter_NamespacesAndKinds(t *testing.T) {
+	f := newSubscriptionFilter(&informer.SubscribeMessage{
+		Namespaces: []string{"shop", "payments"},
+		Kinds:      []string{"Pod", "Node"},
+	})
+	assert.True(t, f.accept(podEvent(informer.EventType_CREATED, "shop", "pod", "node-1")))
+	assert.True(t, f.accept(podEvent(informer.EventType_CREATED, "payments", "pod", "node-1")))
+	assert.True(t, f.accept(podEvent(informer.EventType_CREATED, "shop", "hostnet", "node-2")))
+	// services are filtered by kind
+	assert.False(t, f.accept(&informer.Event{Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{Name: "svc", Namespace: "shop", Kind: "Service", Ips: []string{"10.2.0.1"}}}))
+
+	// pods from other namespaces are only accepted if they have IPs, as they can be peers
+	assert.False(t, f.accept(podEvent(informer.EventType_CREATED, "kube-system", "pod", "node-1")))
+	assert.True(t, f.accept(podEvent(informer.EventType_UPDATED, "kube-system", "pod", "node-1", "10.1.0.4")))
+	assert.True(t, f.accept(podEvent(informer.EventType_UPDATED, "kube-system", "pod", "node-1")))
+	assert.True(t, f.accept(podEvent(informer.EventType_DELETED, "kube-system", "pod", "node-1")))
+	// nodes are not namespaced
+	assert.True(t, f.accept(&informer.Event{Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{Name: "node-1", Kind: "Node"}}))
+	assert.True(t, f.accept(&informer.Event{Type: informer.EventType_SYNC_FINISHED}))
+}
+
+func TestSubscriptionFilter_CrossNamespacePeers(t *testing.T) {
+	f := newSubscriptionFilter(&informer.SubscribeMessage{
+		NodeName:   "node-1",
+		Namespaces: []string{"shop"},
+	})
+	// peers from other namespaces and nodes are required to resolve them by IP
+	assert.True(t, f.accept(podEvent(informer.EventType_CREATED, "payments", "pod", "node-2", "10.1.0.5")))
+	assert.True(t, f.accept(&informer.Event{Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{Name: "svc", Namespace: "payments", Kind: "Service", Ips: []string{"10.2.0.2"}}}))
+	assert.False(t, f.accept(&informer.Event{Type: informer.EventType_CREATED,
+		Resource: &informer.ObjectMeta{Name: "headless", Namespace: "payments", Kind: "Service"}}))
+	// a pod and a service with the same name are tracked separately
+	assert.False(t, f.accept(&informer.Event{Type: informer.EventType_DELETED,
+		Resource: &informer.ObjectMeta{Name: "pod", Namespace: "payments", Kind: "Service"}}))
+	assert.True(t, f.accept(podEvent(informer.EventType_DELETED, "payments", "pod", "node-2")))
+}
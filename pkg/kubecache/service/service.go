@@ -13,7 +13,11 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/obi/pkg/components/helpers/sync"
 	"go.opentelemetry.io/obi/pkg/kubecache"
@@ -38,6 +42,10 @@ type InformersCache struct {
 	SendTimeout time.Duration
 
 	metrics instrument.InternalMetrics
+
+	// number of subscribed clients, to reject new clients after reaching the Config.MaxConnections
+	// limit, so they can connect to another replica of the service
+	clients atomic.Int32
 }
 
 func (ic *InformersCache) Run(ctx context.Context, opts ...meta.InformerOption) error {
@@ -60,10 +68,18 @@ func (ic *InformersCache) Run(ctx context.Context, opts ...meta.InformerOption)
 		return fmt.Errorf("initializing informers: %w", err)
 	}
 
-	s := grpc.NewServer(
-		// TODO: configure other aspects (e.g. secure connections)
+	serverOpts := []grpc.ServerOption{
 		grpc.MaxConcurrentStreams(uint32(ic.Config.MaxConnections)),
-	)
+	}
+	if ic.Config.TLS.Enabled() {
+		tlsConfig, err := ic.Config.TLS.ServerConfig()
+		if err != nil {
+			return fmt.Errorf("configuring TLS: %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		ic.log.Info("TLS enabled", "mutualTLS", ic.Config.TLS.ClientCAFile != "")
+	}
+	s := grpc.NewServer(serverOpts...)
 	informer.RegisterEventStreamServiceServer(s, ic)
 
 	ic.log.Info("server listening", "port", ic.Config.Port)
@@ -90,6 +106,20 @@ func (ic *InformersCache) Subscribe(msg *informer.SubscribeMessage, server infor
 	if !ok {
 		return errors.New("failed to extract peer information")
 	}
+	if clients := int(ic.clients.Add(1)); ic.Config.MaxConnections > 0 && clients > ic.Config.MaxConnections {
+		ic.clients.Add(-1)
+		ic.metrics.ClientReject()
+		ic.log.Warn("maximum number of connections reached. Rejecting client",
+			"id", p.Addr.String(), "maxConnections", ic.Config.MaxConnections)
+		return status.Errorf(codes.ResourceExhausted,
+			"maximum number of connections reached (%d)", ic.Config.MaxConnections)
+	}
+	defer ic.clients.Add(-1)
+	// lets the client know that the subscription has been accepted, even if there are no
+	// events to send yet
+	if err := server.SendHeader(metadata.MD{}); err != nil {
+		return fmt.Errorf("sending headers to client %s: %w", p.Addr.String(), err)
+	}
 	ic.metrics.ClientConnect()
 	o := &connection{
 		log:         ic.log.With("clientID", p.Addr.String()),
@@ -98,15 +128,20 @@ func (ic *InformersCache) Subscribe(msg *informer.SubscribeMessage, server infor
 		sendTimeout: ic.SendTimeout,
 		metrics:     ic.metrics,
 		fromEpoch:   msg.GetFromTimestampEpoch(),
+		filter:      newSubscriptionFilter(msg),
 		messages:    sync.NewQueue[*informer.Event](),
 	}
 	ic.log.Info("client subscribed", "id", o.ID(),
 		"fromEpoch", o.fromEpoch,
-		"fromLast", time.Since(time.Unix(o.fromEpoch, 0)))
+		"fromLast", time.Since(time.Unix(o.fromEpoch, 0)),
+		"nodeName", msg.GetNodeName(),
+		"namespaces", msg.GetNamespaces(),
+		"kinds", msg.GetKinds())
 	ic.informers.Subscribe(o)
 	// Keep the connection open
 	o.handleMessagesQueue(server.Context())
 	ic.informers.Unsubscribe(o)
+	ic.metrics.ClientBacklogRemove(o.ID())
 	ic.metrics.ClientDisconnect()
 	ic.log.Info("client disconnected", "id", o.ID())
 	return nil
@@ -125,7 +160,11 @@ type connection struct {
 	metrics instrument.InternalMetrics
 	// fromEpoch filters events whose timestamp is lower than its value
 	fromEpoch int64
-	messages  *sync.Queue[*informer.Event]
+	// filter selects the events according to the client's node, namespaces and kinds
+	filter   *subscriptionFilter
+	messages *sync.Queue[*informer.Event]
+	// number of enqueued messages that haven't been sent yet
+	backlog atomic.Int64
 }
 
 func (o *connection) ID() string {
@@ -144,8 +183,12 @@ func (o *connection) On(event *informer.Event) error {
 	if event.Type != informer.EventType_SYNC_FINISHED && event.Resource.StatusTimeEpoch < o.fromEpoch {
 		return nil
 	}
+	if !o.filter.accept(event) {
+		return nil
+	}
 	o.metrics.MessageSubmit()
 	o.messages.Enqueue(event)
+	o.metrics.ClientBacklog(o.ID(), int(o.backlog.Add(1)))
 	return nil
 }
 
@@ -157,6 +200,7 @@ func (o *connection) handleMessagesQueue(ctx context.Context) {
 			return
 		default:
 			event := o.messages.Dequeue()
+			o.metrics.ClientBacklog(o.ID(), int(o.backlog.Add(-1)))
 			if err := o.server.Send(event); err != nil {
 				o.log.Debug("Error sending message. Closing client connection", "clientID", o.ID(), "error", err)
 				o.metrics.MessageError()
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	hsync "go.opentelemetry.io/obi/pkg/components/helpers/sync"
+	"go.opentelemetry.io/obi/pkg/kubecache"
+	"go.opentelemetry.io/obi/pkg/kubecache/informer"
+	"go.opentelemetry.io/obi/pkg/kubecache/instrument"
+)
+
+func TestSubscribe_RejectsAfterMaxConnections(t *testing.T) {
+	metrics := &backlogMetrics{backlog: map[string]int{}}
+	ic := &InformersCache{
+		Config:  &kubecache.Config{MaxConnections: 2},
+		log:     slog.Default(),
+		metrics: metrics,
+	}
+	ic.clients.Store(2)
+
+	err := ic.Subscribe(&informer.SubscribeMessage{}, &fakeStream{ctx: peerContext(t)})
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, 1, metrics.rejected)
+	// the rejected client is not accounted
+	assert.EqualValues(t, 2, ic.clients.Load())
+}
+
+func TestConnection_Backlog(t *testing.T) {
+	metrics := &backlogMetrics{backlog: map[string]int{}}
+	sent := make(chan *informer.Event, 10)
+	o := &connection{
+		log:      slog.Default(),
+		id:       "client-1",
+		server:   &fakeStream{ctx: t.Context(), sent: sent},
+		metrics:  metrics,
+		filter:   newSubscriptionFilter(&informer.SubscribeMessage{}),
+		messages: hsync.NewQueue[*informer.Event](),
+	}
+	require.NoError(t, o.On(podEvent(informer.EventType_CREATED, "ns", "pod-1", "node-1")))
+	require.NoError(t, o.On(podEvent(informer.EventType_CREATED, "ns", "pod-2", "node-1")))
+	assert.Equal(t, 2, metrics.clientBacklog("client-1"))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	go o.handleMessagesQueue(ctx)
+	<-sent
+	<-sent
+	assert.Eventually(t, func() bool { return metrics.clientBacklog("client-1") == 0 }, timeout, tick)
+	cancel()
+	// unblock the queue so the sender loop can observe the cancelled context
+	o.messages.Enqueue(&informer.Event{Type: informer.EventType_SYNC_FINISHED})
+}
+
+const (
+	timeout = 5 * time.Second
+	tick    = 10 * time.Millisecond
+)
+
+func peerContext(t *testing.T) context.Context {
+	return peer.NewContext(t.Context(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}})
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *informer.Event
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) Send(event *informer.Event) error {
+	f.sent <- event
+	return nil
+}
+
+type backlogMetrics struct {
+	instrument.InternalMetrics
+	mt       sync.Mutex
+	rejected int
+	backlog  map[string]int
+}
+
+func (m *backlogMetrics) ClientReject()   { m.rejected++ }
+func (m *backlogMetrics) MessageSubmit()  {}
+func (m *backlogMetrics) MessageSucceed() {}
+
+func (m *backlogMetrics) ClientBacklog(clientID string, messages int) {
+	m.mt.Lock()
+	defer m.mt.Unlock()
+	m.backlog[clientID] = messages
+}
+
+func (m *backlogMetrics) clientBacklog(clientID string) int {
+	m.mt.Lock()
+	defer m.mt.Unlock()
+	return m.backlog[clientID]
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package shard distributes the subscribers of the k8s-cache service among its replicas.
+package shard
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes is the number of points that each member occupies in the ring. A higher
+// number provides a more even distribution of the keys among the members.
+const virtualNodes = 128
+
+// Ring assigns keys to members through consistent hashing: adding or removing a member
+// only reassigns the keys of that member, and the rest of keys keep their owner.
+type Ring struct {
+	points  []point
+	members int
+}
+
+type point struct {
+	hash   uint64
+	member string
+}
+
+// NewRing creates a Ring for the given members. Duplicate members are ignored.
+func NewRing(members []string) *Ring {
+	members = slices.Clone(members)
+	slices.Sort(members)
+	members = slices.Compact(members)
+	r := &Ring{
+		points:  make([]point, 0, len(members)*virtualNodes),
+		members: len(members),
+	}
+	for _, m := range members {
+		for v := 0; v < virtualNodes; v++ {
+			r.points = append(r.points, point{hash: hash(m + "#" + strconv.Itoa(v)), member: m})
+		}
+	}
+	slices.SortFunc(r.points, func(a, b point) int {
+		switch {
+		case a.hash < b.hash:
+			return -1
+		case a.hash > b.hash:
+			return 1
+		}
+		return 0
+	})
+	return r
+}
+
+// Owners returns all the members of the ring, sorted by their preference for the given key.
+// The first member is the owner of the key, and the next ones are the members that
+// successively take over the key if the previous ones are not available.
+func (r *Ring) Owners(key string) []string {
+	if len(r.points) == 0 {
+		return nil
+	}
+	owners := make([]string, 0, r.members)
+	seen := make(map[string]struct{}, r.members)
+	h := hash(key)
+	start := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	for i := 0; i < len(r.points) && len(owners) < r.members; i++ {
+		p := r.points[(start+i)%len(r.points)]
+		if _, ok := seen[p.member]; !ok {
+			seen[p.member] = struct{}{}
+			owners = append(owners, p.member)
+		}
+	}
+	return owners
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	// fnv has a poor dispersion for keys that only differ in their last characters,
+	// so the result is mixed with the splitmix64 finalizer
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
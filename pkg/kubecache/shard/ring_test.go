@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package shard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing_Owners(t *testing.T) {
+	r := NewRing([]string{"c:50055", "a:50055", "b:50055", "a:50055"})
+	owners := r.Owners("node-1")
+	assert.ElementsMatch(t, []string{"a:50055", "b:50055", "c:50055"}, owners)
+	// the result is stable
+	assert.Equal(t, owners, r.Owners("node-1"))
+	assert.Equal(t, owners, NewRing([]string{"b:50055", "c:50055", "a:50055"}).Owners("node-1"))
+
+	assert.Empty(t, NewRing(nil).Owners("node-1"))
+	assert.Equal(t, []string{"a"}, NewRing([]string{"a"}).Owners("node-1"))
+}
+
+func TestRing_Distribution(t *testing.T) {
+	members := []string{"10.0.0.1:50055", "10.0.0.2:50055", "10.0.0.3:50055"}
+	r := NewRing(members)
+	const keys = 3000
+	perOwner := map[string]int{}
+	for i := 0; i < keys; i++ {
+		perOwner[r.Owners(fmt.Sprintf("node-%d", i))[0]]++
+	}
+	require.Len(t, perOwner, len(members))
+	for m, n := range perOwner {
+		assert.InDeltaf(t, keys/len(members), n, float64(keys/len(members)/4), "unbalanced member %s", m)
+	}
+}
+
+func TestRing_MinimalReassignment(t *testing.T) {
+	before := NewRing([]string{"a", "b", "c"})
+	after := NewRing([]string{"a", "b", "c", "d"})
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("node-%d", i)
+		oldOwner, newOwner := before.Owners(key)[0], after.Owners(key)[0]
+		// keys are only moved to the new member
+		if oldOwner != newOwner {
+			assert.Equal(t, "d", newOwner)
+		}
+	}
+	// when the owner is removed, its keys go to the next member in the preference order
+	removed := NewRing([]string{"a", "c"})
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("node-%d", i)
+		owners := before.Owners(key)
+		if owners[0] == "b" {
+			assert.Equal(t, owners[1], removed.Owners(key)[0])
+		} else {
+			assert.Equal(t, owners[0], removed.Owners(key)[0])
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kubecache
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ServerTLSConfig configures the TLS connections of the k8s-cache service.
+// The certificate files are reloaded when they change (e.g. when a mounted Secret is rotated).
+type ServerTLSConfig struct {
+	// CertFile and KeyFile are the paths of the PEM-encoded server certificate and private key.
+	// TLS is disabled if CertFile is empty.
+	CertFile string `yaml:"cert_file" env:"OTEL_EBPF_K8S_CACHE_TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"OTEL_EBPF_K8S_CACHE_TLS_KEY_FILE"`
+	// ClientCAFile is the path of the PEM-encoded CA certificates that sign the client certificates.
+	// If set, the clients are required to authenticate with a valid certificate (mutual TLS).
+	ClientCAFile string `yaml:"client_ca_file" env:"OTEL_EBPF_K8S_CACHE_TLS_CLIENT_CA_FILE"`
+}
+
+func (c *ServerTLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+func (c *ServerTLSConfig) Validate() error {
+	if !c.Enabled() {
+		if c.KeyFile != "" || c.ClientCAFile != "" {
+			return errors.New("TLS key_file and client_ca_file require setting cert_file")
+		}
+		return nil
+	}
+	if c.KeyFile == "" {
+		return errors.New("TLS cert_file requires setting key_file")
+	}
+	return nil
+}
+
+// ServerConfig returns the tls.Config of the k8s-cache service
+func (c *ServerTLSConfig) ServerConfig() (*tls.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	files := &certFiles{
+		log:      slog.With("component", "kubecache.ServerTLS"),
+		certFile: c.CertFile,
+		keyFile:  c.KeyFile,
+		caFile:   c.ClientCAFile,
+	}
+	// fail fast if the certificates can't be loaded on startup
+	if _, _, err := files.load(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs, err := files.load()
+			if err != nil {
+				return nil, err
+			}
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if clientCAs != nil {
+				cfg.ClientCAs = clientCAs
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return cfg, nil
+		},
+	}, nil
+}
+
+// ClientTLSConfig configures the TLS connection from OBI to the k8s-cache service.
+// The certificate files are reloaded when they change (e.g. when a mounted Secret is rotated).
+type ClientTLSConfig struct {
+	// Enable connects to the k8s-cache service using TLS. It is implicitly enabled
+	// if any of CAFile or CertFile are set.
+	Enable bool `yaml:"enable" env:"OTEL_EBPF_KUBE_META_CACHE_TLS_ENABLE"`
+	// CAFile is the path of the PEM-encoded CA certificates that sign the server certificate.
+	// If empty, the system's CA certificates are used.
+	CAFile string `yaml:"ca_file" env:"OTEL_EBPF_KUBE_META_CACHE_TLS_CA_FILE"`
+	// CertFile and KeyFile are the paths of the PEM-encoded client certificate and private key,
+	// which are required when the k8s-cache service requires mutual TLS.
+	CertFile string `yaml:"cert_file" env:"OTEL_EBPF_KUBE_META_CACHE_TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"OTEL_EBPF_KUBE_META_CACHE_TLS_KEY_FILE"`
+	// ServerName overrides the name that is used to verify the server certificate.
+	// By default, it is the host of the k8s-cache service address.
+	ServerName string `yaml:"server_name" env:"OTEL_EBPF_KUBE_META_CACHE_TLS_SERVER_NAME"`
+	// InsecureSkipVerify disables the verification of the server certificate.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify" env:"OTEL_EBPF_KUBE_META_CACHE_TLS_INSECURE_SKIP_VERIFY"`
+}
+
+func (c *ClientTLSConfig) Enabled() bool {
+	return c.Enable || c.CAFile != "" || c.CertFile != ""
+}
+
+func (c *ClientTLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("TLS cert_file and key_file must be set together")
+	}
+	return nil
+}
+
+// ClientConfig returns the tls.Config to connect to the k8s-cache service
+func (c *ClientTLSConfig) ClientConfig() (*tls.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	files := &certFiles{
+		log:      slog.With("component", "kubecache.ClientTLS"),
+		certFile: c.CertFile,
+		keyFile:  c.KeyFile,
+		caFile:   c.CAFile,
+	}
+	if _, _, err := files.load(); err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+		// the server certificate is verified in VerifyConnection against the
+		// latest version of the CA file
+		//nolint:gosec
+		InsecureSkipVerify: true,
+	}
+	if c.CertFile != "" {
+		cfg.GetClientCertificate = func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _, err := files.load()
+			return cert, err
+		}
+	}
+	if !c.InsecureSkipVerify {
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			_, rootCAs, err := files.load()
+			if err != nil {
+				return err
+			}
+			return verifyServerCertificate(cs, rootCAs)
+		}
+	}
+	return cfg, nil
+}
+
+func verifyServerCertificate(cs tls.ConnectionState, rootCAs *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("the server did not provide any certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         rootCAs,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// certFiles loads a certificate and a CA pool from the given files, and reloads them
+// whenever any of the files is modified. If the reload fails, the previous version is kept.
+type certFiles struct {
+	log      *slog.Logger
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mt      sync.Mutex
+	version []fileVersion
+	cert    *tls.Certificate
+	caPool  *x509.CertPool
+}
+
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+func (fv fileVersion) equal(o fileVersion) bool {
+	return fv.modTime.Equal(o.modTime) && fv.size == o.size
+}
+
+// load returns the current certificate and CA pool, reloading them if the files changed.
+// Any of them can be nil if their files are not set.
+func (cf *certFiles) load() (*tls.Certificate, *x509.CertPool, error) {
+	cf.mt.Lock()
+	defer cf.mt.Unlock()
+
+	version, err := cf.currentVersion()
+	if err != nil {
+		return cf.keepPrevious(err)
+	}
+	if cf.version != nil && slices.EqualFunc(version, cf.version, fileVersion.equal) {
+		return cf.cert, cf.caPool, nil
+	}
+
+	var cert *tls.Certificate
+	if cf.certFile != "" {
+		c, err := tls.LoadX509KeyPair(cf.certFile, cf.keyFile)
+		if err != nil {
+			return cf.keepPrevious(fmt.Errorf("loading TLS certificate: %w", err))
+		}
+		cert = &c
+	}
+	var caPool *x509.CertPool
+	if cf.caFile != "" {
+		pem, err := os.ReadFile(cf.caFile)
+		if err != nil {
+			return cf.keepPrevious(fmt.Errorf("reading CA file: %w", err))
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(pem) {
+			return cf.keepPrevious(fmt.Errorf("no valid certificates found in CA file %s", cf.caFile))
+		}
+	}
+	if cf.version != nil {
+		cf.log.Info("TLS certificates reloaded")
+	}
+	cf.version, cf.cert, cf.caPool = version, cert, caPool
+	return cf.cert, cf.caPool, nil
+}
+
+// keepPrevious returns the previously loaded certificates, if any, or the error otherwise
+func (cf *certFiles) keepPrevious(err error) (*tls.Certificate, *x509.CertPool, error) {
+	if cf.version == nil {
+		return nil, nil, err
+	}
+	cf.log.Warn("can't reload TLS certificates. Keeping the previous ones", "error", err)
+	return cf.cert, cf.caPool, nil
+}
+
+func (cf *certFiles) currentVersion() ([]fileVersion, error) {
+	var version []fileVersion
+	for _, file := range []string{cf.certFile, cf.keyFile, cf.caFile} {
+		if file == "" {
+			version = append(version, fileVersion{})
+			continue
+		}
+		// Stat follows the symlinks that are swapped when a mounted Secret is updated
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		version = append(version, fileVersion{modTime: info.ModTime(), size: info.Size()})
+	}
+	return version, nil
+}
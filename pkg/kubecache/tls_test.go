@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kubecache
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLS_ServerOnly(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	ca.writeCA(t, dir, "ca.pem")
+	ca.writeCert(t, dir, "server", "localhost")
+
+	srv := ServerTLSConfig{CertFile: filepath.Join(dir, "server.pem"), KeyFile: filepath.Join(dir, "server-key.pem")}
+	cli := ClientTLSConfig{CAFile: filepath.Join(dir, "ca.pem"), ServerName: "localhost"}
+	require.NoError(t, handshake(t, &srv, &cli))
+
+	// the client rejects a server whose name does not match
+	cli.ServerName = "other-host"
+	require.Error(t, handshake(t, &srv, &cli))
+	cli.InsecureSkipVerify = true
+	require.NoError(t, handshake(t, &srv, &cli))
+}
+
+func TestTLS_Mutual(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	ca.writeCA(t, dir, "ca.pem")
+	ca.writeCert(t, dir, "server", "localhost")
+	ca.writeCert(t, dir, "client", "obi")
+
+	srv := ServerTLSConfig{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+	}
+	cli := ClientTLSConfig{
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		CertFile:   filepath.Join(dir, "client.pem"),
+		KeyFile:    filepath.Join(dir, "client-key.pem"),
+		ServerName: "localhost",
+	}
+	require.NoError(t, handshake(t, &srv, &cli))
+
+	// clients without certificate are rejected
+	require.Error(t, handshake(t, &srv, &ClientTLSConfig{CAFile: cli.CAFile, ServerName: "localhost"}))
+
+	// clients with a certificate from another CA are rejected
+	otherDir := t.TempDir()
+	newTestCA(t).writeCert(t, otherDir, "client", "obi")
+	require.Error(t, handshake(t, &srv, &ClientTLSConfig{
+		CAFile:     cli.CAFile,
+		CertFile:   filepath.Join(otherDir, "client.pem"),
+		KeyFile:    filepath.Join(otherDir, "client-key.pem"),
+		ServerName: "localhost",
+	}))
+}
+
+func TestTLS_CertificatesRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+	oldCA.writeCA(t, dir, "ca.pem")
+	oldCA.writeCert(t, dir, "server", "localhost")
+
+	srvCfg := ServerTLSConfig{CertFile: filepath.Join(dir, "server.pem"), KeyFile: filepath.Join(dir, "server-key.pem")}
+	cliCfg := ClientTLSConfig{CAFile: filepath.Join(dir, "ca.pem"), ServerName: "localhost"}
+	srv, err := srvCfg.ServerConfig()
+	require.NoError(t, err)
+	cli, err := cliCfg.ClientConfig()
+	require.NoError(t, err)
+	require.NoError(t, handshakeConfigs(t, srv, cli))
+
+	// the server certificate is rotated before the clients' CA
+	newCA.writeCert(t, dir, "server", "localhost")
+	touch(t, dir, "server.pem", "server-key.pem")
+	require.Error(t, handshakeConfigs(t, srv, cli))
+
+	// the new CA is picked up by the running client
+	newCA.writeCA(t, dir, "ca.pem")
+	touch(t, dir, "ca.pem")
+	require.NoError(t, handshakeConfigs(t, srv, cli))
+
+	// broken files are ignored and the previous certificates are kept
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server.pem"), []byte("broken"), 0o600))
+	touch(t, dir, "server.pem")
+	require.NoError(t, handshakeConfigs(t, srv, cli))
+}
+
+func TestTLS_Validate(t *testing.T) {
+	assert.NoError(t, (&ServerTLSConfig{}).Validate())
+	assert.NoError(t, (&ServerTLSConfig{CertFile: "c", KeyFile: "k"}).Validate())
+	assert.Error(t, (&ServerTLSConfig{CertFile: "c"}).Validate())
+	assert.Error(t, (&ServerTLSConfig{ClientCAFile: "ca"}).Validate())
+
+	assert.NoError(t, (&ClientTLSConfig{}).Validate())
+	assert.NoError(t, (&ClientTLSConfig{CertFile: "c", KeyFile: "k"}).Validate())
+	assert.Error(t, (&ClientTLSConfig{CertFile: "c"}).Validate())
+	assert.Error(t, (&ClientTLSConfig{KeyFile: "k"}).Validate())
+
+	assert.False(t, (&ClientTLSConfig{}).Enabled())
+	assert.True(t, (&ClientTLSConfig{Enable: true}).Enabled())
+	assert.True(t, (&ClientTLSConfig{CAFile: "ca"}).Enabled())
+
+	_, err := (&ServerTLSConfig{CertFile: "/not/found", KeyFile: "/not/found"}).ServerConfig()
+	assert.Error(t, err)
+}
+
+func handshake(t *testing.T, srvCfg *ServerTLSConfig, cliCfg *ClientTLSConfig) error {
+	t.Helper()
+	srv, err := srvCfg.ServerConfig()
+	require.NoError(t, err)
+	cli, err := cliCfg.ClientConfig()
+	require.NoError(t, err)
+	return handshakeConfigs(t, srv, cli)
+}
+
+// handshakeConfigs returns the error of the client or the server side of a TLS handshake
+func handshakeConfigs(t *testing.T, srv, cli *tls.Config) error {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", srv)
+	require.NoError(t, err)
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", lis.Addr().String(), cli)
+	if err == nil {
+		defer conn.Close()
+	}
+	select {
+	case sErr := <-serverErr:
+		if err == nil {
+			err = sErr
+		}
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the server handshake")
+	}
+	return err
+}
+
+// touch advances the modification time of the files, so their changes are detected
+// even if the file system has a coarse time resolution
+func touch(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		mt := info.ModTime().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, mt, mt))
+	}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, der: der}
+}
+
+func (ca *testCA) writeCA(t *testing.T, dir, file string) {
+	t.Helper()
+	writePEM(t, filepath.Join(dir, file), "CERTIFICATE", ca.der)
+}
+
+// writeCert writes the <name>.pem and <name>-key.pem files with a certificate signed by the CA
+func (ca *testCA) writeCert(t *testing.T, dir, name, host string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	writePEM(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+	writePEM(t, filepath.Join(dir, name+"-key.pem"), "EC PRIVATE KEY", keyDER)
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
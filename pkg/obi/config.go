@@ -20,10 +20,12 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 	"go.opentelemetry.io/obi/pkg/export/debug"
+	"go.opentelemetry.io/obi/pkg/export/fileexport"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
 	"go.opentelemetry.io/obi/pkg/export/otel"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/export/prom"
+	"go.opentelemetry.io/obi/pkg/export/zipkin"
 	"go.opentelemetry.io/obi/pkg/filter"
 	"go.opentelemetry.io/obi/pkg/kubeflags"
 	"go.opentelemetry.io/obi/pkg/services"
@@ -79,9 +81,11 @@ var DefaultConfig = Config{
 			MySQL:    0,
 			Postgres: 0,
 		},
-		MySQLPreparedStatementsCacheSize:    1024,
-		PostgresPreparedStatementsCacheSize: 1024,
-		MongoRequestsCacheSize:              1024,
+		MySQLPreparedStatementsCacheSize:     1024,
+		PostgresPreparedStatementsCacheSize:  1024,
+		CassandraPreparedStatementsCacheSize: 1024,
+		CassandraKeyspacesCacheSize:          1024,
+		MongoRequestsCacheSize:               1024,
 	},
 	NameResolver: &transform.NameResolverConfig{
 		Sources:  []string{"k8s"},
@@ -111,6 +115,18 @@ var DefaultConfig = Config{
 			instrumentations.InstrumentationALL,
 		},
 	},
+	ZipkinTraces: zipkin.TracesConfig{
+		Instrumentations: []string{
+			instrumentations.InstrumentationALL,
+		},
+		Timeout:                10 * time.Second,
+		MaxBatchSize:           4096,
+		BatchTimeout:           5 * time.Second,
+		MaxPendingBatches:      10,
+		BackOffInitialInterval: 5 * time.Second,
+		BackOffMaxInterval:     30 * time.Second,
+		BackOffMaxElapsedTime:  5 * time.Minute,
+	},
 	Prometheus: prom.PrometheusConfig{
 		Path:     "/metrics",
 		Buckets:  otelcfg.DefaultBuckets,
@@ -122,6 +138,10 @@ var DefaultConfig = Config{
 		SpanMetricsServiceCacheSize: 10000,
 	},
 	TracePrinter: debug.TracePrinterDisabled,
+	FileExport: fileexport.Config{
+		Format:    fileexport.FormatJSONL,
+		MaxSizeMB: 100,
+	},
 	InternalMetrics: imetrics.Config{
 		Exporter: imetrics.InternalMetricsExporterDisabled,
 		Prometheus: imetrics.PrometheusConfig{
@@ -147,6 +167,10 @@ var DefaultConfig = Config{
 		Unmatch:      transform.UnmatchDefault,
 		WildcardChar: "*",
 	},
+	TailSampling: transform.TailSamplingConfig{
+		DecisionWait: 10 * time.Second,
+		MaxTraces:    50_000,
+	},
 	NetworkFlows: defaultNetworkConfig,
 	Discovery: services.DiscoveryConfig{
 		ExcludeOTelInstrumentedServices: true,
@@ -187,8 +211,13 @@ type Config struct {
 	NameResolver *transform.NameResolverConfig `yaml:"name_resolver"`
 	Metrics      otelcfg.MetricsConfig         `yaml:"otel_metrics_export"`
 	Traces       otelcfg.TracesConfig          `yaml:"otel_traces_export"`
+	ZipkinTraces zipkin.TracesConfig           `yaml:"zipkin_traces_export"`
 	Prometheus   prom.PrometheusConfig         `yaml:"prometheus_export"`
 	TracePrinter debug.TracePrinter            `yaml:"trace_printer" env:"OTEL_EBPF_TRACE_PRINTER"`
+	FileExport   fileexport.Config             `yaml:"file_export"`
+
+	// TailSampling decides which traces are exported once all their spans have been received
+	TailSampling transform.TailSamplingConfig `yaml:"tail_sampling"`
 
 	// Exec allows selecting the instrumented executable whose complete path contains the Exec value.
 	// Deprecated: Use OTEL_EBPF_AUTO_TARGET_EXE
@@ -238,6 +267,13 @@ type Config struct {
 	LogConfig bool `yaml:"log_config" env:"OTEL_EBPF_LOG_CONFIG"`
 
 	NodeJS NodeJSConfig `yaml:"nodejs"`
+
+	// ConfigReload allows updating some configuration sections without restarting OBI
+	ConfigReload ConfigReloadConfig `yaml:"config_reload"`
+
+	// Reloads is set at runtime when the configuration reload is enabled, to forward
+	// the configuration changes to the pipeline nodes.
+	Reloads *ConfigReloads `yaml:"-"`
 }
 
 // Attributes configures the decoration of some extra attributes that will be
@@ -302,6 +338,9 @@ func (c *Config) Validate() error {
 		c.EBPF.ContextPropagation = config.ContextPropagationAll
 	}
 
+	// the Cassandra protocol is only parsed if any exporter reports it
+	c.EBPF.CassandraDetection = c.SelectedInstrumentations().CassandraEnabled()
+
 	if c.willUseTC() {
 		if err := tcmanager.EnsureCiliumCompatibility(c.EBPF.TCBackend); err != nil {
 			return ConfigError("Cilium compatibility error: " + err.Error())
@@ -312,11 +351,24 @@ func (c *Config) Validate() error {
 		return ConfigError("OTEL_EBPF_KUBE_INFORMERS_SYNC_TIMEOUT duration must be greater than 0s")
 	}
 
+	if err := c.Attributes.Kubernetes.MetaCacheTLS.Validate(); err != nil {
+		return ConfigError("invalid meta_cache_tls configuration: " + err.Error())
+	}
+
+	if err := c.NetworkFlows.IPFIX.Validate(); err != nil {
+		return ConfigError(err.Error())
+	}
+
+	if c.NetworkFlows.HeavyHitters.Enabled() && c.NetworkFlows.HeavyHitters.Interval <= 0 {
+		return ConfigError("OTEL_EBPF_NETWORK_HEAVY_HITTERS_INTERVAL duration must be greater than 0s")
+	}
+
 	if c.Enabled(FeatureNetO11y) && !c.Metrics.Enabled() &&
-		!c.Prometheus.Enabled() && !c.NetworkFlows.Print {
+		!c.Prometheus.Enabled() && !c.NetworkFlows.IPFIX.Enabled() && !c.NetworkFlows.Print {
 		return ConfigError("enabling network metrics requires to enable at least the OpenTelemetry" +
 			" metrics exporter: otel_metrics_export or prometheus_export sections in the YAML configuration file; or the" +
-			" OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_METRICS_ENDPOINT or OTEL_EBPF_PROMETHEUS_PORT environment variables. For debugging" +
+			" OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_METRICS_ENDPOINT, OTEL_EBPF_PROMETHEUS_PORT or" +
+			" OTEL_EBPF_NETWORK_IPFIX_ENDPOINT environment variables. For debugging" +
 			" purposes, you can also set OTEL_EBPF_NETWORK_PRINT_FLOWS=true")
 	}
 
@@ -324,11 +376,24 @@ func (c *Config) Validate() error {
 		return ConfigError(fmt.Sprintf("invalid value for trace_printer: '%s'", c.TracePrinter))
 	}
 
+	if err := c.FileExport.Validate(); err != nil {
+		return ConfigError(err.Error())
+	}
+
+	if err := c.ZipkinTraces.Validate(); err != nil {
+		return ConfigError(err.Error())
+	}
+
+	if err := c.TailSampling.Validate(); err != nil {
+		return ConfigError(err.Error())
+	}
+
 	if c.Enabled(FeatureAppO11y) && !c.TracePrinter.Enabled() &&
 		!c.Metrics.Enabled() && !c.Traces.Enabled() &&
-		!c.Prometheus.Enabled() && !c.TracePrinter.Enabled() {
+		!c.Prometheus.Enabled() && !c.TracePrinter.Enabled() && !c.FileExport.Enabled() &&
+		!c.ZipkinTraces.Enabled() {
 		return ConfigError("you need to define at least one exporter: trace_printer," +
-			" otel_metrics_export, otel_traces_export or prometheus_export")
+			" otel_metrics_export, otel_traces_export, zipkin_traces_export, prometheus_export or file_export")
 	}
 
 	if c.Enabled(FeatureAppO11y) &&
@@ -376,7 +441,8 @@ func (c *Config) willUseTC() bool {
 func (c *Config) Enabled(feature Feature) bool {
 	switch feature {
 	case FeatureNetO11y:
-		return c.NetworkFlows.Enable || c.promNetO11yEnabled() || c.otelNetO11yEnabled()
+		return c.NetworkFlows.Enable || c.promNetO11yEnabled() || c.otelNetO11yEnabled() ||
+			c.NetworkFlows.IPFIX.Enabled()
 	case FeatureAppO11y:
 		return c.Port.Len() > 0 || c.AutoTargetExe.IsSet() || len(c.Discovery.Instrument) > 0 ||
 			c.Exec.IsSet() || len(c.Discovery.Services) > 0
@@ -391,6 +457,36 @@ func (c *Config) SpanMetricsEnabledForTraces() bool {
 	return otelSpanMetricsEnabled || promSpanMetricsEnabled
 }
 
+// SelectedInstrumentations returns the instrumentations that are selected by any of the
+// enabled exporters, so the tracers can skip the probes that nobody would report.
+func (c *Config) SelectedInstrumentations() instrumentations.InstrumentationSelection {
+	if c.TracePrinter.Enabled() {
+		return instrumentations.NewInstrumentationSelection([]string{instrumentations.InstrumentationALL})
+	}
+	var selection instrumentations.InstrumentationSelection
+	if c.Metrics.Enabled() {
+		selection |= instrumentations.NewInstrumentationSelection(c.Metrics.Instrumentations)
+	}
+	if c.Prometheus.Enabled() {
+		selection |= instrumentations.NewInstrumentationSelection(c.Prometheus.Instrumentations)
+	}
+	if c.Traces.Enabled() {
+		selection |= instrumentations.NewInstrumentationSelection(c.Traces.Instrumentations)
+	}
+	if c.FileExport.Enabled() {
+		// the JSONL format writes all the spans, and OTLP-JSON follows the traces selection
+		fileSelection := []string{instrumentations.InstrumentationALL}
+		if c.FileExport.Format == fileexport.FormatOTLPJSON {
+			fileSelection = c.Traces.Instrumentations
+		}
+		selection |= instrumentations.NewInstrumentationSelection(fileSelection)
+	}
+	if c.ZipkinTraces.Enabled() {
+		selection |= instrumentations.NewInstrumentationSelection(c.ZipkinTraces.Instrumentations)
+	}
+	return selection
+}
+
 // ExternalLogger sets the logging capabilities of OBI.
 // Used for integrating Beyla with an external logging system (for example Alloy)
 // TODO: maybe this method has too many responsibilities, as it affects the global logger.
@@ -412,6 +508,10 @@ func (c *Config) ExternalLogger(handler slog.Handler, debugMode bool) {
 // 3 - Environment variables
 func LoadConfig(file io.Reader) (*Config, error) {
 	cfg := DefaultConfig
+	// the pointed default sections are copied, so they aren't overridden by the
+	// YAML parser and can be shared by the different loaded configurations
+	routes, nameResolver := *DefaultConfig.Routes, *DefaultConfig.NameResolver
+	cfg.Routes, cfg.NameResolver = &routes, &nameResolver
 	if file != nil {
 		cfgBuf, err := io.ReadAll(file)
 		if err != nil {
@@ -26,9 +26,11 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 	"go.opentelemetry.io/obi/pkg/export/debug"
+	"go.opentelemetry.io/obi/pkg/export/fileexport"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/export/prom"
+	"go.opentelemetry.io/obi/pkg/export/zipkin"
 	"go.opentelemetry.io/obi/pkg/kubeflags"
 	"go.opentelemetry.io/obi/pkg/services"
 	"go.opentelemetry.io/obi/pkg/transform"
@@ -135,9 +137,12 @@ discovery:
 				MySQL:    0,
 				Postgres: 0,
 			},
-			MySQLPreparedStatementsCacheSize:    1024,
-			PostgresPreparedStatementsCacheSize: 1024,
-			MongoRequestsCacheSize:              1024,
+			MySQLPreparedStatementsCacheSize:     1024,
+			PostgresPreparedStatementsCacheSize:  1024,
+			CassandraPreparedStatementsCacheSize: 1024,
+			CassandraKeyspacesCacheSize:          1024,
+			CassandraDetection:                   true,
+			MongoRequestsCacheSize:               1024,
 		},
 		NetworkFlows: nc,
 		Metrics: otelcfg.MetricsConfig{
@@ -150,6 +155,9 @@ discovery:
 				DurationHistogram:     []float64{0, 1, 2},
 				RequestSizeHistogram:  otelcfg.DefaultBuckets.RequestSizeHistogram,
 				ResponseSizeHistogram: otelcfg.DefaultBuckets.ResponseSizeHistogram,
+				TCPRTTHistogram:       otelcfg.DefaultBuckets.TCPRTTHistogram,
+				ConnDurationHistogram: otelcfg.DefaultBuckets.ConnDurationHistogram,
+				GoRuntimeHistogram:    otelcfg.DefaultBuckets.GoRuntimeHistogram,
 			},
 			Features: []string{"application"},
 			Instrumentations: []string{
@@ -168,6 +176,18 @@ discovery:
 				instrumentations.InstrumentationALL,
 			},
 		},
+		ZipkinTraces: zipkin.TracesConfig{
+			Instrumentations: []string{
+				instrumentations.InstrumentationALL,
+			},
+			Timeout:                10 * time.Second,
+			MaxBatchSize:           4096,
+			BatchTimeout:           5 * time.Second,
+			MaxPendingBatches:      10,
+			BackOffInitialInterval: 5 * time.Second,
+			BackOffMaxInterval:     30 * time.Second,
+			BackOffMaxElapsedTime:  5 * time.Minute,
+		},
 		Prometheus: prom.PrometheusConfig{
 			Path:     "/metrics",
 			Features: []string{otelcfg.FeatureApplication},
@@ -180,8 +200,15 @@ discovery:
 				DurationHistogram:     otelcfg.DefaultBuckets.DurationHistogram,
 				RequestSizeHistogram:  []float64{0, 10, 20, 22},
 				ResponseSizeHistogram: []float64{0, 10, 20, 22},
+				TCPRTTHistogram:       otelcfg.DefaultBuckets.TCPRTTHistogram,
+				ConnDurationHistogram: otelcfg.DefaultBuckets.ConnDurationHistogram,
+				GoRuntimeHistogram:    otelcfg.DefaultBuckets.GoRuntimeHistogram,
 			},
 		},
+		FileExport: fileexport.Config{
+			Format:    fileexport.FormatJSONL,
+			MaxSizeMB: 100,
+		},
 		InternalMetrics: imetrics.Config{
 			Exporter: imetrics.InternalMetricsExporterDisabled,
 			Prometheus: imetrics.PrometheusConfig{
@@ -218,6 +245,10 @@ discovery:
 			Unmatch:      transform.UnmatchHeuristic,
 			WildcardChar: "*",
 		},
+		TailSampling: transform.TailSamplingConfig{
+			DecisionWait: 10 * time.Second,
+			MaxTraces:    50_000,
+		},
 		NameResolver: &transform.NameResolverConfig{
 			Sources:  []string{"k8s", "dns"},
 			CacheLen: 1024,
@@ -366,7 +397,11 @@ func TestConfigValidate_TracePrinter(t *testing.T) {
 		},
 		{
 			env:      envMap{"OTEL_EBPF_EXECUTABLE_PATH": "foo"},
-			errorMsg: "you need to define at least one exporter: trace_printer, otel_metrics_export, otel_traces_export or prometheus_export",
+			errorMsg: "you need to define at least one exporter: trace_printer, otel_metrics_export, otel_traces_export, zipkin_traces_export, prometheus_export or file_export",
+		},
+		{
+			env:      envMap{"OTEL_EBPF_EXECUTABLE_PATH": "foo", "OTEL_EBPF_FILE_EXPORT_PATH": "/tmp/spans.jsonl", "OTEL_EBPF_FILE_EXPORT_FORMAT": "xml"},
+			errorMsg: `invalid file_export format: "xml". Accepted values: "jsonl", "otlp_json"`,
 		},
 	}
 
@@ -390,6 +425,48 @@ func TestConfigValidate_TracePrinterFallback(t *testing.T) {
 	assert.Equal(t, debug.TracePrinterText, cfg.TracePrinter)
 }
 
+func TestConfigValidate_FileExport(t *testing.T) {
+	env := envMap{"OTEL_EBPF_EXECUTABLE_PATH": "foo", "OTEL_EBPF_FILE_EXPORT_PATH": "/tmp/spans.jsonl"}
+
+	cfg := loadConfig(t, env)
+	require.NoError(t, cfg.Validate())
+	assert.True(t, cfg.FileExport.Enabled())
+	assert.Equal(t, fileexport.FormatJSONL, cfg.FileExport.Format)
+}
+
+func TestConfigValidate_CassandraDetection(t *testing.T) {
+	env := envMap{
+		"OTEL_EBPF_EXECUTABLE_PATH":          "foo",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "localhost:1234",
+		"OTEL_EBPF_TRACES_INSTRUMENTATIONS":  "http,sql",
+	}
+	cfg := loadConfig(t, env)
+	require.NoError(t, cfg.Validate())
+	assert.False(t, cfg.EBPF.CassandraDetection)
+
+	env["OTEL_EBPF_TRACES_INSTRUMENTATIONS"] = "http,cassandra"
+	cfg = loadConfig(t, env)
+	require.NoError(t, cfg.Validate())
+	assert.True(t, cfg.EBPF.CassandraDetection)
+}
+
+func TestConfigValidate_ZipkinTraces(t *testing.T) {
+	env := envMap{
+		"OTEL_EBPF_EXECUTABLE_PATH":        "foo",
+		"OTEL_EBPF_ZIPKIN_TRACES_ENDPOINT": "http://zipkin:9411/api/v2/spans",
+		"OTEL_EBPF_ZIPKIN_TRACES_HEADERS":  "X-Tenant:foo,Authorization:Bearer bar",
+	}
+
+	cfg := loadConfig(t, env)
+	require.NoError(t, cfg.Validate())
+	assert.True(t, cfg.ZipkinTraces.Enabled())
+	assert.Equal(t, map[string]string{"X-Tenant": "foo", "Authorization": "Bearer bar"}, cfg.ZipkinTraces.Headers)
+
+	env["OTEL_EBPF_ZIPKIN_TRACES_ENDPOINT"] = "zipkin:9411"
+	cfg = loadConfig(t, env)
+	require.Error(t, cfg.Validate())
+}
+
 func TestConfigValidateRoutes(t *testing.T) {
 	userConfig := bytes.NewBufferString(`executable_path: foo
 trace_printer: text
@@ -453,6 +530,25 @@ func TestConfig_NetworkImplicitProm(t *testing.T) {
 	assert.True(t, cfg.Enabled(FeatureNetO11y)) // Net o11y should be on
 }
 
+func TestConfig_NetworkImplicitIPFIX(t *testing.T) {
+	t.Setenv("OTEL_EBPF_NETWORK_IPFIX_ENDPOINT", "collector:4739")
+	cfg, err := LoadConfig(bytes.NewReader(nil))
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled(FeatureNetO11y)) // Net o11y should be on
+	// the enterprise ID has no default value
+	require.Error(t, cfg.Validate())
+
+	t.Setenv("OTEL_EBPF_NETWORK_IPFIX_ENTERPRISE_ID", "32473")
+	cfg, err = LoadConfig(bytes.NewReader(nil))
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+
+	t.Setenv("OTEL_EBPF_NETWORK_IPFIX_TRANSPORT", "sctp")
+	cfg, err = LoadConfig(bytes.NewReader(nil))
+	require.NoError(t, err)
+	require.Error(t, cfg.Validate())
+}
+
 func TestConfig_ExternalLogger(t *testing.T) {
 	type testCase struct {
 		name          string
@@ -24,8 +24,10 @@ package obi
 import (
 	"time"
 
+	"go.opentelemetry.io/obi/pkg/components/netolly/export/ipfix"
 	"go.opentelemetry.io/obi/pkg/components/netolly/flow"
 	"go.opentelemetry.io/obi/pkg/components/netolly/transform/cidr"
+	"go.opentelemetry.io/obi/pkg/components/netolly/transform/cloud"
 )
 
 const (
@@ -111,9 +113,17 @@ type NetworkConfig struct {
 	// This is an experimental feature and it is not guaranteed to work on most virtualized environments
 	// for external traffic.
 	ReverseDNS flow.ReverseDNS `yaml:"reverse_dns"`
+	// L7ProtocolClassification samples the first payload bytes of each flow to classify its
+	// application-layer protocol (HTTP, gRPC, Kafka, Postgres...). The protocol is reported as the
+	// "l7.protocol" attribute, which needs to be explicitly selected for the network metrics.
+	L7ProtocolClassification bool `yaml:"l7_protocol_classification" env:"OTEL_EBPF_NETWORK_L7_PROTOCOL_CLASSIFICATION"`
 	// Print the network flows in the Standard Output, if true
 	Print bool `yaml:"print_flows" env:"OTEL_EBPF_NETWORK_PRINT_FLOWS"`
 
+	// IPFIX exports the network flow records to an IPFIX collector, if its endpoint is set.
+	// Kubernetes and CIDR decoration is sent as enterprise-specific information elements.
+	IPFIX ipfix.Config `yaml:"ipfix"`
+
 	// CIDRs list, to be set as the "src.cidr" and "dst.cidr"
 	// attribute as a function of the source and destination IP addresses.
 	// If an IP does not match any address here, the attributes won't be set.
@@ -121,6 +131,16 @@ type NetworkConfig struct {
 	// narrowest CIDR. By this reason, you can safely add a 0.0.0.0/0 entry to group there
 	// all the traffic that does not match any of the other CIDRs.
 	CIDRs cidr.Definitions `yaml:"cidrs" env:"OTEL_EBPF_NETWORK_CIDRS" envSeparator:","`
+
+	// HeavyHitters bounds the cardinality of the network metrics that report the source and
+	// destination addresses, by keeping them only for the top talkers.
+	HeavyHitters flow.HeavyHitters `yaml:"heavy_hitters"`
+
+	// EgressCost configures the classification of the traffic reported by the egress cost metrics,
+	// which are enabled by adding "network_egress_cost" to the metrics or Prometheus features.
+	// Zones and regions are taken from the topology.kubernetes.io labels of the nodes, or
+	// from the cloud provider ranges file for the addresses out of the cluster.
+	EgressCost cloud.EgressCost `yaml:"egress_cost"`
 }
 
 var defaultNetworkConfig = NetworkConfig{
@@ -139,4 +159,8 @@ var defaultNetworkConfig = NetworkConfig{
 		CacheLen: 256,
 		CacheTTL: time.Hour,
 	},
+	IPFIX: ipfix.DefaultConfig,
+	HeavyHitters: flow.HeavyHitters{
+		Interval: time.Minute,
+	},
 }
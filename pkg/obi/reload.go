@@ -0,0 +1,398 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package obi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/obi/pkg/filter"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/services"
+	"go.opentelemetry.io/obi/pkg/transform"
+)
+
+// ConfigReloadConfig configures the reload of the configuration without restarting OBI.
+type ConfigReloadConfig struct {
+	// Enabled reloads the configuration when OBI receives a SIGHUP signal
+	Enabled bool `yaml:"enabled" env:"OTEL_EBPF_CONFIG_RELOAD_ENABLED"`
+	// PollInterval, if greater than zero, also reloads the configuration when the contents of
+	// the configuration file change. The file is checked at the given interval.
+	PollInterval time.Duration `yaml:"poll_interval" env:"OTEL_EBPF_CONFIG_RELOAD_POLL_INTERVAL"`
+	// Path of the configuration file to reload. If empty, the configuration is only
+	// reloaded from the environment variables.
+	Path string `yaml:"-"`
+}
+
+// ConfigReloads contains the queues that forward the reloaded configuration sections
+// to the pipeline nodes that can apply them without restarting OBI.
+type ConfigReloads struct {
+	// Discovery forwards the whole configuration when the discovery criteria change
+	Discovery  *msg.Queue[*Config]
+	Routes     *msg.Queue[*transform.RoutesConfig]
+	AppFilters *msg.Queue[filter.AttributeFamilyConfig]
+	NetFilters *msg.Queue[filter.AttributeFamilyConfig]
+}
+
+func NewConfigReloads() *ConfigReloads {
+	return &ConfigReloads{
+		Discovery:  msg.NewQueue[*Config](msg.NotBlockIfNoSubscribers()),
+		Routes:     msg.NewQueue[*transform.RoutesConfig](msg.NotBlockIfNoSubscribers()),
+		AppFilters: msg.NewQueue[filter.AttributeFamilyConfig](msg.NotBlockIfNoSubscribers()),
+		NetFilters: msg.NewQueue[filter.AttributeFamilyConfig](msg.NotBlockIfNoSubscribers()),
+	}
+}
+
+// DiscoveryUpdates returns the discovery updates queue, or nil if the configuration reload is disabled.
+func (cr *ConfigReloads) DiscoveryUpdates() *msg.Queue[*Config] {
+	if cr == nil {
+		return nil
+	}
+	return cr.Discovery
+}
+
+// RoutesUpdates returns the routes updates queue, or nil if the configuration reload is disabled.
+func (cr *ConfigReloads) RoutesUpdates() *msg.Queue[*transform.RoutesConfig] {
+	if cr == nil {
+		return nil
+	}
+	return cr.Routes
+}
+
+// AppFiltersUpdates returns the application filters updates queue, or nil if the configuration reload is disabled.
+func (cr *ConfigReloads) AppFiltersUpdates() *msg.Queue[filter.AttributeFamilyConfig] {
+	if cr == nil {
+		return nil
+	}
+	return cr.AppFilters
+}
+
+// NetFiltersUpdates returns the network filters updates queue, or nil if the configuration reload is disabled.
+func (cr *ConfigReloads) NetFiltersUpdates() *msg.Queue[filter.AttributeFamilyConfig] {
+	if cr == nil {
+		return nil
+	}
+	return cr.NetFilters
+}
+
+// top-level Config fields that are part of the discovery criteria. Together with the
+// selectors in the Discovery section, they can be updated without restarting OBI
+var discoveryCriteriaFields = map[string]struct{}{
+	"Exec": {}, "AutoTargetExe": {}, "Port": {}, "ServiceName": {}, "ServiceNamespace": {},
+}
+
+// top-level Config fields that are compared separately, as they can be totally or partially
+// updated without restarting OBI
+var liveFields = map[string]struct{}{
+	"Discovery": {}, "Routes": {}, "Filters": {}, "Reloads": {},
+}
+
+// ReloadReport summarizes the changes found after reloading the configuration
+type ReloadReport struct {
+	// Applied contains the configuration sections whose changes have been applied
+	Applied []string
+	// RestartRequired contains the configuration sections whose changes won't be applied
+	// until OBI is restarted
+	RestartRequired []string
+}
+
+// ConfigReloader reloads the configuration and forwards the changes in the discovery criteria,
+// the routes and the attribute filters to the running pipeline nodes.
+type ConfigReloader struct {
+	log  *slog.Logger
+	path string
+	// injectable function
+	load func() (*Config, error)
+
+	// hash of the configuration file contents, to detect changes
+	lastHash []byte
+
+	reloads *ConfigReloads
+
+	mt sync.Mutex
+	// running configuration. Only the sections that can be updated without
+	// restarting are updated after each reload
+	running configSnapshot
+}
+
+// NewConfigReloader creates a ConfigReloader for the configuration that has been loaded from
+// the provided path, which can be empty if the configuration is only read from the environment.
+// It sets the queues that the pipeline nodes need to listen for the configuration changes.
+func NewConfigReloader(cfg *Config, path string) *ConfigReloader {
+	if cfg.Reloads == nil {
+		cfg.Reloads = NewConfigReloads()
+	}
+	cr := &ConfigReloader{
+		log:     slog.With("component", "obi.ConfigReloader"),
+		path:    path,
+		reloads: cfg.Reloads,
+		running: takeSnapshot(cfg),
+	}
+	cr.load = cr.loadFile
+	if path != "" {
+		cr.lastHash, _ = fileHash(path)
+	}
+	return cr
+}
+
+func (cr *ConfigReloader) loadFile() (*Config, error) {
+	if cr.path == "" {
+		return LoadConfig(nil)
+	}
+	file, err := os.Open(cr.path)
+	if err != nil {
+		return nil, fmt.Errorf("opening configuration file: %w", err)
+	}
+	defer file.Close()
+	return LoadConfig(file)
+}
+
+// Run reloads the configuration each time OBI receives a SIGHUP signal and, if the
+// poll interval is greater than zero, each time the contents of the configuration file change.
+func (cr *ConfigReloader) Run(ctx context.Context, pollInterval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var poll <-chan time.Time
+	if cr.path != "" && pollInterval > 0 {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	cr.log.Info("listening for configuration changes", "path", cr.path, "pollInterval", pollInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			cr.log.Info("SIGHUP received. Reloading configuration")
+			cr.reloadAndLog()
+		case <-poll:
+			hash, err := fileHash(cr.path)
+			if err != nil {
+				cr.log.Warn("can't read configuration file", "error", err)
+				continue
+			}
+			if !bytes.Equal(hash, cr.lastHash) {
+				cr.lastHash = hash
+				cr.log.Info("configuration file changed. Reloading configuration")
+				cr.reloadAndLog()
+			}
+		}
+	}
+}
+
+func (cr *ConfigReloader) reloadAndLog() {
+	report, err := cr.Reload()
+	if err != nil {
+		cr.log.Error("can't reload configuration. Keeping the current one", "error", err)
+		return
+	}
+	if len(report.Applied) == 0 && len(report.RestartRequired) == 0 {
+		cr.log.Info("configuration did not change")
+		return
+	}
+	if len(report.Applied) > 0 {
+		cr.log.Info("configuration changes applied", "sections", report.Applied)
+	}
+	if len(report.RestartRequired) > 0 {
+		cr.log.Warn("some configuration changes require restarting OBI to be applied",
+			"sections", report.RestartRequired)
+	}
+}
+
+// Reload loads and validates the configuration, forwards the changes in the sections that can be
+// updated without restarting, and reports the sections whose changes require a restart.
+// If the new configuration is invalid, nothing is applied and an error is returned.
+func (cr *ConfigReloader) Reload() (*ReloadReport, error) {
+	cfg, err := cr.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.Reloads = cr.reloads
+
+	report, forward := cr.update(cfg)
+	// the queues might block until the pipeline nodes receive the changes, so they are
+	// forwarded without holding the lock
+	for _, f := range forward {
+		f()
+	}
+	return report, nil
+}
+
+// update the running configuration snapshot with the sections of the reloaded configuration that
+// can be applied without restarting. It returns the functions that forward them to the pipeline.
+func (cr *ConfigReloader) update(cfg *Config) (*ReloadReport, []func()) {
+	cr.mt.Lock()
+	defer cr.mt.Unlock()
+
+	reloaded := takeSnapshot(cfg)
+	report := &ReloadReport{RestartRequired: restartRequiredSections(cr.running, reloaded)}
+	var forward []func()
+
+	if discoveryCriteriaChanged(cr.running, reloaded) {
+		report.Applied = append(report.Applied, "discovery")
+		cr.running[discoveryCriteriaKey] = reloaded[discoveryCriteriaKey]
+		for name := range discoveryCriteriaFields {
+			cr.running[name] = reloaded[name]
+		}
+		forward = append(forward, func() { cr.reloads.Discovery.Send(cfg) })
+	}
+	if !bytes.Equal(cr.running[routesKey], reloaded[routesKey]) {
+		report.Applied = append(report.Applied, "routes")
+		cr.running[routesKey] = reloaded[routesKey]
+		forward = append(forward, func() { cr.reloads.Routes.Send(cfg.Routes) })
+	}
+	if !bytes.Equal(cr.running[appFiltersKey], reloaded[appFiltersKey]) {
+		report.Applied = append(report.Applied, "filter.application")
+		cr.running[appFiltersKey] = reloaded[appFiltersKey]
+		forward = append(forward, func() { cr.reloads.AppFilters.Send(cfg.Filters.Application) })
+	}
+	if !bytes.Equal(cr.running[netFiltersKey], reloaded[netFiltersKey]) {
+		report.Applied = append(report.Applied, "filter.network")
+		cr.running[netFiltersKey] = reloaded[netFiltersKey]
+		forward = append(forward, func() { cr.reloads.NetFilters.Send(cfg.Filters.Network) })
+	}
+	return report, forward
+}
+
+// keys of the configSnapshot entries that don't correspond to a top-level Config field
+const (
+	discoveryCriteriaKey = "Discovery.criteria"
+	routesKey            = "Routes"
+	appFiltersKey        = "Filters.Application"
+	netFiltersKey        = "Filters.Network"
+)
+
+// configSnapshot stores the YAML representation of each configuration section, indexed by its
+// Config field name. Unlike a copy of the Config, it doesn't share any pointer, slice or map
+// with the configurations that are used by the pipeline nodes.
+// The Discovery entry excludes the selection criteria, which are stored in discoveryCriteriaKey.
+type configSnapshot map[string][]byte
+
+func takeSnapshot(cfg *Config) configSnapshot {
+	snapshot := configSnapshot{}
+	cv := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		field := cv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := liveFields[field.Name]; ok {
+			continue
+		}
+		snapshot[field.Name] = toYAML(cv.Field(i).Interface())
+	}
+	snapshot["Discovery"] = toYAML(withoutDiscoveryCriteria(cfg.Discovery))
+	snapshot[discoveryCriteriaKey] = toYAML(withDiscoveryCriteria(services.DiscoveryConfig{}, &cfg.Discovery))
+	snapshot[routesKey] = toYAML(cfg.Routes)
+	snapshot[appFiltersKey] = toYAML(cfg.Filters.Application)
+	snapshot[netFiltersKey] = toYAML(cfg.Filters.Network)
+	return snapshot
+}
+
+// restartRequiredSections returns the name of the configuration sections that changed
+// and can't be applied without restarting OBI
+func restartRequiredSections(running, cfg configSnapshot) []string {
+	var sections []string
+	ct := reflect.TypeOf(Config{})
+	for i := 0; i < ct.NumField(); i++ {
+		field := ct.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := liveFields[field.Name]; ok {
+			continue
+		}
+		if _, ok := discoveryCriteriaFields[field.Name]; ok {
+			continue
+		}
+		if !bytes.Equal(running[field.Name], cfg[field.Name]) {
+			sections = append(sections, sectionName(field))
+		}
+	}
+	// changes in the discovery section that are not the selection criteria
+	if !bytes.Equal(running["Discovery"], cfg["Discovery"]) {
+		sections = append(sections, "discovery")
+	}
+	return sections
+}
+
+func discoveryCriteriaChanged(running, cfg configSnapshot) bool {
+	if !bytes.Equal(running[discoveryCriteriaKey], cfg[discoveryCriteriaKey]) {
+		return true
+	}
+	for name := range discoveryCriteriaFields {
+		if !bytes.Equal(running[name], cfg[name]) {
+			return true
+		}
+	}
+	return false
+}
+
+// withDiscoveryCriteria returns a copy of the dst discovery configuration
+// with the selection criteria from the src discovery configuration
+func withDiscoveryCriteria(dst services.DiscoveryConfig, src *services.DiscoveryConfig) services.DiscoveryConfig {
+	dst.Services = src.Services
+	dst.ExcludeServices = src.ExcludeServices
+	dst.DefaultExcludeServices = src.DefaultExcludeServices
+	dst.Instrument = src.Instrument
+	dst.ExcludeInstrument = src.ExcludeInstrument
+	dst.DefaultExcludeInstrument = src.DefaultExcludeInstrument
+	return dst
+}
+
+func withoutDiscoveryCriteria(dc services.DiscoveryConfig) services.DiscoveryConfig {
+	return withDiscoveryCriteria(dc, &services.DiscoveryConfig{})
+}
+
+// toYAML returns the YAML representation of a configuration value, as some configuration
+// types (e.g. the selectors) contain compiled regular expressions or globs that can't be
+// compared. If the value can't be marshalled, the error message is returned instead, so
+// the value is considered unchanged.
+func toYAML(v any) []byte {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return []byte("can't marshal configuration value: " + err.Error())
+	}
+	return out
+}
+
+func sectionName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+func fileHash(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package obi
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/components/testutil"
+	"go.opentelemetry.io/obi/pkg/filter"
+	"go.opentelemetry.io/obi/pkg/transform"
+)
+
+const reloadTimeout = 5 * time.Second
+
+const baseReloadYAML = `
+trace_printer: text
+discovery:
+  instrument:
+    - open_ports: 8080
+`
+
+func loadYAML(t *testing.T, yml string) *Config {
+	t.Helper()
+	cfg, err := LoadConfig(strings.NewReader(yml))
+	require.NoError(t, err)
+	return cfg
+}
+
+func testReloader(t *testing.T, yml *string) *ConfigReloader {
+	t.Helper()
+	cr := NewConfigReloader(loadYAML(t, *yml), "")
+	cr.load = func() (*Config, error) {
+		return LoadConfig(strings.NewReader(*yml))
+	}
+	return cr
+}
+
+func TestConfigReloader_NoChanges(t *testing.T) {
+	yml := baseReloadYAML
+	cr := testReloader(t, &yml)
+	report, err := cr.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, report.Applied)
+	assert.Empty(t, report.RestartRequired)
+}
+
+func TestConfigReloader_LiveChanges(t *testing.T) {
+	yml := baseReloadYAML
+	cr := testReloader(t, &yml)
+	reloads := cr.reloads
+	discovery := reloads.Discovery.Subscribe()
+	routes := reloads.Routes.Subscribe()
+	appFilters := reloads.AppFilters.Subscribe()
+
+	yml = `
+trace_printer: text
+discovery:
+  instrument:
+    - open_ports: 8080
+    - exe_path: "*/server"
+routes:
+  patterns: ["/user/:id"]
+filter:
+  application:
+    url_path:
+      match: "/api/*"
+`
+	report, err := cr.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"discovery", "routes", "filter.application"}, report.Applied)
+	assert.Empty(t, report.RestartRequired)
+
+	newCfg := testutil.ReadChannel(t, discovery, reloadTimeout)
+	require.Len(t, newCfg.Discovery.Instrument, 2)
+	assert.Equal(t, []string{"/user/:id"}, testutil.ReadChannel(t, routes, reloadTimeout).Patterns)
+	assert.Equal(t, filter.AttributeFamilyConfig{"url_path": {Match: "/api/*"}},
+		testutil.ReadChannel(t, appFilters, reloadTimeout))
+
+	// reloading again the same configuration does not forward anything
+	report, err = cr.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, report.Applied)
+}
+
+func TestConfigReloader_RestartRequired(t *testing.T) {
+	yml := baseReloadYAML
+	cr := testReloader(t, &yml)
+	discovery := cr.reloads.Discovery.Subscribe()
+
+	yml = `
+trace_printer: json
+log_level: DEBUG
+discovery:
+  poll_interval: 1m
+  instrument:
+    - open_ports: 8080
+`
+	report, err := cr.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, report.Applied)
+	assert.ElementsMatch(t, []string{"trace_printer", "log_level", "discovery"}, report.RestartRequired)
+	select {
+	case <-discovery:
+		assert.Fail(t, "non-selection discovery properties must not be forwarded")
+	default:
+	}
+
+	// as the changes have not been applied, they are still reported as requiring a restart
+	report, err = cr.Reload()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"trace_printer", "log_level", "discovery"}, report.RestartRequired)
+}
+
+func TestConfigReloader_InvalidConfig(t *testing.T) {
+	yml := baseReloadYAML
+	cr := testReloader(t, &yml)
+	routes := cr.reloads.Routes.Subscribe()
+
+	yml = `
+trace_printer: invalid
+routes:
+  patterns: ["/user/:id"]
+`
+	_, err := cr.Reload()
+	require.Error(t, err)
+	select {
+	case <-routes:
+		assert.Fail(t, "invalid configurations must not be forwarded")
+	default:
+	}
+}
+
+func TestConfigReloader_FileChanges(t *testing.T) {
+	cfgPath := path.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(baseReloadYAML), 0o644))
+
+	cfg := loadYAML(t, baseReloadYAML)
+	cr := NewConfigReloader(cfg, cfgPath)
+	routes := cfg.Reloads.Routes.Subscribe()
+	go cr.Run(t.Context(), 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(cfgPath, []byte(baseReloadYAML+`
+routes:
+  unmatched: path
+`), 0o644))
+	assert.Equal(t, transform.UnmatchPath, testutil.ReadChannel(t, routes, reloadTimeout).Unmatch)
+}
+
+func TestConfigReloader_ForwardedConfigIsNotShared(t *testing.T) {
+	yml := `
+trace_printer: text
+discovery:
+  instrument:
+    - open_ports: 8080
+routes:
+  patterns: ["/user/:id"]
+`
+	cr := testReloader(t, &yml)
+	routes := cr.reloads.Routes.Subscribe()
+
+	yml = `
+trace_printer: text
+discovery:
+  instrument:
+    - open_ports: 8080
+routes:
+  patterns: ["/user/:id", "/item/:id"]
+`
+	report, err := cr.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"routes"}, report.Applied)
+
+	// the pipeline nodes modifying the forwarded configuration must not
+	// affect the detection of the next changes
+	received := testutil.ReadChannel(t, routes, reloadTimeout)
+	received.Patterns[0] = "/modified"
+	received.Patterns = append(received.Patterns, "/other")
+
+	report, err = cr.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, report.Applied)
+}
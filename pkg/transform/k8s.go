@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/components/pipe/global"
 	"go.opentelemetry.io/obi/pkg/components/svc"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/kubecache"
 	"go.opentelemetry.io/obi/pkg/kubeflags"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
 	"go.opentelemetry.io/obi/pkg/pipe/swarm"
@@ -51,13 +52,28 @@ type KubernetesDecorator struct {
 	// kubernetes metadata decoration.
 	DisableInformers []string `yaml:"disable_informers" env:"OTEL_EBPF_KUBE_DISABLE_INFORMERS"`
 
-	// MetaCacheAddress is the host:port address of the beyla-k8s-cache service instance
+	// MetaCacheAddress is the host:port address of the beyla-k8s-cache service instance.
+	// It accepts a comma-separated list of addresses. If the host resolves to multiple IPs
+	// (e.g. a headless Service), each IP is considered a replica of the service. The agents are
+	// distributed among replicas by consistent hashing, and fail over to the next replica
+	// when the connection to their preferred one is lost.
 	MetaCacheAddress string `yaml:"meta_cache_address" env:"OTEL_EBPF_KUBE_META_CACHE_ADDRESS"`
 
+	// MetaCacheTLS configures the secure connection to the beyla-k8s-cache service
+	MetaCacheTLS kubecache.ClientTLSConfig `yaml:"meta_cache_tls"`
+
 	// MetaRestrictLocalNode will download only the metadata from the Pods that are located in the same
 	// node as the Beyla instance. It will also restrict the Node information to the local node.
+	// When MetaCacheAddress is set, the Pods from other nodes are still received if they have IPs,
+	// as they are required to decorate the peers of the local Pods.
 	MetaRestrictLocalNode bool `yaml:"meta_restrict_local_node" env:"OTEL_EBPF_KUBE_META_RESTRICT_LOCAL_NODE"`
 
+	// MetaNamespaces, if not empty, restricts the Pods and Services metadata that is received from
+	// the k8s-cache service to the given namespaces. The Pods and Services from other namespaces are
+	// still received if they have IPs, as they are required to decorate the peers of the Pods in the
+	// given namespaces. It only applies when MetaCacheAddress is set.
+	MetaNamespaces []string `yaml:"meta_namespaces" env:"OTEL_EBPF_KUBE_META_NAMESPACES" envSeparator:","`
+
 	// MetaSourceLabels allows Beyla overriding the service name and namespace of an application from
 	// the given labels.
 	// Deprecated: kept for backwards-compatibility with Beyla 1.9
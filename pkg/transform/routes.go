@@ -62,41 +62,78 @@ type RoutesConfig struct {
 }
 
 func RoutesProvider(rc *RoutesConfig, input, output *msg.Queue[[]request.Span]) swarm.InstanceFunc {
+	return ReloadableRoutesProvider(rc, nil, input, output)
+}
+
+// ReloadableRoutesProvider works as RoutesProvider, but it also listens for new routes configurations
+// in the updates queue, which replace the current configuration without restarting the node.
+// The updates queue can be nil.
+func ReloadableRoutesProvider(rc *RoutesConfig, updates *msg.Queue[*RoutesConfig], input, output *msg.Queue[[]request.Span]) swarm.InstanceFunc {
 	return (&routerNode{
-		config: rc,
-		input:  input,
-		output: output,
+		config:  rc,
+		updates: updates,
+		input:   input,
+		output:  output,
 	}).provideRoutes
 }
 
 type routerNode struct {
 	config     *RoutesConfig
 	classifier *clusterurl.ClusterURLClassifier
+	updates    *msg.Queue[*RoutesConfig]
 	input      *msg.Queue[[]request.Span]
 	output     *msg.Queue[[]request.Span]
 }
 
-func (rn *routerNode) provideRoutes(_ context.Context) (swarm.RunFunc, error) {
-	rc := rn.config
-	if rc == nil {
-		return swarm.Bypass(rn.input, rn.output)
-	}
+// routeRules contains the matchers and actions compiled from a RoutesConfig
+type routeRules struct {
+	unmatchAction func(rn *routerNode, span *request.Span)
+	matcher       route.Matcher
+	discarder     route.Matcher
+	routesEnabled bool
+	ignoreEnabled bool
+	ignoreMode    IgnoreMode
+}
 
+func (rn *routerNode) compileRules() (*routeRules, error) {
+	rc := rn.config
 	// set default value for Unmatch action
 	unmatchAction, err := chooseUnmatchPolicy(rn)
 	if err != nil {
 		return nil, err
 	}
-	matcher := route.NewMatcher(rc.Patterns)
-	discarder := route.NewMatcher(rc.IgnorePatterns)
-	routesEnabled := len(rc.Patterns) > 0
-	ignoreEnabled := len(rc.IgnorePatterns) > 0
-
-	ignoreMode := rc.IgnoredEvents
-	if ignoreMode == "" {
-		ignoreMode = IgnoreDefault
+	rules := &routeRules{
+		unmatchAction: unmatchAction,
+		matcher:       route.NewMatcher(rc.Patterns),
+		discarder:     route.NewMatcher(rc.IgnorePatterns),
+		routesEnabled: len(rc.Patterns) > 0,
+		ignoreEnabled: len(rc.IgnorePatterns) > 0,
+		ignoreMode:    rc.IgnoredEvents,
+	}
+	if rules.ignoreMode == "" {
+		rules.ignoreMode = IgnoreDefault
+	}
+	return rules, nil
+}
+
+func (rn *routerNode) provideRoutes(_ context.Context) (swarm.RunFunc, error) {
+	if rn.config == nil {
+		if rn.updates == nil {
+			return swarm.Bypass(rn.input, rn.output)
+		}
+		// routes can be defined later, so we need to start with a no-op configuration
+		rn.config = &RoutesConfig{Unmatch: UnmatchUnset}
+	}
+
+	rules, err := rn.compileRules()
+	if err != nil {
+		return nil, err
 	}
 
+	var updates <-chan *RoutesConfig
+	if rn.updates != nil {
+		updates = rn.updates.Subscribe()
+	}
 	in := rn.input.Subscribe()
 	out := rn.output
 	return func(ctx context.Context) {
@@ -107,23 +144,15 @@ func (rn *routerNode) provideRoutes(_ context.Context) (swarm.RunFunc, error) {
 			select {
 			case <-ctx.Done():
 				return
+			case rc, ok := <-updates:
+				if !ok {
+					updates = nil
+					continue
+				}
+				rules = rn.reload(rc, rules)
 			case spans := <-in:
 				for i := range spans {
-					s := &spans[i]
-					if ignoreEnabled {
-						if discarder.Find(s.Path) != "" {
-							if ignoreMode == IgnoreAll {
-								request.SetIgnoreMetrics(s)
-								request.SetIgnoreTraces(s)
-							}
-							// we can't discard it here, ignoring is selective (metrics | traces)
-							setSpanIgnoreMode(ignoreMode, s)
-						}
-					}
-					if routesEnabled {
-						s.Route = matcher.Find(s.Path)
-					}
-					unmatchAction(rn, s)
+					rules.apply(rn, &spans[i])
 				}
 				out.Send(spans)
 			}
@@ -131,6 +160,42 @@ func (rn *routerNode) provideRoutes(_ context.Context) (swarm.RunFunc, error) {
 	}, nil
 }
 
+// reload replaces the current routes configuration. If the new configuration
+// can't be applied, the previous one is kept.
+func (rn *routerNode) reload(rc *RoutesConfig, current *routeRules) *routeRules {
+	log := slog.With("component", "RoutesProvider")
+	if rc == nil {
+		rc = &RoutesConfig{Unmatch: UnmatchUnset}
+	}
+	prevConfig, prevClassifier := rn.config, rn.classifier
+	rn.config = rc
+	rules, err := rn.compileRules()
+	if err != nil {
+		log.Error("can't reload routes configuration. Keeping the previous one", "error", err)
+		rn.config, rn.classifier = prevConfig, prevClassifier
+		return current
+	}
+	log.Info("routes configuration reloaded")
+	return rules
+}
+
+func (rr *routeRules) apply(rn *routerNode, s *request.Span) {
+	if rr.ignoreEnabled {
+		if rr.discarder.Find(s.Path) != "" {
+			if rr.ignoreMode == IgnoreAll {
+				request.SetIgnoreMetrics(s)
+				request.SetIgnoreTraces(s)
+			}
+			// we can't discard it here, ignoring is selective (metrics | traces)
+			setSpanIgnoreMode(rr.ignoreMode, s)
+		}
+	}
+	if rr.routesEnabled {
+		s.Route = rr.matcher.Find(s.Path)
+	}
+	rr.unmatchAction(rn, s)
+}
+
 func chooseUnmatchPolicy(rn *routerNode) (func(rn *routerNode, span *request.Span), error) {
 	var unmatchAction func(rn *routerNode, span *request.Span)
 	rc := rn.config
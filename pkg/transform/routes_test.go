@@ -214,3 +214,52 @@ func filterIgnored(reader func() []request.Span) []request.Span {
 		}
 	}
 }
+
+func TestRoutesReload(t *testing.T) {
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	updates := msg.NewQueue[*RoutesConfig](msg.ChannelBufferLen(10))
+	router, err := ReloadableRoutesProvider(&RoutesConfig{Unmatch: UnmatchPath, Patterns: []string{"/user/:id"}},
+		updates, input, output)(t.Context())
+	require.NoError(t, err)
+	out := output.Subscribe()
+	defer input.Close()
+	go router(t.Context())
+	input.Send([]request.Span{{Path: "/item/1234"}})
+	assert.Equal(t, []request.Span{{
+		Path:  "/item/1234",
+		Route: "/item/1234",
+	}}, testutil.ReadChannel(t, out, testTimeout))
+
+	updates.Send(&RoutesConfig{Unmatch: UnmatchWildcard, Patterns: []string{"/item/:id"}})
+	// the update is received asynchronously, so we wait until the new patterns are applied
+	assert.Eventually(t, func() bool {
+		input.Send([]request.Span{{Path: "/item/1234"}})
+		return testutil.ReadChannel(t, out, testTimeout)[0].Route == "/item/:id"
+	}, testTimeout, 10*time.Millisecond)
+	input.Send([]request.Span{{Path: "/user/1234"}})
+	assert.Equal(t, []request.Span{{
+		Path:  "/user/1234",
+		Route: "/**",
+	}}, testutil.ReadChannel(t, out, testTimeout))
+}
+
+func TestRoutesReload_NoInitialConfig(t *testing.T) {
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	updates := msg.NewQueue[*RoutesConfig](msg.ChannelBufferLen(10))
+	router, err := ReloadableRoutesProvider(nil, updates, input, output)(t.Context())
+	require.NoError(t, err)
+	out := output.Subscribe()
+	defer input.Close()
+	go router(t.Context())
+	// without configuration, the routes are left unset
+	input.Send([]request.Span{{Path: "/user/1234"}})
+	assert.Equal(t, []request.Span{{Path: "/user/1234"}}, testutil.ReadChannel(t, out, testTimeout))
+
+	updates.Send(&RoutesConfig{Unmatch: UnmatchUnset, Patterns: []string{"/user/:id"}})
+	assert.Eventually(t, func() bool {
+		input.Send([]request.Span{{Path: "/user/1234"}})
+		return testutil.ReadChannel(t, out, testTimeout)[0].Route == "/user/:id"
+	}, testTimeout, 10*time.Millisecond)
+}
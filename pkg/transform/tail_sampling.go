@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+	"go.opentelemetry.io/obi/pkg/services"
+)
+
+func tslog() *slog.Logger {
+	return slog.With("component", "transform.TailSampling")
+}
+
+// TailSamplingConfig allows deciding which traces are exported after all their spans have been
+// received, according to the properties of the whole trace. It only affects the exported traces:
+// metrics are still calculated from all the spans.
+type TailSamplingConfig struct {
+	Enabled bool `yaml:"enabled" env:"OTEL_EBPF_TAIL_SAMPLING_ENABLED"`
+	// DecisionWait is the time that the spans of a trace are buffered, since the first span
+	// of the trace is received, before deciding whether the trace is kept or dropped.
+	DecisionWait time.Duration `yaml:"decision_wait" env:"OTEL_EBPF_TAIL_SAMPLING_DECISION_WAIT"`
+	// MaxTraces is the maximum number of traces that can be buffered at the same time. If the
+	// limit is reached, the decision for the oldest traces is taken before DecisionWait expires.
+	MaxTraces int `yaml:"max_traces" env:"OTEL_EBPF_TAIL_SAMPLING_MAX_TRACES"`
+
+	Policies TailSamplingPolicies `yaml:"policies"`
+}
+
+// TailSamplingPolicies define the conditions for keeping a trace. A trace is kept if any of its
+// spans matches any of the defined policies. Otherwise, it is kept according to the Probability.
+type TailSamplingPolicies struct {
+	// StatusError keeps the traces having any span with error status
+	StatusError bool `yaml:"status_error" env:"OTEL_EBPF_TAIL_SAMPLING_STATUS_ERROR"`
+	// Latency keeps the traces having any span whose duration is equal or higher than the threshold
+	Latency time.Duration `yaml:"latency" env:"OTEL_EBPF_TAIL_SAMPLING_LATENCY"`
+	// Route keeps the traces having any span whose route matches the provided glob
+	Route services.GlobAttr `yaml:"route" env:"OTEL_EBPF_TAIL_SAMPLING_ROUTE"`
+	// Service keeps the traces having any span whose service name matches the provided glob
+	Service services.GlobAttr `yaml:"service" env:"OTEL_EBPF_TAIL_SAMPLING_SERVICE"`
+	// Probability of keeping the traces that don't match any of the above policies,
+	// from 0 (drop all) to 1 (keep all)
+	Probability float64 `yaml:"probability" env:"OTEL_EBPF_TAIL_SAMPLING_PROBABILITY"`
+}
+
+func (c *TailSamplingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.DecisionWait <= 0 {
+		return errors.New("tail_sampling.decision_wait must be greater than zero")
+	}
+	if c.MaxTraces <= 0 {
+		return errors.New("tail_sampling.max_traces must be greater than zero")
+	}
+	if c.Policies.Probability < 0 || c.Policies.Probability > 1 {
+		return errors.New("tail_sampling.policies.probability must be between 0 and 1")
+	}
+	return nil
+}
+
+// TailSamplingProvider buffers the spans of each trace during the configured decision
+// window, and only forwards the traces that are kept by the sampling policies.
+func TailSamplingProvider(cfg *TailSamplingConfig, input, output *msg.Queue[[]request.Span]) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled {
+			return swarm.Bypass(input, output)
+		}
+		ts := newTailSampler(cfg)
+		in := input.Subscribe()
+		return func(ctx context.Context) {
+			defer output.Close()
+			ts.run(ctx, in, output)
+		}, nil
+	}
+}
+
+type pendingTrace struct {
+	id        trace.TraceID
+	firstSeen time.Time
+	spans     []request.Span
+}
+
+type tailSampler struct {
+	log     *slog.Logger
+	cfg     *TailSamplingConfig
+	sampler sdktrace.Sampler
+	clock   func() time.Time
+
+	pending map[trace.TraceID]*pendingTrace
+	// arrival order of the pending traces. As the decision window is the same for all the traces,
+	// it is also the order in which their decisions expire
+	order []*pendingTrace
+	// decided remembers the decision for the traces whose spans arrive after the decision window
+	decided *expirable.LRU[trace.TraceID, bool]
+}
+
+func newTailSampler(cfg *TailSamplingConfig) *tailSampler {
+	return &tailSampler{
+		log:     tslog(),
+		cfg:     cfg,
+		sampler: sdktrace.TraceIDRatioBased(cfg.Policies.Probability),
+		clock:   time.Now,
+		pending: map[trace.TraceID]*pendingTrace{},
+		decided: expirable.NewLRU[trace.TraceID, bool](cfg.MaxTraces, nil, 2*cfg.DecisionWait),
+	}
+}
+
+func (ts *tailSampler) run(ctx context.Context, in <-chan []request.Span, output *msg.Queue[[]request.Span]) {
+	// checking for expired traces at a fraction of the decision window
+	// bounds the extra wait of each trace to the 25% of the window
+	ticker := time.NewTicker(max(ts.cfg.DecisionWait/4, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			ts.log.Debug("context done. Exiting")
+			return
+		case spans, ok := <-in:
+			if !ok {
+				ts.log.Debug("input channel closed. Flushing pending traces")
+				if out := ts.expire(time.Time{}); len(out) > 0 {
+					output.Send(out)
+				}
+				return
+			}
+			if out := ts.add(spans); len(out) > 0 {
+				output.Send(out)
+			}
+		case <-ticker.C:
+			if out := ts.expire(ts.clock().Add(-ts.cfg.DecisionWait)); len(out) > 0 {
+				output.Send(out)
+			}
+		}
+	}
+}
+
+// add buffers the spans into their traces and returns the spans that can be forwarded
+// immediately: spans without trace context, internal signals, Go runtime events, spans
+// from traces whose decision was already taken, and the spans of the oldest traces if
+// the buffer is full.
+func (ts *tailSampler) add(spans []request.Span) []request.Span {
+	var out []request.Span
+	now := ts.clock()
+	for i := range spans {
+		span := &spans[i]
+		switch {
+		case span.InternalSignal(), span.GoRuntimeSignal():
+			out = append(out, *span)
+		case !span.TraceID.IsValid():
+			// single-span trace: the decision can be taken right now
+			if ts.keep(span.TraceID, spans[i:i+1]) {
+				out = append(out, *span)
+			}
+		default:
+			if keep, ok := ts.decided.Get(span.TraceID); ok {
+				if keep {
+					out = append(out, *span)
+				}
+				continue
+			}
+			pt, ok := ts.pending[span.TraceID]
+			if !ok {
+				if len(ts.pending) >= ts.cfg.MaxTraces {
+					out = ts.decideOldest(out)
+				}
+				pt = &pendingTrace{id: span.TraceID, firstSeen: now}
+				ts.pending[span.TraceID] = pt
+				ts.order = append(ts.order, pt)
+			}
+			pt.spans = append(pt.spans, *span)
+		}
+	}
+	return out
+}
+
+// expire takes the decision for all the traces that were first seen before the provided time,
+// returning the spans of the kept traces. A zero time flushes all the pending traces.
+func (ts *tailSampler) expire(firstSeenBefore time.Time) []request.Span {
+	var out []request.Span
+	for len(ts.order) > 0 &&
+		(firstSeenBefore.IsZero() || ts.order[0].firstSeen.Before(firstSeenBefore)) {
+		out = ts.decideOldest(out)
+	}
+	return out
+}
+
+func (ts *tailSampler) decideOldest(out []request.Span) []request.Span {
+	pt := ts.order[0]
+	ts.order[0] = nil
+	ts.order = ts.order[1:]
+	delete(ts.pending, pt.id)
+
+	keep := ts.keep(pt.id, pt.spans)
+	ts.decided.Add(pt.id, keep)
+	if keep {
+		out = append(out, pt.spans...)
+	}
+	return out
+}
+
+func (ts *tailSampler) keep(id trace.TraceID, spans []request.Span) bool {
+	p := &ts.cfg.Policies
+	for i := range spans {
+		span := &spans[i]
+		if p.StatusError && request.SpanStatusCode(span) == request.StatusCodeError {
+			return true
+		}
+		if p.Latency > 0 && time.Duration(span.End-span.RequestStart) >= p.Latency {
+			return true
+		}
+		if p.Route.IsSet() && span.Route != "" && p.Route.MatchString(span.Route) {
+			return true
+		}
+		if p.Service.IsSet() && p.Service.MatchString(span.Service.UID.Name) {
+			return true
+		}
+	}
+	if !id.IsValid() {
+		return rand.Float64() < p.Probability
+	}
+	return ts.sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: id}).Decision == sdktrace.RecordAndSample
+}
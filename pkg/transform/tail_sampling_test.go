@@ -0,0 +1,214 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/app/request"
+	"go.opentelemetry.io/obi/pkg/components/svc"
+	"go.opentelemetry.io/obi/pkg/components/testutil"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/services"
+)
+
+func tsTraceID(b byte) trace.TraceID {
+	return trace.TraceID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, b}
+}
+
+func tsSpan(traceID trace.TraceID, route string, status int, duration time.Duration) request.Span {
+	return request.Span{
+		Type:         request.EventTypeHTTP,
+		TraceID:      traceID,
+		Route:        route,
+		Status:       status,
+		RequestStart: 1000,
+		Start:        1000,
+		End:          1000 + duration.Nanoseconds(),
+		Service:      svc.Attrs{UID: svc.UID{Name: "frontend"}},
+	}
+}
+
+func tsRoutes(spans []request.Span) []string {
+	var routes []string
+	for i := range spans {
+		routes = append(routes, spans[i].Route)
+	}
+	return routes
+}
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestTailSampler(cfg *TailSamplingConfig) (*tailSampler, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	ts := newTailSampler(cfg)
+	ts.clock = clock.Now
+	return ts, clock
+}
+
+func TestTailSampling_Policies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies TailSamplingPolicies
+		kept     []string
+	}{
+		{
+			name:     "status error",
+			policies: TailSamplingPolicies{StatusError: true},
+			kept:     []string{"/a", "/a/error"},
+		},
+		{
+			name:     "latency",
+			policies: TailSamplingPolicies{Latency: time.Second},
+			kept:     []string{"/b", "/b/slow"},
+		},
+		{
+			name:     "route",
+			policies: TailSamplingPolicies{Route: services.NewGlob("/c/*")},
+			kept:     []string{"/c", "/c/checkout"},
+		},
+		{
+			name:     "service",
+			policies: TailSamplingPolicies{Service: services.NewGlob("front*")},
+			kept:     []string{"/a", "/a/error", "/b", "/b/slow", "/c", "/c/checkout", "/d"},
+		},
+		{
+			name:     "keep all",
+			policies: TailSamplingPolicies{Probability: 1},
+			kept:     []string{"/a", "/a/error", "/b", "/b/slow", "/c", "/c/checkout", "/d"},
+		},
+		{
+			name:     "drop all",
+			policies: TailSamplingPolicies{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, clock := newTestTailSampler(&TailSamplingConfig{
+				Enabled: true, DecisionWait: 5 * time.Second, MaxTraces: 10, Policies: tt.policies,
+			})
+			assert.Empty(t, ts.add([]request.Span{
+				tsSpan(tsTraceID(1), "/a", 200, time.Millisecond),
+				tsSpan(tsTraceID(2), "/b", 200, time.Millisecond),
+				tsSpan(tsTraceID(3), "/c", 200, time.Millisecond),
+				tsSpan(tsTraceID(4), "/d", 200, time.Millisecond),
+			}))
+			clock.now = clock.now.Add(time.Second)
+			assert.Empty(t, ts.add([]request.Span{
+				tsSpan(tsTraceID(1), "/a/error", 500, time.Millisecond),
+				tsSpan(tsTraceID(2), "/b/slow", 200, 2*time.Second),
+				tsSpan(tsTraceID(3), "/c/checkout", 200, time.Millisecond),
+			}))
+
+			// decision window not expired yet
+			assert.Empty(t, ts.expire(clock.now.Add(-5*time.Second)))
+
+			clock.now = clock.now.Add(5 * time.Second)
+			assert.Equal(t, tt.kept, tsRoutes(ts.expire(clock.now.Add(-5*time.Second))))
+			assert.Empty(t, ts.pending)
+			assert.Empty(t, ts.order)
+		})
+	}
+}
+
+func TestTailSampling_LateSpans(t *testing.T) {
+	ts, clock := newTestTailSampler(&TailSamplingConfig{
+		Enabled: true, DecisionWait: 5 * time.Second, MaxTraces: 10,
+		Policies: TailSamplingPolicies{StatusError: true},
+	})
+	ts.add([]request.Span{
+		tsSpan(tsTraceID(1), "/kept", 500, time.Millisecond),
+		tsSpan(tsTraceID(2), "/dropped", 200, time.Millisecond),
+	})
+	clock.now = clock.now.Add(6 * time.Second)
+	assert.Equal(t, []string{"/kept"}, tsRoutes(ts.expire(clock.now.Add(-5*time.Second))))
+
+	// spans arriving after the decision follow the decision of their trace
+	assert.Equal(t, []string{"/kept/late"}, tsRoutes(ts.add([]request.Span{
+		tsSpan(tsTraceID(1), "/kept/late", 200, time.Millisecond),
+		tsSpan(tsTraceID(2), "/dropped/late", 500, time.Millisecond),
+	})))
+	assert.Empty(t, ts.pending)
+}
+
+func TestTailSampling_MaxTraces(t *testing.T) {
+	ts, _ := newTestTailSampler(&TailSamplingConfig{
+		Enabled: true, DecisionWait: time.Hour, MaxTraces: 2,
+		Policies: TailSamplingPolicies{Probability: 1},
+	})
+	assert.Empty(t, ts.add([]request.Span{
+		tsSpan(tsTraceID(1), "/1", 200, time.Millisecond),
+		tsSpan(tsTraceID(2), "/2", 200, time.Millisecond),
+	}))
+	// the oldest traces are decided to make room for the new ones
+	assert.Equal(t, []string{"/1"}, tsRoutes(ts.add([]request.Span{
+		tsSpan(tsTraceID(3), "/3", 200, time.Millisecond),
+	})))
+	assert.Equal(t, []string{"/2", "/3"}, tsRoutes(ts.expire(time.Time{})))
+}
+
+func TestTailSampling_Passthrough(t *testing.T) {
+	ts, _ := newTestTailSampler(&TailSamplingConfig{
+		Enabled: true, DecisionWait: time.Hour, MaxTraces: 10,
+		Policies: TailSamplingPolicies{StatusError: true},
+	})
+	out := ts.add([]request.Span{
+		{Type: request.EventTypeProcessAlive},
+		tsSpan(trace.TraceID{}, "/no-trace/error", 500, time.Millisecond),
+		tsSpan(trace.TraceID{}, "/no-trace", 200, time.Millisecond),
+	})
+	require.Len(t, out, 2)
+	assert.Equal(t, request.EventTypeProcessAlive, out[0].Type)
+	assert.Equal(t, "/no-trace/error", out[1].Route)
+}
+
+func TestTailSamplingProvider(t *testing.T) {
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	sampler, err := TailSamplingProvider(&TailSamplingConfig{
+		Enabled: true, DecisionWait: 10 * time.Millisecond, MaxTraces: 10,
+		Policies: TailSamplingPolicies{StatusError: true},
+	}, input, output)(t.Context())
+	require.NoError(t, err)
+	out := output.Subscribe()
+	defer input.Close()
+	go sampler(t.Context())
+
+	input.Send([]request.Span{
+		tsSpan(tsTraceID(1), "/ok", 200, time.Millisecond),
+		tsSpan(tsTraceID(2), "/error", 500, time.Millisecond),
+	})
+	assert.Equal(t, []string{"/error"}, tsRoutes(testutil.ReadChannel(t, out, testTimeout)))
+}
+
+func TestTailSamplingProvider_Disabled(t *testing.T) {
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	sampler, err := TailSamplingProvider(&TailSamplingConfig{}, input, output)(t.Context())
+	require.NoError(t, err)
+	out := output.Subscribe()
+	defer input.Close()
+	go sampler(t.Context())
+
+	input.Send([]request.Span{tsSpan(tsTraceID(1), "/ok", 200, time.Millisecond)})
+	assert.Equal(t, []string{"/ok"}, tsRoutes(testutil.ReadChannel(t, out, testTimeout)))
+}
+
+func TestTailSamplingConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&TailSamplingConfig{}).Validate())
+	assert.NoError(t, (&TailSamplingConfig{Enabled: true, DecisionWait: time.Second, MaxTraces: 1}).Validate())
+	assert.Error(t, (&TailSamplingConfig{Enabled: true, MaxTraces: 1}).Validate())
+	assert.Error(t, (&TailSamplingConfig{Enabled: true, DecisionWait: time.Second}).Validate())
+	assert.Error(t, (&TailSamplingConfig{
+		Enabled: true, DecisionWait: time.Second, MaxTraces: 1,
+		Policies: TailSamplingPolicies{Probability: 1.5},
+	}).Validate())
+}